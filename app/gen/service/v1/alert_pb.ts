@@ -0,0 +1,332 @@
+// @generated by protoc-gen-es v2.10.2 with parameter "target=ts"
+// @generated from file service/v1/alert.proto (package service.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Timestamp } from "@bufbuild/protobuf/wkt";
+import { file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file service/v1/alert.proto.
+ */
+export const file_service_v1_alert: GenFile = /*@__PURE__*/
+  fileDesc("ChZzZXJ2aWNlL3YxL2FsZXJ0LnByb3RvEgpzZXJ2aWNlLnYxIq4CCglBbGVydFJ1bGUSCgoCaWQYASABKAkSDwoHbm9kZV9pZBgCIAEoCRISCgpzZXJ2aWNlX2lkGAMgASgJEgwKBG5hbWUYBCABKAkSEgoKZXhwcmVzc2lvbhgFIAEoCRIMCgRraW5kGAYgASgJEg8KB2VuYWJsZWQYByABKAgSGAoQY29vbGRvd25fc2Vjb25kcxgIIAEoBRIuCgpjcmVhdGVkX2F0GAkgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAogASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBI1ChFsYXN0X3RyaWdnZXJlZF9hdBgLIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXAieQoWQ3JlYXRlQWxlcnRSdWxlUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEhIKCnNlcnZpY2VfaWQYAiABKAkSDAoEbmFtZRgDIAEoCRISCgpleHByZXNzaW9uGAQgASgJEhgKEGNvb2xkb3duX3NlY29uZHMYBSABKAUiPgoXQ3JlYXRlQWxlcnRSdWxlUmVzcG9uc2USIwoEcnVsZRgBIAEoCzIVLnNlcnZpY2UudjEuQWxlcnRSdWxlIjwKFUxpc3RBbGVydFJ1bGVzUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEhIKCnNlcnZpY2VfaWQYAiABKAkiPgoWTGlzdEFsZXJ0UnVsZXNSZXNwb25zZRIkCgVydWxlcxgBIAMoCzIVLnNlcnZpY2UudjEuQWxlcnRSdWxlInEKFlVwZGF0ZUFsZXJ0UnVsZVJlcXVlc3QSCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRISCgpleHByZXNzaW9uGAMgASgJEg8KB2VuYWJsZWQYBCABKAgSGAoQY29vbGRvd25fc2Vjb25kcxgFIAEoBSI+ChdVcGRhdGVBbGVydFJ1bGVSZXNwb25zZRIjCgRydWxlGAEgASgLMhUuc2VydmljZS52MS5BbGVydFJ1bGUiJAoWRGVsZXRlQWxlcnRSdWxlUmVxdWVzdBIKCgJpZBgBIAEoCSIqChdEZWxldGVBbGVydFJ1bGVSZXNwb25zZRIPCgdzdWNjZXNzGAEgASgIMvsCCgxBbGVydFNlcnZpY2USWgoPQ3JlYXRlQWxlcnRSdWxlEiIuc2VydmljZS52MS5DcmVhdGVBbGVydFJ1bGVSZXF1ZXN0GiMuc2VydmljZS52MS5DcmVhdGVBbGVydFJ1bGVSZXNwb25zZRJXCg5MaXN0QWxlcnRSdWxlcxIhLnNlcnZpY2UudjEuTGlzdEFsZXJ0UnVsZXNSZXF1ZXN0GiIuc2VydmljZS52MS5MaXN0QWxlcnRSdWxlc1Jlc3BvbnNlEloKD1VwZGF0ZUFsZXJ0UnVsZRIiLnNlcnZpY2UudjEuVXBkYXRlQWxlcnRSdWxlUmVxdWVzdBojLnNlcnZpY2UudjEuVXBkYXRlQWxlcnRSdWxlUmVzcG9uc2USWgoPRGVsZXRlQWxlcnRSdWxlEiIuc2VydmljZS52MS5EZWxldGVBbGVydFJ1bGVSZXF1ZXN0GiMuc2VydmljZS52MS5EZWxldGVBbGVydFJ1bGVSZXNwb25zZUIpWid1bmJsaW5rL3NlcnZlci9nZW4vc2VydmljZS92MTtzZXJ2aWNldjFiBnByb3RvMw", [file_google_protobuf_timestamp]);
+
+/**
+ * An alert rule is checked against every VLM result of the services it covers.
+ *
+ * Expressions made only of these predicates (joined by spaces or "and") are
+ * evaluated directly:
+ *   label=car            an object with this label
+ *   in zone driveway     ...whose box center lies in a zone of the service
+ *   count>=2             how many such objects (>, >=, =, <=, <)
+ *   description~parcel   the description contains this text
+ *   after 22:00          local time of the frames (also "10pm"); with
+ *   before 06:00         both, the window may wrap past midnight
+ * Anything else, e.g. "person at the front door after 10pm", is free text
+ * and is judged by the fast model.
+ *
+ * @generated from message service.v1.AlertRule
+ */
+export type AlertRule = Message<"service.v1.AlertRule"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+
+  /**
+   * Empty = every service of the node
+   *
+   * @generated from field: string service_id = 3;
+   */
+  serviceId: string;
+
+  /**
+   * @generated from field: string name = 4;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string expression = 5;
+   */
+  expression: string;
+
+  /**
+   * "predicate" or "natural_language"
+   *
+   * @generated from field: string kind = 6;
+   */
+  kind: string;
+
+  /**
+   * @generated from field: bool enabled = 7;
+   */
+  enabled: boolean;
+
+  /**
+   * No repeat alert for a service within this window
+   *
+   * @generated from field: int32 cooldown_seconds = 8;
+   */
+  cooldownSeconds: number;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 9;
+   */
+  createdAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp updated_at = 10;
+   */
+  updatedAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp last_triggered_at = 11;
+   */
+  lastTriggeredAt?: Timestamp;
+};
+
+/**
+ * Describes the message service.v1.AlertRule.
+ * Use `create(AlertRuleSchema)` to create a new message.
+ */
+export const AlertRuleSchema: GenMessage<AlertRule> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 0);
+
+/**
+ * @generated from message service.v1.CreateAlertRuleRequest
+ */
+export type CreateAlertRuleRequest = Message<"service.v1.CreateAlertRuleRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * Optional: limit the rule to one service
+   *
+   * @generated from field: string service_id = 2;
+   */
+  serviceId: string;
+
+  /**
+   * @generated from field: string name = 3;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string expression = 4;
+   */
+  expression: string;
+
+  /**
+   * 0 = default (300)
+   *
+   * @generated from field: int32 cooldown_seconds = 5;
+   */
+  cooldownSeconds: number;
+};
+
+/**
+ * Describes the message service.v1.CreateAlertRuleRequest.
+ * Use `create(CreateAlertRuleRequestSchema)` to create a new message.
+ */
+export const CreateAlertRuleRequestSchema: GenMessage<CreateAlertRuleRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 1);
+
+/**
+ * @generated from message service.v1.CreateAlertRuleResponse
+ */
+export type CreateAlertRuleResponse = Message<"service.v1.CreateAlertRuleResponse"> & {
+  /**
+   * @generated from field: service.v1.AlertRule rule = 1;
+   */
+  rule?: AlertRule;
+};
+
+/**
+ * Describes the message service.v1.CreateAlertRuleResponse.
+ * Use `create(CreateAlertRuleResponseSchema)` to create a new message.
+ */
+export const CreateAlertRuleResponseSchema: GenMessage<CreateAlertRuleResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 2);
+
+/**
+ * @generated from message service.v1.ListAlertRulesRequest
+ */
+export type ListAlertRulesRequest = Message<"service.v1.ListAlertRulesRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * Optional: only rules covering this service
+   *
+   * @generated from field: string service_id = 2;
+   */
+  serviceId: string;
+};
+
+/**
+ * Describes the message service.v1.ListAlertRulesRequest.
+ * Use `create(ListAlertRulesRequestSchema)` to create a new message.
+ */
+export const ListAlertRulesRequestSchema: GenMessage<ListAlertRulesRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 3);
+
+/**
+ * @generated from message service.v1.ListAlertRulesResponse
+ */
+export type ListAlertRulesResponse = Message<"service.v1.ListAlertRulesResponse"> & {
+  /**
+   * @generated from field: repeated service.v1.AlertRule rules = 1;
+   */
+  rules: AlertRule[];
+};
+
+/**
+ * Describes the message service.v1.ListAlertRulesResponse.
+ * Use `create(ListAlertRulesResponseSchema)` to create a new message.
+ */
+export const ListAlertRulesResponseSchema: GenMessage<ListAlertRulesResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 4);
+
+/**
+ * @generated from message service.v1.UpdateAlertRuleRequest
+ */
+export type UpdateAlertRuleRequest = Message<"service.v1.UpdateAlertRuleRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string expression = 3;
+   */
+  expression: string;
+
+  /**
+   * @generated from field: bool enabled = 4;
+   */
+  enabled: boolean;
+
+  /**
+   * 0 = default (300)
+   *
+   * @generated from field: int32 cooldown_seconds = 5;
+   */
+  cooldownSeconds: number;
+};
+
+/**
+ * Describes the message service.v1.UpdateAlertRuleRequest.
+ * Use `create(UpdateAlertRuleRequestSchema)` to create a new message.
+ */
+export const UpdateAlertRuleRequestSchema: GenMessage<UpdateAlertRuleRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 5);
+
+/**
+ * @generated from message service.v1.UpdateAlertRuleResponse
+ */
+export type UpdateAlertRuleResponse = Message<"service.v1.UpdateAlertRuleResponse"> & {
+  /**
+   * @generated from field: service.v1.AlertRule rule = 1;
+   */
+  rule?: AlertRule;
+};
+
+/**
+ * Describes the message service.v1.UpdateAlertRuleResponse.
+ * Use `create(UpdateAlertRuleResponseSchema)` to create a new message.
+ */
+export const UpdateAlertRuleResponseSchema: GenMessage<UpdateAlertRuleResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 6);
+
+/**
+ * @generated from message service.v1.DeleteAlertRuleRequest
+ */
+export type DeleteAlertRuleRequest = Message<"service.v1.DeleteAlertRuleRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message service.v1.DeleteAlertRuleRequest.
+ * Use `create(DeleteAlertRuleRequestSchema)` to create a new message.
+ */
+export const DeleteAlertRuleRequestSchema: GenMessage<DeleteAlertRuleRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 7);
+
+/**
+ * @generated from message service.v1.DeleteAlertRuleResponse
+ */
+export type DeleteAlertRuleResponse = Message<"service.v1.DeleteAlertRuleResponse"> & {
+  /**
+   * @generated from field: bool success = 1;
+   */
+  success: boolean;
+};
+
+/**
+ * Describes the message service.v1.DeleteAlertRuleResponse.
+ * Use `create(DeleteAlertRuleResponseSchema)` to create a new message.
+ */
+export const DeleteAlertRuleResponseSchema: GenMessage<DeleteAlertRuleResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_alert, 8);
+
+/**
+ * AlertService manages rules that turn VLM results into "alert" events
+ *
+ * @generated from service service.v1.AlertService
+ */
+export const AlertService: GenService<{
+  /**
+   * @generated from rpc service.v1.AlertService.CreateAlertRule
+   */
+  createAlertRule: {
+    methodKind: "unary";
+    input: typeof CreateAlertRuleRequestSchema;
+    output: typeof CreateAlertRuleResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.AlertService.ListAlertRules
+   */
+  listAlertRules: {
+    methodKind: "unary";
+    input: typeof ListAlertRulesRequestSchema;
+    output: typeof ListAlertRulesResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.AlertService.UpdateAlertRule
+   */
+  updateAlertRule: {
+    methodKind: "unary";
+    input: typeof UpdateAlertRuleRequestSchema;
+    output: typeof UpdateAlertRuleResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.AlertService.DeleteAlertRule
+   */
+  deleteAlertRule: {
+    methodKind: "unary";
+    input: typeof DeleteAlertRuleRequestSchema;
+    output: typeof DeleteAlertRuleResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_service_v1_alert, 0);
+
@@ -12,7 +12,7 @@ import type { JsonObject, Message } from "@bufbuild/protobuf";
  * Describes the file service/v1/event.proto.
  */
 export const file_service_v1_event: GenFile = /*@__PURE__*/
-  fileDesc("ChZzZXJ2aWNlL3YxL2V2ZW50LnByb3RvEgpzZXJ2aWNlLnYxIoEBCgVFdmVudBIKCgJpZBgBIAEoCRISCgpzZXJ2aWNlX2lkGAIgASgJEigKB3BheWxvYWQYAyABKAsyFy5nb29nbGUucHJvdG9idWYuU3RydWN0Ei4KCmNyZWF0ZWRfYXQYBCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wIlQKGUxpc3RFdmVudHNCeU5vZGVJZFJlcXVlc3QSDwoHbm9kZV9pZBgBIAEoCRIRCglwYWdlX3NpemUYAiABKAUSEwoLcGFnZV9vZmZzZXQYAyABKAUiVAoaTGlzdEV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USIQoGZXZlbnRzGAEgAygLMhEuc2VydmljZS52MS5FdmVudBITCgt0b3RhbF9jb3VudBgCIAEoBSIbChlDb3VudEV2ZW50c0ZvclVzZXJSZXF1ZXN0IisKGkNvdW50RXZlbnRzRm9yVXNlclJlc3BvbnNlEg0KBWNvdW50GAEgASgDIlsKG1N0cmVhbUV2ZW50c0J5Tm9kZUlkUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEhIKCnNlcnZpY2VfaWQYAiABKAkSFwoPc2luY2VfdGltZXN0YW1wGAMgASgDImIKHFN0cmVhbUV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USIgoFZXZlbnQYASABKAsyES5zZXJ2aWNlLnYxLkV2ZW50SAASEwoJaGVhcnRiZWF0GAIgASgJSABCCQoHcGF5bG9hZDLFAgoMRXZlbnRTZXJ2aWNlEmMKEkxpc3RFdmVudHNCeU5vZGVJZBIlLnNlcnZpY2UudjEuTGlzdEV2ZW50c0J5Tm9kZUlkUmVxdWVzdBomLnNlcnZpY2UudjEuTGlzdEV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USYwoSQ291bnRFdmVudHNGb3JVc2VyEiUuc2VydmljZS52MS5Db3VudEV2ZW50c0ZvclVzZXJSZXF1ZXN0GiYuc2VydmljZS52MS5Db3VudEV2ZW50c0ZvclVzZXJSZXNwb25zZRJrChRTdHJlYW1FdmVudHNCeU5vZGVJZBInLnNlcnZpY2UudjEuU3RyZWFtRXZlbnRzQnlOb2RlSWRSZXF1ZXN0Giguc2VydmljZS52MS5TdHJlYW1FdmVudHNCeU5vZGVJZFJlc3BvbnNlMAFCKVondW5ibGluay9zZXJ2ZXIvZ2VuL3NlcnZpY2UvdjE7c2VydmljZXYxYgZwcm90bzM", [file_google_protobuf_timestamp, file_google_protobuf_struct]);
+  fileDesc("ChZzZXJ2aWNlL3YxL2V2ZW50LnByb3RvEgpzZXJ2aWNlLnYxIoEBCgVFdmVudBIKCgJpZBgBIAEoCRISCgpzZXJ2aWNlX2lkGAIgASgJEigKB3BheWxvYWQYAyABKAsyFy5nb29nbGUucHJvdG9idWYuU3RydWN0Ei4KCmNyZWF0ZWRfYXQYBCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wIlQKGUxpc3RFdmVudHNCeU5vZGVJZFJlcXVlc3QSDwoHbm9kZV9pZBgBIAEoCRIRCglwYWdlX3NpemUYAiABKAUSEwoLcGFnZV9vZmZzZXQYAyABKAUiVAoaTGlzdEV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USIQoGZXZlbnRzGAEgAygLMhEuc2VydmljZS52MS5FdmVudBITCgt0b3RhbF9jb3VudBgCIAEoBSIbChlDb3VudEV2ZW50c0ZvclVzZXJSZXF1ZXN0IisKGkNvdW50RXZlbnRzRm9yVXNlclJlc3BvbnNlEg0KBWNvdW50GAEgASgDIkQKE1NlYXJjaEV2ZW50c1JlcXVlc3QSDQoFcXVlcnkYASABKAkSDwoHbm9kZV9pZBgCIAEoCRINCgVsaW1pdBgDIAEoBSJvChFFdmVudFNlYXJjaFJlc3VsdBIgCgVldmVudBgBIAEoCzIRLnNlcnZpY2UudjEuRXZlbnQSDQoFc2NvcmUYAiABKAESFQoNa2V5d29yZF9tYXRjaBgDIAEoCBISCgpzaW1pbGFyaXR5GAQgASgBIlgKFFNlYXJjaEV2ZW50c1Jlc3BvbnNlEi4KB3Jlc3VsdHMYASADKAsyHS5zZXJ2aWNlLnYxLkV2ZW50U2VhcmNoUmVzdWx0EhAKCHNlbWFudGljGAIgASgIIlsKG1N0cmVhbUV2ZW50c0J5Tm9kZUlkUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEhIKCnNlcnZpY2VfaWQYAiABKAkSFwoPc2luY2VfdGltZXN0YW1wGAMgASgDImIKHFN0cmVhbUV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USIgoFZXZlbnQYASABKAsyES5zZXJ2aWNlLnYxLkV2ZW50SAASEwoJaGVhcnRiZWF0GAIgASgJSABCCQoHcGF5bG9hZDKYAwoMRXZlbnRTZXJ2aWNlEmMKEkxpc3RFdmVudHNCeU5vZGVJZBIlLnNlcnZpY2UudjEuTGlzdEV2ZW50c0J5Tm9kZUlkUmVxdWVzdBomLnNlcnZpY2UudjEuTGlzdEV2ZW50c0J5Tm9kZUlkUmVzcG9uc2USYwoSQ291bnRFdmVudHNGb3JVc2VyEiUuc2VydmljZS52MS5Db3VudEV2ZW50c0ZvclVzZXJSZXF1ZXN0GiYuc2VydmljZS52MS5Db3VudEV2ZW50c0ZvclVzZXJSZXNwb25zZRJRCgxTZWFyY2hFdmVudHMSHy5zZXJ2aWNlLnYxLlNlYXJjaEV2ZW50c1JlcXVlc3QaIC5zZXJ2aWNlLnYxLlNlYXJjaEV2ZW50c1Jlc3BvbnNlEmsKFFN0cmVhbUV2ZW50c0J5Tm9kZUlkEicuc2VydmljZS52MS5TdHJlYW1FdmVudHNCeU5vZGVJZFJlcXVlc3QaKC5zZXJ2aWNlLnYxLlN0cmVhbUV2ZW50c0J5Tm9kZUlkUmVzcG9uc2UwAUIpWid1bmJsaW5rL3NlcnZlci9nZW4vc2VydmljZS92MTtzZXJ2aWNldjFiBnByb3RvMw", [file_google_protobuf_timestamp, file_google_protobuf_struct]);
 
 /**
  * @generated from message service.v1.Event
@@ -131,6 +131,101 @@ export type CountEventsForUserResponse = Message<"service.v1.CountEventsForUserR
 export const CountEventsForUserResponseSchema: GenMessage<CountEventsForUserResponse> = /*@__PURE__*/
   messageDesc(file_service_v1_event, 4);
 
+/**
+ * @generated from message service.v1.SearchEventsRequest
+ */
+export type SearchEventsRequest = Message<"service.v1.SearchEventsRequest"> & {
+  /**
+   * Free text, e.g. "someone carrying a box"
+   *
+   * @generated from field: string query = 1;
+   */
+  query: string;
+
+  /**
+   * Optional: only events of this node
+   *
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+
+  /**
+   * Maximum results (default 20, max 100)
+   *
+   * @generated from field: int32 limit = 3;
+   */
+  limit: number;
+};
+
+/**
+ * Describes the message service.v1.SearchEventsRequest.
+ * Use `create(SearchEventsRequestSchema)` to create a new message.
+ */
+export const SearchEventsRequestSchema: GenMessage<SearchEventsRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_event, 5);
+
+/**
+ * @generated from message service.v1.EventSearchResult
+ */
+export type EventSearchResult = Message<"service.v1.EventSearchResult"> & {
+  /**
+   * @generated from field: service.v1.Event event = 1;
+   */
+  event?: Event;
+
+  /**
+   * Fused rank score; higher is better
+   *
+   * @generated from field: double score = 2;
+   */
+  score: number;
+
+  /**
+   * Matched the query words
+   *
+   * @generated from field: bool keyword_match = 3;
+   */
+  keywordMatch: boolean;
+
+  /**
+   * Cosine similarity to the query (0 = not a semantic match)
+   *
+   * @generated from field: double similarity = 4;
+   */
+  similarity: number;
+};
+
+/**
+ * Describes the message service.v1.EventSearchResult.
+ * Use `create(EventSearchResultSchema)` to create a new message.
+ */
+export const EventSearchResultSchema: GenMessage<EventSearchResult> = /*@__PURE__*/
+  messageDesc(file_service_v1_event, 6);
+
+/**
+ * @generated from message service.v1.SearchEventsResponse
+ */
+export type SearchEventsResponse = Message<"service.v1.SearchEventsResponse"> & {
+  /**
+   * @generated from field: repeated service.v1.EventSearchResult results = 1;
+   */
+  results: EventSearchResult[];
+
+  /**
+   * Whether embeddings were used (false = keywords only)
+   *
+   * @generated from field: bool semantic = 2;
+   */
+  semantic: boolean;
+};
+
+/**
+ * Describes the message service.v1.SearchEventsResponse.
+ * Use `create(SearchEventsResponseSchema)` to create a new message.
+ */
+export const SearchEventsResponseSchema: GenMessage<SearchEventsResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_event, 7);
+
 /**
  * @generated from message service.v1.StreamEventsByNodeIdRequest
  */
@@ -162,7 +257,7 @@ export type StreamEventsByNodeIdRequest = Message<"service.v1.StreamEventsByNode
  * Use `create(StreamEventsByNodeIdRequestSchema)` to create a new message.
  */
 export const StreamEventsByNodeIdRequestSchema: GenMessage<StreamEventsByNodeIdRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_event, 5);
+  messageDesc(file_service_v1_event, 8);
 
 /**
  * @generated from message service.v1.StreamEventsByNodeIdResponse
@@ -195,7 +290,7 @@ export type StreamEventsByNodeIdResponse = Message<"service.v1.StreamEventsByNod
  * Use `create(StreamEventsByNodeIdResponseSchema)` to create a new message.
  */
 export const StreamEventsByNodeIdResponseSchema: GenMessage<StreamEventsByNodeIdResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_event, 6);
+  messageDesc(file_service_v1_event, 9);
 
 /**
  * @generated from service service.v1.EventService
@@ -219,6 +314,16 @@ export const EventService: GenService<{
     input: typeof CountEventsForUserRequestSchema;
     output: typeof CountEventsForUserResponseSchema;
   },
+  /**
+   * Hybrid keyword + semantic search over the VLM descriptions of the user's events
+   *
+   * @generated from rpc service.v1.EventService.SearchEvents
+   */
+  searchEvents: {
+    methodKind: "unary";
+    input: typeof SearchEventsRequestSchema;
+    output: typeof SearchEventsResponseSchema;
+  },
   /**
    * Streaming events
    *
@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file service/v1/service.proto.
  */
 export const file_service_v1_service: GenFile = /*@__PURE__*/
-  fileDesc("ChhzZXJ2aWNlL3YxL3NlcnZpY2UucHJvdG8SCnNlcnZpY2UudjEioQEKB1NlcnZpY2USCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRILCgN1cmwYAyABKAkSDwoHbm9kZV9pZBgEIAEoCRIuCgpjcmVhdGVkX2F0GAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAYgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcCJCChRDcmVhdGVTZXJ2aWNlUmVxdWVzdBIMCgRuYW1lGAEgASgJEgsKA3VybBgCIAEoCRIPCgdub2RlX2lkGAMgASgJIj0KFUNyZWF0ZVNlcnZpY2VSZXNwb25zZRIkCgdzZXJ2aWNlGAEgASgLMhMuc2VydmljZS52MS5TZXJ2aWNlIi4KG0xpc3RTZXJ2aWNlc0J5Tm9kZUlkUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJIkUKHExpc3RTZXJ2aWNlc0J5Tm9kZUlkUmVzcG9uc2USJQoIc2VydmljZXMYASADKAsyEy5zZXJ2aWNlLnYxLlNlcnZpY2UiPQoUVXBkYXRlU2VydmljZVJlcXVlc3QSCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRILCgN1cmwYAyABKAkiPQoVVXBkYXRlU2VydmljZVJlc3BvbnNlEiQKB3NlcnZpY2UYASABKAsyEy5zZXJ2aWNlLnYxLlNlcnZpY2UiKgoURGVsZXRlU2VydmljZVJlcXVlc3QSEgoKc2VydmljZV9pZBgBIAEoCSIoChVEZWxldGVTZXJ2aWNlUmVzcG9uc2USDwoHc3VjY2VzcxgBIAEoCCIrChhBc3NvY2lhdGVVc2VyTm9kZVJlcXVlc3QSDwoHbm9kZV9pZBgBIAEoCSIsChlBc3NvY2lhdGVVc2VyTm9kZVJlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgiFgoUTGlzdFVzZXJOb2Rlc1JlcXVlc3QiKQoVTGlzdFVzZXJOb2Rlc1Jlc3BvbnNlEhAKCG5vZGVfaWRzGAEgAygJMrUECg5TZXJ2aWNlU2VydmljZRJUCg1DcmVhdGVTZXJ2aWNlEiAuc2VydmljZS52MS5DcmVhdGVTZXJ2aWNlUmVxdWVzdBohLnNlcnZpY2UudjEuQ3JlYXRlU2VydmljZVJlc3BvbnNlEmkKFExpc3RTZXJ2aWNlc0J5Tm9kZUlkEicuc2VydmljZS52MS5MaXN0U2VydmljZXNCeU5vZGVJZFJlcXVlc3QaKC5zZXJ2aWNlLnYxLkxpc3RTZXJ2aWNlc0J5Tm9kZUlkUmVzcG9uc2USVAoNVXBkYXRlU2VydmljZRIgLnNlcnZpY2UudjEuVXBkYXRlU2VydmljZVJlcXVlc3QaIS5zZXJ2aWNlLnYxLlVwZGF0ZVNlcnZpY2VSZXNwb25zZRJUCg1EZWxldGVTZXJ2aWNlEiAuc2VydmljZS52MS5EZWxldGVTZXJ2aWNlUmVxdWVzdBohLnNlcnZpY2UudjEuRGVsZXRlU2VydmljZVJlc3BvbnNlEmAKEUFzc29jaWF0ZVVzZXJOb2RlEiQuc2VydmljZS52MS5Bc3NvY2lhdGVVc2VyTm9kZVJlcXVlc3QaJS5zZXJ2aWNlLnYxLkFzc29jaWF0ZVVzZXJOb2RlUmVzcG9uc2USVAoNTGlzdFVzZXJOb2RlcxIgLnNlcnZpY2UudjEuTGlzdFVzZXJOb2Rlc1JlcXVlc3QaIS5zZXJ2aWNlLnYxLkxpc3RVc2VyTm9kZXNSZXNwb25zZUIpWid1bmJsaW5rL3NlcnZlci9nZW4vc2VydmljZS92MTtzZXJ2aWNldjFiBnByb3RvMw", [file_google_protobuf_timestamp]);
+  fileDesc("ChhzZXJ2aWNlL3YxL3NlcnZpY2UucHJvdG8SCnNlcnZpY2UudjEi9AEKB1NlcnZpY2USCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRILCgN1cmwYAyABKAkSDwoHbm9kZV9pZBgEIAEoCRIuCgpjcmVhdGVkX2F0GAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAYgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIhChlyZWNvcmRpbmdfcmV0ZW50aW9uX2hvdXJzGAcgASgFEi4KCGFuYWx5c2lzGAggASgLMhwuc2VydmljZS52MS5BbmFseXNpc1NldHRpbmdzItEBChBBbmFseXNpc1NldHRpbmdzEh0KEGluZGV4aW5nX2VuYWJsZWQYASABKAhIAIgBARIeChZmcmFtZV9pbnRlcnZhbF9zZWNvbmRzGAIgASgBEhIKCmJhdGNoX3NpemUYAyABKAUSDQoFbW9kZWwYBCABKAkSEwoLaW5zdHJ1Y3Rpb24YBSABKAkSEAoIbGFuZ3VhZ2UYBiABKAkSHwoFem9uZXMYByADKAsyEC5zZXJ2aWNlLnYxLlpvbmVCEwoRX2luZGV4aW5nX2VuYWJsZWQiRAoEWm9uZRIMCgRuYW1lGAEgASgJEgoKAngxGAIgASgFEgoKAnkxGAMgASgFEgoKAngyGAQgASgFEgoKAnkyGAUgASgFIokDCgROb2RlEgoKAmlkGAEgASgJEhAKCGhvc3RuYW1lGAIgASgJEhQKDGRpc3BsYXlfbmFtZRgDIAEoCRIOCgZvbmxpbmUYBCABKAgSLQoJbGFzdF9zZWVuGAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIUCgxub2RlX3ZlcnNpb24YBiABKAkSGAoQcHJvdG9jb2xfdmVyc2lvbhgHIAEoBRIVCg1tYWNfYWRkcmVzc2VzGAggAygJEhUKDXNlcnZpY2VfY291bnQYCSABKAUSGwoTYWN0aXZlX2JyaWRnZV9jb3VudBgKIAEoBRIuCgpmaXJzdF9zZWVuGAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIzCg90b2tlbl9pc3N1ZWRfYXQYDCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEi4KCnJldm9rZWRfYXQYDSABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wIrcBChBEaXNjb3ZlcmVkRGV2aWNlEg8KB2FkZHJlc3MYASABKAkSDgoGc291cmNlGAIgASgJEgwKBG5hbWUYAyABKAkSFAoMbWFudWZhY3R1cmVyGAQgASgJEg0KBW1vZGVsGAUgASgJEhEKCW9udmlmX3VybBgGIAEoCRItCgdzdHJlYW1zGAcgAygLMhwuc2VydmljZS52MS5EaXNjb3ZlcmVkU3RyZWFtEg0KBWVycm9yGAggASgJImEKEERpc2NvdmVyZWRTdHJlYW0SDwoHcHJvZmlsZRgBIAEoCRILCgN1cmwYAiABKAkSEAoIZW5jb2RpbmcYAyABKAkSDQoFd2lkdGgYBCABKAUSDgoGaGVpZ2h0GAUgASgFIukDCg1TZXJ2aWNlU3RhdHVzEhIKCnNlcnZpY2VfaWQYASABKAkSDQoFc3RhdGUYAiABKAkSLwoLc3RhdGVfc2luY2UYAyABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEjEKDWxhc3RfZnJhbWVfYXQYBCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEjAKDGxhc3RfZGF0YV9hdBgFIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASEwoLcmV0cnlfY291bnQYBiABKAUSEwoLbWF4X3JldHJpZXMYByABKAUSMQoNbGFzdF9yZXRyeV9hdBgIIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASEgoKbGFzdF9lcnJvchgJIAEoCRIXCg9sYXN0X2Vycm9yX2NvZGUYCiABKAkSFQoNZXh0cmFjdG9yX2ZwcxgLIAEoARIQCghpbmRleGluZxgMIAEoCBIRCglyZWNvcmRpbmcYDSABKAgSHQoVYnJpZGdlX2J1ZmZlcmVkX2J5dGVzGA4gASgDEh4KFmJyaWRnZV9pbl9mbGlnaHRfYnl0ZXMYDyABKAMSGgoSYnJpZGdlX3NlbmRfY3JlZGl0GBAgASgDIk8KC1N0cmVhbUNvZGVjEgwKBGtpbmQYASABKAkSDAoEbmFtZRgCIAEoCRISCgpjbG9ja19yYXRlGAMgASgNEhAKCGNoYW5uZWxzGAQgASgNIkIKFENyZWF0ZVNlcnZpY2VSZXF1ZXN0EgwKBG5hbWUYASABKAkSCwoDdXJsGAIgASgJEg8KB25vZGVfaWQYAyABKAkiPQoVQ3JlYXRlU2VydmljZVJlc3BvbnNlEiQKB3NlcnZpY2UYASABKAsyEy5zZXJ2aWNlLnYxLlNlcnZpY2UiLgobTGlzdFNlcnZpY2VzQnlOb2RlSWRSZXF1ZXN0Eg8KB25vZGVfaWQYASABKAkiRQocTGlzdFNlcnZpY2VzQnlOb2RlSWRSZXNwb25zZRIlCghzZXJ2aWNlcxgBIAMoCzITLnNlcnZpY2UudjEuU2VydmljZSKzAQoUVXBkYXRlU2VydmljZVJlcXVlc3QSCgoCaWQYASABKAkSDAoEbmFtZRgCIAEoCRILCgN1cmwYAyABKAkSJgoZcmVjb3JkaW5nX3JldGVudGlvbl9ob3VycxgEIAEoBUgAiAEBEi4KCGFuYWx5c2lzGAUgASgLMhwuc2VydmljZS52MS5BbmFseXNpc1NldHRpbmdzQhwKGl9yZWNvcmRpbmdfcmV0ZW50aW9uX2hvdXJzIj0KFVVwZGF0ZVNlcnZpY2VSZXNwb25zZRIkCgdzZXJ2aWNlGAEgASgLMhMuc2VydmljZS52MS5TZXJ2aWNlIioKFERlbGV0ZVNlcnZpY2VSZXF1ZXN0EhIKCnNlcnZpY2VfaWQYASABKAkiKAoVRGVsZXRlU2VydmljZVJlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgiZgoWRGlzY292ZXJEZXZpY2VzUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEhAKCHVzZXJuYW1lGAIgASgJEhAKCHBhc3N3b3JkGAMgASgJEhcKD3RpbWVvdXRfc2Vjb25kcxgEIAEoBSJIChdEaXNjb3ZlckRldmljZXNSZXNwb25zZRItCgdkZXZpY2VzGAEgAygLMhwuc2VydmljZS52MS5EaXNjb3ZlcmVkRGV2aWNlIjUKFVRlc3RTZXJ2aWNlVVJMUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJEgsKA3VybBgCIAEoCSKRAgoWVGVzdFNlcnZpY2VVUkxSZXNwb25zZRIPCgdzdWNjZXNzGAEgASgIEhEKCXJlYWNoYWJsZRgCIAEoCBIRCglyZXNwb25kZWQYAyABKAgSEwoLYXV0aF9mYWlsZWQYBCABKAgSEwoLc291cmNlX3R5cGUYBSABKAkSJwoGY29kZWNzGAYgAygLMhcuc2VydmljZS52MS5TdHJlYW1Db2RlYxINCgV3aWR0aBgHIAEoBRIOCgZoZWlnaHQYCCABKAUSFAoMcHJldmlld19qcGVnGAkgASgMEhUKDXByZXZpZXdfZXJyb3IYCiABKAkSDQoFZXJyb3IYCyABKAkSEgoKZXJyb3JfY29kZRgMIAEoCSItChdHZXRTZXJ2aWNlU3RhdHVzUmVxdWVzdBISCgpzZXJ2aWNlX2lkGAEgASgJIkUKGEdldFNlcnZpY2VTdGF0dXNSZXNwb25zZRIpCgZzdGF0dXMYASABKAsyGS5zZXJ2aWNlLnYxLlNlcnZpY2VTdGF0dXMiLwoZV2F0Y2hTZXJ2aWNlU3RhdHVzUmVxdWVzdBISCgpzZXJ2aWNlX2lkGAEgASgJIkcKGldhdGNoU2VydmljZVN0YXR1c1Jlc3BvbnNlEikKBnN0YXR1cxgBIAEoCzIZLnNlcnZpY2UudjEuU2VydmljZVN0YXR1cyIrChhBc3NvY2lhdGVVc2VyTm9kZVJlcXVlc3QSDwoHbm9kZV9pZBgBIAEoCSIsChlBc3NvY2lhdGVVc2VyTm9kZVJlc3BvbnNlEg8KB3N1Y2Nlc3MYASABKAgiFgoUTGlzdFVzZXJOb2Rlc1JlcXVlc3QiSgoVTGlzdFVzZXJOb2Rlc1Jlc3BvbnNlEhAKCG5vZGVfaWRzGAEgAygJEh8KBW5vZGVzGAIgAygLMhAuc2VydmljZS52MS5Ob2RlIjoKEVVwZGF0ZU5vZGVSZXF1ZXN0Eg8KB25vZGVfaWQYASABKAkSFAoMZGlzcGxheV9uYW1lGAIgASgJIjQKElVwZGF0ZU5vZGVSZXNwb25zZRIeCgRub2RlGAEgASgLMhAuc2VydmljZS52MS5Ob2RlIikKFlJldm9rZU5vZGVUb2tlblJlcXVlc3QSDwoHbm9kZV9pZBgBIAEoCSJHChdSZXZva2VOb2RlVG9rZW5SZXNwb25zZRIWCg5yZXZva2VkX3Rva2VucxgBIAEoBRIUCgxkaXNjb25uZWN0ZWQYAiABKAgy2QgKDlNlcnZpY2VTZXJ2aWNlElQKDUNyZWF0ZVNlcnZpY2USIC5zZXJ2aWNlLnYxLkNyZWF0ZVNlcnZpY2VSZXF1ZXN0GiEuc2VydmljZS52MS5DcmVhdGVTZXJ2aWNlUmVzcG9uc2USaQoUTGlzdFNlcnZpY2VzQnlOb2RlSWQSJy5zZXJ2aWNlLnYxLkxpc3RTZXJ2aWNlc0J5Tm9kZUlkUmVxdWVzdBooLnNlcnZpY2UudjEuTGlzdFNlcnZpY2VzQnlOb2RlSWRSZXNwb25zZRJUCg1VcGRhdGVTZXJ2aWNlEiAuc2VydmljZS52MS5VcGRhdGVTZXJ2aWNlUmVxdWVzdBohLnNlcnZpY2UudjEuVXBkYXRlU2VydmljZVJlc3BvbnNlElQKDURlbGV0ZVNlcnZpY2USIC5zZXJ2aWNlLnYxLkRlbGV0ZVNlcnZpY2VSZXF1ZXN0GiEuc2VydmljZS52MS5EZWxldGVTZXJ2aWNlUmVzcG9uc2USWgoPRGlzY292ZXJEZXZpY2VzEiIuc2VydmljZS52MS5EaXNjb3ZlckRldmljZXNSZXF1ZXN0GiMuc2VydmljZS52MS5EaXNjb3ZlckRldmljZXNSZXNwb25zZRJXCg5UZXN0U2VydmljZVVSTBIhLnNlcnZpY2UudjEuVGVzdFNlcnZpY2VVUkxSZXF1ZXN0GiIuc2VydmljZS52MS5UZXN0U2VydmljZVVSTFJlc3BvbnNlEl0KEEdldFNlcnZpY2VTdGF0dXMSIy5zZXJ2aWNlLnYxLkdldFNlcnZpY2VTdGF0dXNSZXF1ZXN0GiQuc2VydmljZS52MS5HZXRTZXJ2aWNlU3RhdHVzUmVzcG9uc2USZQoSV2F0Y2hTZXJ2aWNlU3RhdHVzEiUuc2VydmljZS52MS5XYXRjaFNlcnZpY2VTdGF0dXNSZXF1ZXN0GiYuc2VydmljZS52MS5XYXRjaFNlcnZpY2VTdGF0dXNSZXNwb25zZTABEmAKEUFzc29jaWF0ZVVzZXJOb2RlEiQuc2VydmljZS52MS5Bc3NvY2lhdGVVc2VyTm9kZVJlcXVlc3QaJS5zZXJ2aWNlLnYxLkFzc29jaWF0ZVVzZXJOb2RlUmVzcG9uc2USVAoNTGlzdFVzZXJOb2RlcxIgLnNlcnZpY2UudjEuTGlzdFVzZXJOb2Rlc1JlcXVlc3QaIS5zZXJ2aWNlLnYxLkxpc3RVc2VyTm9kZXNSZXNwb25zZRJLCgpVcGRhdGVOb2RlEh0uc2VydmljZS52MS5VcGRhdGVOb2RlUmVxdWVzdBoeLnNlcnZpY2UudjEuVXBkYXRlTm9kZVJlc3BvbnNlEloKD1Jldm9rZU5vZGVUb2tlbhIiLnNlcnZpY2UudjEuUmV2b2tlTm9kZVRva2VuUmVxdWVzdBojLnNlcnZpY2UudjEuUmV2b2tlTm9kZVRva2VuUmVzcG9uc2VCKVondW5ibGluay9zZXJ2ZXIvZ2VuL3NlcnZpY2UvdjE7c2VydmljZXYxYgZwcm90bzM", [file_google_protobuf_timestamp]);
 
 /**
  * @generated from message service.v1.Service
@@ -47,14 +47,477 @@ export type Service = Message<"service.v1.Service"> & {
    * @generated from field: google.protobuf.Timestamp updated_at = 6;
    */
   updatedAt?: Timestamp;
+
+  /**
+   * How long recorded segments are kept (0 = server default)
+   *
+   * @generated from field: int32 recording_retention_hours = 7;
+   */
+  recordingRetentionHours: number;
+
+  /**
+   * @generated from field: service.v1.AnalysisSettings analysis = 8;
+   */
+  analysis?: AnalysisSettings;
+};
+
+/**
+ * Describes the message service.v1.Service.
+ * Use `create(ServiceSchema)` to create a new message.
+ */
+export const ServiceSchema: GenMessage<Service> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 0);
+
+/**
+ * How a service's frames are indexed. Zero values fall back to the server
+ * config; changes apply to a running service without reconnecting it.
+ *
+ * @generated from message service.v1.AnalysisSettings
+ */
+export type AnalysisSettings = Message<"service.v1.AnalysisSettings"> & {
+  /**
+   * Unset = server default (enable_indexing)
+   *
+   * @generated from field: optional bool indexing_enabled = 1;
+   */
+  indexingEnabled?: boolean;
+
+  /**
+   * Seconds between extracted frames (0.5-3600)
+   *
+   * @generated from field: double frame_interval_seconds = 2;
+   */
+  frameIntervalSeconds: number;
+
+  /**
+   * Frames per VLM request (1-32)
+   *
+   * @generated from field: int32 batch_size = 3;
+   */
+  batchSize: number;
+
+  /**
+   * VLM model
+   *
+   * @generated from field: string model = 4;
+   */
+  model: string;
+
+  /**
+   * Replaces the built-in analysis instruction
+   *
+   * @generated from field: string instruction = 5;
+   */
+  instruction: string;
+
+  /**
+   * Language of descriptions, e.g. "German" (empty = English); labels stay English
+   *
+   * @generated from field: string language = 6;
+   */
+  language: string;
+
+  /**
+   * Regions alert rules can refer to ("in zone driveway")
+   *
+   * @generated from field: repeated service.v1.Zone zones = 7;
+   */
+  zones: Zone[];
+};
+
+/**
+ * Describes the message service.v1.AnalysisSettings.
+ * Use `create(AnalysisSettingsSchema)` to create a new message.
+ */
+export const AnalysisSettingsSchema: GenMessage<AnalysisSettings> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 1);
+
+/**
+ * A named rectangle of the image in normalized 1000 coordinates (0 = top/left,
+ * 1000 = bottom/right), like the boxes of detected objects
+ *
+ * @generated from message service.v1.Zone
+ */
+export type Zone = Message<"service.v1.Zone"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * @generated from field: int32 x1 = 2;
+   */
+  x1: number;
+
+  /**
+   * @generated from field: int32 y1 = 3;
+   */
+  y1: number;
+
+  /**
+   * @generated from field: int32 x2 = 4;
+   */
+  x2: number;
+
+  /**
+   * @generated from field: int32 y2 = 5;
+   */
+  y2: number;
+};
+
+/**
+ * Describes the message service.v1.Zone.
+ * Use `create(ZoneSchema)` to create a new message.
+ */
+export const ZoneSchema: GenMessage<Zone> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 2);
+
+/**
+ * @generated from message service.v1.Node
+ */
+export type Node = Message<"service.v1.Node"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string hostname = 2;
+   */
+  hostname: string;
+
+  /**
+   * Set by users, empty = use hostname
+   *
+   * @generated from field: string display_name = 3;
+   */
+  displayName: string;
+
+  /**
+   * @generated from field: bool online = 4;
+   */
+  online: boolean;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp last_seen = 5;
+   */
+  lastSeen?: Timestamp;
+
+  /**
+   * Software version reported by the node
+   *
+   * @generated from field: string node_version = 6;
+   */
+  nodeVersion: string;
+
+  /**
+   * @generated from field: int32 protocol_version = 7;
+   */
+  protocolVersion: number;
+
+  /**
+   * @generated from field: repeated string mac_addresses = 8;
+   */
+  macAddresses: string[];
+
+  /**
+   * @generated from field: int32 service_count = 9;
+   */
+  serviceCount: number;
+
+  /**
+   * 0 while offline
+   *
+   * @generated from field: int32 active_bridge_count = 10;
+   */
+  activeBridgeCount: number;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp first_seen = 11;
+   */
+  firstSeen?: Timestamp;
+
+  /**
+   * Current token, unset if none is recorded
+   *
+   * @generated from field: google.protobuf.Timestamp token_issued_at = 12;
+   */
+  tokenIssuedAt?: Timestamp;
+
+  /**
+   * Set once the owner revoked the node
+   *
+   * @generated from field: google.protobuf.Timestamp revoked_at = 13;
+   */
+  revokedAt?: Timestamp;
+};
+
+/**
+ * Describes the message service.v1.Node.
+ * Use `create(NodeSchema)` to create a new message.
+ */
+export const NodeSchema: GenMessage<Node> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 3);
+
+/**
+ * A camera found on a node's LAN by DiscoverDevices
+ *
+ * @generated from message service.v1.DiscoveredDevice
+ */
+export type DiscoveredDevice = Message<"service.v1.DiscoveredDevice"> & {
+  /**
+   * IP address
+   *
+   * @generated from field: string address = 1;
+   */
+  address: string;
+
+  /**
+   * "ws-discovery" or "mdns"
+   *
+   * @generated from field: string source = 2;
+   */
+  source: string;
+
+  /**
+   * @generated from field: string name = 3;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string manufacturer = 4;
+   */
+  manufacturer: string;
+
+  /**
+   * @generated from field: string model = 5;
+   */
+  model: string;
+
+  /**
+   * ONVIF device service, empty for mDNS
+   *
+   * @generated from field: string onvif_url = 6;
+   */
+  onvifUrl: string;
+
+  /**
+   * @generated from field: repeated service.v1.DiscoveredStream streams = 7;
+   */
+  streams: DiscoveredStream[];
+
+  /**
+   * Why the streams could not be listed
+   *
+   * @generated from field: string error = 8;
+   */
+  error: string;
+};
+
+/**
+ * Describes the message service.v1.DiscoveredDevice.
+ * Use `create(DiscoveredDeviceSchema)` to create a new message.
+ */
+export const DiscoveredDeviceSchema: GenMessage<DiscoveredDevice> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 4);
+
+/**
+ * A stream of a discovered device, usable as CreateServiceRequest.url
+ *
+ * @generated from message service.v1.DiscoveredStream
+ */
+export type DiscoveredStream = Message<"service.v1.DiscoveredStream"> & {
+  /**
+   * ONVIF profile name
+   *
+   * @generated from field: string profile = 1;
+   */
+  profile: string;
+
+  /**
+   * Includes the credentials from the request
+   *
+   * @generated from field: string url = 2;
+   */
+  url: string;
+
+  /**
+   * @generated from field: string encoding = 3;
+   */
+  encoding: string;
+
+  /**
+   * @generated from field: int32 width = 4;
+   */
+  width: number;
+
+  /**
+   * @generated from field: int32 height = 5;
+   */
+  height: number;
+};
+
+/**
+ * Describes the message service.v1.DiscoveredStream.
+ * Use `create(DiscoveredStreamSchema)` to create a new message.
+ */
+export const DiscoveredStreamSchema: GenMessage<DiscoveredStream> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 5);
+
+/**
+ * Live health of a service. Transitions of state are also recorded as
+ * events with payload type "service-status".
+ *
+ * @generated from message service.v1.ServiceStatus
+ */
+export type ServiceStatus = Message<"service.v1.ServiceStatus"> & {
+  /**
+   * @generated from field: string service_id = 1;
+   */
+  serviceId: string;
+
+  /**
+   * "offline", "online", "reconnecting", "failed", "disabled" or "denied"
+   *
+   * @generated from field: string state = 2;
+   */
+  state: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp state_since = 3;
+   */
+  stateSince?: Timestamp;
+
+  /**
+   * Last frame extracted for indexing
+   *
+   * @generated from field: google.protobuf.Timestamp last_frame_at = 4;
+   */
+  lastFrameAt?: Timestamp;
+
+  /**
+   * Last data received from the camera
+   *
+   * @generated from field: google.protobuf.Timestamp last_data_at = 5;
+   */
+  lastDataAt?: Timestamp;
+
+  /**
+   * Reconnects since the stream was last stable
+   *
+   * @generated from field: int32 retry_count = 6;
+   */
+  retryCount: number;
+
+  /**
+   * @generated from field: int32 max_retries = 7;
+   */
+  maxRetries: number;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp last_retry_at = 8;
+   */
+  lastRetryAt?: Timestamp;
+
+  /**
+   * @generated from field: string last_error = 9;
+   */
+  lastError: string;
+
+  /**
+   * Node's refusal code (e.g. "target_denied")
+   *
+   * @generated from field: string last_error_code = 10;
+   */
+  lastErrorCode: string;
+
+  /**
+   * Frames extracted per second over the last minute
+   *
+   * @generated from field: double extractor_fps = 11;
+   */
+  extractorFps: number;
+
+  /**
+   * Frames are being extracted for indexing
+   *
+   * @generated from field: bool indexing = 12;
+   */
+  indexing: boolean;
+
+  /**
+   * Segments are being recorded
+   *
+   * @generated from field: bool recording = 13;
+   */
+  recording: boolean;
+
+  /**
+   * Received from the node but not yet read by the stream
+   *
+   * @generated from field: int64 bridge_buffered_bytes = 14;
+   */
+  bridgeBufferedBytes: bigint;
+
+  /**
+   * Sent to the node but not yet acknowledged
+   *
+   * @generated from field: int64 bridge_in_flight_bytes = 15;
+   */
+  bridgeInFlightBytes: bigint;
+
+  /**
+   * Bytes the server may still send to the node
+   *
+   * @generated from field: int64 bridge_send_credit = 16;
+   */
+  bridgeSendCredit: bigint;
+};
+
+/**
+ * Describes the message service.v1.ServiceStatus.
+ * Use `create(ServiceStatusSchema)` to create a new message.
+ */
+export const ServiceStatusSchema: GenMessage<ServiceStatus> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 6);
+
+/**
+ * A track offered by a tested service URL
+ *
+ * @generated from message service.v1.StreamCodec
+ */
+export type StreamCodec = Message<"service.v1.StreamCodec"> & {
+  /**
+   * "video" or "audio"
+   *
+   * @generated from field: string kind = 1;
+   */
+  kind: string;
+
+  /**
+   * e.g. "H264", "PCMA", "JPEG"
+   *
+   * @generated from field: string name = 2;
+   */
+  name: string;
+
+  /**
+   * @generated from field: uint32 clock_rate = 3;
+   */
+  clockRate: number;
+
+  /**
+   * @generated from field: uint32 channels = 4;
+   */
+  channels: number;
 };
 
 /**
- * Describes the message service.v1.Service.
- * Use `create(ServiceSchema)` to create a new message.
+ * Describes the message service.v1.StreamCodec.
+ * Use `create(StreamCodecSchema)` to create a new message.
  */
-export const ServiceSchema: GenMessage<Service> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 0);
+export const StreamCodecSchema: GenMessage<StreamCodec> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 7);
 
 /**
  * @generated from message service.v1.CreateServiceRequest
@@ -81,7 +544,7 @@ export type CreateServiceRequest = Message<"service.v1.CreateServiceRequest"> &
  * Use `create(CreateServiceRequestSchema)` to create a new message.
  */
 export const CreateServiceRequestSchema: GenMessage<CreateServiceRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 1);
+  messageDesc(file_service_v1_service, 8);
 
 /**
  * @generated from message service.v1.CreateServiceResponse
@@ -98,7 +561,7 @@ export type CreateServiceResponse = Message<"service.v1.CreateServiceResponse">
  * Use `create(CreateServiceResponseSchema)` to create a new message.
  */
 export const CreateServiceResponseSchema: GenMessage<CreateServiceResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 2);
+  messageDesc(file_service_v1_service, 9);
 
 /**
  * @generated from message service.v1.ListServicesByNodeIdRequest
@@ -115,7 +578,7 @@ export type ListServicesByNodeIdRequest = Message<"service.v1.ListServicesByNode
  * Use `create(ListServicesByNodeIdRequestSchema)` to create a new message.
  */
 export const ListServicesByNodeIdRequestSchema: GenMessage<ListServicesByNodeIdRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 3);
+  messageDesc(file_service_v1_service, 10);
 
 /**
  * @generated from message service.v1.ListServicesByNodeIdResponse
@@ -132,7 +595,7 @@ export type ListServicesByNodeIdResponse = Message<"service.v1.ListServicesByNod
  * Use `create(ListServicesByNodeIdResponseSchema)` to create a new message.
  */
 export const ListServicesByNodeIdResponseSchema: GenMessage<ListServicesByNodeIdResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 4);
+  messageDesc(file_service_v1_service, 11);
 
 /**
  * @generated from message service.v1.UpdateServiceRequest
@@ -152,6 +615,20 @@ export type UpdateServiceRequest = Message<"service.v1.UpdateServiceRequest"> &
    * @generated from field: string url = 3;
    */
   url: string;
+
+  /**
+   * Optional: 0 resets to server default
+   *
+   * @generated from field: optional int32 recording_retention_hours = 4;
+   */
+  recordingRetentionHours?: number;
+
+  /**
+   * Optional: replaces all analysis settings
+   *
+   * @generated from field: service.v1.AnalysisSettings analysis = 5;
+   */
+  analysis?: AnalysisSettings;
 };
 
 /**
@@ -159,7 +636,7 @@ export type UpdateServiceRequest = Message<"service.v1.UpdateServiceRequest"> &
  * Use `create(UpdateServiceRequestSchema)` to create a new message.
  */
 export const UpdateServiceRequestSchema: GenMessage<UpdateServiceRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 5);
+  messageDesc(file_service_v1_service, 12);
 
 /**
  * @generated from message service.v1.UpdateServiceResponse
@@ -176,7 +653,7 @@ export type UpdateServiceResponse = Message<"service.v1.UpdateServiceResponse">
  * Use `create(UpdateServiceResponseSchema)` to create a new message.
  */
 export const UpdateServiceResponseSchema: GenMessage<UpdateServiceResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 6);
+  messageDesc(file_service_v1_service, 13);
 
 /**
  * @generated from message service.v1.DeleteServiceRequest
@@ -193,7 +670,7 @@ export type DeleteServiceRequest = Message<"service.v1.DeleteServiceRequest"> &
  * Use `create(DeleteServiceRequestSchema)` to create a new message.
  */
 export const DeleteServiceRequestSchema: GenMessage<DeleteServiceRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 7);
+  messageDesc(file_service_v1_service, 14);
 
 /**
  * @generated from message service.v1.DeleteServiceResponse
@@ -210,7 +687,246 @@ export type DeleteServiceResponse = Message<"service.v1.DeleteServiceResponse">
  * Use `create(DeleteServiceResponseSchema)` to create a new message.
  */
 export const DeleteServiceResponseSchema: GenMessage<DeleteServiceResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 8);
+  messageDesc(file_service_v1_service, 15);
+
+/**
+ * @generated from message service.v1.DiscoverDevicesRequest
+ */
+export type DiscoverDevicesRequest = Message<"service.v1.DiscoverDevicesRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * ONVIF credentials, needed for stream URLs
+   *
+   * @generated from field: string username = 2;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string password = 3;
+   */
+  password: string;
+
+  /**
+   * How long the node listens for replies (1-10, default 3)
+   *
+   * @generated from field: int32 timeout_seconds = 4;
+   */
+  timeoutSeconds: number;
+};
+
+/**
+ * Describes the message service.v1.DiscoverDevicesRequest.
+ * Use `create(DiscoverDevicesRequestSchema)` to create a new message.
+ */
+export const DiscoverDevicesRequestSchema: GenMessage<DiscoverDevicesRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 16);
+
+/**
+ * @generated from message service.v1.DiscoverDevicesResponse
+ */
+export type DiscoverDevicesResponse = Message<"service.v1.DiscoverDevicesResponse"> & {
+  /**
+   * @generated from field: repeated service.v1.DiscoveredDevice devices = 1;
+   */
+  devices: DiscoveredDevice[];
+};
+
+/**
+ * Describes the message service.v1.DiscoverDevicesResponse.
+ * Use `create(DiscoverDevicesResponseSchema)` to create a new message.
+ */
+export const DiscoverDevicesResponseSchema: GenMessage<DiscoverDevicesResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 17);
+
+/**
+ * @generated from message service.v1.TestServiceURLRequest
+ */
+export type TestServiceURLRequest = Message<"service.v1.TestServiceURLRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * @generated from field: string url = 2;
+   */
+  url: string;
+};
+
+/**
+ * Describes the message service.v1.TestServiceURLRequest.
+ * Use `create(TestServiceURLRequestSchema)` to create a new message.
+ */
+export const TestServiceURLRequestSchema: GenMessage<TestServiceURLRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 18);
+
+/**
+ * Nothing is saved; the bridge is closed once the test ends
+ *
+ * @generated from message service.v1.TestServiceURLResponse
+ */
+export type TestServiceURLResponse = Message<"service.v1.TestServiceURLResponse"> & {
+  /**
+   * The URL can be used as a service
+   *
+   * @generated from field: bool success = 1;
+   */
+  success: boolean;
+
+  /**
+   * The node connected to the camera
+   *
+   * @generated from field: bool reachable = 2;
+   */
+  reachable: boolean;
+
+  /**
+   * The camera answered RTSP/HTTP
+   *
+   * @generated from field: bool responded = 3;
+   */
+  responded: boolean;
+
+  /**
+   * The camera rejected the credentials (or wants some)
+   *
+   * @generated from field: bool auth_failed = 4;
+   */
+  authFailed: boolean;
+
+  /**
+   * "rtsp" or "mjpeg"
+   *
+   * @generated from field: string source_type = 5;
+   */
+  sourceType: string;
+
+  /**
+   * @generated from field: repeated service.v1.StreamCodec codecs = 6;
+   */
+  codecs: StreamCodec[];
+
+  /**
+   * 0 if unknown
+   *
+   * @generated from field: int32 width = 7;
+   */
+  width: number;
+
+  /**
+   * @generated from field: int32 height = 8;
+   */
+  height: number;
+
+  /**
+   * One frame, empty if none could be captured
+   *
+   * @generated from field: bytes preview_jpeg = 9;
+   */
+  previewJpeg: Uint8Array;
+
+  /**
+   * Why there is no preview although the stream works
+   *
+   * @generated from field: string preview_error = 10;
+   */
+  previewError: string;
+
+  /**
+   * Why the URL cannot be used
+   *
+   * @generated from field: string error = 11;
+   */
+  error: string;
+
+  /**
+   * Node's refusal code (e.g. "target_denied", "unreachable")
+   *
+   * @generated from field: string error_code = 12;
+   */
+  errorCode: string;
+};
+
+/**
+ * Describes the message service.v1.TestServiceURLResponse.
+ * Use `create(TestServiceURLResponseSchema)` to create a new message.
+ */
+export const TestServiceURLResponseSchema: GenMessage<TestServiceURLResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 19);
+
+/**
+ * @generated from message service.v1.GetServiceStatusRequest
+ */
+export type GetServiceStatusRequest = Message<"service.v1.GetServiceStatusRequest"> & {
+  /**
+   * @generated from field: string service_id = 1;
+   */
+  serviceId: string;
+};
+
+/**
+ * Describes the message service.v1.GetServiceStatusRequest.
+ * Use `create(GetServiceStatusRequestSchema)` to create a new message.
+ */
+export const GetServiceStatusRequestSchema: GenMessage<GetServiceStatusRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 20);
+
+/**
+ * @generated from message service.v1.GetServiceStatusResponse
+ */
+export type GetServiceStatusResponse = Message<"service.v1.GetServiceStatusResponse"> & {
+  /**
+   * @generated from field: service.v1.ServiceStatus status = 1;
+   */
+  status?: ServiceStatus;
+};
+
+/**
+ * Describes the message service.v1.GetServiceStatusResponse.
+ * Use `create(GetServiceStatusResponseSchema)` to create a new message.
+ */
+export const GetServiceStatusResponseSchema: GenMessage<GetServiceStatusResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 21);
+
+/**
+ * @generated from message service.v1.WatchServiceStatusRequest
+ */
+export type WatchServiceStatusRequest = Message<"service.v1.WatchServiceStatusRequest"> & {
+  /**
+   * @generated from field: string service_id = 1;
+   */
+  serviceId: string;
+};
+
+/**
+ * Describes the message service.v1.WatchServiceStatusRequest.
+ * Use `create(WatchServiceStatusRequestSchema)` to create a new message.
+ */
+export const WatchServiceStatusRequestSchema: GenMessage<WatchServiceStatusRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 22);
+
+/**
+ * Sent right away, on every state change and every 5 seconds
+ *
+ * @generated from message service.v1.WatchServiceStatusResponse
+ */
+export type WatchServiceStatusResponse = Message<"service.v1.WatchServiceStatusResponse"> & {
+  /**
+   * @generated from field: service.v1.ServiceStatus status = 1;
+   */
+  status?: ServiceStatus;
+};
+
+/**
+ * Describes the message service.v1.WatchServiceStatusResponse.
+ * Use `create(WatchServiceStatusResponseSchema)` to create a new message.
+ */
+export const WatchServiceStatusResponseSchema: GenMessage<WatchServiceStatusResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 23);
 
 /**
  * @generated from message service.v1.AssociateUserNodeRequest
@@ -227,7 +943,7 @@ export type AssociateUserNodeRequest = Message<"service.v1.AssociateUserNodeRequ
  * Use `create(AssociateUserNodeRequestSchema)` to create a new message.
  */
 export const AssociateUserNodeRequestSchema: GenMessage<AssociateUserNodeRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 9);
+  messageDesc(file_service_v1_service, 24);
 
 /**
  * @generated from message service.v1.AssociateUserNodeResponse
@@ -244,7 +960,7 @@ export type AssociateUserNodeResponse = Message<"service.v1.AssociateUserNodeRes
  * Use `create(AssociateUserNodeResponseSchema)` to create a new message.
  */
 export const AssociateUserNodeResponseSchema: GenMessage<AssociateUserNodeResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 10);
+  messageDesc(file_service_v1_service, 25);
 
 /**
  * @generated from message service.v1.ListUserNodesRequest
@@ -257,7 +973,7 @@ export type ListUserNodesRequest = Message<"service.v1.ListUserNodesRequest"> &
  * Use `create(ListUserNodesRequestSchema)` to create a new message.
  */
 export const ListUserNodesRequestSchema: GenMessage<ListUserNodesRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 11);
+  messageDesc(file_service_v1_service, 26);
 
 /**
  * @generated from message service.v1.ListUserNodesResponse
@@ -267,6 +983,13 @@ export type ListUserNodesResponse = Message<"service.v1.ListUserNodesResponse">
    * @generated from field: repeated string node_ids = 1;
    */
   nodeIds: string[];
+
+  /**
+   * Same nodes as node_ids, with inventory and live state
+   *
+   * @generated from field: repeated service.v1.Node nodes = 2;
+   */
+  nodes: Node[];
 };
 
 /**
@@ -274,7 +997,87 @@ export type ListUserNodesResponse = Message<"service.v1.ListUserNodesResponse">
  * Use `create(ListUserNodesResponseSchema)` to create a new message.
  */
 export const ListUserNodesResponseSchema: GenMessage<ListUserNodesResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_service, 12);
+  messageDesc(file_service_v1_service, 27);
+
+/**
+ * @generated from message service.v1.UpdateNodeRequest
+ */
+export type UpdateNodeRequest = Message<"service.v1.UpdateNodeRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * @generated from field: string display_name = 2;
+   */
+  displayName: string;
+};
+
+/**
+ * Describes the message service.v1.UpdateNodeRequest.
+ * Use `create(UpdateNodeRequestSchema)` to create a new message.
+ */
+export const UpdateNodeRequestSchema: GenMessage<UpdateNodeRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 28);
+
+/**
+ * @generated from message service.v1.UpdateNodeResponse
+ */
+export type UpdateNodeResponse = Message<"service.v1.UpdateNodeResponse"> & {
+  /**
+   * @generated from field: service.v1.Node node = 1;
+   */
+  node?: Node;
+};
+
+/**
+ * Describes the message service.v1.UpdateNodeResponse.
+ * Use `create(UpdateNodeResponseSchema)` to create a new message.
+ */
+export const UpdateNodeResponseSchema: GenMessage<UpdateNodeResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 29);
+
+/**
+ * @generated from message service.v1.RevokeNodeTokenRequest
+ */
+export type RevokeNodeTokenRequest = Message<"service.v1.RevokeNodeTokenRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+};
+
+/**
+ * Describes the message service.v1.RevokeNodeTokenRequest.
+ * Use `create(RevokeNodeTokenRequestSchema)` to create a new message.
+ */
+export const RevokeNodeTokenRequestSchema: GenMessage<RevokeNodeTokenRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 30);
+
+/**
+ * @generated from message service.v1.RevokeNodeTokenResponse
+ */
+export type RevokeNodeTokenResponse = Message<"service.v1.RevokeNodeTokenResponse"> & {
+  /**
+   * @generated from field: int32 revoked_tokens = 1;
+   */
+  revokedTokens: number;
+
+  /**
+   * The node was online and has been disconnected
+   *
+   * @generated from field: bool disconnected = 2;
+   */
+  disconnected: boolean;
+};
+
+/**
+ * Describes the message service.v1.RevokeNodeTokenResponse.
+ * Use `create(RevokeNodeTokenResponseSchema)` to create a new message.
+ */
+export const RevokeNodeTokenResponseSchema: GenMessage<RevokeNodeTokenResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_service, 31);
 
 /**
  * @generated from service service.v1.ServiceService
@@ -314,6 +1117,40 @@ export const ServiceService: GenService<{
     input: typeof DeleteServiceRequestSchema;
     output: typeof DeleteServiceResponseSchema;
   },
+  /**
+   * @generated from rpc service.v1.ServiceService.DiscoverDevices
+   */
+  discoverDevices: {
+    methodKind: "unary";
+    input: typeof DiscoverDevicesRequestSchema;
+    output: typeof DiscoverDevicesResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.ServiceService.TestServiceURL
+   */
+  testServiceURL: {
+    methodKind: "unary";
+    input: typeof TestServiceURLRequestSchema;
+    output: typeof TestServiceURLResponseSchema;
+  },
+  /**
+   * Service health
+   *
+   * @generated from rpc service.v1.ServiceService.GetServiceStatus
+   */
+  getServiceStatus: {
+    methodKind: "unary";
+    input: typeof GetServiceStatusRequestSchema;
+    output: typeof GetServiceStatusResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.ServiceService.WatchServiceStatus
+   */
+  watchServiceStatus: {
+    methodKind: "server_streaming";
+    input: typeof WatchServiceStatusRequestSchema;
+    output: typeof WatchServiceStatusResponseSchema;
+  },
   /**
    * Node access management
    *
@@ -332,6 +1169,22 @@ export const ServiceService: GenService<{
     input: typeof ListUserNodesRequestSchema;
     output: typeof ListUserNodesResponseSchema;
   },
+  /**
+   * @generated from rpc service.v1.ServiceService.UpdateNode
+   */
+  updateNode: {
+    methodKind: "unary";
+    input: typeof UpdateNodeRequestSchema;
+    output: typeof UpdateNodeResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.ServiceService.RevokeNodeToken
+   */
+  revokeNodeToken: {
+    methodKind: "unary";
+    input: typeof RevokeNodeTokenRequestSchema;
+    output: typeof RevokeNodeTokenResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_service_v1_service, 0);
 
@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file service/v1/storage.proto.
  */
 export const file_service_v1_storage: GenFile = /*@__PURE__*/
-  fileDesc("ChhzZXJ2aWNlL3YxL3N0b3JhZ2UucHJvdG8SCnNlcnZpY2UudjEieAoLU3RvcmFnZUl0ZW0SCgoCaWQYASABKAkSEgoKc2VydmljZV9pZBgCIAEoCRIMCgR0eXBlGAMgASgJEgwKBHNpemUYBCABKAMSLQoJdGltZXN0YW1wGAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcCJaChdMaXN0U3RvcmFnZUl0ZW1zUmVxdWVzdBISCgpzZXJ2aWNlX2lkGAEgASgJEgwKBHR5cGUYAiABKAkSDQoFbGltaXQYAyABKAMSDgoGb2Zmc2V0GAQgASgDIlEKGExpc3RTdG9yYWdlSXRlbXNSZXNwb25zZRImCgVpdGVtcxgBIAMoCzIXLnNlcnZpY2UudjEuU3RvcmFnZUl0ZW0SDQoFdG90YWwYAiABKAMiKAoVR2V0U3RvcmFnZUl0ZW1SZXF1ZXN0Eg8KB2l0ZW1faWQYASABKAkiPwoWR2V0U3RvcmFnZUl0ZW1SZXNwb25zZRIlCgRpdGVtGAEgASgLMhcuc2VydmljZS52MS5TdG9yYWdlSXRlbSJcChxEZWxldGVPbGRTdG9yYWdlSXRlbXNSZXF1ZXN0EhIKCnNlcnZpY2VfaWQYASABKAkSGgoSb2xkZXJfdGhhbl9zZWNvbmRzGAIgASgDEgwKBHR5cGUYAyABKAkiNgodRGVsZXRlT2xkU3RvcmFnZUl0ZW1zUmVzcG9uc2USFQoNZGVsZXRlZF9jb3VudBgBIAEoAzK2AgoOU3RvcmFnZVNlcnZpY2USXQoQTGlzdFN0b3JhZ2VJdGVtcxIjLnNlcnZpY2UudjEuTGlzdFN0b3JhZ2VJdGVtc1JlcXVlc3QaJC5zZXJ2aWNlLnYxLkxpc3RTdG9yYWdlSXRlbXNSZXNwb25zZRJXCg5HZXRTdG9yYWdlSXRlbRIhLnNlcnZpY2UudjEuR2V0U3RvcmFnZUl0ZW1SZXF1ZXN0GiIuc2VydmljZS52MS5HZXRTdG9yYWdlSXRlbVJlc3BvbnNlEmwKFURlbGV0ZU9sZFN0b3JhZ2VJdGVtcxIoLnNlcnZpY2UudjEuRGVsZXRlT2xkU3RvcmFnZUl0ZW1zUmVxdWVzdBopLnNlcnZpY2UudjEuRGVsZXRlT2xkU3RvcmFnZUl0ZW1zUmVzcG9uc2VCKVondW5ibGluay9zZXJ2ZXIvZ2VuL3NlcnZpY2UvdjE7c2VydmljZXYxYgZwcm90bzM", [file_google_protobuf_timestamp]);
+  fileDesc("ChhzZXJ2aWNlL3YxL3N0b3JhZ2UucHJvdG8SCnNlcnZpY2UudjEieAoLU3RvcmFnZUl0ZW0SCgoCaWQYASABKAkSEgoKc2VydmljZV9pZBgCIAEoCRIMCgR0eXBlGAMgASgJEgwKBHNpemUYBCABKAMSLQoJdGltZXN0YW1wGAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcCKsAQoXTGlzdFN0b3JhZ2VJdGVtc1JlcXVlc3QSEgoKc2VydmljZV9pZBgBIAEoCRIMCgR0eXBlGAIgASgJEg0KBWxpbWl0GAMgASgDEg4KBm9mZnNldBgEIAEoAxIoCgRmcm9tGAUgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBImCgJ0bxgGIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXAiUQoYTGlzdFN0b3JhZ2VJdGVtc1Jlc3BvbnNlEiYKBWl0ZW1zGAEgAygLMhcuc2VydmljZS52MS5TdG9yYWdlSXRlbRINCgV0b3RhbBgCIAEoAyIoChVHZXRTdG9yYWdlSXRlbVJlcXVlc3QSDwoHaXRlbV9pZBgBIAEoCSI/ChZHZXRTdG9yYWdlSXRlbVJlc3BvbnNlEiUKBGl0ZW0YASABKAsyFy5zZXJ2aWNlLnYxLlN0b3JhZ2VJdGVtIkAKGEdldFN0b3JhZ2VJdGVtVVJMUmVxdWVzdBIPCgdpdGVtX2lkGAEgASgJEhMKC3R0bF9zZWNvbmRzGAIgASgDIlgKGUdldFN0b3JhZ2VJdGVtVVJMUmVzcG9uc2USCwoDdXJsGAEgASgJEi4KCmV4cGlyZXNfYXQYAiABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wIlwKHERlbGV0ZU9sZFN0b3JhZ2VJdGVtc1JlcXVlc3QSEgoKc2VydmljZV9pZBgBIAEoCRIaChJvbGRlcl90aGFuX3NlY29uZHMYAiABKAMSDAoEdHlwZRgDIAEoCSI2Ch1EZWxldGVPbGRTdG9yYWdlSXRlbXNSZXNwb25zZRIVCg1kZWxldGVkX2NvdW50GAEgASgDIvUBChFFeHBvcnRDbGlwUmVxdWVzdBIQCghldmVudF9pZBgBIAEoCRISCgpzZXJ2aWNlX2lkGAIgASgJEigKBGZyb20YAyABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEiYKAnRvGAQgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIdChBwcmVfcm9sbF9zZWNvbmRzGAUgASgFSACIAQESHgoRcG9zdF9yb2xsX3NlY29uZHMYBiABKAVIAYgBAUITChFfcHJlX3JvbGxfc2Vjb25kc0IUChJfcG9zdF9yb2xsX3NlY29uZHMiOwoSRXhwb3J0Q2xpcFJlc3BvbnNlEiUKBGl0ZW0YASABKAsyFy5zZXJ2aWNlLnYxLlN0b3JhZ2VJdGVtIqQBChZSZWFuYWx5emVGcmFtZXNSZXF1ZXN0EhIKCnNlcnZpY2VfaWQYASABKAkSKAoEZnJvbRgCIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASJgoCdG8YAyABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEhAKCHF1ZXN0aW9uGAQgASgJEhIKCm1heF9mcmFtZXMYBSABKAUiZQoNRnJhbWVDaXRhdGlvbhIQCghmcmFtZV9pZBgBIAEoCRItCgl0aW1lc3RhbXAYAiABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEhMKC29ic2VydmF0aW9uGAMgASgJIokBChdSZWFuYWx5emVGcmFtZXNSZXNwb25zZRIOCgZhbnN3ZXIYASABKAkSLAoJY2l0YXRpb25zGAIgAygLMhkuc2VydmljZS52MS5GcmFtZUNpdGF0aW9uEhcKD2ZyYW1lc19hbmFseXplZBgDIAEoBRIXCg9mcmFtZXNfaW5fcmFuZ2UYBCABKAUywQQKDlN0b3JhZ2VTZXJ2aWNlEl0KEExpc3RTdG9yYWdlSXRlbXMSIy5zZXJ2aWNlLnYxLkxpc3RTdG9yYWdlSXRlbXNSZXF1ZXN0GiQuc2VydmljZS52MS5MaXN0U3RvcmFnZUl0ZW1zUmVzcG9uc2USVwoOR2V0U3RvcmFnZUl0ZW0SIS5zZXJ2aWNlLnYxLkdldFN0b3JhZ2VJdGVtUmVxdWVzdBoiLnNlcnZpY2UudjEuR2V0U3RvcmFnZUl0ZW1SZXNwb25zZRJgChFHZXRTdG9yYWdlSXRlbVVSTBIkLnNlcnZpY2UudjEuR2V0U3RvcmFnZUl0ZW1VUkxSZXF1ZXN0GiUuc2VydmljZS52MS5HZXRTdG9yYWdlSXRlbVVSTFJlc3BvbnNlEmwKFURlbGV0ZU9sZFN0b3JhZ2VJdGVtcxIoLnNlcnZpY2UudjEuRGVsZXRlT2xkU3RvcmFnZUl0ZW1zUmVxdWVzdBopLnNlcnZpY2UudjEuRGVsZXRlT2xkU3RvcmFnZUl0ZW1zUmVzcG9uc2USSwoKRXhwb3J0Q2xpcBIdLnNlcnZpY2UudjEuRXhwb3J0Q2xpcFJlcXVlc3QaHi5zZXJ2aWNlLnYxLkV4cG9ydENsaXBSZXNwb25zZRJaCg9SZWFuYWx5emVGcmFtZXMSIi5zZXJ2aWNlLnYxLlJlYW5hbHl6ZUZyYW1lc1JlcXVlc3QaIy5zZXJ2aWNlLnYxLlJlYW5hbHl6ZUZyYW1lc1Jlc3BvbnNlQilaJ3VuYmxpbmsvc2VydmVyL2dlbi9zZXJ2aWNlL3YxO3NlcnZpY2V2MWIGcHJvdG8z", [file_google_protobuf_timestamp]);
 
 /**
  * StorageItem metadata
@@ -31,7 +31,7 @@ export type StorageItem = Message<"service.v1.StorageItem"> & {
   serviceId: string;
 
   /**
-   * Type of storage item (e.g., "frame", "clip")
+   * Type of storage item ("frame", "snapshot", "segment" or "clip")
    *
    * @generated from field: string type = 3;
    */
@@ -86,6 +86,20 @@ export type ListStorageItemsRequest = Message<"service.v1.ListStorageItemsReques
    * @generated from field: int64 offset = 4;
    */
   offset: bigint;
+
+  /**
+   * Optional: only items at or after this time
+   *
+   * @generated from field: google.protobuf.Timestamp from = 5;
+   */
+  from?: Timestamp;
+
+  /**
+   * Optional: only items before this time
+   *
+   * @generated from field: google.protobuf.Timestamp to = 6;
+   */
+  to?: Timestamp;
 };
 
 /**
@@ -153,6 +167,56 @@ export type GetStorageItemResponse = Message<"service.v1.GetStorageItemResponse"
 export const GetStorageItemResponseSchema: GenMessage<GetStorageItemResponse> = /*@__PURE__*/
   messageDesc(file_service_v1_storage, 4);
 
+/**
+ * Get a signed URL for a storage item
+ *
+ * @generated from message service.v1.GetStorageItemURLRequest
+ */
+export type GetStorageItemURLRequest = Message<"service.v1.GetStorageItemURLRequest"> & {
+  /**
+   * @generated from field: string item_id = 1;
+   */
+  itemId: string;
+
+  /**
+   * URL lifetime (default 300, max 3600)
+   *
+   * @generated from field: int64 ttl_seconds = 2;
+   */
+  ttlSeconds: bigint;
+};
+
+/**
+ * Describes the message service.v1.GetStorageItemURLRequest.
+ * Use `create(GetStorageItemURLRequestSchema)` to create a new message.
+ */
+export const GetStorageItemURLRequestSchema: GenMessage<GetStorageItemURLRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 5);
+
+/**
+ * @generated from message service.v1.GetStorageItemURLResponse
+ */
+export type GetStorageItemURLResponse = Message<"service.v1.GetStorageItemURLResponse"> & {
+  /**
+   * Relative URL: /storage/{id}?expires=...&sig=...
+   *
+   * @generated from field: string url = 1;
+   */
+  url: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp expires_at = 2;
+   */
+  expiresAt?: Timestamp;
+};
+
+/**
+ * Describes the message service.v1.GetStorageItemURLResponse.
+ * Use `create(GetStorageItemURLResponseSchema)` to create a new message.
+ */
+export const GetStorageItemURLResponseSchema: GenMessage<GetStorageItemURLResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 6);
+
 /**
  * Delete old storage items
  *
@@ -184,7 +248,7 @@ export type DeleteOldStorageItemsRequest = Message<"service.v1.DeleteOldStorageI
  * Use `create(DeleteOldStorageItemsRequestSchema)` to create a new message.
  */
 export const DeleteOldStorageItemsRequestSchema: GenMessage<DeleteOldStorageItemsRequest> = /*@__PURE__*/
-  messageDesc(file_service_v1_storage, 5);
+  messageDesc(file_service_v1_storage, 7);
 
 /**
  * @generated from message service.v1.DeleteOldStorageItemsResponse
@@ -201,7 +265,193 @@ export type DeleteOldStorageItemsResponse = Message<"service.v1.DeleteOldStorage
  * Use `create(DeleteOldStorageItemsResponseSchema)` to create a new message.
  */
 export const DeleteOldStorageItemsResponseSchema: GenMessage<DeleteOldStorageItemsResponse> = /*@__PURE__*/
-  messageDesc(file_service_v1_storage, 6);
+  messageDesc(file_service_v1_storage, 8);
+
+/**
+ * Export a clip from recorded segments
+ * Either event_id, or service_id with from/to, must be set
+ *
+ * @generated from message service.v1.ExportClipRequest
+ */
+export type ExportClipRequest = Message<"service.v1.ExportClipRequest"> & {
+  /**
+   * Use the event's service and from_iso/to_iso window
+   *
+   * @generated from field: string event_id = 1;
+   */
+  eventId: string;
+
+  /**
+   * Service to export from (when no event_id)
+   *
+   * @generated from field: string service_id = 2;
+   */
+  serviceId: string;
+
+  /**
+   * Start of the clip (when no event_id)
+   *
+   * @generated from field: google.protobuf.Timestamp from = 3;
+   */
+  from?: Timestamp;
+
+  /**
+   * End of the clip (when no event_id)
+   *
+   * @generated from field: google.protobuf.Timestamp to = 4;
+   */
+  to?: Timestamp;
+
+  /**
+   * Extra seconds before the start (default from server config)
+   *
+   * @generated from field: optional int32 pre_roll_seconds = 5;
+   */
+  preRollSeconds?: number;
+
+  /**
+   * Extra seconds after the end (default from server config)
+   *
+   * @generated from field: optional int32 post_roll_seconds = 6;
+   */
+  postRollSeconds?: number;
+};
+
+/**
+ * Describes the message service.v1.ExportClipRequest.
+ * Use `create(ExportClipRequestSchema)` to create a new message.
+ */
+export const ExportClipRequestSchema: GenMessage<ExportClipRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 9);
+
+/**
+ * @generated from message service.v1.ExportClipResponse
+ */
+export type ExportClipResponse = Message<"service.v1.ExportClipResponse"> & {
+  /**
+   * The new "clip" storage item, served at /storage/{id}
+   *
+   * @generated from field: service.v1.StorageItem item = 1;
+   */
+  item?: StorageItem;
+};
+
+/**
+ * Describes the message service.v1.ExportClipResponse.
+ * Use `create(ExportClipResponseSchema)` to create a new message.
+ */
+export const ExportClipResponseSchema: GenMessage<ExportClipResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 10);
+
+/**
+ * Re-analyze stored frames with a new question
+ *
+ * @generated from message service.v1.ReanalyzeFramesRequest
+ */
+export type ReanalyzeFramesRequest = Message<"service.v1.ReanalyzeFramesRequest"> & {
+  /**
+   * @generated from field: string service_id = 1;
+   */
+  serviceId: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp from = 2;
+   */
+  from?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp to = 3;
+   */
+  to?: Timestamp;
+
+  /**
+   * @generated from field: string question = 4;
+   */
+  question: string;
+
+  /**
+   * Frames sampled evenly from the range (default 48, max 120)
+   *
+   * @generated from field: int32 max_frames = 5;
+   */
+  maxFrames: number;
+};
+
+/**
+ * Describes the message service.v1.ReanalyzeFramesRequest.
+ * Use `create(ReanalyzeFramesRequestSchema)` to create a new message.
+ */
+export const ReanalyzeFramesRequestSchema: GenMessage<ReanalyzeFramesRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 11);
+
+/**
+ * A frame the answer is based on
+ *
+ * @generated from message service.v1.FrameCitation
+ */
+export type FrameCitation = Message<"service.v1.FrameCitation"> & {
+  /**
+   * Storage item ID, served at /storage/{id}
+   *
+   * @generated from field: string frame_id = 1;
+   */
+  frameId: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp timestamp = 2;
+   */
+  timestamp?: Timestamp;
+
+  /**
+   * What the frame shows that bears on the question
+   *
+   * @generated from field: string observation = 3;
+   */
+  observation: string;
+};
+
+/**
+ * Describes the message service.v1.FrameCitation.
+ * Use `create(FrameCitationSchema)` to create a new message.
+ */
+export const FrameCitationSchema: GenMessage<FrameCitation> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 12);
+
+/**
+ * @generated from message service.v1.ReanalyzeFramesResponse
+ */
+export type ReanalyzeFramesResponse = Message<"service.v1.ReanalyzeFramesResponse"> & {
+  /**
+   * @generated from field: string answer = 1;
+   */
+  answer: string;
+
+  /**
+   * Oldest first
+   *
+   * @generated from field: repeated service.v1.FrameCitation citations = 2;
+   */
+  citations: FrameCitation[];
+
+  /**
+   * @generated from field: int32 frames_analyzed = 3;
+   */
+  framesAnalyzed: number;
+
+  /**
+   * Stored frames in the range (more than analyzed when sampled)
+   *
+   * @generated from field: int32 frames_in_range = 4;
+   */
+  framesInRange: number;
+};
+
+/**
+ * Describes the message service.v1.ReanalyzeFramesResponse.
+ * Use `create(ReanalyzeFramesResponseSchema)` to create a new message.
+ */
+export const ReanalyzeFramesResponseSchema: GenMessage<ReanalyzeFramesResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_storage, 13);
 
 /**
  * StorageService provides access to stored items (frames, clips, etc.)
@@ -229,6 +479,16 @@ export const StorageService: GenService<{
     input: typeof GetStorageItemRequestSchema;
     output: typeof GetStorageItemResponseSchema;
   },
+  /**
+   * Get a short-lived signed URL for fetching a storage item over HTTP
+   *
+   * @generated from rpc service.v1.StorageService.GetStorageItemURL
+   */
+  getStorageItemURL: {
+    methodKind: "unary";
+    input: typeof GetStorageItemURLRequestSchema;
+    output: typeof GetStorageItemURLResponseSchema;
+  },
   /**
    * Delete old storage items (by time)
    *
@@ -239,6 +499,26 @@ export const StorageService: GenService<{
     input: typeof DeleteOldStorageItemsRequestSchema;
     output: typeof DeleteOldStorageItemsResponseSchema;
   },
+  /**
+   * Export a clip from recorded segments (by event or by time range)
+   *
+   * @generated from rpc service.v1.StorageService.ExportClip
+   */
+  exportClip: {
+    methodKind: "unary";
+    input: typeof ExportClipRequestSchema;
+    output: typeof ExportClipResponseSchema;
+  },
+  /**
+   * Ask a new question about the stored frames of a time range
+   *
+   * @generated from rpc service.v1.StorageService.ReanalyzeFrames
+   */
+  reanalyzeFrames: {
+    methodKind: "unary";
+    input: typeof ReanalyzeFramesRequestSchema;
+    output: typeof ReanalyzeFramesResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_service_v1_storage, 0);
 
@@ -0,0 +1,490 @@
+// @generated by protoc-gen-es v2.10.2 with parameter "target=ts"
+// @generated from file service/v1/webhook.proto (package service.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Timestamp } from "@bufbuild/protobuf/wkt";
+import { file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file service/v1/webhook.proto.
+ */
+export const file_service_v1_webhook: GenFile = /*@__PURE__*/
+  fileDesc("ChhzZXJ2aWNlL3YxL3dlYmhvb2sucHJvdG8SCnNlcnZpY2UudjEi3QEKB1dlYmhvb2sSCgoCaWQYASABKAkSDwoHbm9kZV9pZBgCIAEoCRISCgpzZXJ2aWNlX2lkGAMgASgJEgsKA3VybBgEIAEoCRITCgtldmVudF90eXBlcxgFIAMoCRIPCgdlbmFibGVkGAYgASgIEg4KBnNlY3JldBgHIAEoCRIuCgpjcmVhdGVkX2F0GAggASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAkgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcCLzAgoPV2ViaG9va0RlbGl2ZXJ5EgoKAmlkGAEgASgJEhIKCndlYmhvb2tfaWQYAiABKAkSEAoIZXZlbnRfaWQYAyABKAkSEgoKZXZlbnRfdHlwZRgEIAEoCRIOCgZzdGF0dXMYBSABKAkSEAoIYXR0ZW1wdHMYBiABKAUSGAoQbGFzdF9zdGF0dXNfY29kZRgHIAEoBRISCgpsYXN0X2Vycm9yGAggASgJEi4KCmNyZWF0ZWRfYXQYCSABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEjMKD25leHRfYXR0ZW1wdF9hdBgKIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASMwoPbGFzdF9hdHRlbXB0X2F0GAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIwCgxkZWxpdmVyZWRfYXQYDCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wIl0KFENyZWF0ZVdlYmhvb2tSZXF1ZXN0Eg8KB25vZGVfaWQYASABKAkSEgoKc2VydmljZV9pZBgCIAEoCRILCgN1cmwYAyABKAkSEwoLZXZlbnRfdHlwZXMYBCADKAkiPQoVQ3JlYXRlV2ViaG9va1Jlc3BvbnNlEiQKB3dlYmhvb2sYASABKAsyEy5zZXJ2aWNlLnYxLldlYmhvb2siJgoTTGlzdFdlYmhvb2tzUmVxdWVzdBIPCgdub2RlX2lkGAEgASgJIj0KFExpc3RXZWJob29rc1Jlc3BvbnNlEiUKCHdlYmhvb2tzGAEgAygLMhMuc2VydmljZS52MS5XZWJob29rIlUKFFVwZGF0ZVdlYmhvb2tSZXF1ZXN0EgoKAmlkGAEgASgJEgsKA3VybBgCIAEoCRITCgtldmVudF90eXBlcxgDIAMoCRIPCgdlbmFibGVkGAQgASgIIj0KFVVwZGF0ZVdlYmhvb2tSZXNwb25zZRIkCgd3ZWJob29rGAEgASgLMhMuc2VydmljZS52MS5XZWJob29rIiIKFERlbGV0ZVdlYmhvb2tSZXF1ZXN0EgoKAmlkGAEgASgJIigKFURlbGV0ZVdlYmhvb2tSZXNwb25zZRIPCgdzdWNjZXNzGAEgASgIImoKHExpc3RXZWJob29rRGVsaXZlcmllc1JlcXVlc3QSEgoKd2ViaG9va19pZBgBIAEoCRIOCgZzdGF0dXMYAiABKAkSEQoJcGFnZV9zaXplGAMgASgFEhMKC3BhZ2Vfb2Zmc2V0GAQgASgFImUKHUxpc3RXZWJob29rRGVsaXZlcmllc1Jlc3BvbnNlEi8KCmRlbGl2ZXJpZXMYASADKAsyGy5zZXJ2aWNlLnYxLldlYmhvb2tEZWxpdmVyeRITCgt0b3RhbF9jb3VudBgCIAEoBSIpChtSZXRyeVdlYmhvb2tEZWxpdmVyeVJlcXVlc3QSCgoCaWQYASABKAkiTQocUmV0cnlXZWJob29rRGVsaXZlcnlSZXNwb25zZRItCghkZWxpdmVyeRgBIAEoCzIbLnNlcnZpY2UudjEuV2ViaG9va0RlbGl2ZXJ5Mr4ECg5XZWJob29rU2VydmljZRJUCg1DcmVhdGVXZWJob29rEiAuc2VydmljZS52MS5DcmVhdGVXZWJob29rUmVxdWVzdBohLnNlcnZpY2UudjEuQ3JlYXRlV2ViaG9va1Jlc3BvbnNlElEKDExpc3RXZWJob29rcxIfLnNlcnZpY2UudjEuTGlzdFdlYmhvb2tzUmVxdWVzdBogLnNlcnZpY2UudjEuTGlzdFdlYmhvb2tzUmVzcG9uc2USVAoNVXBkYXRlV2ViaG9vaxIgLnNlcnZpY2UudjEuVXBkYXRlV2ViaG9va1JlcXVlc3QaIS5zZXJ2aWNlLnYxLlVwZGF0ZVdlYmhvb2tSZXNwb25zZRJUCg1EZWxldGVXZWJob29rEiAuc2VydmljZS52MS5EZWxldGVXZWJob29rUmVxdWVzdBohLnNlcnZpY2UudjEuRGVsZXRlV2ViaG9va1Jlc3BvbnNlEmwKFUxpc3RXZWJob29rRGVsaXZlcmllcxIoLnNlcnZpY2UudjEuTGlzdFdlYmhvb2tEZWxpdmVyaWVzUmVxdWVzdBopLnNlcnZpY2UudjEuTGlzdFdlYmhvb2tEZWxpdmVyaWVzUmVzcG9uc2USaQoUUmV0cnlXZWJob29rRGVsaXZlcnkSJy5zZXJ2aWNlLnYxLlJldHJ5V2ViaG9va0RlbGl2ZXJ5UmVxdWVzdBooLnNlcnZpY2UudjEuUmV0cnlXZWJob29rRGVsaXZlcnlSZXNwb25zZUIpWid1bmJsaW5rL3NlcnZlci9nZW4vc2VydmljZS92MTtzZXJ2aWNldjFiBnByb3RvMw", [file_google_protobuf_timestamp]);
+
+/**
+ * @generated from message service.v1.Webhook
+ */
+export type Webhook = Message<"service.v1.Webhook"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+
+  /**
+   * Empty = every service of the node
+   *
+   * @generated from field: string service_id = 3;
+   */
+  serviceId: string;
+
+  /**
+   * @generated from field: string url = 4;
+   */
+  url: string;
+
+  /**
+   * Payload types, e.g. "alert"; empty = all
+   *
+   * @generated from field: repeated string event_types = 5;
+   */
+  eventTypes: string[];
+
+  /**
+   * @generated from field: bool enabled = 6;
+   */
+  enabled: boolean;
+
+  /**
+   * Signing secret; only set in CreateWebhookResponse
+   *
+   * @generated from field: string secret = 7;
+   */
+  secret: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 8;
+   */
+  createdAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp updated_at = 9;
+   */
+  updatedAt?: Timestamp;
+};
+
+/**
+ * Describes the message service.v1.Webhook.
+ * Use `create(WebhookSchema)` to create a new message.
+ */
+export const WebhookSchema: GenMessage<Webhook> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 0);
+
+/**
+ * @generated from message service.v1.WebhookDelivery
+ */
+export type WebhookDelivery = Message<"service.v1.WebhookDelivery"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string webhook_id = 2;
+   */
+  webhookId: string;
+
+  /**
+   * @generated from field: string event_id = 3;
+   */
+  eventId: string;
+
+  /**
+   * @generated from field: string event_type = 4;
+   */
+  eventType: string;
+
+  /**
+   * "pending", "delivered" or "dead"
+   *
+   * @generated from field: string status = 5;
+   */
+  status: string;
+
+  /**
+   * @generated from field: int32 attempts = 6;
+   */
+  attempts: number;
+
+  /**
+   * 0 = no HTTP response
+   *
+   * @generated from field: int32 last_status_code = 7;
+   */
+  lastStatusCode: number;
+
+  /**
+   * @generated from field: string last_error = 8;
+   */
+  lastError: string;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp created_at = 9;
+   */
+  createdAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp next_attempt_at = 10;
+   */
+  nextAttemptAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp last_attempt_at = 11;
+   */
+  lastAttemptAt?: Timestamp;
+
+  /**
+   * @generated from field: google.protobuf.Timestamp delivered_at = 12;
+   */
+  deliveredAt?: Timestamp;
+};
+
+/**
+ * Describes the message service.v1.WebhookDelivery.
+ * Use `create(WebhookDeliverySchema)` to create a new message.
+ */
+export const WebhookDeliverySchema: GenMessage<WebhookDelivery> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 1);
+
+/**
+ * @generated from message service.v1.CreateWebhookRequest
+ */
+export type CreateWebhookRequest = Message<"service.v1.CreateWebhookRequest"> & {
+  /**
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * Optional: only events of this service
+   *
+   * @generated from field: string service_id = 2;
+   */
+  serviceId: string;
+
+  /**
+   * http:// or https://
+   *
+   * @generated from field: string url = 3;
+   */
+  url: string;
+
+  /**
+   * Optional: only these payload types
+   *
+   * @generated from field: repeated string event_types = 4;
+   */
+  eventTypes: string[];
+};
+
+/**
+ * Describes the message service.v1.CreateWebhookRequest.
+ * Use `create(CreateWebhookRequestSchema)` to create a new message.
+ */
+export const CreateWebhookRequestSchema: GenMessage<CreateWebhookRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 2);
+
+/**
+ * @generated from message service.v1.CreateWebhookResponse
+ */
+export type CreateWebhookResponse = Message<"service.v1.CreateWebhookResponse"> & {
+  /**
+   * @generated from field: service.v1.Webhook webhook = 1;
+   */
+  webhook?: Webhook;
+};
+
+/**
+ * Describes the message service.v1.CreateWebhookResponse.
+ * Use `create(CreateWebhookResponseSchema)` to create a new message.
+ */
+export const CreateWebhookResponseSchema: GenMessage<CreateWebhookResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 3);
+
+/**
+ * @generated from message service.v1.ListWebhooksRequest
+ */
+export type ListWebhooksRequest = Message<"service.v1.ListWebhooksRequest"> & {
+  /**
+   * Optional: only webhooks of this node
+   *
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+};
+
+/**
+ * Describes the message service.v1.ListWebhooksRequest.
+ * Use `create(ListWebhooksRequestSchema)` to create a new message.
+ */
+export const ListWebhooksRequestSchema: GenMessage<ListWebhooksRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 4);
+
+/**
+ * @generated from message service.v1.ListWebhooksResponse
+ */
+export type ListWebhooksResponse = Message<"service.v1.ListWebhooksResponse"> & {
+  /**
+   * @generated from field: repeated service.v1.Webhook webhooks = 1;
+   */
+  webhooks: Webhook[];
+};
+
+/**
+ * Describes the message service.v1.ListWebhooksResponse.
+ * Use `create(ListWebhooksResponseSchema)` to create a new message.
+ */
+export const ListWebhooksResponseSchema: GenMessage<ListWebhooksResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 5);
+
+/**
+ * @generated from message service.v1.UpdateWebhookRequest
+ */
+export type UpdateWebhookRequest = Message<"service.v1.UpdateWebhookRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * @generated from field: string url = 2;
+   */
+  url: string;
+
+  /**
+   * @generated from field: repeated string event_types = 3;
+   */
+  eventTypes: string[];
+
+  /**
+   * @generated from field: bool enabled = 4;
+   */
+  enabled: boolean;
+};
+
+/**
+ * Describes the message service.v1.UpdateWebhookRequest.
+ * Use `create(UpdateWebhookRequestSchema)` to create a new message.
+ */
+export const UpdateWebhookRequestSchema: GenMessage<UpdateWebhookRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 6);
+
+/**
+ * @generated from message service.v1.UpdateWebhookResponse
+ */
+export type UpdateWebhookResponse = Message<"service.v1.UpdateWebhookResponse"> & {
+  /**
+   * @generated from field: service.v1.Webhook webhook = 1;
+   */
+  webhook?: Webhook;
+};
+
+/**
+ * Describes the message service.v1.UpdateWebhookResponse.
+ * Use `create(UpdateWebhookResponseSchema)` to create a new message.
+ */
+export const UpdateWebhookResponseSchema: GenMessage<UpdateWebhookResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 7);
+
+/**
+ * @generated from message service.v1.DeleteWebhookRequest
+ */
+export type DeleteWebhookRequest = Message<"service.v1.DeleteWebhookRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message service.v1.DeleteWebhookRequest.
+ * Use `create(DeleteWebhookRequestSchema)` to create a new message.
+ */
+export const DeleteWebhookRequestSchema: GenMessage<DeleteWebhookRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 8);
+
+/**
+ * @generated from message service.v1.DeleteWebhookResponse
+ */
+export type DeleteWebhookResponse = Message<"service.v1.DeleteWebhookResponse"> & {
+  /**
+   * @generated from field: bool success = 1;
+   */
+  success: boolean;
+};
+
+/**
+ * Describes the message service.v1.DeleteWebhookResponse.
+ * Use `create(DeleteWebhookResponseSchema)` to create a new message.
+ */
+export const DeleteWebhookResponseSchema: GenMessage<DeleteWebhookResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 9);
+
+/**
+ * @generated from message service.v1.ListWebhookDeliveriesRequest
+ */
+export type ListWebhookDeliveriesRequest = Message<"service.v1.ListWebhookDeliveriesRequest"> & {
+  /**
+   * @generated from field: string webhook_id = 1;
+   */
+  webhookId: string;
+
+  /**
+   * Optional: "pending", "delivered" or "dead"
+   *
+   * @generated from field: string status = 2;
+   */
+  status: string;
+
+  /**
+   * Number of deliveries per page (default 20, max 100)
+   *
+   * @generated from field: int32 page_size = 3;
+   */
+  pageSize: number;
+
+  /**
+   * Offset for pagination (0-based)
+   *
+   * @generated from field: int32 page_offset = 4;
+   */
+  pageOffset: number;
+};
+
+/**
+ * Describes the message service.v1.ListWebhookDeliveriesRequest.
+ * Use `create(ListWebhookDeliveriesRequestSchema)` to create a new message.
+ */
+export const ListWebhookDeliveriesRequestSchema: GenMessage<ListWebhookDeliveriesRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 10);
+
+/**
+ * @generated from message service.v1.ListWebhookDeliveriesResponse
+ */
+export type ListWebhookDeliveriesResponse = Message<"service.v1.ListWebhookDeliveriesResponse"> & {
+  /**
+   * @generated from field: repeated service.v1.WebhookDelivery deliveries = 1;
+   */
+  deliveries: WebhookDelivery[];
+
+  /**
+   * @generated from field: int32 total_count = 2;
+   */
+  totalCount: number;
+};
+
+/**
+ * Describes the message service.v1.ListWebhookDeliveriesResponse.
+ * Use `create(ListWebhookDeliveriesResponseSchema)` to create a new message.
+ */
+export const ListWebhookDeliveriesResponseSchema: GenMessage<ListWebhookDeliveriesResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 11);
+
+/**
+ * RetryWebhookDeliveryRequest queues a dead (or delivered) delivery again
+ *
+ * @generated from message service.v1.RetryWebhookDeliveryRequest
+ */
+export type RetryWebhookDeliveryRequest = Message<"service.v1.RetryWebhookDeliveryRequest"> & {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id: string;
+};
+
+/**
+ * Describes the message service.v1.RetryWebhookDeliveryRequest.
+ * Use `create(RetryWebhookDeliveryRequestSchema)` to create a new message.
+ */
+export const RetryWebhookDeliveryRequestSchema: GenMessage<RetryWebhookDeliveryRequest> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 12);
+
+/**
+ * @generated from message service.v1.RetryWebhookDeliveryResponse
+ */
+export type RetryWebhookDeliveryResponse = Message<"service.v1.RetryWebhookDeliveryResponse"> & {
+  /**
+   * @generated from field: service.v1.WebhookDelivery delivery = 1;
+   */
+  delivery?: WebhookDelivery;
+};
+
+/**
+ * Describes the message service.v1.RetryWebhookDeliveryResponse.
+ * Use `create(RetryWebhookDeliveryResponseSchema)` to create a new message.
+ */
+export const RetryWebhookDeliveryResponseSchema: GenMessage<RetryWebhookDeliveryResponse> = /*@__PURE__*/
+  messageDesc(file_service_v1_webhook, 13);
+
+/**
+ * WebhookService manages the current user's webhook subscriptions. Every
+ * event matching a subscription is POSTed to its URL as JSON, signed with
+ * the subscription's secret:
+ *   X-Unblink-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+ *   X-Unblink-Timestamp: Unix seconds of the attempt
+ * Failed deliveries are retried with exponential backoff and end up "dead"
+ * after the last attempt.
+ *
+ * @generated from service service.v1.WebhookService
+ */
+export const WebhookService: GenService<{
+  /**
+   * @generated from rpc service.v1.WebhookService.CreateWebhook
+   */
+  createWebhook: {
+    methodKind: "unary";
+    input: typeof CreateWebhookRequestSchema;
+    output: typeof CreateWebhookResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.WebhookService.ListWebhooks
+   */
+  listWebhooks: {
+    methodKind: "unary";
+    input: typeof ListWebhooksRequestSchema;
+    output: typeof ListWebhooksResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.WebhookService.UpdateWebhook
+   */
+  updateWebhook: {
+    methodKind: "unary";
+    input: typeof UpdateWebhookRequestSchema;
+    output: typeof UpdateWebhookResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.WebhookService.DeleteWebhook
+   */
+  deleteWebhook: {
+    methodKind: "unary";
+    input: typeof DeleteWebhookRequestSchema;
+    output: typeof DeleteWebhookResponseSchema;
+  },
+  /**
+   * Delivery log
+   *
+   * @generated from rpc service.v1.WebhookService.ListWebhookDeliveries
+   */
+  listWebhookDeliveries: {
+    methodKind: "unary";
+    input: typeof ListWebhookDeliveriesRequestSchema;
+    output: typeof ListWebhookDeliveriesResponseSchema;
+  },
+  /**
+   * @generated from rpc service.v1.WebhookService.RetryWebhookDelivery
+   */
+  retryWebhookDelivery: {
+    methodKind: "unary";
+    input: typeof RetryWebhookDeliveryRequestSchema;
+    output: typeof RetryWebhookDeliveryResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_service_v1_webhook, 0);
+
@@ -33,6 +33,15 @@ func main() {
 		case "config":
 			handleConfigCommand()
 			return
+		case "show":
+			configFile, err := node.Load(configPath)
+			if err != nil {
+				log.Fatalf("Failed to load config: %v", err)
+			}
+			if err := configFile.Show(); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			return
 		case "logout":
 			configFile, err := node.Load(configPath)
 			if err != nil {
@@ -53,7 +62,7 @@ func main() {
 
 	// Log startup info
 	log.Printf("[Node] Starting node...")
-	log.Printf("[Node] Server: %s", configFile.Config.RelayAddress)
+	log.Printf("[Node] Relays: %v", configFile.Config.Relays())
 	log.Printf("[Node] Node ID: %s", configFile.Config.NodeID)
 
 	runNode(configFile)
@@ -141,6 +150,7 @@ func printUsage() {
 	fmt.Println("Usage: node [command] [options]")
 	fmt.Println()
 	fmt.Println("Commands:")
+	fmt.Println("  show           Show the config and the relay the node is on")
 	fmt.Println("  config show    Show the config file path and contents")
 	fmt.Println("  config delete  Delete the config file")
 	fmt.Println("  logout         Remove saved token")
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"net/http"
@@ -21,6 +22,7 @@ import (
 	"unblink/server/models"
 	"unblink/server/service"
 	"unblink/server/webrtc"
+	"unblink/server/websearch"
 
 	"connectrpc.com/connect"
 )
@@ -88,18 +90,70 @@ func main() {
 	}
 	chatService := chat.NewService(dbClient, chatCfg, modelRegistry)
 
+	// Embed events for semantic search (optional; needs pgvector)
+	var embedder *models.Embedder
+	if config.EmbeddingOpenAIModel != "" {
+		embeddingCfg := models.ModelConfig{
+			ModelID: config.EmbeddingOpenAIModel,
+			BaseURL: config.EmbeddingOpenAIBaseURL,
+			APIKey:  config.EmbeddingOpenAIAPIKey,
+		}
+		if embeddingCfg.BaseURL == "" {
+			embeddingCfg.BaseURL = config.ChatOpenAIBaseURL
+		}
+		if embeddingCfg.APIKey == "" {
+			embeddingCfg.APIKey = config.ChatOpenAIAPIKey
+		}
+
+		embedder = models.NewEmbedder(embeddingCfg)
+		dims, err := embedder.Dimensions(context.Background())
+		if err == nil {
+			err = dbClient.EnableEventEmbeddings(dims, embeddingCfg.ModelID)
+		}
+		if err != nil {
+			log.Printf("[Main] Semantic event search disabled: %v", err)
+			embedder = nil
+		} else {
+			log.Printf("[Main] Semantic event search enabled: model=%s, dimensions=%d", embeddingCfg.ModelID, dims)
+		}
+	}
+
 	// Register video search tool
-	videoSearchTool := tools.NewVideoSearchTool(dbClient)
+	videoSearchTool := tools.NewVideoSearchTool(dbClient, embedder, []byte(config.JWTSecret))
 	chatService.RegisterTool(videoSearchTool)
 
+	// Offer web search for messages sent with use_web_search (optional)
+	if config.WebSearchProvider != "" {
+		provider, err := websearch.NewProvider(config.WebSearchProvider, config.WebSearchBaseURL, config.WebSearchAPIKey)
+		if err != nil {
+			log.Fatalf("Failed to create web search provider: %v", err)
+		}
+		chatService.SetWebSearchTool(tools.NewWebSearchTool(provider, chatService.ContentTrimmer()))
+		log.Printf("[Main] Web search enabled: provider=%s", provider.Name())
+	}
+
 	// Initialize JWT manager and auth interceptor
 	jwtManager := server.NewJWTManager(config.JWTSecret)
 	authInterceptor := server.NewAuthInterceptor(jwtManager, dbClient)
 	log.Printf("Initialized auth interceptor")
 
-	// Create storage for frames
-	storage := webrtc.NewStorage(config.FramesBaseDir())
-	log.Printf("[Main] Initialized storage: baseDir=%s", config.FramesBaseDir())
+	// Create storage backend for frames, segments and clips
+	var storageBackend webrtc.StorageBackend
+	if config.StorageBackend == "s3" {
+		storageBackend = webrtc.NewS3Backend(webrtc.S3Config{
+			Endpoint:     config.S3Endpoint,
+			Region:       config.S3Region,
+			Bucket:       config.S3Bucket,
+			AccessKey:    config.S3AccessKey,
+			SecretKey:    config.S3SecretKey,
+			UsePathStyle: config.S3UsePathStyle,
+		})
+		log.Printf("[Main] Initialized S3 storage: bucket=%s, endpoint=%s", config.S3Bucket, config.S3Endpoint)
+	} else {
+		storageBackend = webrtc.NewLocalBackend(config.StorageDir())
+		log.Printf("[Main] Initialized local storage: baseDir=%s", config.StorageDir())
+	}
+	storage := webrtc.NewStorage(storageBackend)
 
 	// Create event service BEFORE batch manager (batch manager needs the broadcaster)
 	eventService := service.NewEventService(dbClient)
@@ -110,8 +164,32 @@ func main() {
 	batchManager := webrtc.NewBatchManager(frameClient, config.FrameBatchSize, storage, dbClient, eventService.GetBroadcaster())
 	log.Printf("[Main] Initialized VLM frame client: url=%s, model=%s, batchSize=%d, timeout=%vs", config.VLMOpenAIBaseURL, config.VLMOpenAIModel, config.FrameBatchSize, config.VLMTimeoutSec)
 
+	// Check every VLM result against the users' alert rules
+	alertEngine := service.NewAlertEngine(dbClient, eventService.GetBroadcaster(), modelRegistry, chatCfg.FastOpenAIModel)
+	batchManager.AddResultListener(alertEngine.HandleResult)
+
+	// Keep event embeddings up to date for semantic search
+	if embedder != nil {
+		embeddingIndexer := service.NewEmbeddingIndexer(dbClient, embedder)
+		eventService.GetBroadcaster().AddListener(embeddingIndexer.HandleEvent)
+		embeddingIndexer.Start()
+		eventService.SetEmbedder(embedder)
+	}
+
+	// Roll VLM events up into hour, day and week summaries
+	eventSummarizer := service.NewEventSummarizer(dbClient, eventService.GetBroadcaster(), modelRegistry, chatCfg.FastOpenAIModel)
+	eventSummarizer.Start()
+	defer eventSummarizer.Stop()
+
+	// Send events to webhook subscribers from the outbox
+	webhookDispatcher := service.NewWebhookDispatcher(dbClient)
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+
 	// Initialize node server for WebSocket connections
 	nodeServer := server.NewServer(config)
+	nodeServer.SetNodeRepository(dbClient)
+	nodeServer.SetNodeTokenRepository(dbClient)
 
 	// Create service registry for managing services
 	frameInterval := time.Duration(config.FrameIntervalSeconds * float64(time.Second))
@@ -125,7 +203,17 @@ func main() {
 		idleTimeout,
 		config.BridgeMaxRetries,
 		config.EnableIndexing,
+		service.RecordingConfig{
+			Enabled:          config.EnableRecording,
+			SegmentsDir:      config.SegmentsBaseDir(),
+			SegmentDuration:  config.RecordingSegmentDuration(),
+			DefaultRetention: config.RecordingRetention(),
+		},
 	)
+	serviceRegistry.SetEventBroadcaster(eventService.GetBroadcaster())
+	if config.EnableRecording {
+		log.Printf("[Main] Recording enabled: baseDir=%s, segment=%v, retention=%v", config.SegmentsBaseDir(), config.RecordingSegmentDuration(), config.RecordingRetention())
+	}
 
 	// Wire up node event callbacks
 	nodeServer.OnNodeReady(serviceRegistry.SetNodeOnline)
@@ -136,12 +224,39 @@ func main() {
 		log.Printf("Failed to load services: %v", err)
 	}
 
-	serviceService := service.NewService(dbClient, serviceRegistry)
+	// Let the chat assistant look at cameras live and review stored frames
+	frameAnalyzer := service.NewFrameAnalyzer(dbClient, storageBackend, frameClient)
+	chatService.RegisterTool(tools.NewLiveSnapshotTool(dbClient, serviceRegistry, frameClient, storage, []byte(config.JWTSecret)))
+	chatService.RegisterTool(tools.NewFrameReviewTool(dbClient, serviceRegistry, frameAnalyzer, []byte(config.JWTSecret)))
+
+	// Publish events and camera state to MQTT for Home Assistant (optional)
+	if config.MQTTBrokerURL != "" {
+		mqttPublisher := service.NewMQTTPublisher(service.MQTTConfig{
+			BrokerURL:       config.MQTTBrokerURL,
+			Username:        config.MQTTUsername,
+			Password:        config.MQTTPassword,
+			ClientID:        config.MQTTClientIDOrDefault(),
+			TopicPrefix:     config.MQTTTopicPrefixOrDefault(),
+			DiscoveryPrefix: config.MQTTDiscoveryPrefixOrDefault(),
+		}, serviceRegistry)
+		eventService.GetBroadcaster().AddListener(mqttPublisher.HandleEvent)
+		batchManager.AddResultListener(mqttPublisher.HandleResult)
+		mqttPublisher.Start()
+		defer mqttPublisher.Stop()
+	}
+
+	serviceService := service.NewService(dbClient, serviceRegistry, nodeServer)
 
 	// Create storage service
 	storageService := service.NewStorageService(dbClient, &service.StorageConfig{
+		Backend:        storageBackend,
 		StorageBaseDir: config.FramesBaseDir(),
-	})
+		ClipsDir:       config.ClipsBaseDir(),
+		ClipPreRoll:    config.ClipPreRoll(),
+		ClipPostRoll:   config.ClipPostRoll(),
+		URLSigningKey:  []byte(config.JWTSecret),
+		FrameAnalyzer:  frameAnalyzer,
+	}, authInterceptor)
 
 	// Create HTTP handler with Connect RPC
 	mux := http.NewServeMux()
@@ -188,6 +303,22 @@ func main() {
 	mux.Handle(eventPath, eventHandler)
 	log.Printf("Mounted EventService at %s (with auth)", eventPath)
 
+	// Mount AlertService with auth interceptor
+	alertPath, alertHandler := servicev1connect.NewAlertServiceHandler(
+		service.NewAlertService(dbClient),
+		connect.WithInterceptors(authInterceptor),
+	)
+	mux.Handle(alertPath, alertHandler)
+	log.Printf("Mounted AlertService at %s (with auth)", alertPath)
+
+	// Mount WebhookService with auth interceptor
+	webhookPath, webhookHandler := servicev1connect.NewWebhookServiceHandler(
+		service.NewWebhookService(dbClient),
+		connect.WithInterceptors(authInterceptor),
+	)
+	mux.Handle(webhookPath, webhookHandler)
+	log.Printf("Mounted WebhookService at %s (with auth)", webhookPath)
+
 	// Mount WebRTCService with auth interceptor
 	webrtcService := webrtc.NewService(nodeServer, dbClient)
 	webrtcPath, webrtcHandler := webrtcv1connect.NewWebRTCServiceHandler(
@@ -217,6 +348,8 @@ func main() {
 	log.Printf("  - Service RPC: /service.v1.ServiceService/*")
 	log.Printf("  - Storage RPC: /service.v1.StorageService/*")
 	log.Printf("  - Event RPC: /service.v1.EventService/*")
+	log.Printf("  - Alert RPC: /service.v1.AlertService/*")
+	log.Printf("  - Webhook RPC: /service.v1.WebhookService/*")
 	log.Printf("  - WebRTC RPC: /webrtc.v1.WebRTCService/*")
 	log.Printf("  - Node WebSocket: /node/connect")
 	log.Printf("  - Storage HTTP: /storage/{itemID}")
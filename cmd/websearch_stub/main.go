@@ -0,0 +1,23 @@
+// Command websearch_stub runs a local web search server with generated results,
+// for trying the chat web search without an external provider. Point the
+// server at it with "web_search_provider": "stub" and
+// "web_search_base_url": "http://localhost:8089".
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"unblink/server/websearch"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8089", "listen address")
+	flag.Parse()
+
+	log.Printf("Web search stub listening on http://%s", *addr)
+	if err := http.ListenAndServe(*addr, websearch.NewStubHandler()); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
@@ -20,20 +20,50 @@ func (c *Client) CreateSchema() error {
 	if _, err := c.db.Exec(createUserNodeTablesSQL); err != nil {
 		return fmt.Errorf("failed to create user_node tables: %w", err)
 	}
+	if _, err := c.db.Exec(createNodeTablesSQL); err != nil {
+		return fmt.Errorf("failed to create node tables: %w", err)
+	}
+	if _, err := c.db.Exec(createNodeTokenTablesSQL); err != nil {
+		return fmt.Errorf("failed to create node token tables: %w", err)
+	}
 	if _, err := c.db.Exec(createStorageTablesSQL); err != nil {
 		return fmt.Errorf("failed to create storage tables: %w", err)
 	}
 	if _, err := c.db.Exec(createEventTablesSQL); err != nil {
 		return fmt.Errorf("failed to create event tables: %w", err)
 	}
+	if _, err := c.db.Exec(createEventSearchIndexSQL); err != nil {
+		return fmt.Errorf("failed to create event search index: %w", err)
+	}
+	if _, err := c.db.Exec(createAlertRuleTablesSQL); err != nil {
+		return fmt.Errorf("failed to create alert rule tables: %w", err)
+	}
+	if _, err := c.db.Exec(createWebhookTablesSQL); err != nil {
+		return fmt.Errorf("failed to create webhook tables: %w", err)
+	}
 	return nil
 }
 
 // DropSchema drops all tables
 func (c *Client) DropSchema() error {
+	if _, err := c.db.Exec(dropEventEmbeddingTablesSQL); err != nil {
+		return fmt.Errorf("failed to drop event embedding tables: %w", err)
+	}
+	if _, err := c.db.Exec(dropWebhookTablesSQL); err != nil {
+		return fmt.Errorf("failed to drop webhook tables: %w", err)
+	}
+	if _, err := c.db.Exec(dropAlertRuleTablesSQL); err != nil {
+		return fmt.Errorf("failed to drop alert rule tables: %w", err)
+	}
 	if _, err := c.db.Exec(dropUserNodeTablesSQL); err != nil {
 		return fmt.Errorf("failed to drop user_node tables: %w", err)
 	}
+	if _, err := c.db.Exec(dropNodeTokenTablesSQL); err != nil {
+		return fmt.Errorf("failed to drop node token tables: %w", err)
+	}
+	if _, err := c.db.Exec(dropNodeTablesSQL); err != nil {
+		return fmt.Errorf("failed to drop node tables: %w", err)
+	}
 	if _, err := c.db.Exec(dropStorageTablesSQL); err != nil {
 		return fmt.Errorf("failed to drop storage tables: %w", err)
 	}
@@ -0,0 +1,210 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+const (
+	createAlertRuleTablesSQL = `
+		CREATE TABLE IF NOT EXISTS alert_rules (
+			id TEXT PRIMARY KEY,
+			node_id TEXT NOT NULL,
+			service_id TEXT REFERENCES services(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			expression TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_triggered_at TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_rules_node_id ON alert_rules(node_id);
+		CREATE INDEX IF NOT EXISTS idx_alert_rules_service_id ON alert_rules(service_id);
+	`
+
+	dropAlertRuleTablesSQL = `DROP TABLE IF EXISTS alert_rules CASCADE`
+
+	alertRuleColumns = `id, node_id, service_id, name, expression, kind, enabled, cooldown_seconds, created_at, updated_at, last_triggered_at`
+)
+
+// CreateAlertRule stores a new alert rule (empty serviceID = every service of the node)
+func (c *Client) CreateAlertRule(rule *servicev1.AlertRule) error {
+	insertSQL := `
+		INSERT INTO alert_rules (id, node_id, service_id, name, expression, kind, enabled, cooldown_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := c.db.Exec(insertSQL, rule.Id, rule.NodeId, nullString(rule.ServiceId), rule.Name, rule.Expression, rule.Kind, rule.Enabled, rule.CooldownSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAlertRule updates the user-editable fields of an alert rule
+func (c *Client) UpdateAlertRule(rule *servicev1.AlertRule) error {
+	updateSQL := `
+		UPDATE alert_rules
+		SET name = $1, expression = $2, kind = $3, enabled = $4, cooldown_seconds = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
+	`
+
+	_, err := c.db.Exec(updateSQL, rule.Name, rule.Expression, rule.Kind, rule.Enabled, rule.CooldownSeconds, rule.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// SetAlertRuleTriggered records when an alert rule last matched
+func (c *Client) SetAlertRuleTriggered(id string, at time.Time) error {
+	updateSQL := `UPDATE alert_rules SET last_triggered_at = $1 WHERE id = $2`
+
+	_, err := c.db.Exec(updateSQL, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule trigger time: %w", err)
+	}
+
+	return nil
+}
+
+// TimeSinceLastAlert returns how long ago a rule last created an alert event
+// for a service, or false if it never did
+func (c *Client) TimeSinceLastAlert(ruleID, serviceID string) (time.Duration, bool, error) {
+	querySQL := `
+		SELECT EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - MAX(created_at)))
+		FROM events
+		WHERE service_id = $1 AND payload @> jsonb_build_object('type', 'alert', 'rule_id', $2::text)
+	`
+
+	var seconds sql.NullFloat64
+	if err := c.db.QueryRow(querySQL, serviceID, ruleID).Scan(&seconds); err != nil {
+		return 0, false, fmt.Errorf("failed to get last alert: %w", err)
+	}
+	if !seconds.Valid {
+		return 0, false, nil
+	}
+
+	return time.Duration(seconds.Float64 * float64(time.Second)), true, nil
+}
+
+// GetAlertRule retrieves an alert rule by ID, or nil if it does not exist
+func (c *Client) GetAlertRule(id string) (*servicev1.AlertRule, error) {
+	querySQL := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	rule, err := scanAlertRule(c.db.QueryRow(querySQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteAlertRule removes an alert rule by ID
+func (c *Client) DeleteAlertRule(id string) error {
+	deleteSQL := `DELETE FROM alert_rules WHERE id = $1`
+
+	_, err := c.db.Exec(deleteSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// ListAlertRulesByNodeId retrieves the alert rules of a node. With a serviceID,
+// only rules covering that service (its own and node-wide ones) are returned.
+func (c *Client) ListAlertRulesByNodeId(nodeID, serviceID string) ([]*servicev1.AlertRule, error) {
+	querySQL := `
+		SELECT ` + alertRuleColumns + `
+		FROM alert_rules
+		WHERE node_id = $1 AND ($2 = '' OR service_id IS NULL OR service_id = $2)
+		ORDER BY created_at
+	`
+
+	return c.queryAlertRules(querySQL, nodeID, serviceID)
+}
+
+// ListActiveAlertRulesForService retrieves the enabled rules that cover a service
+func (c *Client) ListActiveAlertRulesForService(serviceID, nodeID string) ([]*servicev1.AlertRule, error) {
+	querySQL := `
+		SELECT ` + alertRuleColumns + `
+		FROM alert_rules
+		WHERE node_id = $1 AND (service_id IS NULL OR service_id = $2) AND enabled
+		ORDER BY created_at
+	`
+
+	return c.queryAlertRules(querySQL, nodeID, serviceID)
+}
+
+func (c *Client) queryAlertRules(querySQL string, args ...any) ([]*servicev1.AlertRule, error) {
+	rows, err := c.db.Query(querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*servicev1.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// scanAlertRule reads a row selected with alertRuleColumns
+func scanAlertRule(row rowScanner) (*servicev1.AlertRule, error) {
+	var rule servicev1.AlertRule
+	var serviceID sql.NullString
+	var createdAt, updatedAt time.Time
+	var lastTriggered sql.NullTime
+
+	if err := row.Scan(
+		&rule.Id,
+		&rule.NodeId,
+		&serviceID,
+		&rule.Name,
+		&rule.Expression,
+		&rule.Kind,
+		&rule.Enabled,
+		&rule.CooldownSeconds,
+		&createdAt,
+		&updatedAt,
+		&lastTriggered,
+	); err != nil {
+		return nil, err
+	}
+
+	rule.ServiceId = serviceID.String
+	rule.CreatedAt = timestampToProto(createdAt)
+	rule.UpdatedAt = timestampToProto(updatedAt)
+	if lastTriggered.Valid {
+		rule.LastTriggeredAt = timestampToProto(lastTriggered.Time)
+	}
+
+	return &rule, nil
+}
+
+// nullString maps the empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
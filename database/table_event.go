@@ -24,6 +24,7 @@ const (
 		CREATE INDEX IF NOT EXISTS idx_events_service_id ON events(service_id);
 		CREATE INDEX IF NOT EXISTS idx_events_created_at ON events(created_at DESC);
 		CREATE INDEX IF NOT EXISTS idx_events_payload_gin ON events USING gin(payload);
+		CREATE INDEX IF NOT EXISTS idx_events_service_from ON events(service_id, (payload->>'from_iso'));
 	`
 
 	dropEventTablesSQL = `DROP TABLE IF EXISTS events CASCADE`
@@ -36,9 +37,28 @@ func (c *Client) CreateEvent(id, serviceID string, payload *structpb.Struct) err
 		return fmt.Errorf("failed to convert payload: %w", err)
 	}
 
+	// The event and its webhook deliveries (the outbox) are written in one
+	// statement, so no event is lost for a webhook if the server stops.
+	// Summary rollups get no deliveries: their events were delivered already.
 	insertSQL := `
-		INSERT INTO events (id, service_id, payload)
-		VALUES ($1, $2, $3::jsonb)
+		WITH event AS (
+			INSERT INTO events (id, service_id, payload)
+			VALUES ($1, $2, $3::jsonb)
+			RETURNING id, service_id, payload
+		)
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type)
+		SELECT w.id, event.id, COALESCE(event.payload->>'type', '')
+		FROM event
+		JOIN services s ON s.id = event.service_id
+		JOIN webhooks w ON w.node_id = s.node_id
+		WHERE w.enabled
+			AND NOT COALESCE((event.payload->>'rollup')::boolean, false)
+			AND (w.service_id IS NULL OR w.service_id = event.service_id)
+			AND (jsonb_array_length(w.event_types) = 0 OR w.event_types ? (event.payload->>'type'))
+			AND (
+				NOT EXISTS (SELECT 1 FROM user_node un WHERE un.node_id = w.node_id)
+				OR EXISTS (SELECT 1 FROM user_node un WHERE un.node_id = w.node_id AND un.user_id = w.user_id)
+			)
 	`
 
 	_, err = c.db.Exec(insertSQL, id, serviceID, payloadJSON)
@@ -187,6 +207,126 @@ func (c *Client) ListEventsByNodeId(nodeID string, pageSize, pageOffset int32) (
 	return events, totalCount, nil
 }
 
+// RollupChild is a VLM event to be merged into a summary of a longer period
+type RollupChild struct {
+	ID          string
+	From        time.Time
+	To          time.Time
+	Description string
+	Labels      []string
+}
+
+// rollupFilterSQL selects the VLM events of service $1 starting in [$2, $3):
+// those the batch manager wrote when rollup is empty, else the summaries of
+// that granularity ($4)
+func rollupFilterSQL(rollup string) string {
+	filter := `
+		e.service_id = $1
+		AND e.payload->>'type' = 'vlm-indexing'
+		AND e.payload->>'from_iso' >= $2
+		AND e.payload->>'from_iso' < $3
+	`
+	if rollup == "" {
+		return filter + ` AND e.payload->>'rollup' IS NULL`
+	}
+	return filter + ` AND e.payload->>'rollup' = 'true' AND e.payload->>'granularity' = $4`
+}
+
+// rollupArgs are the arguments of rollupFilterSQL. from_iso is compared as
+// text, which orders like time for the UTC RFC 3339 form events are written in.
+func rollupArgs(serviceID string, from, to time.Time, rollup string) []any {
+	args := []any{serviceID, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)}
+	if rollup != "" {
+		args = append(args, rollup)
+	}
+	return args
+}
+
+// ListVLMEventStarts returns the distinct minutes (UTC) in which VLM events of
+// a service start, for events starting in [from, to): events the batch manager
+// wrote when rollup is empty, else the summaries of that granularity
+func (c *Client) ListVLMEventStarts(serviceID string, from, to time.Time, rollup string) ([]time.Time, error) {
+	querySQL := `SELECT DISTINCT left(e.payload->>'from_iso', 16) FROM events e WHERE ` + rollupFilterSQL(rollup)
+
+	rows, err := c.db.Query(querySQL, rollupArgs(serviceID, from, to, rollup)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event starts: %w", err)
+	}
+	defer rows.Close()
+
+	var starts []time.Time
+	for rows.Next() {
+		var minute string
+		if err := rows.Scan(&minute); err != nil {
+			return nil, fmt.Errorf("failed to scan event start: %w", err)
+		}
+		start, err := time.Parse("2006-01-02T15:04", minute)
+		if err != nil {
+			continue
+		}
+		starts = append(starts, start)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return starts, nil
+}
+
+// ListRollupChildren retrieves the VLM events of a service starting in
+// [from, to), oldest first (rollup as in ListVLMEventStarts)
+func (c *Client) ListRollupChildren(serviceID string, from, to time.Time, rollup string) ([]*RollupChild, error) {
+	querySQL := `
+		SELECT e.id, e.payload->>'from_iso', COALESCE(e.payload->>'to_iso', e.payload->>'from_iso'),
+			COALESCE(e.payload#>>'{response,description}', ''),
+			COALESCE(jsonb_path_query_array(e.payload, '$.response.objects[*].label')::text, '[]')
+		FROM events e
+		WHERE ` + rollupFilterSQL(rollup) + `
+		ORDER BY e.payload->>'from_iso' ASC
+	`
+
+	rows, err := c.db.Query(querySQL, rollupArgs(serviceID, from, to, rollup)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rollup children: %w", err)
+	}
+	defer rows.Close()
+
+	var children []*RollupChild
+	for rows.Next() {
+		var child RollupChild
+		var fromISO, toISO, labelsJSON string
+		if err := rows.Scan(&child.ID, &fromISO, &toISO, &child.Description, &labelsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		if child.From, err = time.Parse(time.RFC3339, fromISO); err != nil {
+			continue
+		}
+		if child.To, err = time.Parse(time.RFC3339, toISO); err != nil {
+			child.To = child.From
+		}
+
+		var labels []any
+		if err := json.Unmarshal([]byte(labelsJSON), &labels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels: %w", err)
+		}
+		for _, label := range labels {
+			if s, ok := label.(string); ok && s != "" {
+				child.Labels = append(child.Labels, s)
+			}
+		}
+
+		children = append(children, &child)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return children, nil
+}
+
 // protoStructToJSON converts a protobuf Struct to JSON string
 // It converts the protobuf Struct to a native Go map first to get clean JSON
 func protoStructToJSON(s *structpb.Struct) (string, error) {
@@ -235,78 +375,3 @@ func (c *Client) CountEventsForUser(userID string) (int64, error) {
 
 	return count, nil
 }
-
-// SearchEventsByPayload searches events by keywords in JSONB payload
-// Filters for events where type = "vlm-indexing" and any keyword matches the payload text
-func (c *Client) SearchEventsByPayload(keywords []string) ([]*servicev1.Event, error) {
-	const limit = 10
-
-	// Build ILIKE query for each keyword (OR condition)
-	// Also filter for type = "vlm-indexing"
-	querySQL := `
-		SELECT e.id, e.service_id, e.payload, e.created_at
-		FROM events e
-		WHERE e.payload->>'type' = 'vlm-indexing'
-		AND (`
-
-	// Add ILIKE conditions for each keyword
-	conditions := make([]string, len(keywords))
-	args := make([]interface{}, 0, len(keywords))
-	for i, kw := range keywords {
-		conditions[i] = `e.payload::text ILIKE $` + fmt.Sprint(i+1)
-		args = append(args, "%"+kw+"%")
-	}
-	querySQL += fmt.Sprint(conditions[0])
-	for i := 1; i < len(conditions); i++ {
-		querySQL += " OR " + conditions[i]
-	}
-	querySQL += `)
-		ORDER BY e.created_at DESC
-		LIMIT $` + fmt.Sprint(len(keywords)+1)
-	args = append(args, limit)
-
-	rows, err := c.db.Query(querySQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search events: %w", err)
-	}
-	defer rows.Close()
-
-	var events []*servicev1.Event
-
-	for rows.Next() {
-		var event servicev1.Event
-		var svcID sql.NullString
-		var payloadJSON sql.NullString
-		var createdAt time.Time
-
-		if err := rows.Scan(
-			&event.Id,
-			&svcID,
-			&payloadJSON,
-			&createdAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
-		}
-
-		if svcID.Valid {
-			event.ServiceId = svcID.String
-		}
-		if payloadJSON.Valid {
-			payload, err := jsonToProtoStruct(payloadJSON.String)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert payload: %w", err)
-			}
-			event.Payload = payload
-		}
-
-		event.CreatedAt = timestampToProto(createdAt)
-
-		events = append(events, &event)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating events: %w", err)
-	}
-
-	return events, nil
-}
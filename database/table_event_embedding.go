@@ -0,0 +1,458 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+const (
+	// eventSearchDocument is the text of an event matched by keyword search: the
+	// VLM description and object labels
+	eventSearchDocument = `COALESCE(payload#>>'{response,description}', '') || ' ' || COALESCE(jsonb_path_query_array(payload, '$.response.objects[*].label')::text, '')`
+
+	// Start and end of the time span an event covers
+	eventFromSQL = `(e.payload->>'from_iso')::timestamptz`
+	eventToSQL   = `(e.payload->>'to_iso')::timestamptz`
+
+	// defaultTextSearchConfig parses descriptions written without a language
+	// setting, and labels, which are always English
+	defaultTextSearchConfig = "english"
+
+	// fallbackTextSearchConfig parses descriptions in languages Postgres has no
+	// configuration for: words are matched as they are, without stemming
+	fallbackTextSearchConfig = "simple"
+
+	dropEventEmbeddingTablesSQL = `DROP TABLE IF EXISTS event_embeddings CASCADE`
+
+	// HNSW indexes support at most this many dimensions; larger vectors are
+	// searched exactly
+	maxIndexedEmbeddingDimensions = 2000
+
+	// rrfK damps the weight of top ranks in reciprocal rank fusion
+	rrfK = 60
+
+	// defaultSemanticMaxDistance drops vector matches with a cosine
+	// similarity below 0.3
+	defaultSemanticMaxDistance = 0.7
+)
+
+// textSearchConfigs maps the description languages of the analysis settings
+// (English name, native name or ISO 639-1 code, lowercase) to Postgres text
+// search configurations
+var textSearchConfigs = []struct {
+	config string
+	names  []string
+}{
+	{"english", []string{"english", "en"}},
+	{"danish", []string{"danish", "da", "dansk"}},
+	{"dutch", []string{"dutch", "nl", "nederlands"}},
+	{"finnish", []string{"finnish", "fi", "suomi"}},
+	{"french", []string{"french", "fr", "français", "francais"}},
+	{"german", []string{"german", "de", "deutsch"}},
+	{"hungarian", []string{"hungarian", "hu", "magyar"}},
+	{"italian", []string{"italian", "it", "italiano"}},
+	{"norwegian", []string{"norwegian", "no", "nb", "norsk"}},
+	{"portuguese", []string{"portuguese", "pt", "português", "portugues"}},
+	{"romanian", []string{"romanian", "ro", "română", "romana"}},
+	{"russian", []string{"russian", "ru", "русский"}},
+	{"spanish", []string{"spanish", "es", "español", "espanol"}},
+	{"swedish", []string{"swedish", "sv", "svenska"}},
+	{"turkish", []string{"turkish", "tr", "türkçe", "turkce"}},
+}
+
+var (
+	// eventSearchVector is the full-text vector of an event: its document parsed
+	// with the configuration of the language its description is written in
+	// (payload "language"). The full-text index is built on the same
+	// expression, so both must stay identical.
+	eventSearchVector = buildEventSearchVector()
+
+	// createEventSearchIndexSQL replaces the index of the English-only vector
+	createEventSearchIndexSQL = `
+		DROP INDEX IF EXISTS idx_events_search_fts;
+		CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING gin((` + eventSearchVector + `));
+	`
+)
+
+// buildEventSearchVector returns the SQL of eventSearchVector. Every branch
+// names its configuration as a constant, so the expression can be indexed.
+func buildEventSearchVector() string {
+	language := `lower(COALESCE(payload->>'language', ''))`
+
+	var b strings.Builder
+	b.WriteString(`CASE WHEN ` + language + ` = '' THEN to_tsvector('` + defaultTextSearchConfig + `', ` + eventSearchDocument + `)`)
+	for _, c := range textSearchConfigs {
+		names := make([]string, len(c.names))
+		for i, name := range c.names {
+			names[i] = "'" + name + "'"
+		}
+		b.WriteString(` WHEN ` + language + ` IN (` + strings.Join(names, ", ") + `) THEN to_tsvector('` + c.config + `', ` + eventSearchDocument + `)`)
+	}
+	b.WriteString(` ELSE to_tsvector('` + fallbackTextSearchConfig + `', ` + eventSearchDocument + `) END`)
+	return b.String()
+}
+
+// eventSearchQuery returns the SQL of a tsquery matching events of any
+// language: the query parsed with every configuration, so a word matches the
+// stem each language gives it
+func eventSearchQuery(query string) string {
+	parts := []string{`to_tsquery('` + fallbackTextSearchConfig + `', ` + query + `)`}
+	for _, c := range textSearchConfigs {
+		parts = append(parts, `to_tsquery('`+c.config+`', `+query+`)`)
+	}
+	return `(` + strings.Join(parts, " || ") + `)`
+}
+
+// EventText is the searchable text of an event that has no embedding yet
+type EventText struct {
+	ID          string
+	Description string
+	Labels      []string
+}
+
+// EventSearchParams describes a hybrid event search. Without a query or
+// embedding, the events matching the filters are listed instead.
+type EventSearchParams struct {
+	Query        string    // Free text for keyword matching
+	Embedding    []float32 // Query vector (nil = keywords only)
+	MaxDistance  float64   // Cosine distance beyond which vector matches are dropped (0 = 0.7)
+	UserID       string    // Only events of nodes the user can access (empty = all)
+	NodeID       string    // Only events of this node (empty = all)
+	ServiceNames []string  // Only events of services whose name contains one of these, case-insensitive (empty = all)
+	Labels       []string  // Only events with an object label containing one of these, case-insensitive (empty = all)
+	Granularity  string    // Only events of this granularity (empty = all)
+	From         time.Time // Only events ending at or after this time (zero = unbounded)
+	To           time.Time // Only events starting before this time (zero = unbounded)
+	Limit        int
+}
+
+// EnableEventEmbeddings creates the pgvector extension and the embeddings side
+// table for vectors of the given size. Stored vectors of another size or model
+// are discarded, so they are embedded again.
+func (c *Client) EnableEventEmbeddings(dimensions int, model string) error {
+	if _, err := c.db.Exec(`CREATE EXTENSION IF NOT EXISTS vector`); err != nil {
+		return fmt.Errorf("failed to create vector extension (is pgvector installed?): %w", err)
+	}
+
+	var current sql.NullString
+	err := c.db.QueryRow(`
+		SELECT format_type(atttypid, atttypmod)
+		FROM pg_attribute
+		WHERE attrelid = to_regclass('event_embeddings') AND attname = 'embedding'
+	`).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check event embeddings table: %w", err)
+	}
+
+	columnType := fmt.Sprintf("vector(%d)", dimensions)
+	if current.Valid && current.String != columnType {
+		if _, err := c.db.Exec(dropEventEmbeddingTablesSQL); err != nil {
+			return fmt.Errorf("failed to drop event embeddings table: %w", err)
+		}
+	}
+
+	createSQL := `
+		CREATE TABLE IF NOT EXISTS event_embeddings (
+			event_id TEXT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			model TEXT NOT NULL,
+			embedding ` + columnType + ` NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	if _, err := c.db.Exec(createSQL); err != nil {
+		return fmt.Errorf("failed to create event embeddings table: %w", err)
+	}
+
+	if dimensions <= maxIndexedEmbeddingDimensions {
+		indexSQL := `CREATE INDEX IF NOT EXISTS idx_event_embeddings_hnsw ON event_embeddings USING hnsw (embedding vector_cosine_ops)`
+		if _, err := c.db.Exec(indexSQL); err != nil {
+			return fmt.Errorf("failed to create event embeddings index: %w", err)
+		}
+	}
+
+	if _, err := c.db.Exec(`DELETE FROM event_embeddings WHERE model <> $1`, model); err != nil {
+		return fmt.Errorf("failed to discard embeddings of other models: %w", err)
+	}
+
+	return nil
+}
+
+// StoreEventEmbedding stores (or replaces) the embedding of an event
+func (c *Client) StoreEventEmbedding(eventID, model string, embedding []float32) error {
+	upsertSQL := `
+		INSERT INTO event_embeddings (event_id, model, embedding)
+		VALUES ($1, $2, $3::vector)
+		ON CONFLICT (event_id) DO UPDATE SET model = EXCLUDED.model, embedding = EXCLUDED.embedding, created_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := c.db.Exec(upsertSQL, eventID, model, vectorLiteral(embedding))
+	if err != nil {
+		return fmt.Errorf("failed to store event embedding: %w", err)
+	}
+
+	return nil
+}
+
+// ListEventsWithoutEmbedding retrieves the newest described events that have
+// no embedding yet
+func (c *Client) ListEventsWithoutEmbedding(limit int) ([]*EventText, error) {
+	querySQL := `
+		SELECT e.id, e.payload#>>'{response,description}',
+			COALESCE((SELECT array_to_json(array_agg(o->>'label'))::text
+				FROM jsonb_array_elements(e.payload#>'{response,objects}') o), '[]')
+		FROM events e
+		LEFT JOIN event_embeddings ee ON ee.event_id = e.id
+		WHERE ee.event_id IS NULL
+			AND e.payload->>'type' = 'vlm-indexing'
+			AND jsonb_typeof(e.payload#>'{response,objects}') = 'array'
+			AND COALESCE(e.payload#>>'{response,description}', '') <> ''
+		ORDER BY e.created_at DESC
+		LIMIT $1
+	`
+
+	rows, err := c.db.Query(querySQL, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events without embedding: %w", err)
+	}
+	defer rows.Close()
+
+	var texts []*EventText
+	for rows.Next() {
+		var text EventText
+		var labelsJSON string
+		if err := rows.Scan(&text.ID, &text.Description, &labelsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := json.Unmarshal([]byte(labelsJSON), &text.Labels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels: %w", err)
+		}
+		texts = append(texts, &text)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return texts, nil
+}
+
+// SearchEvents ranks VLM events by keyword match and, with a query vector,
+// semantic similarity. Both rankings are merged by reciprocal rank fusion, so
+// an event found both ways ranks above one found only one way. Without a
+// query, the latest matching events are returned oldest first, unscored.
+func (c *Client) SearchEvents(params EventSearchParams) ([]*servicev1.EventSearchResult, error) {
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+	if params.MaxDistance <= 0 {
+		params.MaxDistance = defaultSemanticMaxDistance
+	}
+	candidates := max(params.Limit*4, 50)
+
+	tsQuery := keywordTSQuery(params.Query)
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	filter := `e.payload->>'type' = 'vlm-indexing'`
+	if params.UserID != "" {
+		user := arg(params.UserID)
+		filter += ` AND (
+			NOT EXISTS (SELECT 1 FROM user_node un WHERE un.node_id = s.node_id)
+			OR EXISTS (SELECT 1 FROM user_node un WHERE un.node_id = s.node_id AND un.user_id = ` + user + `)
+		)`
+	}
+	if params.NodeID != "" {
+		filter += ` AND s.node_id = ` + arg(params.NodeID)
+	}
+	if len(params.ServiceNames) > 0 {
+		var names []string
+		for _, name := range params.ServiceNames {
+			names = append(names, `s.name ILIKE `+arg(containsPattern(name)))
+		}
+		filter += ` AND (` + strings.Join(names, " OR ") + `)`
+	}
+	if len(params.Labels) > 0 {
+		var labels []string
+		for _, label := range params.Labels {
+			labels = append(labels, `l ILIKE `+arg(containsPattern(label)))
+		}
+		filter += ` AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(jsonb_path_query_array(e.payload, '$.response.objects[*].label')) l
+			WHERE ` + strings.Join(labels, " OR ") + `
+		)`
+	}
+	if params.Granularity != "" {
+		filter += ` AND e.payload->>'granularity' = ` + arg(params.Granularity)
+	}
+	if !params.From.IsZero() {
+		filter += ` AND ` + eventToSQL + ` >= ` + arg(params.From)
+	}
+	if !params.To.IsZero() {
+		filter += ` AND ` + eventFromSQL + ` < ` + arg(params.To)
+	}
+
+	if tsQuery == "" && params.Embedding == nil {
+		return c.queryEventSearchResults(`
+			SELECT id, service_id, payload, created_at, 0::float8, false, 0::float8
+			FROM (
+				SELECT e.id, e.service_id, e.payload, e.created_at, `+eventFromSQL+` AS event_from
+				FROM events e
+				JOIN services s ON s.id = e.service_id
+				WHERE `+filter+`
+				ORDER BY event_from DESC
+				LIMIT `+arg(params.Limit)+`
+			) latest
+			ORDER BY event_from ASC
+		`, args)
+	}
+
+	limitArg := arg(candidates)
+
+	keywordCTE := `SELECT NULL::text AS id, NULL::bigint AS rank WHERE false`
+	if tsQuery != "" {
+		query := arg(tsQuery)
+		keywordCTE = `
+			SELECT id, row_number() OVER (ORDER BY text_rank DESC, created_at DESC) AS rank
+			FROM (
+				SELECT e.id, e.created_at, ts_rank_cd(` + eventSearchVector + `, q) AS text_rank
+				FROM events e
+				JOIN services s ON s.id = e.service_id,
+				` + eventSearchQuery(query) + ` q
+				WHERE ` + filter + ` AND ` + eventSearchVector + ` @@ q
+				ORDER BY text_rank DESC, e.created_at DESC
+				LIMIT ` + limitArg + `
+			) matches`
+	}
+
+	semanticCTE := `SELECT NULL::text AS id, NULL::bigint AS rank, NULL::float8 AS similarity WHERE false`
+	if params.Embedding != nil {
+		vector := arg(vectorLiteral(params.Embedding))
+		maxDistance := arg(params.MaxDistance)
+		semanticCTE = `
+			SELECT id, row_number() OVER (ORDER BY distance) AS rank, 1 - distance AS similarity
+			FROM (
+				SELECT e.id, ee.embedding <=> ` + vector + `::vector AS distance
+				FROM event_embeddings ee
+				JOIN events e ON e.id = ee.event_id
+				JOIN services s ON s.id = e.service_id
+				WHERE ` + filter + `
+				ORDER BY ee.embedding <=> ` + vector + `::vector
+				LIMIT ` + limitArg + `
+			) nearest
+			WHERE distance <= ` + maxDistance
+	}
+
+	querySQL := `
+		WITH keyword AS (` + keywordCTE + `),
+		semantic AS (` + semanticCTE + `),
+		fused AS (
+			SELECT COALESCE(k.id, sm.id) AS id,
+				COALESCE(1.0 / (` + strconv.Itoa(rrfK) + ` + k.rank), 0) + COALESCE(1.0 / (` + strconv.Itoa(rrfK) + ` + sm.rank), 0) AS score,
+				k.id IS NOT NULL AS keyword_match,
+				COALESCE(sm.similarity, 0) AS similarity
+			FROM keyword k
+			FULL OUTER JOIN semantic sm ON sm.id = k.id
+		)
+		SELECT e.id, e.service_id, e.payload, e.created_at, f.score, f.keyword_match, f.similarity
+		FROM fused f
+		JOIN events e ON e.id = f.id
+		ORDER BY f.score DESC, e.created_at DESC
+		LIMIT ` + arg(params.Limit)
+
+	return c.queryEventSearchResults(querySQL, args)
+}
+
+// queryEventSearchResults runs a search query selecting id, service_id,
+// payload, created_at, score, keyword_match and similarity
+func (c *Client) queryEventSearchResults(querySQL string, args []any) ([]*servicev1.EventSearchResult, error) {
+	rows, err := c.db.Query(querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*servicev1.EventSearchResult
+	for rows.Next() {
+		var event servicev1.Event
+		var result servicev1.EventSearchResult
+		var payloadJSON string
+		var createdAt time.Time
+
+		if err := rows.Scan(
+			&event.Id,
+			&event.ServiceId,
+			&payloadJSON,
+			&createdAt,
+			&result.Score,
+			&result.KeywordMatch,
+			&result.Similarity,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		payload, err := jsonToProtoStruct(payloadJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert payload: %w", err)
+		}
+		event.Payload = payload
+		event.CreatedAt = timestampToProto(createdAt)
+
+		result.Event = &event
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return results, nil
+}
+
+// keywordTSQuery turns free text into a tsquery matching any of its words
+func keywordTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range words {
+		if seen[word] || len(terms) >= 32 {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return strings.Join(terms, " | ")
+}
+
+// containsPattern builds an ILIKE pattern matching text anywhere in a value
+func containsPattern(text string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(strings.TrimSpace(text)) + "%"
+}
+
+// vectorLiteral formats a vector in pgvector's text format
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
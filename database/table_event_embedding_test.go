@@ -0,0 +1,71 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeywordTSQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"person", "person"},
+		{"Red car at the GATE", "red | car | at | the | gate"},
+		// Operators and quotes of the tsquery syntax are separators
+		{"car & !truck | (van) 'bus':*", "car | truck | van | bus"},
+		{"car car CAR", "car"},
+		{"door-bell 2nd", "door | bell | 2nd"},
+		{"fiets straße", "fiets | straße"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, keywordTSQuery(tt.text), tt.text)
+	}
+}
+
+func TestKeywordTSQueryLimitsTerms(t *testing.T) {
+	var words []string
+	for i := 0; i < 40; i++ {
+		words = append(words, "w"+strings.Repeat("x", i))
+	}
+	terms := strings.Split(keywordTSQuery(strings.Join(words, " ")), " | ")
+	assert.Len(t, terms, 32)
+	assert.Equal(t, "w", terms[0])
+}
+
+func TestVectorLiteral(t *testing.T) {
+	assert.Equal(t, "[]", vectorLiteral(nil))
+	assert.Equal(t, "[1]", vectorLiteral([]float32{1}))
+	assert.Equal(t, "[0.5,-0.25,0,3.1415927]", vectorLiteral([]float32{0.5, -0.25, 0, 3.14159265}))
+	// Small values are written out in full, without an exponent
+	assert.Equal(t, "[0.0000001,120000]", vectorLiteral([]float32{1e-7, 1.2e5}))
+}
+
+func TestContainsPattern(t *testing.T) {
+	assert.Equal(t, "%garage%", containsPattern(" garage "))
+	assert.Equal(t, `%100\%\_a\\b%`, containsPattern(`100%_a\b`))
+}
+
+func TestEventSearchVector(t *testing.T) {
+	assert.True(t, strings.HasPrefix(eventSearchVector, "CASE WHEN "))
+	assert.True(t, strings.HasSuffix(eventSearchVector, " END"))
+	for _, c := range textSearchConfigs {
+		assert.Contains(t, eventSearchVector, "to_tsvector('"+c.config+"', ")
+	}
+	assert.Contains(t, eventSearchVector, "ELSE to_tsvector('simple', ")
+
+	// Languages by English name, native name or code; no language is English
+	assert.Contains(t, eventSearchVector, "CASE WHEN lower(COALESCE(payload->>'language', '')) = '' THEN to_tsvector('english', ")
+	assert.Contains(t, eventSearchVector, "IN ('german', 'de', 'deutsch') THEN to_tsvector('german', ")
+
+	// The query is parsed with every configuration the vector may use
+	query := eventSearchQuery("$1")
+	assert.Contains(t, query, "to_tsquery('simple', $1)")
+	for _, c := range textSearchConfigs {
+		assert.Contains(t, query, "to_tsquery('"+c.config+"', $1)")
+	}
+}
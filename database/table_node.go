@@ -0,0 +1,195 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+const (
+	createNodeTablesSQL = `
+		CREATE TABLE IF NOT EXISTS nodes (
+			id TEXT PRIMARY KEY,
+			hostname TEXT NOT NULL DEFAULT '',
+			display_name TEXT NOT NULL DEFAULT '',
+			mac_addresses JSONB NOT NULL DEFAULT '[]',
+			node_version TEXT NOT NULL DEFAULT '',
+			protocol_version INTEGER NOT NULL DEFAULT 0,
+			first_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`
+
+	dropNodeTablesSQL = `DROP TABLE IF EXISTS nodes CASCADE`
+)
+
+// UpsertNode records a node registration, keeping its display name and first-seen time
+func (c *Client) UpsertNode(id, hostname string, macAddresses []string, nodeVersion string, protocolVersion int) error {
+	if macAddresses == nil {
+		macAddresses = []string{}
+	}
+	macsJSON, err := json.Marshal(macAddresses)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mac addresses: %w", err)
+	}
+
+	upsertSQL := `
+		INSERT INTO nodes (id, hostname, mac_addresses, node_version, protocol_version)
+		VALUES ($1, $2, $3::jsonb, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			hostname = EXCLUDED.hostname,
+			mac_addresses = EXCLUDED.mac_addresses,
+			node_version = EXCLUDED.node_version,
+			protocol_version = EXCLUDED.protocol_version,
+			last_seen_at = CURRENT_TIMESTAMP
+	`
+
+	_, err = c.db.Exec(upsertSQL, id, hostname, string(macsJSON), nodeVersion, protocolVersion)
+	if err != nil {
+		return fmt.Errorf("failed to upsert node: %w", err)
+	}
+
+	return nil
+}
+
+// TouchNode sets a node's last seen time to now
+func (c *Client) TouchNode(id string) error {
+	updateSQL := `UPDATE nodes SET last_seen_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := c.db.Exec(updateSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to update node last seen: %w", err)
+	}
+
+	return nil
+}
+
+// SetNodeDisplayName sets the user-facing name of a node (empty = use hostname)
+func (c *Client) SetNodeDisplayName(id, displayName string) error {
+	upsertSQL := `
+		INSERT INTO nodes (id, display_name)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET display_name = EXCLUDED.display_name
+	`
+
+	_, err := c.db.Exec(upsertSQL, id, displayName)
+	if err != nil {
+		return fmt.Errorf("failed to set node display name: %w", err)
+	}
+
+	return nil
+}
+
+// GetNode retrieves the stored inventory of a node, or nil if it never registered.
+// Live fields (online, active bridges) are left unset.
+func (c *Client) GetNode(id string) (*servicev1.Node, error) {
+	querySQL := `
+		SELECT n.id, n.hostname, n.display_name, n.mac_addresses, n.node_version, n.protocol_version,
+			n.first_seen_at, n.last_seen_at,
+			(SELECT COUNT(*) FROM services s WHERE s.node_id = n.id),
+			(SELECT MAX(t.issued_at) FROM node_tokens t WHERE t.node_id = n.id AND t.revoked_at IS NULL),
+			n.revoked_at
+		FROM nodes n
+		WHERE n.id = $1
+	`
+
+	node, err := scanNode(c.db.QueryRow(querySQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	return node, nil
+}
+
+// ListUserNodeInventory returns the stored inventory of every node associated
+// with a user. Nodes that have not registered since the inventory was added
+// only carry their ID and service count.
+func (c *Client) ListUserNodeInventory(userID string) ([]*servicev1.Node, error) {
+	querySQL := `
+		SELECT un.node_id, COALESCE(n.hostname, ''), COALESCE(n.display_name, ''),
+			COALESCE(n.mac_addresses, '[]'::jsonb), COALESCE(n.node_version, ''), COALESCE(n.protocol_version, 0),
+			n.first_seen_at, n.last_seen_at,
+			(SELECT COUNT(*) FROM services s WHERE s.node_id = un.node_id),
+			(SELECT MAX(t.issued_at) FROM node_tokens t WHERE t.node_id = un.node_id AND t.revoked_at IS NULL),
+			n.revoked_at
+		FROM user_node un
+		LEFT JOIN nodes n ON n.id = un.node_id
+		WHERE un.user_id = $1
+		ORDER BY un.created_at DESC
+	`
+
+	rows, err := c.db.Query(querySQL, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*servicev1.Node
+	for rows.Next() {
+		node, err := scanNode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan node: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNode(row rowScanner) (*servicev1.Node, error) {
+	var node servicev1.Node
+	var macsJSON []byte
+	var firstSeen, lastSeen, tokenIssued, revoked sql.NullTime
+	var serviceCount int64
+
+	if err := row.Scan(
+		&node.Id,
+		&node.Hostname,
+		&node.DisplayName,
+		&macsJSON,
+		&node.NodeVersion,
+		&node.ProtocolVersion,
+		&firstSeen,
+		&lastSeen,
+		&serviceCount,
+		&tokenIssued,
+		&revoked,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(macsJSON) > 0 {
+		if err := json.Unmarshal(macsJSON, &node.MacAddresses); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal mac addresses: %w", err)
+		}
+	}
+	if firstSeen.Valid {
+		node.FirstSeen = timestampToProto(firstSeen.Time)
+	}
+	if lastSeen.Valid {
+		node.LastSeen = timestampToProto(lastSeen.Time)
+	}
+	if tokenIssued.Valid {
+		node.TokenIssuedAt = timestampToProto(tokenIssued.Time)
+	}
+	if revoked.Valid {
+		node.RevokedAt = timestampToProto(revoked.Time)
+	}
+	node.ServiceCount = int32(serviceCount)
+
+	return &node, nil
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	createNodeTokenTablesSQL = `
+		CREATE TABLE IF NOT EXISTS node_tokens (
+			id TEXT PRIMARY KEY,
+			node_id TEXT NOT NULL,
+			issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP,
+			revoked_at TIMESTAMP,
+			replaced_by TEXT
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_node_tokens_node_id ON node_tokens(node_id);
+
+		ALTER TABLE nodes ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+	`
+
+	dropNodeTokenTablesSQL = `DROP TABLE IF EXISTS node_tokens CASCADE`
+)
+
+// CreateNodeToken records a token issued to a node (zero expiresAt = never expires)
+func (c *Client) CreateNodeToken(id, nodeID string, issuedAt, expiresAt time.Time) error {
+	insertSQL := `
+		INSERT INTO node_tokens (id, node_id, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := c.db.Exec(insertSQL, id, nodeID, issuedAt, nullTime(expiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to create node token: %w", err)
+	}
+
+	return nil
+}
+
+// RotateNodeToken records a new token for a node and revokes the one it
+// replaces once overlap has passed, so the node keeps access if it could not
+// store the new token
+func (c *Client) RotateNodeToken(oldID, newID, nodeID string, issuedAt, expiresAt time.Time, overlap time.Duration) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertSQL := `
+		INSERT INTO node_tokens (id, node_id, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.Exec(insertSQL, newID, nodeID, issuedAt, nullTime(expiresAt)); err != nil {
+		return fmt.Errorf("failed to create node token: %w", err)
+	}
+
+	updateSQL := `
+		UPDATE node_tokens SET revoked_at = CURRENT_TIMESTAMP + make_interval(secs => $4), replaced_by = $1
+		WHERE id = $2 AND node_id = $3 AND (revoked_at IS NULL OR revoked_at > CURRENT_TIMESTAMP)
+	`
+	if _, err := tx.Exec(updateSQL, newID, oldID, nodeID, overlap.Seconds()); err != nil {
+		return fmt.Errorf("failed to revoke replaced node token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsNodeTokenActive reports whether a token was issued to a node and not
+// revoked (a replaced token stays active until its overlap has passed).
+// Expiry is checked on the token itself.
+func (c *Client) IsNodeTokenActive(id, nodeID string) (bool, error) {
+	var active bool
+	checkSQL := `
+		SELECT EXISTS(
+			SELECT 1 FROM node_tokens
+			WHERE id = $1 AND node_id = $2 AND (revoked_at IS NULL OR revoked_at > CURRENT_TIMESTAMP)
+		)
+	`
+	if err := c.db.QueryRow(checkSQL, id, nodeID).Scan(&active); err != nil {
+		return false, fmt.Errorf("failed to check node token: %w", err)
+	}
+
+	return active, nil
+}
+
+// RevokeReplacedNodeTokens ends the overlap of the tokens replaced by id, once
+// the node has shown it stored the new token by using it
+func (c *Client) RevokeReplacedNodeTokens(id, nodeID string) error {
+	updateSQL := `
+		UPDATE node_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE replaced_by = $1 AND node_id = $2 AND revoked_at > CURRENT_TIMESTAMP
+	`
+	if _, err := c.db.Exec(updateSQL, id, nodeID); err != nil {
+		return fmt.Errorf("failed to revoke replaced node tokens: %w", err)
+	}
+
+	return nil
+}
+
+// FirstNodeTokenIssuedAt returns when the first recorded token of a node was
+// issued (zero if the node has none)
+func (c *Client) FirstNodeTokenIssuedAt(nodeID string) (time.Time, error) {
+	var issuedAt sql.NullTime
+	querySQL := `SELECT MIN(issued_at) FROM node_tokens WHERE node_id = $1`
+	if err := c.db.QueryRow(querySQL, nodeID).Scan(&issuedAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get first node token: %w", err)
+	}
+
+	return issuedAt.Time, nil
+}
+
+// IsNodeRevoked reports whether a node's tokens were revoked by its owner
+func (c *Client) IsNodeRevoked(nodeID string) (bool, error) {
+	var revoked bool
+	checkSQL := `SELECT EXISTS(SELECT 1 FROM nodes WHERE id = $1 AND revoked_at IS NOT NULL)`
+	if err := c.db.QueryRow(checkSQL, nodeID).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check node revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// RevokeNodeTokens revokes every active token of a node and marks the node as
+// revoked, so it cannot get new ones. Returns the number of tokens revoked.
+func (c *Client) RevokeNodeTokens(nodeID string) (int64, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateSQL := `
+		UPDATE node_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE node_id = $1 AND (revoked_at IS NULL OR revoked_at > CURRENT_TIMESTAMP)
+	`
+	result, err := tx.Exec(updateSQL, nodeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke node tokens: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	upsertSQL := `
+		INSERT INTO nodes (id, revoked_at)
+		VALUES ($1, CURRENT_TIMESTAMP)
+		ON CONFLICT (id) DO UPDATE SET revoked_at = COALESCE(nodes.revoked_at, CURRENT_TIMESTAMP)
+	`
+	if _, err := tx.Exec(upsertSQL, nodeID); err != nil {
+		return 0, fmt.Errorf("failed to mark node revoked: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return count, nil
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
+
 	servicev1 "unblink/server/gen/service/v1"
 )
 
@@ -15,10 +17,15 @@ const (
 			name TEXT NOT NULL,
 			url TEXT NOT NULL,
 			node_id TEXT NOT NULL,
+			recording_retention_hours INTEGER NOT NULL DEFAULT 0,
+			analysis_settings JSONB NOT NULL DEFAULT '{}',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
 
+		ALTER TABLE services ADD COLUMN IF NOT EXISTS recording_retention_hours INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE services ADD COLUMN IF NOT EXISTS analysis_settings JSONB NOT NULL DEFAULT '{}';
+
 		CREATE INDEX IF NOT EXISTS idx_services_node_id ON services(node_id);
 	`
 
@@ -96,10 +103,62 @@ func (c *Client) UpdateService(id, name, url string) error {
 	return nil
 }
 
+// SetServiceRecordingRetention sets how long recorded segments are kept for a service (0 = server default)
+func (c *Client) SetServiceRecordingRetention(id string, hours int32) error {
+	updateSQL := `
+		UPDATE services
+		SET recording_retention_hours = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	_, err := c.db.Exec(updateSQL, hours, id)
+	if err != nil {
+		return fmt.Errorf("failed to update service recording retention: %w", err)
+	}
+
+	return nil
+}
+
+// SetServiceAnalysisSettings replaces the analysis settings of a service
+func (c *Client) SetServiceAnalysisSettings(id string, settings *servicev1.AnalysisSettings) error {
+	if settings == nil {
+		settings = &servicev1.AnalysisSettings{}
+	}
+	settingsJSON, err := protojson.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis settings: %w", err)
+	}
+
+	updateSQL := `
+		UPDATE services
+		SET analysis_settings = $1::jsonb, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	_, err = c.db.Exec(updateSQL, string(settingsJSON), id)
+	if err != nil {
+		return fmt.Errorf("failed to update service analysis settings: %w", err)
+	}
+
+	return nil
+}
+
+// analysisSettingsFromJSON decodes stored analysis settings
+func analysisSettingsFromJSON(data []byte) (*servicev1.AnalysisSettings, error) {
+	settings := &servicev1.AnalysisSettings{}
+	if len(data) == 0 {
+		return settings, nil
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal analysis settings: %w", err)
+	}
+	return settings, nil
+}
+
 // GetService retrieves a service by ID (no authorization check - use with DeleteService)
 func (c *Client) GetService(id string) (*servicev1.Service, error) {
 	querySQL := `
-		SELECT s.id, s.name, s.url, s.node_id, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.url, s.node_id, s.recording_retention_hours, s.analysis_settings, s.created_at, s.updated_at
 		FROM services s
 		WHERE s.id = $1
 	`
@@ -107,6 +166,7 @@ func (c *Client) GetService(id string) (*servicev1.Service, error) {
 	var svc servicev1.Service
 	var name, url sql.NullString
 	var svcNodeID sql.NullString
+	var analysis []byte
 	var createdAt, updatedAt time.Time
 
 	err := c.db.QueryRow(querySQL, id).Scan(
@@ -114,6 +174,8 @@ func (c *Client) GetService(id string) (*servicev1.Service, error) {
 		&name,
 		&url,
 		&svcNodeID,
+		&svc.RecordingRetentionHours,
+		&analysis,
 		&createdAt,
 		&updatedAt,
 	)
@@ -135,6 +197,9 @@ func (c *Client) GetService(id string) (*servicev1.Service, error) {
 		svc.NodeId = svcNodeID.String
 	}
 
+	if svc.Analysis, err = analysisSettingsFromJSON(analysis); err != nil {
+		return nil, err
+	}
 	svc.CreatedAt = timestampToProto(createdAt)
 	svc.UpdatedAt = timestampToProto(updatedAt)
 
@@ -156,7 +221,7 @@ func (c *Client) DeleteService(id string) error {
 // ListServicesByNodeId retrieves all services for a node
 func (c *Client) ListServicesByNodeId(nodeID string) ([]*servicev1.Service, error) {
 	querySQL := `
-		SELECT id, name, url, node_id, created_at, updated_at
+		SELECT id, name, url, node_id, recording_retention_hours, analysis_settings, created_at, updated_at
 		FROM services
 		WHERE node_id = $1
 		ORDER BY created_at DESC
@@ -173,6 +238,7 @@ func (c *Client) ListServicesByNodeId(nodeID string) ([]*servicev1.Service, erro
 	for rows.Next() {
 		var svc servicev1.Service
 		var name, url, svcNodeID sql.NullString
+		var analysis []byte
 		var createdAt, updatedAt time.Time
 
 		if err := rows.Scan(
@@ -180,6 +246,8 @@ func (c *Client) ListServicesByNodeId(nodeID string) ([]*servicev1.Service, erro
 			&name,
 			&url,
 			&svcNodeID,
+			&svc.RecordingRetentionHours,
+			&analysis,
 			&createdAt,
 			&updatedAt,
 		); err != nil {
@@ -196,6 +264,11 @@ func (c *Client) ListServicesByNodeId(nodeID string) ([]*servicev1.Service, erro
 			svc.NodeId = svcNodeID.String
 		}
 
+		settings, err := analysisSettingsFromJSON(analysis)
+		if err != nil {
+			return nil, err
+		}
+		svc.Analysis = settings
 		svc.CreatedAt = timestampToProto(createdAt)
 		svc.UpdatedAt = timestampToProto(updatedAt)
 
@@ -212,7 +285,7 @@ func (c *Client) ListServicesByNodeId(nodeID string) ([]*servicev1.Service, erro
 // ListAllServices retrieves all services for registry initialization
 func (c *Client) ListAllServices() ([]*servicev1.Service, error) {
 	querySQL := `
-		SELECT s.id, s.name, s.url, s.node_id, s.created_at, s.updated_at
+		SELECT s.id, s.name, s.url, s.node_id, s.recording_retention_hours, s.analysis_settings, s.created_at, s.updated_at
 		FROM services s
 		ORDER BY s.created_at DESC
 	`
@@ -228,6 +301,7 @@ func (c *Client) ListAllServices() ([]*servicev1.Service, error) {
 	for rows.Next() {
 		var svc servicev1.Service
 		var name, url, svcNodeID sql.NullString
+		var analysis []byte
 		var createdAt, updatedAt time.Time
 
 		if err := rows.Scan(
@@ -235,6 +309,8 @@ func (c *Client) ListAllServices() ([]*servicev1.Service, error) {
 			&name,
 			&url,
 			&svcNodeID,
+			&svc.RecordingRetentionHours,
+			&analysis,
 			&createdAt,
 			&updatedAt,
 		); err != nil {
@@ -251,6 +327,11 @@ func (c *Client) ListAllServices() ([]*servicev1.Service, error) {
 			svc.NodeId = svcNodeID.String
 		}
 
+		settings, err := analysisSettingsFromJSON(analysis)
+		if err != nil {
+			return nil, err
+		}
+		svc.Analysis = settings
 		svc.CreatedAt = timestampToProto(createdAt)
 		svc.UpdatedAt = timestampToProto(updatedAt)
 
@@ -13,7 +13,10 @@ import (
 type StorageType string
 
 const (
-	StorageTypeFrame StorageType = "frame" // JPEG video frame
+	StorageTypeFrame    StorageType = "frame"    // JPEG video frame
+	StorageTypeSnapshot StorageType = "snapshot" // Annotated JPEG of a live snapshot, not a recorded frame
+	StorageTypeSegment  StorageType = "segment"  // MP4 recording segment
+	StorageTypeClip     StorageType = "clip"     // MP4 clip exported from segments
 )
 
 // FrameMetadata holds additional metadata for frames
@@ -22,6 +25,19 @@ type FrameMetadata struct {
 	Height int `json:"height,omitempty"`
 }
 
+// SegmentMetadata holds additional metadata for recording segments
+type SegmentMetadata struct {
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// ClipMetadata holds additional metadata for exported clips
+type ClipMetadata struct {
+	EventID    string `json:"event_id,omitempty"`
+	From       string `json:"from"` // ISO 8601, including pre-roll
+	To         string `json:"to"`   // ISO 8601, including post-roll
+	DurationMs int64  `json:"duration_ms"`
+}
+
 // StorageEntry represents a row in the storage table
 type StorageEntry struct {
 	ID          string
@@ -35,15 +51,15 @@ type StorageEntry struct {
 	Metadata    string // JSON-encoded metadata
 }
 
-// SaveStorageItem saves storage item metadata to the database
-func (c *Client) SaveStorageItem(serviceID, storagePath string, timestamp time.Time, fileSize int64, storageType StorageType, contentType string, metadata *FrameMetadata) error {
+// SaveStorageItem saves storage item metadata to the database and returns the new item ID
+func (c *Client) SaveStorageItem(serviceID, storagePath string, timestamp time.Time, fileSize int64, storageType StorageType, contentType string, metadata any) (string, error) {
 	id := uuid.New().String()
 
 	var metadataJSON sql.NullString
 	if metadata != nil {
 		metadataBytes, err := json.Marshal(metadata)
 		if err != nil {
-			return fmt.Errorf("failed to marshal storage metadata: %w", err)
+			return "", fmt.Errorf("failed to marshal storage metadata: %w", err)
 		}
 		metadataJSON = sql.NullString{String: string(metadataBytes), Valid: true}
 	}
@@ -57,22 +73,34 @@ func (c *Client) SaveStorageItem(serviceID, storagePath string, timestamp time.T
 		id, serviceID, storageType, storagePath, timestamp, fileSize, contentType, metadataJSON,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to save storage item: %w", err)
+		return "", fmt.Errorf("failed to save storage item: %w", err)
 	}
 
-	return nil
+	return id, nil
 }
 
-// ListStorageItemsForService lists storage entries for a service from the database
-func (c *Client) ListStorageItemsForService(serviceID string, storageType string, limit, offset int64) ([]*StorageEntry, int64, error) {
-	// Get total count
-	var total int64
-	countSQL := `SELECT COUNT(*) FROM storage WHERE service_id = $1`
+// ListStorageItemsForService lists storage entries for a service from the database, newest first.
+// Zero from/to leave the time range open.
+func (c *Client) ListStorageItemsForService(serviceID string, storageType string, from, to time.Time, limit, offset int64) ([]*StorageEntry, int64, error) {
+	filterSQL := ` WHERE service_id = $1`
+	args := []any{serviceID}
+
 	if storageType != "" {
-		countSQL += ` AND type = $2`
+		args = append(args, storageType)
+		filterSQL += fmt.Sprintf(` AND type = $%d`, len(args))
+	}
+	if !from.IsZero() {
+		args = append(args, from)
+		filterSQL += fmt.Sprintf(` AND timestamp >= $%d`, len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		filterSQL += fmt.Sprintf(` AND timestamp < $%d`, len(args))
 	}
 
-	err := c.db.QueryRow(countSQL, serviceID, storageType).Scan(&total)
+	// Get total count
+	var total int64
+	err := c.db.QueryRow(`SELECT COUNT(*) FROM storage`+filterSQL, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count storage entries: %w", err)
 	}
@@ -81,18 +109,8 @@ func (c *Client) ListStorageItemsForService(serviceID string, storageType string
 	querySQL := `
 		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
 		FROM storage
-		WHERE service_id = $1
-	`
-	args := []any{serviceID}
-
-	argOffset := 2
-	if storageType != "" {
-		querySQL += fmt.Sprintf(` AND type = $%d`, argOffset)
-		args = append(args, storageType)
-		argOffset++
-	}
-
-	querySQL += fmt.Sprintf(` ORDER BY timestamp DESC LIMIT $%d OFFSET $%d`, argOffset, argOffset+1)
+	` + filterSQL
+	querySQL += fmt.Sprintf(` ORDER BY timestamp DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
 	args = append(args, limit, offset)
 
 	rows, err := c.db.Query(querySQL, args...)
@@ -172,28 +190,191 @@ func (c *Client) GetStorageItemInfo(itemID string) (*StorageEntry, error) {
 	return &entry, nil
 }
 
-// DeleteOldStorageItems deletes storage entries older than the specified duration
-func (c *Client) DeleteOldStorageItems(serviceID string, storageType string, olderThanSeconds int64) (int64, error) {
-	deleteSQL := `
-		DELETE FROM storage
+// ListOldStorageItems lists up to limit storage entries of a service created
+// before the cutoff, oldest first. An empty storageType matches all types.
+func (c *Client) ListOldStorageItems(serviceID string, storageType string, cutoff time.Time, limit int) ([]*StorageEntry, error) {
+	querySQL := `
+		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
+		FROM storage
 		WHERE service_id = $1
-		AND type = $2
+		AND ($2::text = '' OR type = $2)
 		AND created_at < $3
+		ORDER BY created_at ASC
+		LIMIT $4
+	`
+
+	rows, err := c.db.Query(querySQL, serviceID, storageType, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list old storage entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanStorageEntries(rows)
+}
+
+// ListExpiredStorageItems lists storage entries of a type whose timestamp is before the cutoff
+func (c *Client) ListExpiredStorageItems(serviceID string, storageType StorageType, cutoff time.Time) ([]*StorageEntry, error) {
+	querySQL := `
+		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
+		FROM storage
+		WHERE service_id = $1
+		AND type = $2
+		AND timestamp < $3
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := c.db.Query(querySQL, serviceID, storageType, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired storage entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanStorageEntries(rows)
+}
+
+// ListSegmentsInRange lists recording segments that overlap [from, to), oldest first.
+// A segment's end is derived from its duration_ms metadata.
+func (c *Client) ListSegmentsInRange(serviceID string, from, to time.Time) ([]*StorageEntry, error) {
+	querySQL := `
+		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
+		FROM storage
+		WHERE service_id = $1
+		AND type = $2
+		AND timestamp < $4
+		AND timestamp + COALESCE((metadata::jsonb->>'duration_ms')::bigint, 0) * INTERVAL '1 millisecond' > $3
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := c.db.Query(querySQL, serviceID, StorageTypeSegment, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanStorageEntries(rows)
+}
+
+// ListFramesInRange lists up to limit frames captured within [from, to], newest first
+func (c *Client) ListFramesInRange(serviceID string, from, to time.Time, limit int) ([]*StorageEntry, error) {
+	querySQL := `
+		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
+		FROM storage
+		WHERE service_id = $1
+		AND type = $2
+		AND timestamp >= $3
+		AND timestamp <= $4
+		ORDER BY timestamp DESC, created_at DESC
+		LIMIT $5
 	`
 
-	cutoffTime := time.Now().Add(-time.Duration(olderThanSeconds) * time.Second)
+	rows, err := c.db.Query(querySQL, serviceID, StorageTypeFrame, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list frames: %w", err)
+	}
+	defer rows.Close()
+
+	return scanStorageEntries(rows)
+}
 
-	result, err := c.db.Exec(deleteSQL, serviceID, storageType, cutoffTime)
+// SampleFramesInRange picks up to n frames captured within [from, to), spread
+// evenly over all frames of the range, oldest first. It also returns the number
+// of frames in the range.
+func (c *Client) SampleFramesInRange(serviceID string, from, to time.Time, n int) ([]*StorageEntry, int64, error) {
+	var total int64
+	countSQL := `
+		SELECT COUNT(*)
+		FROM storage
+		WHERE service_id = $1
+		AND type = $2
+		AND timestamp >= $3
+		AND timestamp < $4
+	`
+	if err := c.db.QueryRow(countSQL, serviceID, StorageTypeFrame, from, to).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count frames: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// ntile splits the frames into n groups of (nearly) equal size in time
+	// order; the first frame of every group is picked
+	querySQL := `
+		SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata
+		FROM (
+			SELECT DISTINCT ON (tile) *
+			FROM (
+				SELECT id, service_id, type, storage_path, timestamp, file_size, content_type, created_at, metadata,
+					ntile($5) OVER (ORDER BY timestamp, id) AS tile
+				FROM storage
+				WHERE service_id = $1
+				AND type = $2
+				AND timestamp >= $3
+				AND timestamp < $4
+			) tiled
+			ORDER BY tile, timestamp, id
+		) sampled
+		ORDER BY timestamp ASC, id
+	`
+
+	rows, err := c.db.Query(querySQL, serviceID, StorageTypeFrame, from, to, n)
 	if err != nil {
-		return 0, fmt.Errorf("failed to delete old storage entries: %w", err)
+		return nil, 0, fmt.Errorf("failed to sample frames: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	entries, err := scanStorageEntries(rows)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// DeleteStorageItem deletes a storage entry by ID
+func (c *Client) DeleteStorageItem(itemID string) error {
+	deleteSQL := `DELETE FROM storage WHERE id = $1`
+
+	_, err := c.db.Exec(deleteSQL, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to delete storage entry: %w", err)
+	}
+
+	return nil
+}
+
+// scanStorageEntries scans storage rows into entries
+func scanStorageEntries(rows *sql.Rows) ([]*StorageEntry, error) {
+	var entries []*StorageEntry
+	for rows.Next() {
+		var entry StorageEntry
+		var metadata sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ServiceID,
+			&entry.Type,
+			&entry.StoragePath,
+			&entry.Timestamp,
+			&entry.FileSize,
+			&entry.ContentType,
+			&entry.CreatedAt,
+			&metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan storage entry: %w", err)
+		}
+
+		if metadata.Valid {
+			entry.Metadata = metadata.String
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage entries: %w", err)
 	}
 
-	return rowsAffected, nil
+	return entries, nil
 }
 
 // DeleteStorageByPath deletes a storage entry by its file path
@@ -0,0 +1,445 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+// Webhook delivery states (webhook_deliveries.status)
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+const (
+	createWebhookTablesSQL = `
+		CREATE TABLE IF NOT EXISTS webhooks (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			node_id TEXT NOT NULL,
+			service_id TEXT REFERENCES services(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			event_types JSONB NOT NULL DEFAULT '[]',
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id);
+		CREATE INDEX IF NOT EXISTS idx_webhooks_node_id ON webhooks(node_id);
+
+		-- Outbox: one row per event and matching webhook, written with the event
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+			webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+			event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			event_type TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_status_code INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_attempt_at TIMESTAMP,
+			delivered_at TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+	`
+
+	dropWebhookTablesSQL = `DROP TABLE IF EXISTS webhook_deliveries, webhooks CASCADE`
+
+	webhookColumns         = `id, node_id, service_id, url, event_types, enabled, created_at, updated_at`
+	webhookDeliveryColumns = `id, webhook_id, event_id, event_type, status, attempts, last_status_code, last_error, created_at, next_attempt_at, last_attempt_at, delivered_at`
+)
+
+// WebhookJob is a claimed delivery together with what is needed to send it
+type WebhookJob struct {
+	DeliveryID string
+	Attempts   int32 // Attempts made before this one
+	URL        string
+	Secret     string
+	NodeID     string
+	Event      *servicev1.Event
+}
+
+// CreateWebhook stores a new webhook subscription owned by a user
+func (c *Client) CreateWebhook(userID, secret string, webhook *servicev1.Webhook) error {
+	eventTypes, err := json.Marshal(nonNilStrings(webhook.EventTypes))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event types: %w", err)
+	}
+
+	insertSQL := `
+		INSERT INTO webhooks (id, user_id, node_id, service_id, url, secret, event_types, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8)
+	`
+
+	_, err = c.db.Exec(insertSQL, webhook.Id, userID, webhook.NodeId, nullString(webhook.ServiceId), webhook.Url, secret, string(eventTypes), webhook.Enabled)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWebhook updates the URL, event types and enabled state of a webhook
+func (c *Client) UpdateWebhook(webhook *servicev1.Webhook) error {
+	eventTypes, err := json.Marshal(nonNilStrings(webhook.EventTypes))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event types: %w", err)
+	}
+
+	updateSQL := `
+		UPDATE webhooks
+		SET url = $1, event_types = $2::jsonb, enabled = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`
+
+	_, err = c.db.Exec(updateSQL, webhook.Url, string(eventTypes), webhook.Enabled, webhook.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhook retrieves a webhook owned by a user, or nil if there is none
+func (c *Client) GetWebhook(id, userID string) (*servicev1.Webhook, error) {
+	querySQL := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	webhook, err := scanWebhook(c.db.QueryRow(querySQL, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (c *Client) DeleteWebhook(id string) error {
+	deleteSQL := `DELETE FROM webhooks WHERE id = $1`
+
+	_, err := c.db.Exec(deleteSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhooksByUser retrieves a user's webhooks, optionally only those of one node
+func (c *Client) ListWebhooksByUser(userID, nodeID string) ([]*servicev1.Webhook, error) {
+	querySQL := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND ($2 = '' OR node_id = $2)
+		ORDER BY created_at
+	`
+
+	rows, err := c.db.Query(querySQL, userID, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*servicev1.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// ListWebhookDeliveries retrieves the delivery log of a webhook, newest first
+func (c *Client) ListWebhookDeliveries(webhookID, status string, pageSize, pageOffset int32) ([]*servicev1.WebhookDelivery, int32, error) {
+	// Set defaults
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	if pageOffset < 0 {
+		pageOffset = 0
+	}
+
+	var totalCount int32
+	countSQL := `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1 AND ($2 = '' OR status = $2)`
+	if err := c.db.QueryRow(countSQL, webhookID, status).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	querySQL := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := c.db.Query(querySQL, webhookID, status, pageSize, pageOffset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*servicev1.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, totalCount, nil
+}
+
+// GetWebhookDelivery retrieves a delivery by ID, or nil if it does not exist
+func (c *Client) GetWebhookDelivery(id string) (*servicev1.WebhookDelivery, error) {
+	querySQL := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(c.db.QueryRow(querySQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// RequeueWebhookDelivery makes a delivery pending again with a fresh set of attempts
+func (c *Client) RequeueWebhookDelivery(id string) error {
+	updateSQL := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := c.db.Exec(updateSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries picks up to limit due deliveries of enabled webhooks
+// and leases them: they are not due again until the lease runs out, so a
+// delivery interrupted by a restart is retried after the lease.
+func (c *Client) ClaimWebhookDeliveries(limit int, lease time.Duration) ([]*WebhookJob, error) {
+	claimSQL := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND w.enabled
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM due, webhooks w, events e
+		WHERE d.id = due.id AND w.id = d.webhook_id AND e.id = d.event_id
+		RETURNING d.id, d.attempts, w.url, w.secret, w.node_id, e.id, e.service_id, e.payload, e.created_at
+	`
+
+	rows, err := c.db.Query(claimSQL, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*WebhookJob
+	for rows.Next() {
+		job := &WebhookJob{Event: &servicev1.Event{}}
+		var payloadJSON string
+		var createdAt time.Time
+
+		if err := rows.Scan(
+			&job.DeliveryID,
+			&job.Attempts,
+			&job.URL,
+			&job.Secret,
+			&job.NodeID,
+			&job.Event.Id,
+			&job.Event.ServiceId,
+			&payloadJSON,
+			&createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		payload, err := jsonToProtoStruct(payloadJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert payload: %w", err)
+		}
+		job.Event.Payload = payload
+		job.Event.CreatedAt = timestampToProto(createdAt)
+
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// MarkWebhookDelivered records a successful attempt
+func (c *Client) MarkWebhookDelivered(id string, statusCode int) error {
+	updateSQL := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, last_status_code = $1, last_error = '',
+			last_attempt_at = CURRENT_TIMESTAMP, delivered_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	_, err := c.db.Exec(updateSQL, statusCode, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery delivered: %w", err)
+	}
+
+	return nil
+}
+
+// MarkWebhookFailed records a failed attempt and schedules the next one after
+// retryIn, or moves the delivery to the dead-letter state when dead is set
+func (c *Client) MarkWebhookFailed(id string, statusCode int, errMsg string, retryIn time.Duration, dead bool) error {
+	status := WebhookDeliveryPending
+	if dead {
+		status = WebhookDeliveryDead
+	}
+
+	updateSQL := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, last_status_code = $2, last_error = $3,
+			last_attempt_at = CURRENT_TIMESTAMP, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $4)
+		WHERE id = $5
+	`
+
+	_, err := c.db.Exec(updateSQL, status, statusCode, errMsg, retryIn.Seconds(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook delivery failed: %w", err)
+	}
+
+	return nil
+}
+
+// PruneWebhookDeliveries deletes delivered entries older than maxAge
+func (c *Client) PruneWebhookDeliveries(maxAge time.Duration) (int64, error) {
+	deleteSQL := `
+		DELETE FROM webhook_deliveries
+		WHERE status = 'delivered' AND delivered_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	result, err := c.db.Exec(deleteSQL, maxAge.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanWebhook reads a row selected with webhookColumns
+func scanWebhook(row rowScanner) (*servicev1.Webhook, error) {
+	var webhook servicev1.Webhook
+	var serviceID sql.NullString
+	var eventTypes []byte
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(
+		&webhook.Id,
+		&webhook.NodeId,
+		&serviceID,
+		&webhook.Url,
+		&eventTypes,
+		&webhook.Enabled,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(eventTypes, &webhook.EventTypes); err != nil {
+		return nil, fmt.Errorf("failed to parse event types: %w", err)
+	}
+	webhook.ServiceId = serviceID.String
+	webhook.CreatedAt = timestampToProto(createdAt)
+	webhook.UpdatedAt = timestampToProto(updatedAt)
+
+	return &webhook, nil
+}
+
+// scanWebhookDelivery reads a row selected with webhookDeliveryColumns
+func scanWebhookDelivery(row rowScanner) (*servicev1.WebhookDelivery, error) {
+	var delivery servicev1.WebhookDelivery
+	var createdAt, nextAttempt time.Time
+	var lastAttempt, deliveredAt sql.NullTime
+
+	if err := row.Scan(
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&createdAt,
+		&nextAttempt,
+		&lastAttempt,
+		&deliveredAt,
+	); err != nil {
+		return nil, err
+	}
+
+	delivery.CreatedAt = timestampToProto(createdAt)
+	if delivery.Status == WebhookDeliveryPending {
+		delivery.NextAttemptAt = timestampToProto(nextAttempt)
+	}
+	if lastAttempt.Valid {
+		delivery.LastAttemptAt = timestampToProto(lastAttempt.Time)
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = timestampToProto(deliveredAt.Time)
+	}
+
+	return &delivery, nil
+}
+
+// nonNilStrings makes an empty list marshal as [] instead of null
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
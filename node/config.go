@@ -17,6 +17,14 @@ type Config struct {
 	NodeID       string        `json:"node_id"`
 	Token        string        `json:"token"`
 	Reconnect    ReconnectConf `json:"reconnect"`
+
+	// Ordered relays (primary first); replaces relay_address when set
+	RelayAddresses []string `json:"relay_addresses,omitempty"`
+	// Relay that last accepted the node, written by the node
+	CurrentRelay string `json:"current_relay,omitempty"`
+
+	// Which targets the relay may open bridges to (everything when empty)
+	BridgePolicy BridgePolicy `json:"bridge_policy"`
 }
 
 // ReconnectConf defines reconnection behavior
@@ -86,6 +94,12 @@ func Load(customPath string) (*ConfigFile, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
+	if err := cfg.validateRelays(); err != nil {
+		return nil, err
+	}
+	if err := cfg.BridgePolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("bridge_policy: %w", err)
+	}
 
 	return &ConfigFile{
 		Path:   configPath,
@@ -120,9 +134,23 @@ func (cf *ConfigFile) Show() error {
 	fmt.Println("  Path:", cf.Path)
 	fmt.Println()
 	fmt.Println("Config:")
-	fmt.Printf("  relay_address: %s\n", cf.Config.RelayAddress)
+	if len(cf.Config.RelayAddresses) > 0 {
+		fmt.Println("  relay_addresses:")
+		for i, address := range cf.Config.RelayAddresses {
+			fmt.Printf("    %d. %s\n", i+1, address)
+		}
+	} else {
+		fmt.Printf("  relay_address: %s\n", cf.Config.RelayAddress)
+	}
+	if cf.Config.CurrentRelay != "" {
+		fmt.Printf("  current_relay: %s\n", cf.Config.CurrentRelay)
+	}
 	fmt.Printf("  node_id: %s\n", cf.Config.NodeID)
 	fmt.Printf("  token: %s\n", cf.Config.Token)
+	if policy := cf.Config.BridgePolicy; len(policy.Allow) > 0 || len(policy.Deny) > 0 || policy.ServicesOnly {
+		fmt.Printf("  bridge_policy: allow=%v deny=%v services_only=%v (%d services)\n",
+			policy.Allow, policy.Deny, policy.ServicesOnly, len(policy.Services))
+	}
 	fmt.Println()
 
 	if cf.Config.Token != "" {
@@ -1,11 +1,13 @@
 package node
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"os"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -16,6 +18,13 @@ import (
 
 const MaxMessageSize = 16 * 1024 * 1024 // 16 MB
 
+// bridgeStatsInterval is how often the flow control state of open bridges is logged
+const bridgeStatsInterval = 1 * time.Minute
+
+// Version is the node software version reported to the relay.
+// Release builds set it with -ldflags "-X unblink/node.Version=...".
+var Version = "dev"
+
 // MessageTransport defines the interface for reading/writing messages
 type MessageTransport interface {
 	ReadMessage() (any, error)
@@ -25,21 +34,30 @@ type MessageTransport interface {
 
 // Conn manages the connection to the server
 type Conn struct {
-	configFile *ConfigFile // Config with its path
-	wsConn     any         // *websocket.Conn
+	configFile   *ConfigFile // Config with its path
+	relayAddress string      // Relay this connection dials
+	wsConn       any         // *websocket.Conn
 
 	// Transport
 	transport MessageTransport
 
 	// State management
-	state   ConnState
-	stateMu sync.RWMutex
+	state        ConnState
+	capabilities []string // Negotiated at registration
+	stateMu      sync.RWMutex
 
 	// Node state
-	nodeID   string
-	shutdown chan struct{}
-	closed   bool
-	closeMu  sync.Mutex
+	nodeID     string
+	shutdown   chan struct{}
+	closed     bool
+	closeMu    sync.Mutex
+	registered atomic.Bool // The relay accepted the registration
+
+	// Session resume
+	sessionID      string     // Assigned by the server on registration
+	disconnectedAt time.Time  // When the WebSocket dropped (zero while connected)
+	sessionMu      sync.Mutex // Guards sessionID and disconnectedAt
+	runErr         error      // Why the connection ended, if it was not a shutdown
 
 	// Bridges
 	bridges  map[string]*Bridge
@@ -61,16 +79,36 @@ type Bridge struct {
 	shutdown  chan struct{}
 	byteCount int64
 	msgCount  int64
+	buffered  int64 // atomic: received from the server but not yet written to the service
 	closeOnce sync.Once
+
+	// Flow control
+	sendWindow *shared.FlowWindow    // Credit for node -> server data
+	recvCredit *shared.CreditTracker // Batches window updates for server -> node data
+
+	// Resume state, guarded by mu
+	mu           sync.Mutex
+	relay        *Conn // Connection the bridge currently sends through (changes on resume)
+	paused       bool  // Holding data until the session is resumed
+	sendSeq      uint64
+	lastReceived uint64
+	retransmit   shared.RetransmitBuffer
+
+	// Datagram bridges (Transport "udp")
+	packet    *net.UDPConn  // Same socket as Conn; nil for TCP bridges
+	remoteIPs []net.IP      // Allowed service addresses; datagrams from other hosts are dropped
+	remoteIP  net.IP        // Address the service answered from, guarded by mu (nil = none yet)
+	policy    *targetPolicy // Checked for every datagram port the relay sends to
 }
 
 // NewConn creates a new node connection
 func NewConn(configFile *ConfigFile) *Conn {
 	c := &Conn{
-		configFile: configFile,
-		shutdown:   make(chan struct{}),
-		bridges:    make(map[string]*Bridge),
-		msgID:      1,
+		configFile:   configFile,
+		relayAddress: configFile.Config.initialRelay(),
+		shutdown:     make(chan struct{}),
+		bridges:      make(map[string]*Bridge),
+		msgID:        1,
 	}
 	c.state = NewDisconnectedState()
 	return c
@@ -101,9 +139,15 @@ func (c *Conn) Run() error {
 		return err
 	}
 
+	go c.logBridgeStats()
+
 	// Wait for message loop to exit (disconnection or shutdown)
 	<-c.shutdown
 
+	// Report a dropped connection so the reconnector retries
+	if c.runErr != nil {
+		return fmt.Errorf("connection closed: %w", c.runErr)
+	}
 	return nil
 }
 
@@ -111,6 +155,11 @@ func (c *Conn) Run() error {
 func (c *Conn) messageLoop() {
 	defer func() {
 		log.Printf("[Conn] Message loop ended")
+		c.sessionMu.Lock()
+		if c.disconnectedAt.IsZero() {
+			c.disconnectedAt = time.Now()
+		}
+		c.sessionMu.Unlock()
 		// Signal that message loop has ended
 		select {
 		case <-c.shutdown:
@@ -130,6 +179,11 @@ func (c *Conn) messageLoop() {
 		msg, err := c.transport.ReadMessage()
 		if err != nil {
 			log.Printf("[Conn] Read error: %v", err)
+			c.closeMu.Lock()
+			if !c.closed && c.runErr == nil {
+				c.runErr = err
+			}
+			c.closeMu.Unlock()
 			return
 		}
 
@@ -162,6 +216,9 @@ func (c *Conn) handleMessage(msg any) error {
 	case shared.MessageTypeRegisterResponse:
 		return state.OnRegisterResponse(c, msg.(*shared.RegisterResponse))
 
+	case shared.MessageTypeRotateTokenResponse:
+		return state.OnRotateTokenResponse(c, msg.(*shared.RotateTokenResponse))
+
 	case shared.MessageTypeOpenBridgeRequest:
 		return state.HandleOpenBridge(c, msg.(*shared.OpenBridgeRequest))
 
@@ -171,6 +228,12 @@ func (c *Conn) handleMessage(msg any) error {
 	case shared.MessageTypeBridgeData:
 		return state.HandleBridgeData(c, msg.(*shared.BridgeDataMessage))
 
+	case shared.MessageTypeBridgeWindowUpdate:
+		return state.HandleBridgeWindowUpdate(c, msg.(*shared.BridgeWindowUpdateMessage))
+
+	case shared.MessageTypeDiscoverRequest:
+		return state.HandleDiscover(c, msg.(*shared.DiscoverRequest))
+
 	default:
 		return fmt.Errorf("unknown message type: %s", msgType)
 	}
@@ -217,6 +280,7 @@ func (c *Conn) sendNewTokenRequest() error {
 func (c *Conn) sendRegisterRequest() error {
 	hostname, macs, _ := getSystemInfo()
 
+	sessionID, _ := c.session()
 	msg := &shared.RegisterRequest{
 		Message: shared.Message{
 			Type: shared.MessageTypeRegisterRequest,
@@ -226,12 +290,86 @@ func (c *Conn) sendRegisterRequest() error {
 		Token:        c.configFile.Config.Token,
 		Hostname:     hostname,
 		MACAddresses: macs,
+		NodeVersion:  Version,
+
+		ProtocolVersion:    shared.ProtocolVersion,
+		MinProtocolVersion: shared.MinProtocolVersion,
+		Capabilities:       shared.SupportedCapabilities,
+
+		SessionID: sessionID,
+		Bridges:   c.bridgeResumeStates(),
+	}
+
+	if msg.SessionID != "" {
+		log.Printf("[Conn] Sending register_request: nodeID=%s, resuming session %s with %d bridges", c.configFile.Config.NodeID, msg.SessionID, len(msg.Bridges))
+		return c.transport.WriteMessage(msg)
 	}
 
 	log.Printf("[Conn] Sending register_request: nodeID=%s", c.configFile.Config.NodeID)
 	return c.transport.WriteMessage(msg)
 }
 
+// ============================================================
+// Token Rotation
+// ============================================================
+
+// tokenRotationRetryDelay is how long to keep the current token after a failed rotation
+const tokenRotationRetryDelay = 1 * time.Hour
+
+// scheduleTokenRotation asks the relay for a new token at rotateAt (unix
+// seconds, 0 = never), or right away if that time has passed
+func (c *Conn) scheduleTokenRotation(rotateAt int64) {
+	if rotateAt == 0 || !c.hasCapability(shared.CapabilityTokenRotation) {
+		return
+	}
+
+	delay := time.Until(time.Unix(rotateAt, 0))
+	go func() {
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-c.shutdown:
+				return
+			}
+		}
+
+		msg := &shared.RotateTokenRequest{
+			Message: shared.Message{
+				Type: shared.MessageTypeRotateTokenRequest,
+				ID:   c.getMsgID(),
+			},
+		}
+		log.Printf("[Conn] Sending rotate_token_request: nodeID=%s", c.configFile.Config.NodeID)
+		if err := c.transport.WriteMessage(msg); err != nil {
+			log.Printf("[Conn] Failed to request token rotation: %v", err)
+		}
+	}()
+}
+
+// handleRotateTokenResponse saves the rotated token. The relay keeps the old
+// token working for a while, so if the new one cannot be saved the node keeps
+// the old one and rotates again later.
+func (c *Conn) handleRotateTokenResponse(resp *shared.RotateTokenResponse) error {
+	if resp.Error != "" {
+		c.scheduleTokenRotation(time.Now().Add(tokenRotationRetryDelay).Unix())
+		return fmt.Errorf("token rotation failed, keeping current token: %s", resp.Error)
+	}
+
+	oldToken := c.configFile.Config.Token
+	c.configFile.Config.Token = resp.Token
+	if err := c.configFile.Save(); err != nil {
+		c.configFile.Config.Token = oldToken
+		c.scheduleTokenRotation(time.Now().Add(tokenRotationRetryDelay).Unix())
+		return fmt.Errorf("save rotated token, keeping current token: %w", err)
+	}
+
+	log.Printf("[Conn] Token rotated and saved to config")
+	c.scheduleTokenRotation(resp.RotateAt)
+	return nil
+}
+
 // ============================================================
 // Bridge Handlers
 // ============================================================
@@ -240,32 +378,64 @@ func (c *Conn) handleOpenBridge(req *shared.OpenBridgeRequest) error {
 	parsed, err := shared.ParseServiceURL(req.Service.ServiceURL)
 	if err != nil {
 		log.Printf("[Conn] Failed to parse service URL %s: %v", req.Service.ServiceURL, err)
-		resp := &shared.OpenBridgeResponse{
-			Message: shared.Message{
-				Type: shared.MessageTypeOpenBridgeResponse,
-				ID:   req.ID,
-			},
-			Success: false,
-		}
-		_ = c.transport.WriteMessage(resp)
+		c.rejectOpenBridge(req, shared.BridgeErrorInvalidURL, err)
 		return fmt.Errorf("parse service URL: %w", err)
 	}
 
 	log.Printf("[Conn] OpenBridge request: bridgeID=%s, service=%s:%d%s",
 		req.BridgeID, parsed.Host, parsed.Port, parsed.Path)
 
+	// Check the target against the bridge policy before touching the network
+	policy, err := c.configFile.Config.BridgePolicy.compile()
+	if err != nil {
+		c.rejectOpenBridge(req, shared.BridgeErrorTargetDenied, err)
+		return fmt.Errorf("bridge policy: %w", err)
+	}
+	ips, err := policy.resolve(parsed.Host, parsed.Port)
+	if err != nil {
+		code := shared.BridgeErrorUnreachable
+		if errors.Is(err, ErrTargetDenied) {
+			log.Printf("[Conn] Refusing bridge %s: %v", req.BridgeID, err)
+			code = shared.BridgeErrorTargetDenied
+		}
+		c.rejectOpenBridge(req, code, err)
+		return fmt.Errorf("check target: %w", err)
+	}
+
+	// Dial the checked addresses, not the hostname, so DNS cannot redirect us
 	serviceAddr := net.JoinHostPort(parsed.Host, strconv.Itoa(parsed.Port))
-	svcConn, err := net.DialTimeout("tcp", serviceAddr, 10*time.Second)
+
+	// The relay picks the UDP port the node binds, so it is held to the same port rules
+	if req.Transport == shared.BridgeTransportUDP && req.LocalPort != 0 {
+		ips, err = policy.filterPort(parsed.Host, ips, req.LocalPort)
+		if err != nil {
+			log.Printf("[Conn] Refusing bridge %s: %v", req.BridgeID, err)
+			c.rejectOpenBridge(req, shared.BridgeErrorTargetDenied, err)
+			return fmt.Errorf("check local port: %w", err)
+		}
+	}
+
+	var svcConn net.Conn
+	var packetConn *net.UDPConn
+	switch req.Transport {
+	case "", shared.BridgeTransportTCP:
+		for _, ip := range ips {
+			svcConn, err = net.DialTimeout("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(parsed.Port)), 10*time.Second)
+			if err == nil {
+				break
+			}
+		}
+	case shared.BridgeTransportUDP:
+		// There is no handshake to pick an address, so every allowed one is tried
+		// until the service answers (see datagramTargets)
+		packetConn, err = net.ListenUDP("udp", &net.UDPAddr{Port: req.LocalPort})
+		svcConn = packetConn
+	default:
+		err = fmt.Errorf("unsupported bridge transport %q", req.Transport)
+	}
 	if err != nil {
 		log.Printf("[Conn] Failed to connect to service %s: %v", serviceAddr, err)
-		resp := &shared.OpenBridgeResponse{
-			Message: shared.Message{
-				Type: shared.MessageTypeOpenBridgeResponse,
-				ID:   req.ID,
-			},
-			Success: false,
-		}
-		_ = c.transport.WriteMessage(resp)
+		c.rejectOpenBridge(req, shared.BridgeErrorUnreachable, err)
 		return fmt.Errorf("connect to service: %w", err)
 	}
 
@@ -279,6 +449,16 @@ func (c *Conn) handleOpenBridge(req *shared.OpenBridgeRequest) error {
 		Path:      parsed.Path,
 		Conn:      svcConn,
 		shutdown:  make(chan struct{}),
+
+		sendWindow: shared.NewFlowWindow(shared.DefaultBridgeWindow),
+		recvCredit: shared.NewCreditTracker(shared.DefaultBridgeWindow),
+		relay:      c,
+
+		packet: packetConn,
+		policy: policy,
+	}
+	if packetConn != nil {
+		bridge.remoteIPs = ips
 	}
 
 	c.bridgeMu.Lock()
@@ -292,12 +472,21 @@ func (c *Conn) handleOpenBridge(req *shared.OpenBridgeRequest) error {
 		},
 		Success: true,
 	}
+	if packetConn != nil {
+		resp.LocalPort = packetConn.LocalAddr().(*net.UDPAddr).Port
+	}
 
 	if err := c.transport.WriteMessage(resp); err != nil {
 		c.closeBridge(bridge)
 		return fmt.Errorf("send open bridge response: %w", err)
 	}
 
+	if packetConn != nil {
+		log.Printf("[Conn] Opened UDP bridge %s on port %d for %s", req.BridgeID, resp.LocalPort, parsed.Host)
+		go c.forwardDatagramsToServer(bridge)
+		return nil
+	}
+
 	log.Printf("[Conn] Opened bridge %s to %s:%d%s", req.BridgeID, parsed.Host, parsed.Port, parsed.Path)
 
 	go c.forwardServiceToServer(bridge)
@@ -305,6 +494,20 @@ func (c *Conn) handleOpenBridge(req *shared.OpenBridgeRequest) error {
 	return nil
 }
 
+// rejectOpenBridge answers an OpenBridgeRequest that failed, with a code the relay can act on
+func (c *Conn) rejectOpenBridge(req *shared.OpenBridgeRequest, code string, err error) {
+	resp := &shared.OpenBridgeResponse{
+		Message: shared.Message{
+			Type:  shared.MessageTypeOpenBridgeResponse,
+			ID:    req.ID,
+			Error: err.Error(),
+		},
+		Success: false,
+		Code:    code,
+	}
+	_ = c.transport.WriteMessage(resp)
+}
+
 func (c *Conn) handleCloseBridge(req *shared.CloseBridgeRequest) error {
 	log.Printf("[Conn] CloseBridge request: bridgeID=%s", req.BridgeID)
 
@@ -351,7 +554,27 @@ func (c *Conn) handleBridgeData(msg *shared.BridgeDataMessage) error {
 		return fmt.Errorf("bridge not found: %s", msg.BridgeID)
 	}
 
+	if bridge.packet != nil {
+		return c.handleBridgeDatagram(bridge, msg)
+	}
+
+	// Drop data the server replayed after a resume but we already have
+	bridge.mu.Lock()
+	if msg.Sequence != 0 && msg.Sequence <= bridge.lastReceived {
+		bridge.mu.Unlock()
+		return nil
+	}
+	if msg.Sequence != 0 {
+		bridge.lastReceived = msg.Sequence
+	}
+	ack := bridge.lastReceived
+	bridge.mu.Unlock()
+
+	bridge.recvCredit.Received(len(msg.Data))
+
+	atomic.AddInt64(&bridge.buffered, int64(len(msg.Data)))
 	_, err := bridge.Conn.Write(msg.Data)
+	atomic.AddInt64(&bridge.buffered, -int64(len(msg.Data)))
 	if err != nil {
 		log.Printf("[Conn] Write to service failed: %v", err)
 		c.closeBridge(bridge)
@@ -361,14 +584,81 @@ func (c *Conn) handleBridgeData(msg *shared.BridgeDataMessage) error {
 	atomic.AddInt64(&bridge.byteCount, int64(len(msg.Data)))
 	atomic.AddInt64(&bridge.msgCount, 1)
 
+	// Data is already written to the service, so the server can send more
+	if !c.hasCapability(shared.CapabilityFlowControl) {
+		return nil
+	}
+	if credit := bridge.recvCredit.Consume(len(msg.Data)); credit > 0 {
+		if err := c.sendWindowUpdate(bridge.ID, credit, ack); err != nil {
+			return fmt.Errorf("send window update: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// handleBridgeDatagram sends one datagram to the service. Datagram bridges
+// have no flow control; like plain UDP, packets may be lost.
+func (c *Conn) handleBridgeDatagram(bridge *Bridge, msg *shared.BridgeDataMessage) error {
+	if msg.Port == 0 {
+		return fmt.Errorf("datagram for bridge %s has no port", bridge.ID)
+	}
+
+	sent := false
+	for _, ip := range bridge.datagramTargets() {
+		if !bridge.policy.allows(bridge.Addr, ip, msg.Port) {
+			continue
+		}
+		sent = true
+		if _, err := bridge.packet.WriteToUDP(msg.Data, &net.UDPAddr{IP: ip, Port: msg.Port}); err != nil {
+			// Unreachable ports and the like are not fatal for a datagram socket
+			log.Printf("[Conn] Write to service %s failed for UDP bridge %s: %v", ip, bridge.ID, err)
+		}
+	}
+	if !sent {
+		return fmt.Errorf("datagram for bridge %s to port %d: %w", bridge.ID, msg.Port, ErrTargetDenied)
+	}
+
+	atomic.AddInt64(&bridge.byteCount, int64(len(msg.Data)))
+	atomic.AddInt64(&bridge.msgCount, 1)
+	return nil
+}
+
+func (c *Conn) handleBridgeWindowUpdate(msg *shared.BridgeWindowUpdateMessage) error {
+	c.bridgeMu.RLock()
+	bridge, exists := c.bridges[msg.BridgeID]
+	c.bridgeMu.RUnlock()
+
+	if !exists {
+		// Bridge already closed, late updates are expected
+		return nil
+	}
+
+	bridge.mu.Lock()
+	bridge.retransmit.Ack(msg.Ack)
+	bridge.mu.Unlock()
+
+	bridge.sendWindow.Release(msg.Credit)
+	return nil
+}
+
+func (c *Conn) sendWindowUpdate(bridgeID string, credit int64, ack uint64) error {
+	msg := &shared.BridgeWindowUpdateMessage{
+		Message: shared.Message{
+			Type: shared.MessageTypeBridgeWindowUpdate,
+			ID:   c.getMsgID(),
+		},
+		BridgeID: bridgeID,
+		Credit:   credit,
+		Ack:      ack,
+	}
+	return c.transport.WriteMessage(msg)
+}
+
 func (c *Conn) forwardServiceToServer(bridge *Bridge) {
 	defer log.Printf("[Conn] Stopped forwarding for bridge %s", bridge.ID)
 
 	buf := make([]byte, 4096)
-	var sequence uint64
 
 	log.Printf("[Conn] Started forwarding for bridge %s", bridge.ID)
 
@@ -378,8 +668,26 @@ func (c *Conn) forwardServiceToServer(bridge *Bridge) {
 			log.Printf("[Conn] Shutdown signal received for bridge %s", bridge.ID)
 			return
 		default:
+			// Only read from the service when the server has room for it,
+			// so a slow consumer pushes back on this bridge alone
+			allowed := len(buf)
+			flowControl := bridge.hasCapability(shared.CapabilityFlowControl)
+			if flowControl {
+				var err error
+				allowed, err = bridge.sendWindow.Acquire(len(buf), bridge.shutdown, time.Now().Add(1*time.Second))
+				if err == shared.ErrWindowTimeout {
+					continue
+				}
+				if err != nil {
+					return
+				}
+			}
+
 			bridge.Conn.SetReadDeadline(time.Now().Add(1 * time.Second))
-			n, err := bridge.Conn.Read(buf)
+			n, err := bridge.Conn.Read(buf[:allowed])
+			if flowControl {
+				bridge.sendWindow.Release(int64(allowed - n))
+			}
 
 			if err != nil {
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
@@ -392,33 +700,175 @@ func (c *Conn) forwardServiceToServer(bridge *Bridge) {
 			}
 
 			if n > 0 {
-				sequence++
-
-				msg := &shared.BridgeDataMessage{
-					Message: shared.Message{
-						Type: shared.MessageTypeBridgeData,
-						ID:   c.getMsgID(),
-					},
-					BridgeID: bridge.ID,
-					Sequence: sequence,
-					Data:     buf[:n],
-				}
+				bridge.sendData(buf[:n])
+			}
+		}
+	}
+}
 
-				if err := c.transport.WriteMessage(msg); err != nil {
-					log.Printf("[Conn] Failed to send data to server: %v", err)
-					return
-				}
+// forwardDatagramsToServer sends every datagram from the service host to the
+// server as its own BridgeData message
+func (c *Conn) forwardDatagramsToServer(bridge *Bridge) {
+	defer log.Printf("[Conn] Stopped forwarding for bridge %s", bridge.ID)
+
+	buf := make([]byte, 65536)
+
+	log.Printf("[Conn] Started forwarding datagrams for bridge %s", bridge.ID)
+
+	for {
+		select {
+		case <-bridge.shutdown:
+			return
+		default:
+		}
+
+		bridge.packet.SetReadDeadline(time.Now().Add(1 * time.Second))
+		n, addr, err := bridge.packet.ReadFromUDP(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
 			}
+			select {
+			case <-bridge.shutdown:
+			default:
+				log.Printf("[Conn] Read from UDP service error: %v", err)
+			}
+			return
 		}
+
+		// Only the service host may send through the bridge
+		if !bridge.fromService(addr.IP) {
+			continue
+		}
+
+		bridge.sendDatagram(buf[:n], addr.Port)
 	}
 }
 
+// datagramTargets returns the addresses to send datagrams to: the one the
+// service answered from, or every allowed address until it has answered
+func (b *Bridge) datagramTargets() []net.IP {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remoteIP != nil {
+		return []net.IP{b.remoteIP}
+	}
+	return b.remoteIPs
+}
+
+// fromService reports whether a datagram came from one of the service's
+// addresses, and remembers that address for replies
+func (b *Bridge) fromService(ip net.IP) bool {
+	for _, remoteIP := range b.remoteIPs {
+		if remoteIP.Equal(ip) {
+			b.mu.Lock()
+			b.remoteIP = remoteIP
+			b.mu.Unlock()
+			return true
+		}
+	}
+	return false
+}
+
+// sendDatagram sends one datagram. Unlike sendData nothing is kept for
+// retransmission; datagrams sent while the session is down are dropped.
+func (b *Bridge) sendDatagram(data []byte, port int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.paused {
+		return
+	}
+
+	b.sendSeq++
+	msg := &shared.BridgeDataMessage{
+		Message: shared.Message{
+			Type: shared.MessageTypeBridgeData,
+			ID:   b.relay.getMsgID(),
+		},
+		BridgeID: b.ID,
+		Sequence: b.sendSeq,
+		Data:     data,
+		Port:     port,
+	}
+	if err := b.relay.transport.WriteMessage(msg); err != nil {
+		log.Printf("[Conn] Failed to send datagram for bridge %s, pausing until resume: %v", b.ID, err)
+		b.paused = true
+	}
+}
+
+// sendData sequences data and keeps it for retransmission. It is sent right away
+// unless the bridge is waiting for its session to be resumed.
+func (b *Bridge) sendData(data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sendSeq++
+	if b.relay.hasCapability(shared.CapabilityResume) {
+		b.retransmit.Add(b.sendSeq, data)
+	}
+
+	if b.paused {
+		return
+	}
+
+	if err := b.writeData(b.sendSeq, data); err != nil {
+		log.Printf("[Conn] Failed to send data for bridge %s, holding it for resume: %v", b.ID, err)
+		b.paused = true
+	}
+}
+
+// writeData sends one data message through the bridge's current connection (caller holds mu)
+func (b *Bridge) writeData(seq uint64, data []byte) error {
+	msg := &shared.BridgeDataMessage{
+		Message: shared.Message{
+			Type: shared.MessageTypeBridgeData,
+			ID:   b.relay.getMsgID(),
+		},
+		BridgeID: b.ID,
+		Sequence: seq,
+		Data:     data,
+	}
+	return b.relay.transport.WriteMessage(msg)
+}
+
+// hasCapability reports whether the bridge's current connection negotiated a capability
+func (b *Bridge) hasCapability(capability string) bool {
+	b.mu.Lock()
+	relay := b.relay
+	b.mu.Unlock()
+	return relay.hasCapability(capability)
+}
+
+// resume re-synchronizes the bridge with the server's receive position and
+// replays everything the server has not seen
+func (b *Bridge) resume(peer shared.BridgeResumeState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.retransmit.Ack(peer.LastReceived)
+	// Window updates may have been lost with the old connection, so start over
+	b.sendWindow.Reset(shared.DefaultBridgeWindow - peer.Outstanding - b.retransmit.Bytes())
+
+	for _, entry := range b.retransmit.Entries() {
+		if err := b.writeData(entry.Sequence, entry.Data); err != nil {
+			log.Printf("[Conn] Failed to replay data for bridge %s: %v", b.ID, err)
+			return
+		}
+	}
+
+	log.Printf("[Conn] Resumed bridge %s (replayed %d messages, %d bytes)", b.ID, len(b.retransmit.Entries()), b.retransmit.Bytes())
+	b.paused = false
+}
+
 func (c *Conn) closeBridge(bridge *Bridge) {
 	bridge.closeOnce.Do(func() {
 		if bridge.Conn != nil {
 			close(bridge.shutdown)
 			bridge.Conn.Close()
 		}
+		bridge.sendWindow.Close()
 
 		c.bridgeMu.Lock()
 		delete(c.bridges, bridge.ID)
@@ -441,23 +891,181 @@ func (c *Conn) Close() error {
 
 	close(c.shutdown)
 
+	c.CloseAllBridges()
+
+	if c.transport != nil {
+		c.transport.Close()
+	}
+
+	return nil
+}
+
+// ============================================================
+// Session Resume
+// ============================================================
+
+// Resumable reports whether this connection left open bridges behind that
+// the server may still hold (within shared.ResumeGracePeriod)
+func (c *Conn) Resumable() bool {
+	c.bridgeMu.RLock()
+	count := len(c.bridges)
+	c.bridgeMu.RUnlock()
+
+	sessionID, disconnectedAt := c.session()
+	return sessionID != "" && count > 0 &&
+		!disconnectedAt.IsZero() && time.Since(disconnectedAt) < shared.ResumeGracePeriod
+}
+
+// session returns the session ID and when its connection dropped
+func (c *Conn) session() (string, time.Time) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	return c.sessionID, c.disconnectedAt
+}
+
+// ResumeFrom takes over the session and open bridges of a previous connection.
+// They are resumed or closed once this connection registers.
+func (c *Conn) ResumeFrom(prev *Conn) {
+	resumable := prev.Resumable()
+
+	prev.bridgeMu.Lock()
+	bridges := prev.bridges
+	prev.bridges = make(map[string]*Bridge)
+	prev.bridgeMu.Unlock()
+
+	if !resumable {
+		for _, bridge := range bridges {
+			prev.closeBridge(bridge)
+		}
+		return
+	}
+
+	sessionID, disconnectedAt := prev.session()
+	c.sessionMu.Lock()
+	c.sessionID = sessionID
+	c.disconnectedAt = disconnectedAt
+	c.sessionMu.Unlock()
+
+	// Keep buffering under the old capabilities until the relay answers
+	prev.stateMu.RLock()
+	c.capabilities = prev.capabilities
+	prev.stateMu.RUnlock()
+
 	c.bridgeMu.Lock()
+	for id, bridge := range bridges {
+		bridge.mu.Lock()
+		bridge.relay = c
+		bridge.paused = true
+		bridge.mu.Unlock()
+		c.bridges[id] = bridge
+	}
+	c.bridgeMu.Unlock()
+
+	log.Printf("[Conn] Holding %d bridges to resume session %s", len(bridges), sessionID)
+}
+
+// bridgeResumeStates reports the receive position of every bridge for a resume request
+func (c *Conn) bridgeResumeStates() []shared.BridgeResumeState {
+	if sessionID, _ := c.session(); sessionID == "" {
+		return nil
+	}
+
+	c.bridgeMu.RLock()
+	defer c.bridgeMu.RUnlock()
+
+	states := make([]shared.BridgeResumeState, 0, len(c.bridges))
 	for _, bridge := range c.bridges {
-		if bridge.Conn != nil {
-			close(bridge.shutdown)
-			bridge.Conn.Close()
+		bridge.mu.Lock()
+		states = append(states, shared.BridgeResumeState{
+			BridgeID:     bridge.ID,
+			LastReceived: bridge.lastReceived,
+			Outstanding:  bridge.recvCredit.Outstanding(),
+		})
+		bridge.mu.Unlock()
+	}
+	return states
+}
+
+// finishResume resumes the bridges the server kept and closes the others
+func (c *Conn) finishResume(resp *shared.RegisterResponse) {
+	c.sessionMu.Lock()
+	c.sessionID = resp.SessionID
+	c.disconnectedAt = time.Time{}
+	c.sessionMu.Unlock()
+
+	c.bridgeMu.RLock()
+	bridges := make([]*Bridge, 0, len(c.bridges))
+	for _, bridge := range c.bridges {
+		bridges = append(bridges, bridge)
+	}
+	c.bridgeMu.RUnlock()
+
+	if len(bridges) == 0 {
+		return
+	}
+
+	if !resp.Resumed {
+		log.Printf("[Conn] Session not resumed, closing %d bridges", len(bridges))
+		for _, bridge := range bridges {
+			c.closeBridge(bridge)
 		}
+		return
 	}
-	c.bridges = make(map[string]*Bridge)
-	c.bridgeMu.Unlock()
 
-	if c.transport != nil {
-		c.transport.Close()
+	kept := make(map[string]shared.BridgeResumeState, len(resp.Bridges))
+	for _, state := range resp.Bridges {
+		kept[state.BridgeID] = state
+	}
+
+	for _, bridge := range bridges {
+		state, ok := kept[bridge.ID]
+		if !ok {
+			log.Printf("[Conn] Bridge %s not kept by server, closing", bridge.ID)
+			c.closeBridge(bridge)
+			continue
+		}
+		bridge.resume(state)
+	}
+}
+
+// ============================================================
+// Protocol Negotiation
+// ============================================================
+
+// negotiate checks the relay's protocol version and records the capabilities it accepted
+func (c *Conn) negotiate(resp *shared.RegisterResponse) error {
+	if err := shared.CheckVersion(resp.ProtocolVersion, 0, "relay"); err != nil {
+		return err
 	}
 
+	c.stateMu.Lock()
+	c.capabilities = resp.Capabilities
+	c.stateMu.Unlock()
+
+	log.Printf("[Conn] Relay speaks protocol v%d, capabilities=%v", shared.EffectiveVersion(resp.ProtocolVersion), resp.Capabilities)
 	return nil
 }
 
+// hasCapability reports whether a protocol feature may be used on this connection
+func (c *Conn) hasCapability(capability string) bool {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return slices.Contains(c.capabilities, capability)
+}
+
+// fail ends the connection with an error that Run returns to the caller
+func (c *Conn) fail(err error) {
+	c.closeMu.Lock()
+	if c.runErr == nil {
+		c.runErr = err
+	}
+	c.closeMu.Unlock()
+
+	if c.transport != nil {
+		c.transport.Close()
+	}
+}
+
 func (c *Conn) getMsgID() uint64 {
 	c.msgMu.Lock()
 	defer c.msgMu.Unlock()
@@ -471,6 +1079,73 @@ func (c *Conn) NodeID() string {
 	return c.configFile.Config.NodeID
 }
 
+// SetRelay sets the relay address to dial (before Run)
+func (c *Conn) SetRelay(address string) {
+	c.relayAddress = address
+}
+
+// Relay returns the relay address this connection dials
+func (c *Conn) Relay() string {
+	return c.relayAddress
+}
+
+// Registered reports whether the relay accepted this connection's registration
+func (c *Conn) Registered() bool {
+	return c.registered.Load()
+}
+
+// recordRelay marks the connection registered and remembers the relay in the
+// config file, so the node sticks to it and `node config show` can report it
+func (c *Conn) recordRelay() {
+	c.registered.Store(true)
+
+	if c.configFile.Config.CurrentRelay == c.relayAddress {
+		return
+	}
+	c.configFile.Config.CurrentRelay = c.relayAddress
+	if err := c.configFile.Save(); err != nil {
+		log.Printf("[Conn] Warning: failed to save current relay to config: %v", err)
+	}
+}
+
+// BridgeStats returns flow control diagnostics for all open bridges
+func (c *Conn) BridgeStats() []shared.BridgeStats {
+	c.bridgeMu.RLock()
+	defer c.bridgeMu.RUnlock()
+
+	stats := make([]shared.BridgeStats, 0, len(c.bridges))
+	for _, bridge := range c.bridges {
+		credit := bridge.sendWindow.Credit()
+		stats = append(stats, shared.BridgeStats{
+			BridgeID:      bridge.ID,
+			ServiceID:     bridge.ServiceID,
+			BufferedBytes: atomic.LoadInt64(&bridge.buffered),
+			InFlightBytes: shared.DefaultBridgeWindow - credit,
+			SendCredit:    credit,
+		})
+	}
+	return stats
+}
+
+// logBridgeStats periodically logs the flow control state of open bridges,
+// so a stalled stream shows whether the node or the server is holding data
+func (c *Conn) logBridgeStats() {
+	ticker := time.NewTicker(bridgeStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.shutdown:
+			return
+		case <-ticker.C:
+			for _, stats := range c.BridgeStats() {
+				log.Printf("[Conn] Bridge %s (service %s): buffered=%d in_flight=%d send_credit=%d",
+					stats.BridgeID, stats.ServiceID, stats.BufferedBytes, stats.InFlightBytes, stats.SendCredit)
+			}
+		}
+	}
+}
+
 func getSystemInfo() (string, []string, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -509,27 +1184,22 @@ func (c *Conn) CloseBridge(bridgeID string) error {
 		return fmt.Errorf("bridge not found: %s", bridgeID)
 	}
 
-	if bridge.Conn != nil {
-		close(bridge.shutdown)
-		bridge.Conn.Close()
-		log.Printf("[Conn] Closed bridge %s", bridgeID)
-	}
+	c.closeBridge(bridge)
+	log.Printf("[Conn] Closed bridge %s", bridgeID)
 
 	return nil
 }
 
 func (c *Conn) CloseAllBridges() error {
 	c.bridgeMu.Lock()
-	defer c.bridgeMu.Unlock()
+	bridges := c.bridges
+	c.bridges = make(map[string]*Bridge)
+	c.bridgeMu.Unlock()
 
-	for _, bridge := range c.bridges {
-		if bridge.Conn != nil {
-			close(bridge.shutdown)
-			bridge.Conn.Close()
-			log.Printf("[Conn] Closed bridge %s", bridge.ID)
-		}
+	for _, bridge := range bridges {
+		c.closeBridge(bridge)
+		log.Printf("[Conn] Closed bridge %s", bridge.ID)
 	}
 
-	c.bridges = make(map[string]*Bridge)
 	return nil
 }
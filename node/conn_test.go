@@ -0,0 +1,336 @@
+package node
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"unblink/shared"
+)
+
+// fakeTransport records the messages written to the relay
+type fakeTransport struct {
+	mu      sync.Mutex
+	written []any
+}
+
+func (t *fakeTransport) ReadMessage() (any, error) { select {} }
+
+func (t *fakeTransport) WriteMessage(msg any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.written = append(t.written, msg)
+	return nil
+}
+
+func (t *fakeTransport) Close() error { return nil }
+
+func (t *fakeTransport) messages() []any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]any(nil), t.written...)
+}
+
+func newTestConn() *Conn {
+	c := NewConn(&ConfigFile{Config: &Config{}})
+	c.transport = &fakeTransport{}
+	return c
+}
+
+// addTestBridge opens a bridge whose service side is the returned pipe end
+func addTestBridge(t *testing.T, c *Conn, id string) (*Bridge, net.Conn) {
+	svcConn, peer := net.Pipe()
+	t.Cleanup(func() { peer.Close() })
+
+	bridge := &Bridge{
+		ID:         id,
+		ServiceID:  "svc-" + id,
+		Conn:       svcConn,
+		shutdown:   make(chan struct{}),
+		sendWindow: shared.NewFlowWindow(shared.DefaultBridgeWindow),
+		recvCredit: shared.NewCreditTracker(shared.DefaultBridgeWindow),
+		relay:      c,
+	}
+	c.bridgeMu.Lock()
+	c.bridges[id] = bridge
+	c.bridgeMu.Unlock()
+	return bridge, peer
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCloseBridgeIsIdempotent(t *testing.T) {
+	c := newTestConn()
+	a, _ := addTestBridge(t, c, "a")
+	b, _ := addTestBridge(t, c, "b")
+
+	require.NoError(t, c.CloseBridge("a"))
+	assert.True(t, isClosed(a.shutdown))
+	assert.Error(t, c.CloseBridge("a"))
+
+	// A bridge closed by its own error path must not be closed again
+	c.closeBridge(b)
+	require.NoError(t, c.CloseAllBridges())
+	require.NoError(t, c.Close())
+	require.NoError(t, c.Close())
+
+	assert.True(t, isClosed(b.shutdown))
+	assert.Empty(t, c.bridges)
+}
+
+func TestCloseClosesBridges(t *testing.T) {
+	c := newTestConn()
+	a, _ := addTestBridge(t, c, "a")
+
+	require.NoError(t, c.Close())
+	assert.True(t, isClosed(a.shutdown))
+	assert.True(t, isClosed(c.shutdown))
+
+	// The window is closed so the forwarder stops waiting for credit
+	_, err := a.sendWindow.Acquire(1, nil, time.Time{})
+	assert.ErrorIs(t, err, shared.ErrWindowClosed)
+}
+
+func TestResumable(t *testing.T) {
+	c := newTestConn()
+	assert.False(t, c.Resumable(), "no session")
+
+	c.sessionID = "session-1"
+	addTestBridge(t, c, "a")
+	assert.False(t, c.Resumable(), "still connected")
+
+	c.disconnectedAt = time.Now()
+	assert.True(t, c.Resumable())
+
+	c.disconnectedAt = time.Now().Add(-shared.ResumeGracePeriod)
+	assert.False(t, c.Resumable(), "grace period over")
+}
+
+func TestResumeFromReplaysKeptBridges(t *testing.T) {
+	prev := newTestConn()
+	prev.sessionID = "session-1"
+	prev.disconnectedAt = time.Now()
+	prev.capabilities = []string{shared.CapabilityResume}
+	kept, _ := addTestBridge(t, prev, "kept")
+	dropped, _ := addTestBridge(t, prev, "dropped")
+
+	kept.sendData([]byte("one"))
+	kept.sendData([]byte("two"))
+
+	c := newTestConn()
+	c.ResumeFrom(prev)
+
+	sessionID, _ := c.session()
+	assert.Equal(t, "session-1", sessionID)
+	assert.Empty(t, prev.bridges)
+	assert.Len(t, c.bridges, 2)
+	assert.True(t, kept.paused)
+
+	// Held data is not sent until the relay answers
+	kept.sendData([]byte("three"))
+	assert.Empty(t, c.transport.(*fakeTransport).messages())
+
+	c.finishResume(&shared.RegisterResponse{
+		SessionID: "session-2",
+		Resumed:   true,
+		Bridges:   []shared.BridgeResumeState{{BridgeID: "kept", LastReceived: 1}},
+	})
+
+	sessionID, disconnectedAt := c.session()
+	assert.Equal(t, "session-2", sessionID)
+	assert.True(t, disconnectedAt.IsZero())
+
+	assert.True(t, isClosed(dropped.shutdown))
+	assert.False(t, isClosed(kept.shutdown))
+	assert.False(t, kept.paused)
+
+	// Everything after the relay's last received message is replayed in order
+	var replayed []string
+	for _, msg := range c.transport.(*fakeTransport).messages() {
+		data := msg.(*shared.BridgeDataMessage)
+		assert.Equal(t, "kept", data.BridgeID)
+		replayed = append(replayed, string(data.Data))
+	}
+	assert.Equal(t, []string{"two", "three"}, replayed)
+}
+
+func TestResumeFromExpiredSession(t *testing.T) {
+	prev := newTestConn()
+	prev.sessionID = "session-1"
+	prev.disconnectedAt = time.Now().Add(-shared.ResumeGracePeriod)
+	bridge, _ := addTestBridge(t, prev, "a")
+
+	c := newTestConn()
+	c.ResumeFrom(prev)
+
+	sessionID, _ := c.session()
+	assert.Empty(t, sessionID)
+	assert.Empty(t, c.bridges)
+	assert.True(t, isClosed(bridge.shutdown))
+}
+
+func TestFinishResumeNotResumed(t *testing.T) {
+	c := newTestConn()
+	c.sessionID = "session-1"
+	bridge, _ := addTestBridge(t, c, "a")
+
+	c.finishResume(&shared.RegisterResponse{SessionID: "session-2"})
+
+	assert.True(t, isClosed(bridge.shutdown))
+	assert.Empty(t, c.bridges)
+}
+
+func TestRotateTokenResponseSaved(t *testing.T) {
+	c := newTestConn()
+	c.configFile.Path = filepath.Join(t.TempDir(), "config.json")
+	c.configFile.Config.Token = "old"
+
+	require.NoError(t, c.handleRotateTokenResponse(&shared.RotateTokenResponse{Token: "new"}))
+	assert.Equal(t, "new", c.configFile.Config.Token)
+
+	data, err := os.ReadFile(c.configFile.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"new"`)
+}
+
+func TestRotateTokenResponseSaveFails(t *testing.T) {
+	// The config directory cannot be created below a regular file
+	blocker := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(blocker, nil, 0600))
+
+	c := newTestConn()
+	c.configFile.Path = filepath.Join(blocker, "config.json")
+	c.configFile.Config.Token = "old"
+
+	err := c.handleRotateTokenResponse(&shared.RotateTokenResponse{Token: "new"})
+	assert.ErrorContains(t, err, "keeping current token")
+	assert.Equal(t, "old", c.configFile.Config.Token)
+}
+
+func TestRotateTokenResponseError(t *testing.T) {
+	c := newTestConn()
+	c.configFile.Config.Token = "old"
+
+	err := c.handleRotateTokenResponse(&shared.RotateTokenResponse{
+		Message: shared.Message{Error: "node revoked"},
+	})
+	assert.ErrorContains(t, err, "node revoked")
+	assert.Equal(t, "old", c.configFile.Config.Token)
+}
+
+// freeUDPPort returns a UDP port that was free a moment ago
+func freeUDPPort(t *testing.T) int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func openUDPBridge(c *Conn, id string, localPort int) (*shared.OpenBridgeResponse, error) {
+	err := c.handleOpenBridge(&shared.OpenBridgeRequest{
+		Message:   shared.Message{Type: shared.MessageTypeOpenBridgeRequest, ID: 7},
+		BridgeID:  id,
+		ServiceID: "svc-" + id,
+		Service:   shared.Service{ServiceURL: "rtsp://127.0.0.1:554/stream1"},
+		Transport: shared.BridgeTransportUDP,
+		LocalPort: localPort,
+	})
+	msgs := c.transport.(*fakeTransport).messages()
+	return msgs[len(msgs)-1].(*shared.OpenBridgeResponse), err
+}
+
+func TestOpenUDPBridgeChecksLocalPort(t *testing.T) {
+	port := freeUDPPort(t)
+	c := newTestConn()
+	c.configFile.Config.BridgePolicy = BridgePolicy{Allow: []string{":554", fmt.Sprintf(":%d", port)}}
+
+	// The relay may not pick a port outside the rules
+	resp, err := openUDPBridge(c, "a", port+1)
+	assert.ErrorIs(t, err, ErrTargetDenied)
+	assert.False(t, resp.Success)
+	assert.Equal(t, shared.BridgeErrorTargetDenied, resp.Code)
+
+	resp, err = openUDPBridge(c, "b", port)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, port, resp.LocalPort)
+	require.NoError(t, c.CloseBridge("b"))
+}
+
+func TestDatagramBridgeTriesEveryAddress(t *testing.T) {
+	// The service answers on the second of its addresses
+	service, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2)})
+	require.NoError(t, err)
+	defer service.Close()
+	servicePort := service.LocalAddr().(*net.UDPAddr).Port
+
+	packet, err := net.ListenUDP("udp", &net.UDPAddr{})
+	require.NoError(t, err)
+	defer packet.Close()
+
+	policy, err := (&BridgePolicy{}).compile()
+	require.NoError(t, err)
+	bridge := &Bridge{
+		ID:        "a",
+		Addr:      "camera.lan",
+		packet:    packet,
+		remoteIPs: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 2)},
+		policy:    policy,
+	}
+	c := newTestConn()
+
+	// Until the service answers, datagrams go to every address
+	require.NoError(t, c.handleBridgeDatagram(bridge, &shared.BridgeDataMessage{Port: servicePort, Data: []byte("rtcp")}))
+	buf := make([]byte, 64)
+	require.NoError(t, service.SetReadDeadline(time.Now().Add(time.Second)))
+	n, from, err := service.ReadFromUDP(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "rtcp", string(buf[:n]))
+
+	// Its answer picks the address
+	_, err = service.WriteToUDP([]byte("rtp"), &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: from.Port})
+	require.NoError(t, err)
+	require.NoError(t, packet.SetReadDeadline(time.Now().Add(time.Second)))
+	_, addr, err := packet.ReadFromUDP(buf)
+	require.NoError(t, err)
+	assert.True(t, bridge.fromService(addr.IP))
+	assert.Equal(t, []net.IP{net.IPv4(127, 0, 0, 2)}, bridge.datagramTargets())
+
+	// Other hosts are not the service
+	assert.False(t, bridge.fromService(net.IPv4(127, 0, 0, 3)))
+	assert.Equal(t, []net.IP{net.IPv4(127, 0, 0, 2)}, bridge.datagramTargets())
+}
+
+func TestDatagramBridgeChecksEveryAddress(t *testing.T) {
+	packet, err := net.ListenUDP("udp", &net.UDPAddr{})
+	require.NoError(t, err)
+	defer packet.Close()
+
+	policy, err := (&BridgePolicy{Deny: []string{"127.0.0.1", "127.0.0.2:9"}}).compile()
+	require.NoError(t, err)
+	bridge := &Bridge{
+		ID:        "a",
+		Addr:      "camera.lan",
+		packet:    packet,
+		remoteIPs: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 2)},
+		policy:    policy,
+	}
+	c := newTestConn()
+
+	assert.NoError(t, c.handleBridgeDatagram(bridge, &shared.BridgeDataMessage{Port: 10, Data: []byte("x")}))
+	assert.ErrorIs(t, c.handleBridgeDatagram(bridge, &shared.BridgeDataMessage{Port: 9, Data: []byte("x")}), ErrTargetDenied)
+}
@@ -0,0 +1,379 @@
+package node
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexxIT/go2rtc/pkg/onvif"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"unblink/shared"
+)
+
+const (
+	// maxDiscoveryTimeout caps how long the relay can make the node listen for replies
+	maxDiscoveryTimeout = 10 * time.Second
+
+	// onvifQueryTimeout bounds asking all discovered devices for their streams
+	onvifQueryTimeout = 20 * time.Second
+)
+
+var (
+	wsDiscoveryAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 3702}
+	mdnsAddr        = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+)
+
+// mdnsRTSPService is the mDNS service cameras advertise RTSP streams under
+const mdnsRTSPService = "_rtsp._tcp.local."
+
+// handleDiscover looks for cameras on the LAN without blocking the message loop
+func (c *Conn) handleDiscover(req *shared.DiscoverRequest) error {
+	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = shared.DefaultDiscoveryTimeout
+	}
+	timeout = min(timeout, maxDiscoveryTimeout)
+
+	log.Printf("[Conn] Discover request: timeout=%v, credentials=%v", timeout, req.Username != "")
+
+	go func() {
+		resp := &shared.DiscoverResponse{
+			Message: shared.Message{
+				Type: shared.MessageTypeDiscoverResponse,
+				ID:   req.ID,
+			},
+		}
+
+		devices, err := c.discover(timeout, req.Username, req.Password)
+		if err != nil {
+			log.Printf("[Conn] Discovery failed: %v", err)
+			resp.Error = err.Error()
+			if errors.Is(err, ErrTargetDenied) {
+				resp.Code = shared.BridgeErrorTargetDenied
+			}
+		} else {
+			log.Printf("[Conn] Discovered %d devices", len(devices))
+			resp.Success = true
+			resp.Devices = devices
+		}
+
+		if err := c.transport.WriteMessage(resp); err != nil {
+			log.Printf("[Conn] Failed to send discover response: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// discover runs WS-Discovery, falling back to mDNS when no ONVIF device answers,
+// and asks every ONVIF device for its details and streams
+func (c *Conn) discover(timeout time.Duration, username, password string) ([]shared.DiscoveredDevice, error) {
+	policy, err := c.configFile.Config.BridgePolicy.compile()
+	if err != nil {
+		return nil, fmt.Errorf("bridge policy: %w", err)
+	}
+	if policy.ServicesOnly {
+		return nil, fmt.Errorf("%w: discovery is disabled with services_only", ErrTargetDenied)
+	}
+
+	devices, err := wsDiscover(timeout)
+	if err != nil {
+		log.Printf("[Conn] WS-Discovery failed: %v", err)
+	}
+	if len(devices) == 0 {
+		mdnsDevices, mdnsErr := mdnsDiscover(timeout, policy)
+		if mdnsErr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("ws-discovery: %v; mdns: %w", err, mdnsErr)
+			}
+			return nil, fmt.Errorf("mdns: %w", mdnsErr)
+		}
+		return mdnsDevices, nil
+	}
+
+	var user *url.Userinfo
+	if username != "" {
+		user = url.UserPassword(username, password)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), onvifQueryTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range devices {
+		wg.Add(1)
+		go func(device *shared.DiscoveredDevice) {
+			defer wg.Done()
+			queryONVIFDevice(ctx, device, user, policy)
+		}(&devices[i])
+	}
+	wg.Wait()
+
+	return devices, nil
+}
+
+// queryONVIFDevice fills in the model and, with credentials, the streams of a device
+func queryONVIFDevice(ctx context.Context, device *shared.DiscoveredDevice, user *url.Userinfo, policy *targetPolicy) {
+	client := newONVIFClient(device.ONVIFURL, user, policy)
+
+	manufacturer, model, err := client.deviceInformation(ctx)
+	if err == nil {
+		device.Manufacturer, device.Model = manufacturer, model
+	}
+
+	if user == nil {
+		if err != nil && err != errONVIFUnauthorized {
+			device.Error = err.Error()
+		}
+		return
+	}
+
+	streams, err := client.streams(ctx)
+	device.Streams = streams
+	if err != nil {
+		device.Error = err.Error()
+	}
+}
+
+// ============================================================
+// WS-Discovery
+// ============================================================
+
+// wsProbeMatches is a WS-Discovery ProbeMatches message
+type wsProbeMatches struct {
+	Matches []struct {
+		Address string `xml:"EndpointReference>Address"`
+		Types   string `xml:"Types"`
+		Scopes  string `xml:"Scopes"`
+		XAddrs  string `xml:"XAddrs"`
+	} `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+// wsDiscover probes for ONVIF video transmitters and collects the answers until timeout
+func wsDiscover(timeout time.Duration) ([]shared.DiscoveredDevice, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// ONVIF Core Specification, 7.3 Discovery
+	probe := `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing">
+	<s:Header>
+		<a:Action>http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action>
+		<a:MessageID>urn:uuid:` + onvif.UUID() + `</a:MessageID>
+		<a:To>urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To>
+	</s:Header>
+	<s:Body>
+		<d:Probe xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+			<d:Types xmlns:dn="http://www.onvif.org/ver10/network/wsdl">dn:NetworkVideoTransmitter</d:Types>
+		</d:Probe>
+	</s:Body>
+</s:Envelope>`
+
+	if _, err := conn.WriteTo([]byte(probe), wsDiscoveryAddr); err != nil {
+		return nil, fmt.Errorf("send probe: %w", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+
+	var devices []shared.DiscoveredDevice
+	seen := make(map[string]bool)
+	buf := make([]byte, 65536)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break // Deadline reached
+		}
+
+		var matches wsProbeMatches
+		if err := xml.Unmarshal(buf[:n], &matches); err != nil {
+			continue
+		}
+
+		for _, match := range matches.Matches {
+			xaddr := pickXAddr(strings.Fields(match.XAddrs), from.IP)
+			key := match.Address
+			if key == "" {
+				key = xaddr
+			}
+			if xaddr == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			scopes := strings.Fields(match.Scopes)
+			devices = append(devices, shared.DiscoveredDevice{
+				Address:  from.IP.String(),
+				Source:   shared.DiscoverySourceWSDiscovery,
+				Name:     findScope(scopes, "onvif://www.onvif.org/name/"),
+				Model:    findScope(scopes, "onvif://www.onvif.org/hardware/"),
+				ONVIFURL: xaddr,
+			})
+		}
+	}
+
+	return devices, nil
+}
+
+// pickXAddr prefers the device service address on the host that answered
+func pickXAddr(xaddrs []string, from net.IP) string {
+	if len(xaddrs) == 0 {
+		return ""
+	}
+	for _, xaddr := range xaddrs {
+		if u, err := url.Parse(xaddr); err == nil && net.ParseIP(u.Hostname()).Equal(from) {
+			return xaddr
+		}
+	}
+	return fixXAddr(xaddrs[0], from)
+}
+
+// findScope returns the value of the first scope with the given prefix
+func findScope(scopes []string, prefix string) string {
+	for _, scope := range scopes {
+		if value, ok := strings.CutPrefix(scope, prefix); ok {
+			value, _ = url.PathUnescape(value)
+			return value
+		}
+	}
+	return ""
+}
+
+// ============================================================
+// mDNS
+// ============================================================
+
+// mdnsInstance collects the records of one advertised service instance
+type mdnsInstance struct {
+	target string // SRV target host
+	port   uint16
+	path   string // TXT "path=" entry
+	from   net.IP // Responder, when no A record came along
+}
+
+// mdnsDiscover browses for RTSP services and collects answers until timeout.
+// Services the bridge policy does not allow are left out.
+func mdnsDiscover(timeout time.Duration, policy *targetPolicy) ([]shared.DiscoveredDevice, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	query, err := newMDNSQuery(mdnsRTSPService)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo(query, mdnsAddr); err != nil {
+		return nil, fmt.Errorf("send query: %w", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+
+	instances := make(map[string]*mdnsInstance)
+	var order []string
+	hosts := make(map[string]net.IP)
+
+	buf := make([]byte, 65536)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break // Deadline reached
+		}
+
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil {
+			continue
+		}
+
+		instance := func(name string) *mdnsInstance {
+			if inst, ok := instances[name]; ok {
+				return inst
+			}
+			inst := &mdnsInstance{from: from.IP}
+			instances[name] = inst
+			order = append(order, name)
+			return inst
+		}
+
+		records := append(append(msg.Answers, msg.Additionals...), msg.Authorities...)
+		for _, record := range records {
+			name := record.Header.Name.String()
+			switch body := record.Body.(type) {
+			case *dnsmessage.PTRResource:
+				if strings.EqualFold(name, mdnsRTSPService) {
+					instance(body.PTR.String())
+				}
+			case *dnsmessage.SRVResource:
+				inst := instance(name)
+				inst.target, inst.port = body.Target.String(), body.Port
+			case *dnsmessage.TXTResource:
+				for _, txt := range body.TXT {
+					if path, ok := strings.CutPrefix(txt, "path="); ok {
+						instance(name).path = path
+					}
+				}
+			case *dnsmessage.AResource:
+				hosts[strings.ToLower(name)] = net.IP(body.A[:])
+			}
+		}
+	}
+
+	var devices []shared.DiscoveredDevice
+	for _, name := range order {
+		inst := instances[name]
+		if !strings.HasSuffix(strings.ToLower(name), mdnsRTSPService) || inst.port == 0 {
+			continue
+		}
+
+		ip := hosts[strings.ToLower(inst.target)]
+		if ip == nil {
+			ip = inst.from
+		}
+		if !policy.allows(ip.String(), ip, int(inst.port)) {
+			log.Printf("[Conn] Skipping mDNS service %s at %s:%d, denied by bridge policy", name, ip, inst.port)
+			continue
+		}
+		path := inst.path
+		if path != "" && !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		devices = append(devices, shared.DiscoveredDevice{
+			Address: ip.String(),
+			Source:  shared.DiscoverySourceMDNS,
+			Name:    strings.TrimSuffix(strings.TrimSuffix(name, mdnsRTSPService), "."),
+			Streams: []shared.DiscoveredStream{{
+				URL: "rtsp://" + net.JoinHostPort(ip.String(), strconv.Itoa(int(inst.port))) + path,
+			}},
+		})
+	}
+
+	return devices, nil
+}
+
+// newMDNSQuery builds a PTR query for a service, asking for unicast replies
+func newMDNSQuery(service string) ([]byte, error) {
+	name, err := dnsmessage.NewName(service)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{
+			Name:  name,
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET | 1<<15, // QU bit: unicast response (RFC 6762, section 5.4)
+		}},
+	}
+	return msg.Pack()
+}
@@ -0,0 +1,75 @@
+package node
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickXAddr(t *testing.T) {
+	from := net.ParseIP("192.168.1.20")
+
+	tests := []struct {
+		name   string
+		xaddrs []string
+		want   string
+	}{
+		{"none", nil, ""},
+		{
+			"prefers the answering host",
+			[]string{"http://10.0.0.5/onvif/device_service", "http://192.168.1.20:8080/onvif/device_service"},
+			"http://192.168.1.20:8080/onvif/device_service",
+		},
+		{
+			"falls back to the first address",
+			[]string{"http://10.0.0.5/onvif/device_service", "http://[fe80::1]/onvif/device_service"},
+			"http://10.0.0.5/onvif/device_service",
+		},
+		{
+			"fixes an unspecified first address",
+			[]string{"http://0.0.0.0:8000/onvif/device_service"},
+			"http://192.168.1.20:8000/onvif/device_service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pickXAddr(tt.xaddrs, from))
+		})
+	}
+}
+
+func TestFixXAddr(t *testing.T) {
+	from := net.ParseIP("192.168.1.20")
+
+	tests := []struct {
+		xaddr string
+		want  string
+	}{
+		{"http://0.0.0.0/onvif/device_service", "http://192.168.1.20/onvif/device_service"},
+		{"http://0.0.0.0:8080/onvif/device_service", "http://192.168.1.20:8080/onvif/device_service"},
+		{"http://[::]:80/onvif", "http://192.168.1.20:80/onvif"},
+		{"http://10.0.0.5/onvif/device_service", "http://10.0.0.5/onvif/device_service"},
+		{"http://camera.local/onvif", "http://camera.local/onvif"},
+		{"://bad", "://bad"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fixXAddr(tt.xaddr, from), tt.xaddr)
+	}
+}
+
+func TestFindScope(t *testing.T) {
+	scopes := []string{
+		"onvif://www.onvif.org/type/video_encoder",
+		"onvif://www.onvif.org/name/Front%20Door",
+		"onvif://www.onvif.org/hardware/IPC-123",
+		"onvif://www.onvif.org/name/Second",
+	}
+
+	assert.Equal(t, "Front Door", findScope(scopes, "onvif://www.onvif.org/name/"))
+	assert.Equal(t, "IPC-123", findScope(scopes, "onvif://www.onvif.org/hardware/"))
+	assert.Empty(t, findScope(scopes, "onvif://www.onvif.org/location/"))
+	assert.Empty(t, findScope(nil, "onvif://www.onvif.org/name/"))
+}
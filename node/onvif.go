@@ -0,0 +1,230 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexxIT/go2rtc/pkg/onvif"
+
+	"unblink/shared"
+)
+
+// onvifRequestTimeout bounds each SOAP call to a camera
+const onvifRequestTimeout = 5 * time.Second
+
+// errONVIFUnauthorized is returned when a camera rejects the credentials
+var errONVIFUnauthorized = errors.New("ONVIF authentication failed")
+
+// onvifClient calls the ONVIF device and media services of one camera.
+// Every connection is checked against the bridge policy.
+type onvifClient struct {
+	deviceURL string
+	user      *url.Userinfo // Added to stream URLs; nil = anonymous
+	soapUser  *url.Userinfo // Same credentials, escaped for the SOAP header
+	policy    *targetPolicy
+	http      *http.Client
+}
+
+func newONVIFClient(deviceURL string, user *url.Userinfo, policy *targetPolicy) *onvifClient {
+	c := &onvifClient{
+		deviceURL: deviceURL,
+		user:      user,
+		policy:    policy,
+	}
+	c.http = &http.Client{
+		Timeout:   onvifRequestTimeout,
+		Transport: &http.Transport{DialContext: c.dial},
+	}
+	if user != nil {
+		password, _ := user.Password()
+		c.soapUser = url.UserPassword(xmlEscape(user.Username()), password)
+	}
+	return c
+}
+
+// SOAP responses, matched on local names so any namespace prefix works
+type onvifDeviceInformation struct {
+	Manufacturer string `xml:"Body>GetDeviceInformationResponse>Manufacturer"`
+	Model        string `xml:"Body>GetDeviceInformationResponse>Model"`
+}
+
+type onvifCapabilities struct {
+	MediaXAddr string `xml:"Body>GetCapabilitiesResponse>Capabilities>Media>XAddr"`
+}
+
+type onvifProfiles struct {
+	Profiles []struct {
+		Token   string `xml:"token,attr"`
+		Name    string `xml:"Name"`
+		Encoder struct {
+			Encoding string `xml:"Encoding"`
+			Width    int    `xml:"Resolution>Width"`
+			Height   int    `xml:"Resolution>Height"`
+		} `xml:"VideoEncoderConfiguration"`
+	} `xml:"Body>GetProfilesResponse>Profiles"`
+}
+
+type onvifStreamURI struct {
+	URI string `xml:"Body>GetStreamUriResponse>MediaUri>Uri"`
+}
+
+// deviceInformation returns the manufacturer and model of the camera
+func (c *onvifClient) deviceInformation(ctx context.Context) (string, string, error) {
+	var info onvifDeviceInformation
+	if err := c.call(ctx, c.deviceURL, `<tds:GetDeviceInformation/>`, &info); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(info.Manufacturer), strings.TrimSpace(info.Model), nil
+}
+
+// streams lists the RTSP stream of every media profile. The URLs carry the
+// client's credentials so they can be used as service URLs as they are.
+func (c *onvifClient) streams(ctx context.Context) ([]shared.DiscoveredStream, error) {
+	var capabilities onvifCapabilities
+	if err := c.call(ctx, c.deviceURL, `<tds:GetCapabilities><tds:Category>Media</tds:Category></tds:GetCapabilities>`, &capabilities); err != nil {
+		return nil, err
+	}
+	mediaURL := c.resolveXAddr(strings.TrimSpace(capabilities.MediaXAddr), "/onvif/media_service")
+
+	var profiles onvifProfiles
+	if err := c.call(ctx, mediaURL, `<trt:GetProfiles/>`, &profiles); err != nil {
+		return nil, err
+	}
+
+	var streams []shared.DiscoveredStream
+	for _, profile := range profiles.Profiles {
+		var streamURI onvifStreamURI
+		body := `<trt:GetStreamUri><trt:StreamSetup><tt:Stream>RTP-Unicast</tt:Stream>` +
+			`<tt:Transport><tt:Protocol>RTSP</tt:Protocol></tt:Transport></trt:StreamSetup>` +
+			`<trt:ProfileToken>` + xmlEscape(profile.Token) + `</trt:ProfileToken></trt:GetStreamUri>`
+		if err := c.call(ctx, mediaURL, body, &streamURI); err != nil {
+			return streams, fmt.Errorf("profile %s: %w", profile.Token, err)
+		}
+
+		streamURL, err := url.Parse(strings.TrimSpace(streamURI.URI))
+		if err != nil || streamURL.Host == "" {
+			continue
+		}
+		if streamURL.User == nil {
+			streamURL.User = c.user
+		}
+
+		name := profile.Name
+		if name == "" {
+			name = profile.Token
+		}
+		streams = append(streams, shared.DiscoveredStream{
+			Profile:  name,
+			URL:      streamURL.String(),
+			Encoding: profile.Encoder.Encoding,
+			Width:    profile.Encoder.Width,
+			Height:   profile.Encoder.Height,
+		})
+	}
+	return streams, nil
+}
+
+// resolveXAddr keeps service URLs on the host the device service answered on,
+// since cameras often advertise addresses of another interface
+func (c *onvifClient) resolveXAddr(xaddr, defaultPath string) string {
+	device, _ := url.Parse(c.deviceURL)
+	path := defaultPath
+	if u, err := url.Parse(xaddr); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	return device.Scheme + "://" + device.Host + path
+}
+
+// call posts a SOAP request and decodes the response into out
+func (c *onvifClient) call(ctx context.Context, serviceURL, body string, out any) error {
+	envelope := onvif.NewEnvelopeWithUser(c.soapUser)
+	envelope.Append(body)
+
+	ctx, cancel := context.WithTimeout(ctx, onvifRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, serviceURL, bytes.NewReader(envelope.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || bytes.Contains(data, []byte("NotAuthorized")) {
+		return errONVIFUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		if reason := onvif.FindTagValue(data, "Text"); reason != "" {
+			return fmt.Errorf("ONVIF request failed: %s: %s", resp.Status, reason)
+		}
+		return fmt.Errorf("ONVIF request failed: %s", resp.Status)
+	}
+
+	if err := xml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode ONVIF response: %w", err)
+	}
+	return nil
+}
+
+// dial connects to an address the bridge policy allows, like bridges do
+func (c *onvifClient) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portStr)
+	}
+
+	ips, err := c.policy.resolve(host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+	return d.DialContext(ctx, network, net.JoinHostPort(ips[0].String(), portStr))
+}
+
+// fixXAddr replaces the unspecified address some cameras advertise with the
+// address the probe match came from
+func fixXAddr(xaddr string, from net.IP) string {
+	u, err := url.Parse(xaddr)
+	if err != nil {
+		return xaddr
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && ip.IsUnspecified() {
+		if port := u.Port(); port != "" {
+			u.Host = net.JoinHostPort(from.String(), port)
+		} else {
+			u.Host = from.String()
+		}
+	}
+	return u.String()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
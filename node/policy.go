@@ -0,0 +1,280 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"unblink/shared"
+)
+
+// ErrTargetDenied is returned when the bridge policy does not allow a target
+var ErrTargetDenied = errors.New("target denied by bridge policy")
+
+// BridgePolicy restricts which hosts and ports the relay may open bridges to.
+//
+// Rules are a host, a port, or both:
+//
+//	"192.168.1.0/24"     CIDR (IPv4 or IPv6)
+//	"192.168.1.20"       single IP
+//	"camera.lan"         hostname, "*.cams.lan" for subdomains
+//	":554", ":8000-8100" port or port range on any host
+//	"10.0.0.5:554"       host and port ("[fd00::/8]:554" for IPv6)
+//
+// Deny rules win over allow rules. An empty allow list allows every target that
+// is not denied. Hostnames are resolved on the node and every address is checked,
+// so a name cannot be used to reach a denied network.
+type BridgePolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+
+	// ServicesOnly rejects every target that is not listed in Services
+	ServicesOnly bool     `json:"services_only,omitempty"`
+	Services     []string `json:"services,omitempty"` // Pre-declared service URLs
+}
+
+// policyRule is one parsed allow or deny entry
+type policyRule struct {
+	network  *net.IPNet // nil = any address
+	hostname string     // Exact name, or suffix when wildcard is set
+	wildcard bool
+	minPort  int // 0 = any port
+	maxPort  int
+}
+
+// Validate checks that every rule and pre-declared service parses
+func (p *BridgePolicy) Validate() error {
+	if _, err := parseRules(p.Allow); err != nil {
+		return fmt.Errorf("allow: %w", err)
+	}
+	if _, err := parseRules(p.Deny); err != nil {
+		return fmt.Errorf("deny: %w", err)
+	}
+	for _, serviceURL := range p.Services {
+		if _, err := shared.ParseServiceURL(serviceURL); err != nil {
+			return fmt.Errorf("services: %s: %w", serviceURL, err)
+		}
+	}
+	if p.ServicesOnly && len(p.Services) == 0 {
+		return fmt.Errorf("services_only is set but no services are listed")
+	}
+	return nil
+}
+
+// targetPolicy is a BridgePolicy with its rules parsed
+type targetPolicy struct {
+	*BridgePolicy
+	allow []policyRule
+	deny  []policyRule
+}
+
+func (p *BridgePolicy) compile() (*targetPolicy, error) {
+	allow, err := parseRules(p.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parseRules(p.Deny)
+	if err != nil {
+		return nil, err
+	}
+	return &targetPolicy{BridgePolicy: p, allow: allow, deny: deny}, nil
+}
+
+// resolve checks a target against the policy and returns the addresses that
+// may be dialed. The caller must dial one of these rather than the hostname,
+// so DNS cannot change the answer between the check and the connection.
+// Errors for disallowed targets wrap ErrTargetDenied.
+func (p *targetPolicy) resolve(host string, port int) ([]net.IP, error) {
+	if p.ServicesOnly && !p.declared(host, port) {
+		return nil, fmt.Errorf("%w: %s is not a pre-declared service", ErrTargetDenied, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+
+	ips, err := resolveHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed []net.IP
+	for _, ip := range ips {
+		if matchAny(p.deny, host, ip, port) {
+			return nil, fmt.Errorf("%w: %s (%s) port %d matches a deny rule", ErrTargetDenied, host, ip, port)
+		}
+		if p.allows(host, ip, port) {
+			allowed = append(allowed, ip)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("%w: %s port %d matches no allow rule", ErrTargetDenied, host, port)
+	}
+	return allowed, nil
+}
+
+// allows reports whether an already resolved address may be reached on a port.
+// Used for every datagram of a UDP bridge, whose ports are picked by the relay.
+func (p *targetPolicy) allows(host string, ip net.IP, port int) bool {
+	if matchAny(p.deny, host, ip, port) {
+		return false
+	}
+	return len(p.allow) == 0 || matchAny(p.allow, host, ip, port)
+}
+
+// filterPort returns the resolved addresses of host that may also be reached
+// on another port. Used for the UDP port the relay asks the node to bind.
+// Errors wrap ErrTargetDenied.
+func (p *targetPolicy) filterPort(host string, ips []net.IP, port int) ([]net.IP, error) {
+	var allowed []net.IP
+	for _, ip := range ips {
+		if p.allows(host, ip, port) {
+			allowed = append(allowed, ip)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("%w: %s port %d is not allowed", ErrTargetDenied, host, port)
+	}
+	return allowed, nil
+}
+
+// declared reports whether host:port belongs to a pre-declared service
+func (p *targetPolicy) declared(host string, port int) bool {
+	for _, serviceURL := range p.Services {
+		parsed, err := shared.ParseServiceURL(serviceURL)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(parsed.Host, host) && parsed.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveHost(host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := net.LookupIP(host)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", host, err)
+	}
+	return addrs, nil
+}
+
+func matchAny(rules []policyRule, host string, ip net.IP, port int) bool {
+	for _, rule := range rules {
+		if rule.matches(host, ip, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r policyRule) matches(host string, ip net.IP, port int) bool {
+	if r.minPort != 0 && (port < r.minPort || port > r.maxPort) {
+		return false
+	}
+
+	switch {
+	case r.network != nil:
+		return r.network.Contains(ip)
+	case r.wildcard:
+		return strings.HasSuffix(strings.ToLower(host), r.hostname)
+	case r.hostname != "":
+		return strings.EqualFold(host, r.hostname)
+	default:
+		return true
+	}
+}
+
+func parseRules(entries []string) ([]policyRule, error) {
+	rules := make([]policyRule, 0, len(entries))
+	for _, entry := range entries {
+		rule, err := parseRule(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", entry, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(entry string) (policyRule, error) {
+	var rule policyRule
+	if entry == "" {
+		return rule, fmt.Errorf("empty rule")
+	}
+
+	// Split off the port: ":554", "host:554", "[v6]:554"; bare IPv6 has no port
+	host, port := entry, ""
+	switch {
+	case strings.HasPrefix(entry, "["):
+		end := strings.Index(entry, "]")
+		if end < 0 {
+			return rule, fmt.Errorf("missing ]")
+		}
+		host = entry[1:end]
+		if rest := entry[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return rule, fmt.Errorf("unexpected %q after ]", rest)
+			}
+			port = rest[1:]
+		}
+	case strings.Count(entry, ":") == 1:
+		host, port, _ = strings.Cut(entry, ":")
+	}
+
+	if port != "" {
+		low, high, isRange := strings.Cut(port, "-")
+		minPort, err := parsePort(low)
+		if err != nil {
+			return rule, err
+		}
+		maxPort := minPort
+		if isRange {
+			if maxPort, err = parsePort(high); err != nil {
+				return rule, err
+			}
+			if maxPort < minPort {
+				return rule, fmt.Errorf("port range %s is reversed", port)
+			}
+		}
+		rule.minPort, rule.maxPort = minPort, maxPort
+	}
+
+	switch {
+	case host == "" || host == "*":
+		if rule.minPort == 0 {
+			return rule, fmt.Errorf("rule matches everything")
+		}
+	case strings.Contains(host, "/"):
+		_, network, err := net.ParseCIDR(host)
+		if err != nil {
+			return rule, err
+		}
+		rule.network = network
+	case net.ParseIP(host) != nil:
+		ip := net.ParseIP(host)
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		rule.network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	case strings.HasPrefix(host, "*."):
+		rule.hostname = strings.ToLower(host[1:])
+		rule.wildcard = true
+	default:
+		rule.hostname = strings.ToLower(host)
+	}
+	return rule, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
@@ -2,35 +2,34 @@ package node
 
 import (
 	"log"
-	"math"
-	"math/rand"
 	"strings"
 	"time"
 )
 
-// Reconnector handles automatic reconnection with exponential backoff
+// resumeRetryDelay is the reconnect delay while open bridges can still be resumed
+const resumeRetryDelay = 1 * time.Second
+
+// Reconnector handles automatic reconnection with per-relay backoff and failover
 type Reconnector struct {
-	configFile        *ConfigFile
-	initialDelay      time.Duration
-	backoffMultiplier float64
-	jitterFactor      float64
-	shutdown          chan struct{}
+	configFile *ConfigFile
+	relays     *relaySet
+	shutdown   chan struct{}
 }
 
 // NewReconnector creates a new reconnector with the given config
 func NewReconnector(configFile *ConfigFile) *Reconnector {
 	return &Reconnector{
-		configFile:        configFile,
-		initialDelay:      10 * time.Second,
-		backoffMultiplier: 2.0,
-		jitterFactor:      0.1,
-		shutdown:          make(chan struct{}),
+		configFile: configFile,
+		relays:     newRelaySet(configFile.Config.Relays(), configFile.Config.initialRelay()),
+		shutdown:   make(chan struct{}),
 	}
 }
 
-// Run runs the node connection with automatic reconnection
+// Run runs the node connection with automatic reconnection.
+// Each new connection takes over the bridges of the previous one so they can be resumed.
 func (r *Reconnector) Run(createConn func() *Conn) {
 	attempt := int64(0)
+	var prev *Conn
 
 	for {
 		// Check for shutdown BEFORE attempting connection
@@ -41,14 +40,30 @@ func (r *Reconnector) Run(createConn func() *Conn) {
 		default:
 		}
 
+		// Wait for the relay's backoff, or shutdown
+		relay, wait := r.relays.next()
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-r.shutdown:
+				log.Printf("[Node] Shutdown requested during backoff, exiting")
+				return
+			}
+		}
+
 		attempt++
 
 		if attempt > 1 {
-			log.Printf("[Node] Connection attempt %d", attempt)
+			log.Printf("[Node] Connection attempt %d to %s", attempt, relay.address)
 		}
 
 		conn := createConn()
+		conn.SetRelay(relay.address)
+		if prev != nil {
+			conn.ResumeFrom(prev)
+		}
 		err := conn.Run()
+		prev = conn
 
 		// Check if we received shutdown signal FIRST (before processing error)
 		select {
@@ -78,34 +93,19 @@ func (r *Reconnector) Run(createConn func() *Conn) {
 
 		log.Printf("[Node] Error is retryable, will attempt reconnection")
 
-		// Calculate backoff delay
-		delay := r.calculateDelay(attempt)
-		log.Printf("[Node] Connection failed: %v. Reconnecting in %v (attempt %d)...", err, delay, attempt)
-
-		// Wait for delay or shutdown
-		select {
-		case <-time.After(delay):
-			// Continue to next attempt
-		case <-r.shutdown:
-			log.Printf("[Node] Shutdown requested during backoff, exiting")
-			return
+		// A relay that accepted us is healthy; this drop starts its backoff over
+		if conn.Registered() {
+			r.relays.succeeded(relay)
 		}
-	}
-}
+		delay := r.relays.failed(relay)
 
-// calculateDelay calculates the backoff delay
-// First 3 attempts: exponential backoff with jitter
-// After 3 attempts: uniform 5-minute interval (no jitter)
-func (r *Reconnector) calculateDelay(attempt int64) time.Duration {
-	// First 3 attempts: exponential backoff with jitter
-	if attempt <= 3 {
-		delay := float64(r.initialDelay) * math.Pow(r.backoffMultiplier, float64(attempt-1))
-		jitter := 1.0 + (rand.Float64()*2-1)*r.jitterFactor
-		delay *= jitter
-		return time.Duration(delay)
+		// Retry quickly while the relay may still hold our bridges
+		if conn.Resumable() && delay > resumeRetryDelay {
+			r.relays.retrySoon(relay, resumeRetryDelay)
+			delay = resumeRetryDelay
+		}
+		log.Printf("[Node] Connection to %s failed: %v. Backing off %v (attempt %d)...", relay.address, err, delay, attempt)
 	}
-	// After 3 attempts: uniform 5-minute interval (no jitter)
-	return 5 * time.Minute
 }
 
 // Close signals the reconnector to stop
@@ -132,6 +132,8 @@ func IsRetryableError(err error) bool {
 		"invalid relay address",
 		"unauthorized",
 		"forbidden",
+		"incompatible protocol",
+		"node revoked",
 	}
 
 	for _, pattern := range nonRetryablePatterns {
@@ -0,0 +1,145 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// failoverThreshold is how many failed attempts in a row make the node try
+// other relays instead of waiting for the one it is on
+const failoverThreshold = 2
+
+// relayEndpoint tracks the health of one relay address
+type relayEndpoint struct {
+	address  string
+	failures int       // Failed attempts in a row
+	retryAt  time.Time // Earliest time to try again (backoff)
+}
+
+// relaySet picks which relay to connect to. The node sticks to the relay
+// that accepted it until that relay fails failoverThreshold times in a row,
+// then tries whichever relay's backoff ends first (earlier entries win ties).
+type relaySet struct {
+	endpoints []*relayEndpoint
+	current   *relayEndpoint
+
+	initialDelay      time.Duration
+	backoffMultiplier float64
+	jitterFactor      float64
+}
+
+func newRelaySet(addresses []string, current string) *relaySet {
+	rs := &relaySet{
+		initialDelay:      10 * time.Second,
+		backoffMultiplier: 2.0,
+		jitterFactor:      0.1,
+	}
+	for _, address := range addresses {
+		endpoint := &relayEndpoint{address: address}
+		rs.endpoints = append(rs.endpoints, endpoint)
+		if address == current {
+			rs.current = endpoint
+		}
+	}
+	if rs.current == nil && len(rs.endpoints) > 0 {
+		rs.current = rs.endpoints[0]
+	}
+	return rs
+}
+
+// next returns the relay to try and how long to wait before trying it
+func (rs *relaySet) next() (*relayEndpoint, time.Duration) {
+	pick := rs.current
+	if pick.failures >= failoverThreshold {
+		for _, endpoint := range rs.endpoints {
+			if endpoint.retryAt.Before(pick.retryAt) {
+				pick = endpoint
+			}
+		}
+	}
+
+	if pick != rs.current {
+		log.Printf("[Node] Relay %s failed %d times, failing over to %s", rs.current.address, rs.current.failures, pick.address)
+		rs.current = pick
+	}
+
+	return pick, max(time.Until(pick.retryAt), 0)
+}
+
+// succeeded marks a relay healthy after it accepted the node
+func (rs *relaySet) succeeded(endpoint *relayEndpoint) {
+	endpoint.failures = 0
+	endpoint.retryAt = time.Time{}
+	rs.current = endpoint
+}
+
+// failed backs a relay off after a failed attempt or a dropped connection
+func (rs *relaySet) failed(endpoint *relayEndpoint) time.Duration {
+	endpoint.failures++
+	delay := rs.calculateDelay(endpoint.failures)
+	endpoint.retryAt = time.Now().Add(delay)
+	return delay
+}
+
+// retrySoon lets the next attempt on a relay start after delay at the latest
+// (used while the relay may still hold bridges to resume)
+func (rs *relaySet) retrySoon(endpoint *relayEndpoint, delay time.Duration) {
+	if at := time.Now().Add(delay); endpoint.retryAt.After(at) {
+		endpoint.retryAt = at
+	}
+}
+
+// calculateDelay calculates the backoff delay of a relay, with jitter so
+// nodes that lost the same relay do not all come back at once.
+// First 3 failures: exponential backoff. After that: about 5 minutes.
+func (rs *relaySet) calculateDelay(failures int) time.Duration {
+	delay := float64(5 * time.Minute)
+	if failures <= 3 {
+		delay = float64(rs.initialDelay) * math.Pow(rs.backoffMultiplier, float64(failures-1))
+	}
+	jitter := 1.0 + (rand.Float64()*2-1)*rs.jitterFactor
+	return time.Duration(delay * jitter)
+}
+
+// Relays returns the relay addresses in order of preference
+func (c *Config) Relays() []string {
+	if len(c.RelayAddresses) > 0 {
+		return c.RelayAddresses
+	}
+	if c.RelayAddress == "" {
+		return nil
+	}
+	return []string{c.RelayAddress}
+}
+
+// initialRelay returns the relay that last accepted the node, or the first one
+func (c *Config) initialRelay() string {
+	relays := c.Relays()
+	if slices.Contains(relays, c.CurrentRelay) {
+		return c.CurrentRelay
+	}
+	if len(relays) == 0 {
+		return ""
+	}
+	return relays[0]
+}
+
+// validateRelays checks that at least one relay is configured and all are WebSocket URLs
+func (c *Config) validateRelays() error {
+	relays := c.Relays()
+	if len(relays) == 0 {
+		return fmt.Errorf("invalid relay address: relay_address or relay_addresses is required")
+	}
+	for _, address := range relays {
+		u, err := url.Parse(address)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return fmt.Errorf("invalid relay address %q: must be a ws:// or wss:// URL", address)
+		}
+	}
+	return nil
+}
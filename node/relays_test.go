@@ -0,0 +1,179 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaySetStartsAtCurrent(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a", "ws://b"}, "ws://b")
+	endpoint, delay := rs.next()
+	assert.Equal(t, "ws://b", endpoint.address)
+	assert.Zero(t, delay)
+
+	// An unknown current relay falls back to the first one
+	rs = newRelaySet([]string{"ws://a", "ws://b"}, "ws://gone")
+	endpoint, _ = rs.next()
+	assert.Equal(t, "ws://a", endpoint.address)
+}
+
+func TestRelaySetStaysBelowFailoverThreshold(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a", "ws://b"}, "")
+
+	a, _ := rs.next()
+	for i := 1; i < failoverThreshold; i++ {
+		rs.failed(a)
+	}
+
+	endpoint, delay := rs.next()
+	assert.Equal(t, "ws://a", endpoint.address, "sticks to its relay")
+	assert.Greater(t, delay, time.Duration(0), "waits for the backoff")
+}
+
+func TestRelaySetFailsOver(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a", "ws://b", "ws://c"}, "")
+
+	a, _ := rs.next()
+	for i := 0; i < failoverThreshold; i++ {
+		rs.failed(a)
+	}
+
+	// b and c are both ready; the earlier entry wins
+	endpoint, delay := rs.next()
+	assert.Equal(t, "ws://b", endpoint.address)
+	assert.Zero(t, delay)
+
+	// The node sticks to b until b also reaches the threshold
+	rs.failed(endpoint)
+	endpoint, _ = rs.next()
+	assert.Equal(t, "ws://b", endpoint.address)
+
+	rs.failed(endpoint)
+	endpoint, delay = rs.next()
+	assert.Equal(t, "ws://c", endpoint.address)
+	assert.Zero(t, delay)
+
+	rs.succeeded(endpoint)
+	assert.Zero(t, endpoint.failures)
+	assert.True(t, endpoint.retryAt.IsZero())
+	assert.Same(t, endpoint, rs.current)
+}
+
+func TestRelaySetPicksEarliestRetry(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a", "ws://b"}, "")
+
+	a, _ := rs.next()
+	b := rs.endpoints[1]
+	for i := 0; i < failoverThreshold; i++ {
+		rs.failed(a)
+	}
+	b.failures = 1
+	b.retryAt = time.Now().Add(time.Hour)
+
+	// a's backoff ends long before b's
+	endpoint, delay := rs.next()
+	assert.Equal(t, "ws://a", endpoint.address)
+	assert.Greater(t, delay, time.Duration(0))
+	assert.Less(t, delay, time.Hour)
+}
+
+func TestRelaySetRetrySoon(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a"}, "")
+	a, _ := rs.next()
+
+	for i := 0; i < 5; i++ {
+		rs.failed(a)
+	}
+	rs.retrySoon(a, time.Second)
+	_, delay := rs.next()
+	assert.LessOrEqual(t, delay, time.Second)
+
+	// Never pushes an earlier retry back
+	a.retryAt = time.Time{}
+	rs.retrySoon(a, time.Minute)
+	assert.True(t, a.retryAt.IsZero())
+}
+
+func TestRelaySetCalculateDelay(t *testing.T) {
+	rs := newRelaySet([]string{"ws://a"}, "")
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, 5 * time.Minute},
+		{10, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		delay := rs.calculateDelay(tt.failures)
+		jitter := time.Duration(float64(tt.want) * rs.jitterFactor)
+		assert.InDelta(t, float64(tt.want), float64(delay), float64(jitter), "failures=%d", tt.failures)
+	}
+}
+
+func TestConfigRelays(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		relays  []string
+		initial string
+		wantErr bool
+	}{
+		{
+			name:    "single address",
+			config:  Config{RelayAddress: "ws://a"},
+			relays:  []string{"ws://a"},
+			initial: "ws://a",
+		},
+		{
+			name:    "list wins over single address",
+			config:  Config{RelayAddress: "ws://a", RelayAddresses: []string{"wss://b", "ws://c"}},
+			relays:  []string{"wss://b", "ws://c"},
+			initial: "wss://b",
+		},
+		{
+			name:    "sticks to the current relay",
+			config:  Config{RelayAddresses: []string{"ws://a", "ws://b"}, CurrentRelay: "ws://b"},
+			relays:  []string{"ws://a", "ws://b"},
+			initial: "ws://b",
+		},
+		{
+			name:    "current relay no longer listed",
+			config:  Config{RelayAddresses: []string{"ws://a"}, CurrentRelay: "ws://old"},
+			relays:  []string{"ws://a"},
+			initial: "ws://a",
+		},
+		{
+			name:    "none",
+			wantErr: true,
+		},
+		{
+			name:    "not a websocket URL",
+			config:  Config{RelayAddresses: []string{"ws://a", "http://b"}},
+			relays:  []string{"ws://a", "http://b"},
+			initial: "ws://a",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.relays, tt.config.Relays())
+			assert.Equal(t, tt.initial, tt.config.initialRelay())
+
+			err := tt.config.validateRelays()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -15,9 +15,12 @@ type ConnState interface {
 	OnTokenCheckResponse(*Conn, *shared.TokenCheckResponse) error
 	OnNewTokenResponse(*Conn, *shared.NewTokenResponse) error
 	OnRegisterResponse(*Conn, *shared.RegisterResponse) error
+	OnRotateTokenResponse(*Conn, *shared.RotateTokenResponse) error
 	HandleOpenBridge(*Conn, *shared.OpenBridgeRequest) error
 	HandleBridgeData(*Conn, *shared.BridgeDataMessage) error
+	HandleBridgeWindowUpdate(*Conn, *shared.BridgeWindowUpdateMessage) error
 	HandleCloseBridge(*Conn, *shared.CloseBridgeRequest) error
+	HandleDiscover(*Conn, *shared.DiscoverRequest) error
 }
 
 // baseState provides default error implementations
@@ -36,15 +39,24 @@ func (s *baseState) OnNewTokenResponse(*Conn, *shared.NewTokenResponse) error {
 func (s *baseState) OnRegisterResponse(*Conn, *shared.RegisterResponse) error {
 	return fmt.Errorf("unexpected register_response in %s state", s.name)
 }
+func (s *baseState) OnRotateTokenResponse(*Conn, *shared.RotateTokenResponse) error {
+	return fmt.Errorf("unexpected rotate_token_response in %s state", s.name)
+}
 func (s *baseState) HandleOpenBridge(*Conn, *shared.OpenBridgeRequest) error {
 	return fmt.Errorf("cannot handle bridges in %s state", s.name)
 }
 func (s *baseState) HandleBridgeData(*Conn, *shared.BridgeDataMessage) error {
 	return fmt.Errorf("cannot handle bridge data in %s state", s.name)
 }
+func (s *baseState) HandleBridgeWindowUpdate(*Conn, *shared.BridgeWindowUpdateMessage) error {
+	return fmt.Errorf("cannot handle bridge window update in %s state", s.name)
+}
 func (s *baseState) HandleCloseBridge(*Conn, *shared.CloseBridgeRequest) error {
 	return fmt.Errorf("cannot handle bridge close in %s state", s.name)
 }
+func (s *baseState) HandleDiscover(*Conn, *shared.DiscoverRequest) error {
+	return fmt.Errorf("cannot handle discovery in %s state", s.name)
+}
 
 // ============================================================
 // DisconnectedState - initial state
@@ -57,9 +69,9 @@ func NewDisconnectedState() *DisconnectedState {
 }
 
 func (s *DisconnectedState) Connect(c *Conn) error {
-	wsConn, _, err := websocket.DefaultDialer.Dial(c.configFile.Config.RelayAddress, nil)
+	wsConn, _, err := websocket.DefaultDialer.Dial(c.relayAddress, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("dial %s: %w", c.relayAddress, err)
 	}
 
 	c.wsConn = wsConn
@@ -114,7 +126,9 @@ func NewRequestingTokenState() *RequestingTokenState {
 func (s *RequestingTokenState) OnNewTokenResponse(c *Conn, r *shared.NewTokenResponse) error {
 	if r.Error != "" {
 		c.setState(NewClosedState())
-		return fmt.Errorf("failed to get new token: %s", r.Error)
+		err := fmt.Errorf("failed to get new token: %s", r.Error)
+		c.fail(err)
+		return err
 	}
 
 	log.Printf("[State] Received new token, saving to config")
@@ -143,15 +157,26 @@ func NewRegisteringState() *RegisteringState {
 func (s *RegisteringState) OnRegisterResponse(c *Conn, r *shared.RegisterResponse) error {
 	if !r.Success {
 		c.setState(NewClosedState())
-		return fmt.Errorf("registration failed: %s", r.Error)
+		err := fmt.Errorf("registration failed: %s", r.Error)
+		c.fail(err)
+		return err
+	}
+
+	if err := c.negotiate(r); err != nil {
+		c.setState(NewClosedState())
+		c.fail(err)
+		return err
 	}
 
-	log.Printf("[State] Registration successful, sending node_ready")
+	log.Printf("[State] Registration with %s successful, sending node_ready", c.relayAddress)
+	c.recordRelay()
 
 	if r.DashboardURL != "" {
 		log.Printf("[Node] Dashboard URL: %s", r.DashboardURL)
 	}
 
+	c.finishResume(r)
+
 	c.setState(NewRegisteredState())
 
 	msg := &shared.NodeReadyMessage{
@@ -160,7 +185,12 @@ func (s *RegisteringState) OnRegisterResponse(c *Conn, r *shared.RegisterRespons
 			ID:   c.getMsgID(),
 		},
 	}
-	return c.transport.WriteMessage(msg)
+	if err := c.transport.WriteMessage(msg); err != nil {
+		return err
+	}
+
+	c.scheduleTokenRotation(r.TokenRotateAt)
+	return nil
 }
 
 // ============================================================
@@ -181,10 +211,22 @@ func (s *RegisteredState) HandleBridgeData(c *Conn, m *shared.BridgeDataMessage)
 	return c.handleBridgeData(m)
 }
 
+func (s *RegisteredState) HandleBridgeWindowUpdate(c *Conn, m *shared.BridgeWindowUpdateMessage) error {
+	return c.handleBridgeWindowUpdate(m)
+}
+
 func (s *RegisteredState) HandleCloseBridge(c *Conn, r *shared.CloseBridgeRequest) error {
 	return c.handleCloseBridge(r)
 }
 
+func (s *RegisteredState) HandleDiscover(c *Conn, r *shared.DiscoverRequest) error {
+	return c.handleDiscover(r)
+}
+
+func (s *RegisteredState) OnRotateTokenResponse(c *Conn, r *shared.RotateTokenResponse) error {
+	return c.handleRotateTokenResponse(r)
+}
+
 // ============================================================
 // ClosedState - terminal state
 // ============================================================
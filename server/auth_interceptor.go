@@ -125,6 +125,12 @@ func (i *AuthInterceptor) authenticate(ctx context.Context, header http.Header)
 	return newCtx, nil
 }
 
+// AuthenticateRequest validates the JWT token of a plain HTTP request and returns a
+// context carrying the user info. Errors are connect errors so callers can map codes.
+func (i *AuthInterceptor) AuthenticateRequest(r *http.Request) (context.Context, error) {
+	return i.authenticate(r.Context(), r.Header)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -7,6 +7,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"unblink/shared"
 )
 
 // BridgeConn implements net.Conn for bridge connections
@@ -57,6 +59,7 @@ func (bc *BridgeConn) Read(p []byte) (int, error) {
 	if len(bc.readBuf) > 0 {
 		n := copy(p, bc.readBuf)
 		bc.readBuf = bc.readBuf[n:]
+		bc.nodeConn.bridgeConsumed(bc.bridgeID, n)
 		return n, nil
 	}
 
@@ -97,6 +100,9 @@ func (bc *BridgeConn) Read(p []byte) (int, error) {
 			bc.readBuf = data[n:]
 		}
 
+		// Grant the node more credit for what was read
+		bc.nodeConn.bridgeConsumed(bc.bridgeID, n)
+
 		return n, nil
 
 	case <-timerCh:
@@ -121,8 +127,8 @@ func (bc *BridgeConn) Write(p []byte) (int, error) {
 		return 0, fmt.Errorf("write deadline exceeded")
 	}
 
-	// Send data through the bridge
-	if err := bc.nodeConn.SendData(bc.bridgeID, p); err != nil {
+	// Send data through the bridge (blocks while the node's window is exhausted)
+	if err := bc.nodeConn.SendData(bc.bridgeID, p, deadline); err != nil {
 		return 0, err
 	}
 
@@ -185,6 +191,11 @@ func (bc *BridgeConn) LastDataTime() time.Time {
 	return bc.lastDataTime
 }
 
+// FlowStats returns the flow control diagnostics of the bridge, or false once it is closed
+func (bc *BridgeConn) FlowStats() (shared.BridgeStats, bool) {
+	return bc.nodeConn.BridgeStat(bc.bridgeID)
+}
+
 // IdleDuration returns how long the connection has been idle
 func (bc *BridgeConn) IdleDuration() time.Duration {
 	bc.idleMu.RLock()
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// BridgePacketConn implements net.PacketConn for UDP bridges.
+// Packets written to a port are sent by the node to that port on the service
+// host; packets the service host sends to the node's socket are read back.
+type BridgePacketConn struct {
+	nodeConn  *NodeConn
+	bridgeID  string
+	dataChan  chan []byte
+	localPort int // Port of the node's UDP socket
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	readDeadline time.Time
+	deadlineMu   sync.RWMutex
+}
+
+// NewBridgePacketConn creates a new BridgePacketConn
+func NewBridgePacketConn(nodeConn *NodeConn, bridgeID string, dataChan chan []byte, localPort int) *BridgePacketConn {
+	return &BridgePacketConn{
+		nodeConn:  nodeConn,
+		bridgeID:  bridgeID,
+		dataChan:  dataChan,
+		localPort: localPort,
+		closed:    make(chan struct{}),
+	}
+}
+
+// ReadFrom implements net.PacketConn.ReadFrom. Datagrams larger than p are truncated.
+func (pc *BridgePacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	pc.deadlineMu.RLock()
+	deadline := pc.readDeadline
+	pc.deadlineMu.RUnlock()
+
+	var timerCh <-chan time.Time
+	if !deadline.IsZero() {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return 0, nil, fmt.Errorf("read deadline exceeded")
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+
+	select {
+	case data, ok := <-pc.dataChan:
+		if !ok {
+			return 0, nil, io.EOF
+		}
+		pc.nodeConn.bridgeConsumed(pc.bridgeID, len(data))
+		return copy(p, data), pc.remoteAddr(), nil
+
+	case <-pc.closed:
+		return 0, nil, net.ErrClosed
+
+	case <-timerCh:
+		return 0, nil, fmt.Errorf("read deadline exceeded")
+	}
+}
+
+// WriteTo implements net.PacketConn.WriteTo. Only the port of addr is used:
+// the node always sends to the service host.
+func (pc *BridgePacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	select {
+	case <-pc.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, fmt.Errorf("unsupported address type %T", addr)
+	}
+
+	if err := pc.nodeConn.SendDatagram(pc.bridgeID, udpAddr.Port, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close implements net.PacketConn.Close and closes the bridge on the node
+func (pc *BridgePacketConn) Close() error {
+	pc.closeOnce.Do(func() {
+		close(pc.closed)
+		log.Printf("[BridgePacketConn] Closing bridge %s", pc.bridgeID)
+
+		// Nobody else holds the bridge ID, so close it on the node as well
+		go func() {
+			if err := pc.nodeConn.CloseBridge(context.Background(), pc.bridgeID); err != nil {
+				log.Printf("[BridgePacketConn] Failed to close bridge %s: %v", pc.bridgeID, err)
+			}
+		}()
+	})
+	return nil
+}
+
+// LocalAddr implements net.PacketConn.LocalAddr. It reports the port the
+// node bound, which is what the service has to send to.
+func (pc *BridgePacketConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{Port: pc.localPort}
+}
+
+// SetDeadline implements net.PacketConn.SetDeadline. Writes never block.
+func (pc *BridgePacketConn) SetDeadline(t time.Time) error {
+	return pc.SetReadDeadline(t)
+}
+
+// SetReadDeadline implements net.PacketConn.SetReadDeadline
+func (pc *BridgePacketConn) SetReadDeadline(t time.Time) error {
+	pc.deadlineMu.Lock()
+	defer pc.deadlineMu.Unlock()
+	pc.readDeadline = t
+	return nil
+}
+
+// SetWriteDeadline implements net.PacketConn.SetWriteDeadline. Writes never block.
+func (pc *BridgePacketConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (pc *BridgePacketConn) remoteAddr() net.Addr {
+	return &bridgeAddr{bridgeID: pc.bridgeID, local: false}
+}
+
+const listenPacketPairAttempts = 10
+
+// ListenPacketPair opens two UDP bridges on consecutive node ports (even port
+// first), as RTP and RTCP expect. It is the bridge counterpart of rtsp.ListenUDPPair.
+func (nc *NodeConn) ListenPacketPair(ctx context.Context, serviceID, serviceURL string) (net.PacketConn, net.PacketConn, error) {
+	for i := 0; i < listenPacketPairAttempts; i++ {
+		conn1, err := nc.OpenPacketBridge(ctx, serviceID, serviceURL, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// RTP SHOULD use an even port and RTCP the next higher (odd) one (RFC 3550, section 11)
+		port1 := conn1.localPort
+		port2 := port1 + 1
+		if port1&1 > 0 {
+			port2 = port1 - 1
+		}
+
+		conn2, err := nc.OpenPacketBridge(ctx, serviceID, serviceURL, port2)
+		if err != nil {
+			_ = conn1.Close()
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			continue
+		}
+
+		if port1 < port2 {
+			return conn1, conn2, nil
+		}
+		return conn2, conn1, nil
+	}
+
+	return nil, nil, fmt.Errorf("can't open two UDP ports on node %s", nc.nodeID)
+}
@@ -79,8 +79,8 @@ func RunAILoop(
 
 		// Prepend system prompt with datetime if configured
 		if systemPrompt != "" {
-			// Human readable format: "Thursday, January 30, 2026 at 2:30 PM"
-			currentDateTime := time.Now().Format("Monday, January 2, 2006 at 3:04 PM")
+			// Human readable format: "Thursday, January 30, 2026 at 2:30 PM PST"
+			currentDateTime := time.Now().Format("Monday, January 2, 2006 at 3:04 PM MST")
 			systemPromptWithDateTime := fmt.Sprintf("%s\n\nCurrent date/time: %s", systemPrompt, currentDateTime)
 			messagesToSend = append([]openai.ChatCompletionMessageParamUnion{
 				openai.SystemMessage(systemPromptWithDateTime),
@@ -67,7 +67,7 @@ func (s *Service) SendMessage(ctx context.Context, req *connect.Request[chatv1.S
 	// 2. Run the AI loop
 	aiConfig := &AILoopConfig{
 		OpenAI:         s.openai,
-		Tools:          s.tools,
+		Tools:          s.messageTools(req.Msg.UseWebSearch),
 		Config:         s.cfg,
 		ContentTrimmer: s.contentTrimmer,
 	}
@@ -79,6 +79,19 @@ func (s *Service) SendMessage(ctx context.Context, req *connect.Request[chatv1.S
 	return nil
 }
 
+// messageTools returns the tools offered for a message: web search only when
+// the message asks for it
+func (s *Service) messageTools(useWebSearch bool) *ToolRegistry {
+	if !useWebSearch {
+		return s.tools
+	}
+	if s.webSearchTool == nil {
+		log.Printf("[ChatService] Web search requested but no provider is configured")
+		return s.tools
+	}
+	return s.tools.With(s.webSearchTool)
+}
+
 // saveUIBlock saves or updates a UI block with a specific ID and timestamp.
 // The block's Data field should already be JSON-encoded.
 func (s *Service) saveUIBlock(block *chatv1.UIBlock) (*chatv1.UIBlock, error) {
@@ -0,0 +1,46 @@
+package chat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTool is a tool that does nothing
+type fakeTool string
+
+func (t fakeTool) Name() string                                             { return string(t) }
+func (t fakeTool) Description() string                                      { return "" }
+func (t fakeTool) Parameters() map[string]any                               { return nil }
+func (t fakeTool) Execute(ctx context.Context, argumentsJSON string) string { return "" }
+
+func TestMessageToolsWebSearch(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register(fakeTool("search_video_events"))
+	s := &Service{tools: registry, webSearchTool: fakeTool("web_search")}
+
+	// Only offered when the message asks for it
+	tools := s.messageTools(false)
+	_, ok := tools.Get("web_search")
+	assert.False(t, ok)
+
+	tools = s.messageTools(true)
+	_, ok = tools.Get("web_search")
+	assert.True(t, ok)
+	_, ok = tools.Get("search_video_events")
+	assert.True(t, ok)
+
+	// The shared registry is left alone
+	_, ok = registry.Get("web_search")
+	assert.False(t, ok)
+	_, ok = s.messageTools(false).Get("web_search")
+	assert.False(t, ok)
+}
+
+func TestMessageToolsWithoutProvider(t *testing.T) {
+	registry := NewToolRegistry()
+	s := &Service{tools: registry}
+
+	assert.Same(t, registry, s.messageTools(true))
+}
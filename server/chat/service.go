@@ -51,6 +51,7 @@ type Service struct {
 	fastOpenai     *openai.Client
 	cfg            *Config
 	tools          *ToolRegistry
+	webSearchTool  Tool // Added for messages sent with use_web_search; nil = not configured
 	modelRegistry  *models.Registry
 	contentTrimmer *models.Trimmer
 }
@@ -148,5 +149,15 @@ func (s *Service) RegisterTool(tool Tool) {
 	s.tools.Register(tool)
 }
 
+// SetWebSearchTool sets the tool offered for messages sent with use_web_search
+func (s *Service) SetWebSearchTool(tool Tool) {
+	s.webSearchTool = tool
+}
+
+// ContentTrimmer returns the trimmer of the main chat model (nil without a chat model)
+func (s *Service) ContentTrimmer() *models.Trimmer {
+	return s.contentTrimmer
+}
+
 // Ensure Service implements interface
 var _ chatv1connect.ChatServiceHandler = (*Service)(nil)
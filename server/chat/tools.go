@@ -52,6 +52,19 @@ func (r *ToolRegistry) Register(tool Tool) {
 	r.tools[tool.Name()] = tool
 }
 
+// With returns a copy of the registry with extra tools, for tools that are
+// only offered for some messages
+func (r *ToolRegistry) With(tools ...Tool) *ToolRegistry {
+	copied := NewToolRegistry()
+	for name, tool := range r.tools {
+		copied.tools[name] = tool
+	}
+	for _, tool := range tools {
+		copied.Register(tool)
+	}
+	return copied
+}
+
 // Get returns a tool by name
 func (r *ToolRegistry) Get(name string) (Tool, bool) {
 	tool, ok := r.tools[name]
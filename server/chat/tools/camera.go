@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"unblink/database"
+	"unblink/server/service"
+)
+
+// resolveCamera finds the service a user means by a camera name: an exact
+// (case-insensitive) name match, else the only service whose name contains it.
+// Services of nodes the user cannot access are never considered.
+func resolveCamera(db *database.Client, registry *service.ServiceRegistry, userID, name string) (service.ServiceInfo, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return service.ServiceInfo{}, fmt.Errorf("camera is required")
+	}
+
+	access := make(map[string]bool)
+	var cameras, exact, partial []service.ServiceInfo
+	for _, svc := range registry.ListServices() {
+		allowed, checked := access[svc.NodeID]
+		if !checked {
+			ok, err := db.CheckNodeAccess(svc.NodeID, userID)
+			if err != nil {
+				return service.ServiceInfo{}, fmt.Errorf("failed to check access: %w", err)
+			}
+			allowed = ok
+			access[svc.NodeID] = ok
+		}
+		if !allowed {
+			continue
+		}
+
+		cameras = append(cameras, svc)
+		switch lower := strings.ToLower(svc.Name); {
+		case lower == name:
+			exact = append(exact, svc)
+		case strings.Contains(lower, name):
+			partial = append(partial, svc)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) == 0 && len(partial) == 1:
+		return partial[0], nil
+	case len(exact) > 1 || len(partial) > 1:
+		return service.ServiceInfo{}, fmt.Errorf("%q matches several cameras: %s", name, cameraNames(append(exact, partial...)))
+	case len(cameras) == 0:
+		return service.ServiceInfo{}, fmt.Errorf("the user has no cameras")
+	default:
+		return service.ServiceInfo{}, fmt.Errorf("no camera named %q; cameras: %s", name, cameraNames(cameras))
+	}
+}
+
+// cameraNames lists the distinct names of services, sorted
+func cameraNames(services []service.ServiceInfo) string {
+	var names []string
+	for _, svc := range services {
+		if !slices.Contains(names, svc.Name) {
+			names = append(names, svc.Name)
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"unblink/database"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+	"unblink/server/service"
+)
+
+// FrameReviewTool asks a new question about the stored frames of a camera
+type FrameReviewTool struct {
+	db            *database.Client
+	registry      *service.ServiceRegistry
+	analyzer      *service.FrameAnalyzer
+	urlSigningKey []byte // Signs the links to cited frames
+}
+
+// NewFrameReviewTool creates a new frame review tool
+func NewFrameReviewTool(db *database.Client, registry *service.ServiceRegistry, analyzer *service.FrameAnalyzer, urlSigningKey []byte) *FrameReviewTool {
+	return &FrameReviewTool{
+		db:            db,
+		registry:      registry,
+		analyzer:      analyzer,
+		urlSigningKey: urlSigningKey,
+	}
+}
+
+// Name returns the tool name
+func (t *FrameReviewTool) Name() string {
+	return "review_camera_frames"
+}
+
+// Description returns the tool description
+func (t *FrameReviewTool) Description() string {
+	return "Look again at the stored frames of a camera in a time range (at most 24 hours) and answer a new question about them (example: did anyone pick up the package between 2 and 4pm?). " +
+		"Use this when search_video_events does not answer the question, since event summaries only describe what the original prompt asked for. " +
+		"Returns the answer and the frames it is based on, with links. Slower than search_video_events."
+}
+
+// Parameters returns the JSON schema for tool parameters
+func (t *FrameReviewTool) Parameters() map[string]any {
+	return map[string]any{
+		"camera": map[string]any{
+			"type":        "string",
+			"description": "Name of the camera (or part of it, case-insensitive, example: porch).",
+		},
+		"question": map[string]any{
+			"type":        "string",
+			"description": "What to find out from the frames (example: Did anyone pick up the package?).",
+		},
+		"from": map[string]any{
+			"type":        "string",
+			"description": "Start of the time range, ISO 8601 (example: 2026-02-01T14:00:00). Times without an offset are in the local time of the current date/time.",
+		},
+		"to": map[string]any{
+			"type":        "string",
+			"description": "End of the time range, ISO 8601, same format as from.",
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *FrameReviewTool) Execute(ctx context.Context, argumentsJSON string) string {
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return t.errorResult(fmt.Errorf("not authenticated"))
+	}
+
+	var args struct {
+		Camera   string `json:"camera"`
+		Question string `json:"question"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+	}
+	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+		return t.errorResult(fmt.Errorf("invalid arguments: %w", err))
+	}
+
+	from, err := parseSearchTime(args.From)
+	if err != nil {
+		return t.errorResult(fmt.Errorf("invalid from: %w", err))
+	}
+	to, err := parseSearchTime(args.To)
+	if err != nil {
+		return t.errorResult(fmt.Errorf("invalid to: %w", err))
+	}
+	if from.IsZero() || to.IsZero() {
+		return t.errorResult(fmt.Errorf("from and to must be provided"))
+	}
+
+	camera, err := resolveCamera(t.db, t.registry, userID, args.Camera)
+	if err != nil {
+		return t.errorResult(err)
+	}
+
+	result, err := t.analyzer.Analyze(ctx, camera.ID, from, to, args.Question, 0)
+	if err != nil {
+		log.Printf("[FrameReviewTool] Failed to analyze frames of service %s: %v", camera.ID, err)
+		return t.errorResult(err)
+	}
+
+	type citation struct {
+		FrameID     string `json:"frame_id"`
+		Timestamp   string `json:"timestamp"`
+		Observation string `json:"observation"`
+		URL         string `json:"url"`
+	}
+
+	expiresAt := time.Now().Add(videoSearchFrameURLTTL)
+	citations := make([]citation, len(result.Citations))
+	for i, c := range result.Citations {
+		citations[i] = citation{
+			FrameID:     c.FrameId,
+			Timestamp:   c.Timestamp.AsTime().Local().Format(time.RFC3339),
+			Observation: c.Observation,
+			URL:         storageurl.Sign(t.urlSigningKey, c.FrameId, expiresAt),
+		}
+	}
+
+	responseJSON, _ := json.Marshal(map[string]any{
+		"camera":          camera.Name,
+		"answer":          result.Answer,
+		"citations":       citations,
+		"frames_analyzed": result.FramesAnalyzed,
+		"frames_in_range": result.FramesInRange,
+	})
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(responseJSON))
+}
+
+// DisplayMessage returns a human-friendly message describing what the tool is doing
+func (t *FrameReviewTool) DisplayMessage(argumentsJSON string) string {
+	var args struct {
+		Camera string `json:"camera"`
+	}
+	json.Unmarshal([]byte(argumentsJSON), &args)
+	return fmt.Sprintf("Reviewing frames of %s", args.Camera)
+}
+
+func (t *FrameReviewTool) errorResult(err error) string {
+	errorJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(errorJSON))
+}
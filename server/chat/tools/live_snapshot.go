@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"unblink/database"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+	"unblink/server/service"
+	"unblink/server/webrtc"
+)
+
+// snapshotInstruction asks the VLM to answer a question about one frame; the
+// answer goes in the description so the usual response schema applies
+const snapshotInstruction = `You are looking at the current image of the security camera %q. Answer this question about it:
+
+%s
+
+Put a short, direct answer in the description, based only on what is visible. If the image does not show enough to answer, say so.
+Return only the objects relevant to the answer (MAX 10), with bounding boxes in NORMALIZED 1000 COORDINATES (0=top/left, 1000=bottom/right).`
+
+// LiveSnapshotTool answers questions about what a camera sees right now
+type LiveSnapshotTool struct {
+	db            *database.Client
+	registry      *service.ServiceRegistry
+	frameClient   *webrtc.FrameClient
+	storage       *webrtc.Storage
+	urlSigningKey []byte // Signs the link to the annotated frame
+}
+
+// NewLiveSnapshotTool creates a new live snapshot tool
+func NewLiveSnapshotTool(db *database.Client, registry *service.ServiceRegistry, frameClient *webrtc.FrameClient, storage *webrtc.Storage, urlSigningKey []byte) *LiveSnapshotTool {
+	return &LiveSnapshotTool{
+		db:            db,
+		registry:      registry,
+		frameClient:   frameClient,
+		storage:       storage,
+		urlSigningKey: urlSigningKey,
+	}
+}
+
+// Name returns the tool name
+func (t *LiveSnapshotTool) Name() string {
+	return "ask_camera_now"
+}
+
+// Description returns the tool description
+func (t *LiveSnapshotTool) Description() string {
+	return "Look at a camera right now and answer a question about the current image (example: is the garage door open right now?). " +
+		"Returns the answer, the relevant objects and a link to the annotated image. Use search_video_events for questions about the past."
+}
+
+// Parameters returns the JSON schema for tool parameters
+func (t *LiveSnapshotTool) Parameters() map[string]any {
+	return map[string]any{
+		"camera": map[string]any{
+			"type":        "string",
+			"description": "Name of the camera (or part of it, case-insensitive, example: garage).",
+		},
+		"question": map[string]any{
+			"type":        "string",
+			"description": "What to find out from the current image (example: Is the garage door open?).",
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *LiveSnapshotTool) Execute(ctx context.Context, argumentsJSON string) string {
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"error": "not authenticated"}`)
+	}
+
+	var args struct {
+		Camera   string `json:"camera"`
+		Question string `json:"question"`
+	}
+	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), fmt.Sprintf(`{"error": "invalid arguments: %v"}`, err))
+	}
+	if args.Question == "" {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"error": "question must be provided"}`)
+	}
+
+	camera, err := resolveCamera(t.db, t.registry, userID, args.Camera)
+	if err != nil {
+		return t.errorResult(err)
+	}
+
+	frame, err := t.registry.CaptureFrame(ctx, camera.ID)
+	if err != nil {
+		log.Printf("[LiveSnapshotTool] Failed to capture frame of service %s: %v", camera.ID, err)
+		return t.errorResult(fmt.Errorf("could not get an image from %s: %w", camera.Name, err))
+	}
+
+	// Same preparation as indexed frames: max 800px edge, timestamp burned in
+	data, err := webrtc.PreprocessFrame(frame.Data, frame.Timestamp)
+	if err != nil {
+		log.Printf("[LiveSnapshotTool] Failed to preprocess frame of service %s: %v", camera.ID, err)
+		data = frame.Data
+	}
+
+	instruction := fmt.Sprintf(snapshotInstruction, camera.Name, args.Question)
+	response, err := t.frameClient.SendFrameBatchWithStructuredOutput(ctx, []*webrtc.Frame{{Data: data, Timestamp: frame.Timestamp, ServiceID: camera.ID}}, instruction, "")
+	if err != nil {
+		return t.errorResult(fmt.Errorf("image analysis failed: %w", err))
+	}
+	if len(response.Choices) == 0 {
+		return t.errorResult(fmt.Errorf("image analysis returned no answer"))
+	}
+
+	content := response.Choices[0].Message.Content
+	var vlmResp webrtc.VLMResponse
+	if err := json.Unmarshal([]byte(content), &vlmResp); err != nil {
+		return t.errorResult(fmt.Errorf("failed to parse image analysis: %w", err))
+	}
+
+	type objectResult struct {
+		Label string    `json:"label"`
+		BBox  []float64 `json:"bbox"`
+	}
+	objects := make([]objectResult, len(vlmResp.Objects))
+	for i, obj := range vlmResp.Objects {
+		objects[i] = objectResult{Label: obj.Label, BBox: obj.BBox}
+	}
+
+	result := map[string]any{
+		"camera":      camera.Name,
+		"service_id":  camera.ID,
+		"captured_at": frame.Timestamp.Local().Format(time.RFC3339),
+		"answer":      vlmResp.Description,
+		"objects":     objects,
+	}
+
+	annotated, err := webrtc.AnnotateFrame(data, content)
+	if err != nil {
+		log.Printf("[LiveSnapshotTool] Failed to annotate frame: %v", err)
+		annotated = data
+	}
+	if itemID, err := t.saveSnapshot(ctx, camera.ID, annotated, frame.Timestamp); err != nil {
+		log.Printf("[LiveSnapshotTool] Failed to save annotated frame of service %s: %v", camera.ID, err)
+	} else {
+		result["frame"] = map[string]string{
+			"id":  itemID,
+			"url": storageurl.Sign(t.urlSigningKey, itemID, time.Now().Add(videoSearchFrameURLTTL)),
+		}
+	}
+
+	responseJSON, _ := json.Marshal(result)
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(responseJSON))
+}
+
+// DisplayMessage returns a human-friendly message describing what the tool is doing
+func (t *LiveSnapshotTool) DisplayMessage(argumentsJSON string) string {
+	var args struct {
+		Camera string `json:"camera"`
+	}
+	json.Unmarshal([]byte(argumentsJSON), &args)
+	return fmt.Sprintf("Looking at %s", args.Camera)
+}
+
+// saveSnapshot stores an annotated frame as a storage item and returns its ID.
+// It is kept apart from the recorded frames, so event frame links and
+// re-analysis do not pick up the boxes drawn on it.
+func (t *LiveSnapshotTool) saveSnapshot(ctx context.Context, serviceID string, data []byte, timestamp time.Time) (string, error) {
+	backend := t.storage.Backend()
+	if backend == nil {
+		return "", fmt.Errorf("no storage backend configured")
+	}
+
+	key := path.Join("snapshots", serviceID, uuid.New().String()+".jpg")
+	if err := backend.Put(ctx, key, data, "image/jpeg"); err != nil {
+		return "", err
+	}
+	return t.db.SaveStorageItem(serviceID, key, timestamp, int64(len(data)), database.StorageTypeSnapshot, "image/jpeg", &database.FrameMetadata{})
+}
+
+func (t *LiveSnapshotTool) errorResult(err error) string {
+	errorJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(errorJSON))
+}
@@ -4,20 +4,65 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"slices"
 	"strings"
+	"time"
 
 	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+	"unblink/server/internal/timeutil"
+	"unblink/server/models"
 )
 
+const (
+	videoSearchDefaultLimit = 10 // Events returned when no limit is given
+	videoSearchMaxLimit     = 50
+	videoSearchFramesPerHit = 3         // Frame links per event, newest first
+	videoSearchFrameURLTTL  = time.Hour // Lifetime of signed frame links
+)
+
+// videoSearchTimeLayouts are the accepted from/to formats; times without an
+// offset are in server local time, like the date/time in the system prompt
+var videoSearchTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var videoSearchGranularities = []string{
+	string(timeutil.GranularitySecond),
+	string(timeutil.GranularityMinute),
+	string(timeutil.GranularityHour),
+	string(timeutil.GranularityDay),
+	string(timeutil.GranularityWeek),
+	string(timeutil.GranularityMonth),
+}
+
+// VideoSearchDatabase defines the database operations of the video search tool
+type VideoSearchDatabase interface {
+	SearchEvents(params database.EventSearchParams) ([]*servicev1.EventSearchResult, error)
+	GetService(id string) (*servicev1.Service, error)
+	ListFramesInRange(serviceID string, from, to time.Time, limit int) ([]*database.StorageEntry, error)
+}
+
 // VideoSearchTool is a tool for searching videos
 type VideoSearchTool struct {
-	db *database.Client
+	db            VideoSearchDatabase
+	embedder      *models.Embedder // Embeds queries for semantic search (nil = keywords only)
+	urlSigningKey []byte           // Signs frame links (see storageurl.Sign)
 }
 
 // NewVideoSearchTool creates a new video search tool
-func NewVideoSearchTool(db *database.Client) *VideoSearchTool {
+func NewVideoSearchTool(db VideoSearchDatabase, embedder *models.Embedder, urlSigningKey []byte) *VideoSearchTool {
 	return &VideoSearchTool{
-		db: db,
+		db:            db,
+		embedder:      embedder,
+		urlSigningKey: urlSigningKey,
 	}
 }
 
@@ -28,66 +73,197 @@ func (t *VideoSearchTool) Name() string {
 
 // Description returns the tool description
 func (t *VideoSearchTool) Description() string {
-	return "Search video events of the users. Returns matching VLM-indexed video events from user cameras with summaries and metadata. Only return results from this tool - do not hallucinate."
+	return "Search video events of the user's cameras. Returns matching VLM-indexed video events with the camera, time span, summary, detected objects and links to frames. " +
+		"Without query or keywords, lists the latest events matching the filters in chronological order (use this for questions like \"what happened at the garage yesterday between 6 and 8pm\"). " +
+		"Only return results from this tool - do not hallucinate."
 }
 
 // Parameters returns the JSON schema for tool parameters
 func (t *VideoSearchTool) Parameters() map[string]any {
 	return map[string]any{
+		"query": map[string]any{
+			"type":        "string",
+			"description": "What happened, in plain words (example: someone carrying a box to the door). Matched by meaning, not only exact words.",
+		},
 		"keywords": map[string]any{
 			"type":        "array",
 			"items":       map[string]any{"type": "string"},
 			"description": "List of keywords to search for in video event (example keywords: person, car, dog, cat, bicycle, etc.)",
 		},
+		"from": map[string]any{
+			"type":        "string",
+			"description": "Only events at or after this time, ISO 8601 (example: 2026-02-01T18:00:00). Times without an offset are in the local time of the current date/time.",
+		},
+		"to": map[string]any{
+			"type":        "string",
+			"description": "Only events before this time, ISO 8601, same format as from.",
+		},
+		"cameras": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Only events of cameras whose name contains one of these (case-insensitive, example: garage).",
+		},
+		"labels": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Only events in which an object with one of these labels was detected (singular nouns, example: person, car).",
+		},
+		"granularity": map[string]any{
+			"type":        "string",
+			"enum":        videoSearchGranularities,
+			"description": "Only events covering a span of this size. hour, day and week events summarize the shorter events of that hour, day or week; use them for broad questions over long ranges.",
+		},
+		"limit": map[string]any{
+			"type":        "integer",
+			"description": fmt.Sprintf("Maximum number of events to return (default %d, at most %d).", videoSearchDefaultLimit, videoSearchMaxLimit),
+		},
 	}
 }
 
 // Execute executes the tool with the given arguments
 func (t *VideoSearchTool) Execute(ctx context.Context, argumentsJSON string) string {
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"error": "not authenticated"}`)
+	}
+
 	var args struct {
-		Keywords []string `json:"keywords"`
+		Query       string   `json:"query"`
+		Keywords    []string `json:"keywords"`
+		From        string   `json:"from"`
+		To          string   `json:"to"`
+		Cameras     []string `json:"cameras"`
+		Labels      []string `json:"labels"`
+		Granularity string   `json:"granularity"`
+		Limit       int      `json:"limit"`
 	}
 
 	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
 		return fmt.Sprintf("tool %s returned: %s", t.Name(), fmt.Sprintf(`{"error": "invalid arguments: %v"}`, err))
 	}
 
-	if len(args.Keywords) == 0 {
-		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"error": "keywords must be provided"}`)
+	params := database.EventSearchParams{
+		Query:        strings.TrimSpace(args.Query + " " + strings.Join(args.Keywords, " ")),
+		UserID:       userID,
+		ServiceNames: nonEmpty(args.Cameras),
+		Labels:       nonEmpty(args.Labels),
+		Granularity:  args.Granularity,
+		Limit:        videoSearchDefaultLimit,
+	}
+	if args.Limit > 0 {
+		params.Limit = min(args.Limit, videoSearchMaxLimit)
+	}
+
+	if params.Granularity != "" && !slices.Contains(videoSearchGranularities, params.Granularity) {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), fmt.Sprintf(`{"error": "granularity must be one of: %s"}`, strings.Join(videoSearchGranularities, ", ")))
+	}
+
+	// The errors quote the given time, so they are marshaled rather than formatted
+	var err error
+	if params.From, err = parseSearchTime(args.From); err != nil {
+		errorJSON, _ := json.Marshal(map[string]string{"error": "invalid from: " + err.Error()})
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), string(errorJSON))
+	}
+	if params.To, err = parseSearchTime(args.To); err != nil {
+		errorJSON, _ := json.Marshal(map[string]string{"error": "invalid to: " + err.Error()})
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), string(errorJSON))
+	}
+	if !params.From.IsZero() && !params.To.IsZero() && !params.From.Before(params.To) {
+		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"error": "from must be before to"}`)
+	}
+
+	// Rank events by keyword match and, if available, semantic similarity
+	if params.Query != "" && t.embedder != nil {
+		if embedding, err := t.embedder.EmbedOne(ctx, params.Query); err != nil {
+			log.Printf("[VideoSearchTool] Failed to embed query, using keywords only: %v", err)
+		} else {
+			params.Embedding = embedding
+		}
 	}
 
-	// Search events by keywords
-	events, err := t.db.SearchEventsByPayload(args.Keywords)
+	found, err := t.db.SearchEvents(params)
 	if err != nil {
 		return fmt.Sprintf("tool %s returned: %s", t.Name(), fmt.Sprintf(`{"error": "search failed: %v"}`, err))
 	}
 
-	if len(events) == 0 {
+	if len(found) == 0 {
 		return fmt.Sprintf("tool %s returned: %s", t.Name(), `{"result": "There is no such video in the database."}`)
 	}
 
 	// Convert events to JSON response
+	type frameLink struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		Timestamp string `json:"timestamp"`
+	}
 	type eventResult struct {
-		ID        string         `json:"id"`
-		ServiceID string         `json:"service_id"`
-		Payload   map[string]any `json:"payload"`
-		CreatedAt string         `json:"created_at"`
-	}
-
-	results := make([]eventResult, len(events))
-	for i, e := range events {
-		results[i] = eventResult{
-			ID:        e.Id,
-			ServiceID: e.ServiceId,
-			Payload:   e.Payload.AsMap(),
-			CreatedAt: e.CreatedAt.AsTime().Format("2006-01-02T15:04:05Z"),
+		ID          string      `json:"id"`
+		ServiceID   string      `json:"service_id"`
+		Camera      string      `json:"camera,omitempty"`
+		From        string      `json:"from,omitempty"`
+		To          string      `json:"to,omitempty"`
+		Granularity string      `json:"granularity,omitempty"`
+		Description string      `json:"description"`
+		Objects     []string    `json:"objects,omitempty"`
+		Score       float64     `json:"score,omitempty"`
+		Frames      []frameLink `json:"frames,omitempty"`
+	}
+
+	cameraNames := make(map[string]string)
+	expiresAt := time.Now().Add(videoSearchFrameURLTTL)
+
+	results := make([]eventResult, len(found))
+	for i, r := range found {
+		e := r.Event
+		payload := e.Payload.AsMap()
+		response, _ := payload["response"].(map[string]any)
+		description, _ := response["description"].(string)
+		granularity, _ := payload["granularity"].(string)
+
+		camera, seen := cameraNames[e.ServiceId]
+		if !seen {
+			if svc, err := t.db.GetService(e.ServiceId); err == nil && svc != nil {
+				camera = svc.Name
+			}
+			cameraNames[e.ServiceId] = camera
+		}
+
+		result := eventResult{
+			ID:          e.Id,
+			ServiceID:   e.ServiceId,
+			Camera:      camera,
+			Granularity: granularity,
+			Description: description,
+			Objects:     objectLabels(response),
+			Score:       r.Score,
+		}
+
+		from, fromErr := time.Parse(time.RFC3339, stringField(payload, "from_iso"))
+		to, toErr := time.Parse(time.RFC3339, stringField(payload, "to_iso"))
+		if fromErr == nil && toErr == nil {
+			result.From = from.Local().Format(time.RFC3339)
+			result.To = to.Local().Format(time.RFC3339)
+
+			frames, err := t.db.ListFramesInRange(e.ServiceId, from, to, videoSearchFramesPerHit)
+			if err != nil {
+				log.Printf("[VideoSearchTool] Failed to list frames of event %s: %v", e.Id, err)
+			}
+			for _, f := range frames {
+				result.Frames = append(result.Frames, frameLink{
+					ID:        f.ID,
+					URL:       storageurl.Sign(t.urlSigningKey, f.ID, expiresAt),
+					Timestamp: f.Timestamp.Local().Format(time.RFC3339),
+				})
+			}
 		}
+
+		results[i] = result
 	}
 
 	responseJSON, _ := json.Marshal(map[string]any{
-		"result": fmt.Sprintf("Found %d matching video(s)", len(events)),
+		"result": fmt.Sprintf("Found %d matching video(s)", len(found)),
 		"videos": results,
-		"count":  len(events),
+		"count":  len(found),
 	})
 
 	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(responseJSON))
@@ -96,8 +272,67 @@ func (t *VideoSearchTool) Execute(ctx context.Context, argumentsJSON string) str
 // DisplayMessage returns a human-friendly message describing what the tool is doing
 func (t *VideoSearchTool) DisplayMessage(argumentsJSON string) string {
 	var args struct {
+		Query    string   `json:"query"`
 		Keywords []string `json:"keywords"`
+		Cameras  []string `json:"cameras"`
 	}
 	json.Unmarshal([]byte(argumentsJSON), &args)
-	return fmt.Sprintf("Searching videos for: %s", strings.Join(args.Keywords, ", "))
+	if args.Query != "" {
+		return fmt.Sprintf("Searching videos for: %s", args.Query)
+	}
+	if len(args.Keywords) > 0 {
+		return fmt.Sprintf("Searching videos for: %s", strings.Join(args.Keywords, ", "))
+	}
+	if len(args.Cameras) > 0 {
+		return fmt.Sprintf("Looking through events of: %s", strings.Join(args.Cameras, ", "))
+	}
+	return "Looking through recent events"
+}
+
+// parseSearchTime parses an RFC 3339 time or a local time without offset
+// (empty = zero time)
+func parseSearchTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range videoSearchTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected ISO 8601 like 2026-02-01T18:00:00, got %q", value)
+}
+
+// objectLabels returns the distinct object labels of a VLM response
+func objectLabels(response map[string]any) []string {
+	objects, _ := response["objects"].([]any)
+
+	var labels []string
+	for _, o := range objects {
+		obj, _ := o.(map[string]any)
+		label, _ := obj["label"].(string)
+		if label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+)
+
+const videoSearchPrefix = "tool search_video_events returned: "
+
+// fakeVideoSearchDatabase records the search parameters and returns canned results
+type fakeVideoSearchDatabase struct {
+	VideoSearchDatabase
+	params  *database.EventSearchParams
+	results []*servicev1.EventSearchResult
+	frames  []*database.StorageEntry
+}
+
+func (db *fakeVideoSearchDatabase) SearchEvents(params database.EventSearchParams) ([]*servicev1.EventSearchResult, error) {
+	db.params = &params
+	return db.results, nil
+}
+
+func (db *fakeVideoSearchDatabase) GetService(id string) (*servicev1.Service, error) {
+	return &servicev1.Service{Id: id, Name: "Garage"}, nil
+}
+
+func (db *fakeVideoSearchDatabase) ListFramesInRange(serviceID string, from, to time.Time, limit int) ([]*database.StorageEntry, error) {
+	return db.frames, nil
+}
+
+func executeVideoSearch(t *testing.T, db *fakeVideoSearchDatabase, args string) map[string]any {
+	tool := NewVideoSearchTool(db, nil, []byte("test-key"))
+	ctx := ctxutil.SetUserIDInContext(context.Background(), "alice")
+
+	output := tool.Execute(ctx, args)
+	require.True(t, strings.HasPrefix(output, videoSearchPrefix), output)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(output, videoSearchPrefix)), &response))
+	return response
+}
+
+func TestVideoSearchFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want database.EventSearchParams
+	}{
+		{
+			name: "no filters",
+			args: `{}`,
+			want: database.EventSearchParams{Limit: videoSearchDefaultLimit},
+		},
+		{
+			name: "query and keywords",
+			args: `{"query": "someone at the door", "keywords": ["person", "box"]}`,
+			want: database.EventSearchParams{Query: "someone at the door person box", Limit: videoSearchDefaultLimit},
+		},
+		{
+			name: "local time range",
+			args: `{"from": "2026-02-01T18:00", "to": "2026-02-01 20:00:00"}`,
+			want: database.EventSearchParams{
+				From:  time.Date(2026, 2, 1, 18, 0, 0, 0, time.Local),
+				To:    time.Date(2026, 2, 1, 20, 0, 0, 0, time.Local),
+				Limit: videoSearchDefaultLimit,
+			},
+		},
+		{
+			name: "time range with offset",
+			args: `{"from": "2026-02-01T18:00:00Z", "to": "2026-02-02"}`,
+			want: database.EventSearchParams{
+				From:  time.Date(2026, 2, 1, 18, 0, 0, 0, time.UTC),
+				To:    time.Date(2026, 2, 2, 0, 0, 0, 0, time.Local),
+				Limit: videoSearchDefaultLimit,
+			},
+		},
+		{
+			name: "only from",
+			args: `{"from": "2026-02-01"}`,
+			want: database.EventSearchParams{
+				From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local),
+				Limit: videoSearchDefaultLimit,
+			},
+		},
+		{
+			name: "cameras",
+			args: `{"cameras": [" garage ", "", "front door"]}`,
+			want: database.EventSearchParams{ServiceNames: []string{"garage", "front door"}, Limit: videoSearchDefaultLimit},
+		},
+		{
+			name: "labels",
+			args: `{"labels": ["person", " ", "car"]}`,
+			want: database.EventSearchParams{Labels: []string{"person", "car"}, Limit: videoSearchDefaultLimit},
+		},
+		{
+			name: "granularity",
+			args: `{"granularity": "hour"}`,
+			want: database.EventSearchParams{Granularity: "hour", Limit: videoSearchDefaultLimit},
+		},
+		{
+			name: "limit",
+			args: `{"limit": 5}`,
+			want: database.EventSearchParams{Limit: 5},
+		},
+		{
+			name: "limit capped",
+			args: `{"limit": 500}`,
+			want: database.EventSearchParams{Limit: videoSearchMaxLimit},
+		},
+		{
+			name: "negative limit",
+			args: `{"limit": -3}`,
+			want: database.EventSearchParams{Limit: videoSearchDefaultLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeVideoSearchDatabase{}
+			response := executeVideoSearch(t, db, tt.args)
+			assert.Equal(t, "There is no such video in the database.", response["result"])
+
+			require.NotNil(t, db.params)
+			tt.want.UserID = "alice"
+			assert.True(t, tt.want.From.Equal(db.params.From), "from: want %v, got %v", tt.want.From, db.params.From)
+			assert.True(t, tt.want.To.Equal(db.params.To), "to: want %v, got %v", tt.want.To, db.params.To)
+			tt.want.From, tt.want.To = db.params.From, db.params.To
+			assert.Equal(t, tt.want, *db.params)
+		})
+	}
+}
+
+func TestVideoSearchInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr string
+	}{
+		{name: "from after to", args: `{"from": "2026-02-01T20:00", "to": "2026-02-01T18:00"}`, wantErr: "from must be before to"},
+		{name: "empty range", args: `{"from": "2026-02-01T18:00", "to": "2026-02-01T18:00"}`, wantErr: "from must be before to"},
+		{name: "invalid from", args: `{"from": "yesterday"}`, wantErr: "invalid from"},
+		{name: "invalid to", args: `{"to": "02/01/2026"}`, wantErr: "invalid to"},
+		{name: "unknown granularity", args: `{"granularity": "year"}`, wantErr: "granularity must be one of"},
+		{name: "malformed JSON", args: `{"limit": "ten"}`, wantErr: "invalid arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeVideoSearchDatabase{}
+			response := executeVideoSearch(t, db, tt.args)
+			assert.Contains(t, response["error"], tt.wantErr)
+			assert.Nil(t, db.params, "must not search")
+		})
+	}
+}
+
+func TestVideoSearchRequiresUser(t *testing.T) {
+	db := &fakeVideoSearchDatabase{}
+	tool := NewVideoSearchTool(db, nil, []byte("test-key"))
+
+	output := tool.Execute(context.Background(), `{}`)
+	assert.Equal(t, videoSearchPrefix+`{"error": "not authenticated"}`, output)
+	assert.Nil(t, db.params)
+}
+
+func TestVideoSearchResults(t *testing.T) {
+	payload, err := structpb.NewStruct(map[string]any{
+		"from_iso":    "2026-02-01T18:00:00Z",
+		"to_iso":      "2026-02-01T18:00:30Z",
+		"granularity": "second",
+		"response": map[string]any{
+			"description": "A person carries a box to the door",
+			"objects": []any{
+				map[string]any{"label": "person"},
+				map[string]any{"label": "box"},
+				map[string]any{"label": "person"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	db := &fakeVideoSearchDatabase{
+		results: []*servicev1.EventSearchResult{{
+			Event: &servicev1.Event{Id: "e1", ServiceId: "cam1", Payload: payload},
+			Score: 0.5,
+		}},
+		frames: []*database.StorageEntry{
+			{ID: "f1", ServiceID: "cam1", Timestamp: time.Date(2026, 2, 1, 18, 0, 10, 0, time.UTC)},
+		},
+	}
+	response := executeVideoSearch(t, db, `{"query": "box"}`)
+	assert.Equal(t, float64(1), response["count"])
+
+	videos, _ := response["videos"].([]any)
+	require.Len(t, videos, 1)
+	video := videos[0].(map[string]any)
+	assert.Equal(t, "e1", video["id"])
+	assert.Equal(t, "Garage", video["camera"])
+	assert.Equal(t, "A person carries a box to the door", video["description"])
+	assert.Equal(t, []any{"person", "box"}, video["objects"])
+	assert.Equal(t, time.Date(2026, 2, 1, 18, 0, 0, 0, time.UTC).Local().Format(time.RFC3339), video["from"])
+
+	// Frame links are signed for the frame they point to
+	frames, _ := video["frames"].([]any)
+	require.Len(t, frames, 1)
+	link, err := url.Parse(frames[0].(map[string]any)["url"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "/storage/f1", link.Path)
+	assert.NoError(t, storageurl.Verify([]byte("test-key"), "f1", link.Query()))
+}
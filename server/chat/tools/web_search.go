@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"unblink/server/models"
+	"unblink/server/websearch"
+)
+
+const (
+	webSearchResults      = 5    // Results requested from the provider
+	webSearchPages        = 3    // Top results whose pages are read
+	webSearchPageMaxChars = 8000 // Per page, so one long page does not crowd out the others
+	webSearchMaxTokens    = 4000 // Whole tool result, trimmed by the content trimmer
+	webSearchPageTimeout  = 10 * time.Second
+)
+
+// WebSearchTool searches the web and reads the top result pages. It is only
+// offered for messages sent with use_web_search.
+type WebSearchTool struct {
+	provider websearch.Provider
+	trimmer  *models.Trimmer // Trims the pages to the chat model's context
+	client   *http.Client
+}
+
+// NewWebSearchTool creates a new web search tool
+func NewWebSearchTool(provider websearch.Provider, trimmer *models.Trimmer) *WebSearchTool {
+	return &WebSearchTool{
+		provider: provider,
+		trimmer:  trimmer,
+		client:   websearch.NewPageClient(provider, webSearchPageTimeout),
+	}
+}
+
+// Name returns the tool name
+func (t *WebSearchTool) Name() string {
+	return "web_search"
+}
+
+// Description returns the tool description
+func (t *WebSearchTool) Description() string {
+	return "Search the web and read the top result pages. Use it for information that is not about the user's cameras (example: the weather last night, what a delivery company's van looks like). " +
+		"Returns the pages as sections with their source URL; cite the sources you use."
+}
+
+// Parameters returns the JSON schema for tool parameters
+func (t *WebSearchTool) Parameters() map[string]any {
+	return map[string]any{
+		"query": map[string]any{
+			"type":        "string",
+			"description": "Web search query (example: heavy rain Amsterdam 14 October 2026).",
+		},
+	}
+}
+
+// Execute executes the tool with the given arguments
+func (t *WebSearchTool) Execute(ctx context.Context, argumentsJSON string) string {
+	var args struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+		return t.errorResult(fmt.Errorf("invalid arguments: %w", err))
+	}
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return t.errorResult(fmt.Errorf("query must be provided"))
+	}
+
+	results, err := t.provider.Search(ctx, query, webSearchResults)
+	if err != nil {
+		log.Printf("[WebSearchTool] %s search failed: %v", t.provider.Name(), err)
+		return t.errorResult(fmt.Errorf("web search failed: %w", err))
+	}
+	if len(results) == 0 {
+		return fmt.Sprintf("tool %s returned: no results for %q", t.Name(), query)
+	}
+
+	pages := t.fetchPages(ctx, results[:min(webSearchPages, len(results))])
+
+	// One section per result in the news format, so the trimmer drops whole
+	// results from the end before cutting into one
+	sections := make([]string, len(results))
+	for i, result := range results {
+		text := result.Snippet
+		if i < len(pages) && pages[i] != "" {
+			text = pages[i]
+		}
+		if len(text) > webSearchPageMaxChars {
+			text = text[:webSearchPageMaxChars] + "\n[Page truncated...]"
+		}
+		sections[i] = fmt.Sprintf("## %s\n**Source:** %s\n\n%s", result.Title, result.URL, text)
+	}
+
+	content := strings.Join(sections, "\n\n---\n\n")
+	if t.trimmer != nil {
+		content = t.trimmer.TrimNewsContent(content, webSearchMaxTokens)
+	}
+	content = strings.ToValidUTF8(content, "") // Truncation may split a character
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), content)
+}
+
+// DisplayMessage returns a human-friendly message describing what the tool is doing
+func (t *WebSearchTool) DisplayMessage(argumentsJSON string) string {
+	var args struct {
+		Query string `json:"query"`
+	}
+	json.Unmarshal([]byte(argumentsJSON), &args)
+	return fmt.Sprintf("Searching the web for %q", args.Query)
+}
+
+// fetchPages reads the pages of results in parallel; pages that cannot be read
+// are left empty
+func (t *WebSearchTool) fetchPages(ctx context.Context, results []websearch.Result) []string {
+	pages := make([]string, len(results))
+	var wg sync.WaitGroup
+	for i, result := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, err := websearch.FetchPage(ctx, t.client, result.URL)
+			if err != nil {
+				log.Printf("[WebSearchTool] Failed to read %s: %v", result.URL, err)
+				return
+			}
+			pages[i] = text
+		}()
+	}
+	wg.Wait()
+	return pages
+}
+
+func (t *WebSearchTool) errorResult(err error) string {
+	errorJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return fmt.Sprintf("tool %s returned: %s", t.Name(), string(errorJSON))
+}
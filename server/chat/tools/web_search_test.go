@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"unblink/server/models"
+	"unblink/server/websearch"
+)
+
+const webSearchPrefix = "tool web_search returned: "
+
+func newStubSearch(t *testing.T) (websearch.Provider, string) {
+	server := httptest.NewServer(websearch.NewStubHandler())
+	t.Cleanup(server.Close)
+
+	provider, err := websearch.NewProvider("stub", server.URL, "")
+	require.NoError(t, err)
+	return provider, server.URL
+}
+
+func TestWebSearchToolExecute(t *testing.T) {
+	provider, baseURL := newStubSearch(t)
+	tool := NewWebSearchTool(provider, nil)
+
+	output := tool.Execute(context.Background(), `{"query": "red van"}`)
+	require.True(t, strings.HasPrefix(output, webSearchPrefix), output)
+
+	// One section per result in the news format
+	sections := strings.Split(strings.TrimPrefix(output, webSearchPrefix), "\n\n---\n\n")
+	require.Len(t, sections, 3)
+	for i, section := range sections {
+		n := i + 1
+		assert.True(t, strings.HasPrefix(section, fmt.Sprintf("## Stub result %d for red van\n**Source:** %s/pages/%d?q=%s\n\n", n, baseURL, n, url.QueryEscape("red van"))), section)
+
+		// The page text, not the snippet, without script, navigation and footer
+		assert.Contains(t, section, fmt.Sprintf("Stub page %d about red van\nParagraph 1 of page %d: this stub page is about red van.", n, n))
+		assert.Contains(t, section, fmt.Sprintf("Paragraph 20 of page %d", n))
+		assert.NotContains(t, section, "Snippet")
+		assert.NotContains(t, section, "console.log")
+		assert.NotContains(t, section, "Home")
+		assert.NotContains(t, section, "Stub footer")
+		assert.NotContains(t, section, "margin")
+	}
+}
+
+func TestWebSearchToolTrimsContent(t *testing.T) {
+	provider, _ := newStubSearch(t)
+
+	// Leaves room for about 1500 characters: the first page and part of the second
+	trimmer := models.NewTrimmer(3000, 0)
+	tool := NewWebSearchTool(provider, trimmer)
+
+	output := tool.Execute(context.Background(), `{"query": "red van"}`)
+	content := strings.TrimPrefix(output, webSearchPrefix)
+	assert.LessOrEqual(t, len(content), 1500+len("\n\n[Article content truncated...]"))
+
+	sections := strings.Split(content, "\n\n---\n\n")
+	require.Len(t, sections, 2)
+	assert.Contains(t, sections[0], "Paragraph 20 of page 1")
+	assert.True(t, strings.HasPrefix(sections[1], "## Stub result 2 for red van"))
+	assert.True(t, strings.HasSuffix(sections[1], "[Article content truncated...]"))
+	assert.NotContains(t, content, "Stub result 3")
+}
+
+// fixedProvider returns the same results for every query
+type fixedProvider []websearch.Result
+
+func (p fixedProvider) Name() string { return "fixed" }
+
+func (p fixedProvider) Search(ctx context.Context, query string, limit int) ([]websearch.Result, error) {
+	return p, nil
+}
+
+func TestWebSearchToolSkipsPrivatePages(t *testing.T) {
+	var fetched bool
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("internal page"))
+	}))
+	defer page.Close()
+
+	// Only the stub server's pages may be local
+	tool := NewWebSearchTool(fixedProvider{{Title: "Internal", URL: page.URL, Snippet: "The snippet."}}, nil)
+
+	output := tool.Execute(context.Background(), `{"query": "anything"}`)
+	assert.False(t, fetched)
+	assert.Contains(t, output, "**Source:** "+page.URL+"\n\nThe snippet.")
+	assert.NotContains(t, output, "internal page")
+}
+
+func TestWebSearchToolErrors(t *testing.T) {
+	provider, _ := newStubSearch(t)
+	tool := NewWebSearchTool(provider, nil)
+
+	assert.Contains(t, tool.Execute(context.Background(), `{"query": "  "}`), "query must be provided")
+	assert.Contains(t, tool.Execute(context.Background(), `not json`), "invalid arguments")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // Config represents the server configuration
@@ -23,6 +24,9 @@ type Config struct {
 	// JWT secret for authentication
 	JWTSecret string `json:"jwt_secret"`
 
+	// Node tokens expire after this many hours (default 720); nodes rotate them halfway through
+	NodeTokenTTLHours int `json:"node_token_ttl_hours,omitempty"`
+
 	// Main chat model settings
 	ChatOpenAIModel   string `json:"chat_openai_model"`
 	ChatOpenAIBaseURL string `json:"chat_openai_base_url"`
@@ -33,6 +37,12 @@ type Config struct {
 	FastOpenAIBaseURL string `json:"fast_openai_base_url"`
 	FastOpenAIAPIKey  string `json:"fast_openai_api_key,omitempty"`
 
+	// Embedding model for semantic event search (disabled without a model);
+	// base URL and API key fall back to the chat model's
+	EmbeddingOpenAIModel   string `json:"embedding_openai_model,omitempty"`
+	EmbeddingOpenAIBaseURL string `json:"embedding_openai_base_url,omitempty"`
+	EmbeddingOpenAIAPIKey  string `json:"embedding_openai_api_key,omitempty"`
+
 	// Content trimming safety margin (percentage)
 	ContentTrimSafetyMargin int `json:"content_trim_safety_margin"`
 
@@ -55,6 +65,37 @@ type Config struct {
 
 	// Frame indexing settings
 	EnableIndexing bool `json:"enable_indexing"` // Enable frame indexing (default true). When true, batch manager is not created.
+
+	// Continuous recording settings
+	EnableRecording         bool `json:"enable_recording"`                    // Record rolling MP4 segments for every online service
+	RecordingSegmentSeconds int  `json:"recording_segment_seconds,omitempty"` // Segment length in seconds (default 60)
+	RecordingRetentionHours int  `json:"recording_retention_hours,omitempty"` // Default retention when a service has none set (default 24)
+
+	// Clip export settings
+	ClipPreRollSeconds  int `json:"clip_pre_roll_seconds,omitempty"`  // Default seconds added before a clip (default 5)
+	ClipPostRollSeconds int `json:"clip_post_roll_seconds,omitempty"` // Default seconds added after a clip (default 5)
+
+	// Storage backend for frames, segments and clips
+	StorageBackend string `json:"storage_backend,omitempty"` // "local" (default) or "s3"
+	S3Endpoint     string `json:"s3_endpoint,omitempty"`     // Custom endpoint for R2/MinIO (empty = AWS)
+	S3Region       string `json:"s3_region,omitempty"`       // Bucket region ("auto" for R2)
+	S3Bucket       string `json:"s3_bucket,omitempty"`
+	S3AccessKey    string `json:"s3_access_key,omitempty"`
+	S3SecretKey    string `json:"s3_secret_key,omitempty"`
+	S3UsePathStyle bool   `json:"s3_use_path_style,omitempty"` // Path-style addressing (required for MinIO)
+
+	// MQTT publishing for Home Assistant (disabled without a broker URL)
+	MQTTBrokerURL       string `json:"mqtt_broker_url,omitempty"` // e.g. "tcp://localhost:1883" or "ssl://host:8883"
+	MQTTUsername        string `json:"mqtt_username,omitempty"`
+	MQTTPassword        string `json:"mqtt_password,omitempty"`
+	MQTTClientID        string `json:"mqtt_client_id,omitempty"`        // Default "unblink-server"
+	MQTTTopicPrefix     string `json:"mqtt_topic_prefix,omitempty"`     // Default "unblink"
+	MQTTDiscoveryPrefix string `json:"mqtt_discovery_prefix,omitempty"` // Home Assistant discovery prefix (default "homeassistant")
+
+	// Web search for chat messages sent with use_web_search (disabled without a provider)
+	WebSearchProvider string `json:"web_search_provider,omitempty"` // "brave", "searxng" or "stub" (local test server)
+	WebSearchBaseURL  string `json:"web_search_base_url,omitempty"` // Instance URL for searxng and stub; optional for brave
+	WebSearchAPIKey   string `json:"web_search_api_key,omitempty"`  // Required for brave
 }
 
 // ConfigPath returns the default config file path
@@ -158,6 +199,36 @@ func (c *Config) Validate() error {
 		missing = append(missing, "bridge_max_retries")
 	}
 
+	switch c.StorageBackend {
+	case "", "local":
+	case "s3":
+		if c.S3Bucket == "" {
+			missing = append(missing, "s3_bucket")
+		}
+		if c.S3AccessKey == "" {
+			missing = append(missing, "s3_access_key")
+		}
+		if c.S3SecretKey == "" {
+			missing = append(missing, "s3_secret_key")
+		}
+	default:
+		return fmt.Errorf("storage_backend must be \"local\" or \"s3\", got %q", c.StorageBackend)
+	}
+
+	switch c.WebSearchProvider {
+	case "":
+	case "brave":
+		if c.WebSearchAPIKey == "" {
+			missing = append(missing, "web_search_api_key")
+		}
+	case "searxng", "stub":
+		if c.WebSearchBaseURL == "" {
+			missing = append(missing, "web_search_base_url")
+		}
+	default:
+		return fmt.Errorf("web_search_provider must be \"brave\", \"searxng\" or \"stub\", got %q", c.WebSearchProvider)
+	}
+
 	if len(missing) > 0 {
 		return fmt.Errorf("missing required fields: %v", missing)
 	}
@@ -170,6 +241,15 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// StorageDir returns the root directory of the local storage backend
+func (c *Config) StorageDir() string {
+	if c.AppDir == "" {
+		// Default to current directory if not set
+		return "storage"
+	}
+	return filepath.Join(c.AppDir, "storage")
+}
+
 // FramesBaseDir returns the base directory for storing extracted frames (without serviceID)
 func (c *Config) FramesBaseDir() string {
 	if c.AppDir == "" {
@@ -188,6 +268,88 @@ func (c *Config) FramesDir(serviceID string) string {
 	return filepath.Join(c.AppDir, "storage", "frames", serviceID)
 }
 
+// SegmentsBaseDir returns the base directory for storing recording segments (without serviceID)
+func (c *Config) SegmentsBaseDir() string {
+	if c.AppDir == "" {
+		// Default to current directory if not set
+		return filepath.Join("storage", "segments")
+	}
+	return filepath.Join(c.AppDir, "storage", "segments")
+}
+
+// NodeTokenTTL returns how long node tokens are valid, falling back to 30 days
+func (c *Config) NodeTokenTTL() time.Duration {
+	if c.NodeTokenTTLHours <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(c.NodeTokenTTLHours) * time.Hour
+}
+
+// RecordingSegmentDuration returns the recording segment length, falling back to 60 seconds
+func (c *Config) RecordingSegmentDuration() time.Duration {
+	if c.RecordingSegmentSeconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(c.RecordingSegmentSeconds) * time.Second
+}
+
+// RecordingRetention returns the default segment retention, falling back to 24 hours
+func (c *Config) RecordingRetention() time.Duration {
+	if c.RecordingRetentionHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.RecordingRetentionHours) * time.Hour
+}
+
+// ClipsBaseDir returns the base directory for storing exported clips (without serviceID)
+func (c *Config) ClipsBaseDir() string {
+	if c.AppDir == "" {
+		// Default to current directory if not set
+		return filepath.Join("storage", "clips")
+	}
+	return filepath.Join(c.AppDir, "storage", "clips")
+}
+
+// ClipPreRoll returns the default clip pre-roll, falling back to 5 seconds
+func (c *Config) ClipPreRoll() time.Duration {
+	if c.ClipPreRollSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(c.ClipPreRollSeconds) * time.Second
+}
+
+// ClipPostRoll returns the default clip post-roll, falling back to 5 seconds
+func (c *Config) ClipPostRoll() time.Duration {
+	if c.ClipPostRollSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(c.ClipPostRollSeconds) * time.Second
+}
+
+// MQTTClientIDOrDefault returns the MQTT client ID, falling back to "unblink-server"
+func (c *Config) MQTTClientIDOrDefault() string {
+	if c.MQTTClientID == "" {
+		return "unblink-server"
+	}
+	return c.MQTTClientID
+}
+
+// MQTTTopicPrefixOrDefault returns the MQTT topic prefix, falling back to "unblink"
+func (c *Config) MQTTTopicPrefixOrDefault() string {
+	if c.MQTTTopicPrefix == "" {
+		return "unblink"
+	}
+	return c.MQTTTopicPrefix
+}
+
+// MQTTDiscoveryPrefixOrDefault returns the Home Assistant discovery prefix, falling back to "homeassistant"
+func (c *Config) MQTTDiscoveryPrefixOrDefault() string {
+	if c.MQTTDiscoveryPrefix == "" {
+		return "homeassistant"
+	}
+	return c.MQTTDiscoveryPrefix
+}
+
 // Save writes the config to a file
 func (c *Config) Save(path string) error {
 	// Validate before saving
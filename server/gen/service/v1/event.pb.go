@@ -283,6 +283,186 @@ func (x *CountEventsForUserResponse) GetCount() int64 {
 	return 0
 }
 
+type SearchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`                 // Free text, e.g. "someone carrying a box"
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"` // Optional: only events of this node
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                // Maximum results (default 20, max 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEventsRequest) Reset() {
+	*x = SearchEventsRequest{}
+	mi := &file_service_v1_event_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchEventsRequest) ProtoMessage() {}
+
+func (x *SearchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_event_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchEventsRequest.ProtoReflect.Descriptor instead.
+func (*SearchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_event_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchEventsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchEventsRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *SearchEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type EventSearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`                                  // Fused rank score; higher is better
+	KeywordMatch  bool                   `protobuf:"varint,3,opt,name=keyword_match,json=keywordMatch,proto3" json:"keyword_match,omitempty"` // Matched the query words
+	Similarity    float64                `protobuf:"fixed64,4,opt,name=similarity,proto3" json:"similarity,omitempty"`                        // Cosine similarity to the query (0 = not a semantic match)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventSearchResult) Reset() {
+	*x = EventSearchResult{}
+	mi := &file_service_v1_event_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventSearchResult) ProtoMessage() {}
+
+func (x *EventSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_event_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventSearchResult.ProtoReflect.Descriptor instead.
+func (*EventSearchResult) Descriptor() ([]byte, []int) {
+	return file_service_v1_event_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EventSearchResult) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EventSearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *EventSearchResult) GetKeywordMatch() bool {
+	if x != nil {
+		return x.KeywordMatch
+	}
+	return false
+}
+
+func (x *EventSearchResult) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type SearchEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*EventSearchResult   `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Semantic      bool                   `protobuf:"varint,2,opt,name=semantic,proto3" json:"semantic,omitempty"` // Whether embeddings were used (false = keywords only)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEventsResponse) Reset() {
+	*x = SearchEventsResponse{}
+	mi := &file_service_v1_event_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchEventsResponse) ProtoMessage() {}
+
+func (x *SearchEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_event_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchEventsResponse.ProtoReflect.Descriptor instead.
+func (*SearchEventsResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_event_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchEventsResponse) GetResults() []*EventSearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchEventsResponse) GetSemantic() bool {
+	if x != nil {
+		return x.Semantic
+	}
+	return false
+}
+
 type StreamEventsByNodeIdRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	NodeId         string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                          // Required: filter events by node
@@ -294,7 +474,7 @@ type StreamEventsByNodeIdRequest struct {
 
 func (x *StreamEventsByNodeIdRequest) Reset() {
 	*x = StreamEventsByNodeIdRequest{}
-	mi := &file_service_v1_event_proto_msgTypes[5]
+	mi := &file_service_v1_event_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -306,7 +486,7 @@ func (x *StreamEventsByNodeIdRequest) String() string {
 func (*StreamEventsByNodeIdRequest) ProtoMessage() {}
 
 func (x *StreamEventsByNodeIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_event_proto_msgTypes[5]
+	mi := &file_service_v1_event_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -319,7 +499,7 @@ func (x *StreamEventsByNodeIdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamEventsByNodeIdRequest.ProtoReflect.Descriptor instead.
 func (*StreamEventsByNodeIdRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_event_proto_rawDescGZIP(), []int{5}
+	return file_service_v1_event_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *StreamEventsByNodeIdRequest) GetNodeId() string {
@@ -356,7 +536,7 @@ type StreamEventsByNodeIdResponse struct {
 
 func (x *StreamEventsByNodeIdResponse) Reset() {
 	*x = StreamEventsByNodeIdResponse{}
-	mi := &file_service_v1_event_proto_msgTypes[6]
+	mi := &file_service_v1_event_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -368,7 +548,7 @@ func (x *StreamEventsByNodeIdResponse) String() string {
 func (*StreamEventsByNodeIdResponse) ProtoMessage() {}
 
 func (x *StreamEventsByNodeIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_event_proto_msgTypes[6]
+	mi := &file_service_v1_event_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -381,7 +561,7 @@ func (x *StreamEventsByNodeIdResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamEventsByNodeIdResponse.ProtoReflect.Descriptor instead.
 func (*StreamEventsByNodeIdResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_event_proto_rawDescGZIP(), []int{6}
+	return file_service_v1_event_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StreamEventsByNodeIdResponse) GetPayload() isStreamEventsByNodeIdResponse_Payload {
@@ -449,7 +629,21 @@ const file_service_v1_event_proto_rawDesc = "" +
 	"totalCount\"\x1b\n" +
 	"\x19CountEventsForUserRequest\"2\n" +
 	"\x1aCountEventsForUserResponse\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\x03R\x05count\"~\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"Z\n" +
+	"\x13SearchEventsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x97\x01\n" +
+	"\x11EventSearchResult\x12'\n" +
+	"\x05event\x18\x01 \x01(\v2\x11.service.v1.EventR\x05event\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12#\n" +
+	"\rkeyword_match\x18\x03 \x01(\bR\fkeywordMatch\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x04 \x01(\x01R\n" +
+	"similarity\"k\n" +
+	"\x14SearchEventsResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.service.v1.EventSearchResultR\aresults\x12\x1a\n" +
+	"\bsemantic\x18\x02 \x01(\bR\bsemantic\"~\n" +
 	"\x1bStreamEventsByNodeIdRequest\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1d\n" +
 	"\n" +
@@ -458,10 +652,11 @@ const file_service_v1_event_proto_rawDesc = "" +
 	"\x1cStreamEventsByNodeIdResponse\x12)\n" +
 	"\x05event\x18\x01 \x01(\v2\x11.service.v1.EventH\x00R\x05event\x12\x1e\n" +
 	"\theartbeat\x18\x02 \x01(\tH\x00R\theartbeatB\t\n" +
-	"\apayload2\xc5\x02\n" +
+	"\apayload2\x98\x03\n" +
 	"\fEventService\x12c\n" +
 	"\x12ListEventsByNodeId\x12%.service.v1.ListEventsByNodeIdRequest\x1a&.service.v1.ListEventsByNodeIdResponse\x12c\n" +
-	"\x12CountEventsForUser\x12%.service.v1.CountEventsForUserRequest\x1a&.service.v1.CountEventsForUserResponse\x12k\n" +
+	"\x12CountEventsForUser\x12%.service.v1.CountEventsForUserRequest\x1a&.service.v1.CountEventsForUserResponse\x12Q\n" +
+	"\fSearchEvents\x12\x1f.service.v1.SearchEventsRequest\x1a .service.v1.SearchEventsResponse\x12k\n" +
 	"\x14StreamEventsByNodeId\x12'.service.v1.StreamEventsByNodeIdRequest\x1a(.service.v1.StreamEventsByNodeIdResponse0\x01B)Z'unblink/server/gen/service/v1;servicev1b\x06proto3"
 
 var (
@@ -476,34 +671,41 @@ func file_service_v1_event_proto_rawDescGZIP() []byte {
 	return file_service_v1_event_proto_rawDescData
 }
 
-var file_service_v1_event_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_service_v1_event_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_service_v1_event_proto_goTypes = []any{
 	(*Event)(nil),                        // 0: service.v1.Event
 	(*ListEventsByNodeIdRequest)(nil),    // 1: service.v1.ListEventsByNodeIdRequest
 	(*ListEventsByNodeIdResponse)(nil),   // 2: service.v1.ListEventsByNodeIdResponse
 	(*CountEventsForUserRequest)(nil),    // 3: service.v1.CountEventsForUserRequest
 	(*CountEventsForUserResponse)(nil),   // 4: service.v1.CountEventsForUserResponse
-	(*StreamEventsByNodeIdRequest)(nil),  // 5: service.v1.StreamEventsByNodeIdRequest
-	(*StreamEventsByNodeIdResponse)(nil), // 6: service.v1.StreamEventsByNodeIdResponse
-	(*structpb.Struct)(nil),              // 7: google.protobuf.Struct
-	(*timestamppb.Timestamp)(nil),        // 8: google.protobuf.Timestamp
+	(*SearchEventsRequest)(nil),          // 5: service.v1.SearchEventsRequest
+	(*EventSearchResult)(nil),            // 6: service.v1.EventSearchResult
+	(*SearchEventsResponse)(nil),         // 7: service.v1.SearchEventsResponse
+	(*StreamEventsByNodeIdRequest)(nil),  // 8: service.v1.StreamEventsByNodeIdRequest
+	(*StreamEventsByNodeIdResponse)(nil), // 9: service.v1.StreamEventsByNodeIdResponse
+	(*structpb.Struct)(nil),              // 10: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),        // 11: google.protobuf.Timestamp
 }
 var file_service_v1_event_proto_depIdxs = []int32{
-	7, // 0: service.v1.Event.payload:type_name -> google.protobuf.Struct
-	8, // 1: service.v1.Event.created_at:type_name -> google.protobuf.Timestamp
-	0, // 2: service.v1.ListEventsByNodeIdResponse.events:type_name -> service.v1.Event
-	0, // 3: service.v1.StreamEventsByNodeIdResponse.event:type_name -> service.v1.Event
-	1, // 4: service.v1.EventService.ListEventsByNodeId:input_type -> service.v1.ListEventsByNodeIdRequest
-	3, // 5: service.v1.EventService.CountEventsForUser:input_type -> service.v1.CountEventsForUserRequest
-	5, // 6: service.v1.EventService.StreamEventsByNodeId:input_type -> service.v1.StreamEventsByNodeIdRequest
-	2, // 7: service.v1.EventService.ListEventsByNodeId:output_type -> service.v1.ListEventsByNodeIdResponse
-	4, // 8: service.v1.EventService.CountEventsForUser:output_type -> service.v1.CountEventsForUserResponse
-	6, // 9: service.v1.EventService.StreamEventsByNodeId:output_type -> service.v1.StreamEventsByNodeIdResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	10, // 0: service.v1.Event.payload:type_name -> google.protobuf.Struct
+	11, // 1: service.v1.Event.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: service.v1.ListEventsByNodeIdResponse.events:type_name -> service.v1.Event
+	0,  // 3: service.v1.EventSearchResult.event:type_name -> service.v1.Event
+	6,  // 4: service.v1.SearchEventsResponse.results:type_name -> service.v1.EventSearchResult
+	0,  // 5: service.v1.StreamEventsByNodeIdResponse.event:type_name -> service.v1.Event
+	1,  // 6: service.v1.EventService.ListEventsByNodeId:input_type -> service.v1.ListEventsByNodeIdRequest
+	3,  // 7: service.v1.EventService.CountEventsForUser:input_type -> service.v1.CountEventsForUserRequest
+	5,  // 8: service.v1.EventService.SearchEvents:input_type -> service.v1.SearchEventsRequest
+	8,  // 9: service.v1.EventService.StreamEventsByNodeId:input_type -> service.v1.StreamEventsByNodeIdRequest
+	2,  // 10: service.v1.EventService.ListEventsByNodeId:output_type -> service.v1.ListEventsByNodeIdResponse
+	4,  // 11: service.v1.EventService.CountEventsForUser:output_type -> service.v1.CountEventsForUserResponse
+	7,  // 12: service.v1.EventService.SearchEvents:output_type -> service.v1.SearchEventsResponse
+	9,  // 13: service.v1.EventService.StreamEventsByNodeId:output_type -> service.v1.StreamEventsByNodeIdResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_service_v1_event_proto_init() }
@@ -511,7 +713,7 @@ func file_service_v1_event_proto_init() {
 	if File_service_v1_event_proto != nil {
 		return
 	}
-	file_service_v1_event_proto_msgTypes[6].OneofWrappers = []any{
+	file_service_v1_event_proto_msgTypes[9].OneofWrappers = []any{
 		(*StreamEventsByNodeIdResponse_Event)(nil),
 		(*StreamEventsByNodeIdResponse_Heartbeat)(nil),
 	}
@@ -521,7 +723,7 @@ func file_service_v1_event_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_v1_event_proto_rawDesc), len(file_service_v1_event_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
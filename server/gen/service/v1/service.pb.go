@@ -23,15 +23,17 @@ const (
 )
 
 type Service struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
-	NodeId        string                 `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url                     string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	NodeId                  string                 `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	CreatedAt               *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt               *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	RecordingRetentionHours int32                  `protobuf:"varint,7,opt,name=recording_retention_hours,json=recordingRetentionHours,proto3" json:"recording_retention_hours,omitempty"` // How long recorded segments are kept (0 = server default)
+	Analysis                *AnalysisSettings      `protobuf:"bytes,8,opt,name=analysis,proto3" json:"analysis,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *Service) Reset() {
@@ -106,30 +108,142 @@ func (x *Service) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-type CreateServiceRequest struct {
+func (x *Service) GetRecordingRetentionHours() int32 {
+	if x != nil {
+		return x.RecordingRetentionHours
+	}
+	return 0
+}
+
+func (x *Service) GetAnalysis() *AnalysisSettings {
+	if x != nil {
+		return x.Analysis
+	}
+	return nil
+}
+
+// How a service's frames are indexed. Zero values fall back to the server
+// config; changes apply to a running service without reconnecting it.
+type AnalysisSettings struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	IndexingEnabled      *bool                  `protobuf:"varint,1,opt,name=indexing_enabled,json=indexingEnabled,proto3,oneof" json:"indexing_enabled,omitempty"`             // Unset = server default (enable_indexing)
+	FrameIntervalSeconds float64                `protobuf:"fixed64,2,opt,name=frame_interval_seconds,json=frameIntervalSeconds,proto3" json:"frame_interval_seconds,omitempty"` // Seconds between extracted frames (0.5-3600)
+	BatchSize            int32                  `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`                                     // Frames per VLM request (1-32)
+	Model                string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`                                                               // VLM model
+	Instruction          string                 `protobuf:"bytes,5,opt,name=instruction,proto3" json:"instruction,omitempty"`                                                   // Replaces the built-in analysis instruction
+	Language             string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`                                                         // Language of descriptions, e.g. "German" (empty = English); labels stay English
+	Zones                []*Zone                `protobuf:"bytes,7,rep,name=zones,proto3" json:"zones,omitempty"`                                                               // Regions alert rules can refer to ("in zone driveway")
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *AnalysisSettings) Reset() {
+	*x = AnalysisSettings{}
+	mi := &file_service_v1_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisSettings) ProtoMessage() {}
+
+func (x *AnalysisSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisSettings.ProtoReflect.Descriptor instead.
+func (*AnalysisSettings) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnalysisSettings) GetIndexingEnabled() bool {
+	if x != nil && x.IndexingEnabled != nil {
+		return *x.IndexingEnabled
+	}
+	return false
+}
+
+func (x *AnalysisSettings) GetFrameIntervalSeconds() float64 {
+	if x != nil {
+		return x.FrameIntervalSeconds
+	}
+	return 0
+}
+
+func (x *AnalysisSettings) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *AnalysisSettings) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AnalysisSettings) GetInstruction() string {
+	if x != nil {
+		return x.Instruction
+	}
+	return ""
+}
+
+func (x *AnalysisSettings) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *AnalysisSettings) GetZones() []*Zone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+// A named rectangle of the image in normalized 1000 coordinates (0 = top/left,
+// 1000 = bottom/right), like the boxes of detected objects
+type Zone struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	X1            int32                  `protobuf:"varint,2,opt,name=x1,proto3" json:"x1,omitempty"`
+	Y1            int32                  `protobuf:"varint,3,opt,name=y1,proto3" json:"y1,omitempty"`
+	X2            int32                  `protobuf:"varint,4,opt,name=x2,proto3" json:"x2,omitempty"`
+	Y2            int32                  `protobuf:"varint,5,opt,name=y2,proto3" json:"y2,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateServiceRequest) Reset() {
-	*x = CreateServiceRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[1]
+func (x *Zone) Reset() {
+	*x = Zone{}
+	mi := &file_service_v1_service_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateServiceRequest) String() string {
+func (x *Zone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateServiceRequest) ProtoMessage() {}
+func (*Zone) ProtoMessage() {}
 
-func (x *CreateServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[1]
+func (x *Zone) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -140,54 +254,1362 @@ func (x *CreateServiceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateServiceRequest.ProtoReflect.Descriptor instead.
-func (*CreateServiceRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Zone.ProtoReflect.Descriptor instead.
+func (*Zone) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CreateServiceRequest) GetName() string {
+func (x *Zone) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateServiceRequest) GetUrl() string {
+func (x *Zone) GetX1() int32 {
 	if x != nil {
-		return x.Url
+		return x.X1
+	}
+	return 0
+}
+
+func (x *Zone) GetY1() int32 {
+	if x != nil {
+		return x.Y1
+	}
+	return 0
+}
+
+func (x *Zone) GetX2() int32 {
+	if x != nil {
+		return x.X2
+	}
+	return 0
+}
+
+func (x *Zone) GetY2() int32 {
+	if x != nil {
+		return x.Y2
+	}
+	return 0
+}
+
+type Node struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Hostname          string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	DisplayName       string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"` // Set by users, empty = use hostname
+	Online            bool                   `protobuf:"varint,4,opt,name=online,proto3" json:"online,omitempty"`
+	LastSeen          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	NodeVersion       string                 `protobuf:"bytes,6,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"` // Software version reported by the node
+	ProtocolVersion   int32                  `protobuf:"varint,7,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	MacAddresses      []string               `protobuf:"bytes,8,rep,name=mac_addresses,json=macAddresses,proto3" json:"mac_addresses,omitempty"`
+	ServiceCount      int32                  `protobuf:"varint,9,opt,name=service_count,json=serviceCount,proto3" json:"service_count,omitempty"`
+	ActiveBridgeCount int32                  `protobuf:"varint,10,opt,name=active_bridge_count,json=activeBridgeCount,proto3" json:"active_bridge_count,omitempty"` // 0 while offline
+	FirstSeen         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	TokenIssuedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=token_issued_at,json=tokenIssuedAt,proto3" json:"token_issued_at,omitempty"` // Current token, unset if none is recorded
+	RevokedAt         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`               // Set once the owner revoked the node
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_service_v1_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Node) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Node) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Node) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Node) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *Node) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Node) GetNodeVersion() string {
+	if x != nil {
+		return x.NodeVersion
+	}
+	return ""
+}
+
+func (x *Node) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *Node) GetMacAddresses() []string {
+	if x != nil {
+		return x.MacAddresses
+	}
+	return nil
+}
+
+func (x *Node) GetServiceCount() int32 {
+	if x != nil {
+		return x.ServiceCount
+	}
+	return 0
+}
+
+func (x *Node) GetActiveBridgeCount() int32 {
+	if x != nil {
+		return x.ActiveBridgeCount
+	}
+	return 0
+}
+
+func (x *Node) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+func (x *Node) GetTokenIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokenIssuedAt
+	}
+	return nil
+}
+
+func (x *Node) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+// A camera found on a node's LAN by DiscoverDevices
+type DiscoveredDevice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"` // IP address
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`   // "ws-discovery" or "mdns"
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Manufacturer  string                 `protobuf:"bytes,4,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	Model         string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	OnvifUrl      string                 `protobuf:"bytes,6,opt,name=onvif_url,json=onvifUrl,proto3" json:"onvif_url,omitempty"` // ONVIF device service, empty for mDNS
+	Streams       []*DiscoveredStream    `protobuf:"bytes,7,rep,name=streams,proto3" json:"streams,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"` // Why the streams could not be listed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveredDevice) Reset() {
+	*x = DiscoveredDevice{}
+	mi := &file_service_v1_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveredDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveredDevice) ProtoMessage() {}
+
+func (x *DiscoveredDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveredDevice.ProtoReflect.Descriptor instead.
+func (*DiscoveredDevice) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DiscoveredDevice) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetOnvifUrl() string {
+	if x != nil {
+		return x.OnvifUrl
+	}
+	return ""
+}
+
+func (x *DiscoveredDevice) GetStreams() []*DiscoveredStream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *DiscoveredDevice) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// A stream of a discovered device, usable as CreateServiceRequest.url
+type DiscoveredStream struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"` // ONVIF profile name
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`         // Includes the credentials from the request
+	Encoding      string                 `protobuf:"bytes,3,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	Width         int32                  `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveredStream) Reset() {
+	*x = DiscoveredStream{}
+	mi := &file_service_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveredStream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveredStream) ProtoMessage() {}
+
+func (x *DiscoveredStream) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveredStream.ProtoReflect.Descriptor instead.
+func (*DiscoveredStream) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiscoveredStream) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *DiscoveredStream) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DiscoveredStream) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *DiscoveredStream) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *DiscoveredStream) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// Live health of a service. Transitions of state are also recorded as
+// events with payload type "service-status".
+type ServiceStatus struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId           string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	State               string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // "offline", "online", "reconnecting", "failed", "disabled" or "denied"
+	StateSince          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=state_since,json=stateSince,proto3" json:"state_since,omitempty"`
+	LastFrameAt         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=last_frame_at,json=lastFrameAt,proto3" json:"last_frame_at,omitempty"` // Last frame extracted for indexing
+	LastDataAt          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_data_at,json=lastDataAt,proto3" json:"last_data_at,omitempty"`    // Last data received from the camera
+	RetryCount          int32                  `protobuf:"varint,6,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`     // Reconnects since the stream was last stable
+	MaxRetries          int32                  `protobuf:"varint,7,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	LastRetryAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_retry_at,json=lastRetryAt,proto3" json:"last_retry_at,omitempty"`
+	LastError           string                 `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastErrorCode       string                 `protobuf:"bytes,10,opt,name=last_error_code,json=lastErrorCode,proto3" json:"last_error_code,omitempty"`                      // Node's refusal code (e.g. "target_denied")
+	ExtractorFps        float64                `protobuf:"fixed64,11,opt,name=extractor_fps,json=extractorFps,proto3" json:"extractor_fps,omitempty"`                         // Frames extracted per second over the last minute
+	Indexing            bool                   `protobuf:"varint,12,opt,name=indexing,proto3" json:"indexing,omitempty"`                                                      // Frames are being extracted for indexing
+	Recording           bool                   `protobuf:"varint,13,opt,name=recording,proto3" json:"recording,omitempty"`                                                    // Segments are being recorded
+	BridgeBufferedBytes int64                  `protobuf:"varint,14,opt,name=bridge_buffered_bytes,json=bridgeBufferedBytes,proto3" json:"bridge_buffered_bytes,omitempty"`   // Received from the node but not yet read by the stream
+	BridgeInFlightBytes int64                  `protobuf:"varint,15,opt,name=bridge_in_flight_bytes,json=bridgeInFlightBytes,proto3" json:"bridge_in_flight_bytes,omitempty"` // Sent to the node but not yet acknowledged
+	BridgeSendCredit    int64                  `protobuf:"varint,16,opt,name=bridge_send_credit,json=bridgeSendCredit,proto3" json:"bridge_send_credit,omitempty"`            // Bytes the server may still send to the node
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ServiceStatus) Reset() {
+	*x = ServiceStatus{}
+	mi := &file_service_v1_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceStatus) ProtoMessage() {}
+
+func (x *ServiceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceStatus.ProtoReflect.Descriptor instead.
+func (*ServiceStatus) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ServiceStatus) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ServiceStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ServiceStatus) GetStateSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StateSince
+	}
+	return nil
+}
+
+func (x *ServiceStatus) GetLastFrameAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFrameAt
+	}
+	return nil
+}
+
+func (x *ServiceStatus) GetLastDataAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastDataAt
+	}
+	return nil
+}
+
+func (x *ServiceStatus) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *ServiceStatus) GetMaxRetries() int32 {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return 0
+}
+
+func (x *ServiceStatus) GetLastRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRetryAt
+	}
+	return nil
+}
+
+func (x *ServiceStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ServiceStatus) GetLastErrorCode() string {
+	if x != nil {
+		return x.LastErrorCode
+	}
+	return ""
+}
+
+func (x *ServiceStatus) GetExtractorFps() float64 {
+	if x != nil {
+		return x.ExtractorFps
+	}
+	return 0
+}
+
+func (x *ServiceStatus) GetIndexing() bool {
+	if x != nil {
+		return x.Indexing
+	}
+	return false
+}
+
+func (x *ServiceStatus) GetRecording() bool {
+	if x != nil {
+		return x.Recording
+	}
+	return false
+}
+
+func (x *ServiceStatus) GetBridgeBufferedBytes() int64 {
+	if x != nil {
+		return x.BridgeBufferedBytes
+	}
+	return 0
+}
+
+func (x *ServiceStatus) GetBridgeInFlightBytes() int64 {
+	if x != nil {
+		return x.BridgeInFlightBytes
+	}
+	return 0
+}
+
+func (x *ServiceStatus) GetBridgeSendCredit() int64 {
+	if x != nil {
+		return x.BridgeSendCredit
+	}
+	return 0
+}
+
+// A track offered by a tested service URL
+type StreamCodec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // "video" or "audio"
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // e.g. "H264", "PCMA", "JPEG"
+	ClockRate     uint32                 `protobuf:"varint,3,opt,name=clock_rate,json=clockRate,proto3" json:"clock_rate,omitempty"`
+	Channels      uint32                 `protobuf:"varint,4,opt,name=channels,proto3" json:"channels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCodec) Reset() {
+	*x = StreamCodec{}
+	mi := &file_service_v1_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCodec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCodec) ProtoMessage() {}
+
+func (x *StreamCodec) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCodec.ProtoReflect.Descriptor instead.
+func (*StreamCodec) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamCodec) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StreamCodec) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamCodec) GetClockRate() uint32 {
+	if x != nil {
+		return x.ClockRate
+	}
+	return 0
+}
+
+func (x *StreamCodec) GetChannels() uint32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+type CreateServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceRequest) Reset() {
+	*x = CreateServiceRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceRequest) ProtoMessage() {}
+
+func (x *CreateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateServiceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateServiceRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateServiceRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type CreateServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceResponse) Reset() {
+	*x = CreateServiceResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceResponse) ProtoMessage() {}
+
+func (x *CreateServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateServiceResponse) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type ListServicesByNodeIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesByNodeIdRequest) Reset() {
+	*x = ListServicesByNodeIdRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesByNodeIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesByNodeIdRequest) ProtoMessage() {}
+
+func (x *ListServicesByNodeIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicesByNodeIdRequest.ProtoReflect.Descriptor instead.
+func (*ListServicesByNodeIdRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListServicesByNodeIdRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type ListServicesByNodeIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*Service             `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesByNodeIdResponse) Reset() {
+	*x = ListServicesByNodeIdResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesByNodeIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesByNodeIdResponse) ProtoMessage() {}
+
+func (x *ListServicesByNodeIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicesByNodeIdResponse.ProtoReflect.Descriptor instead.
+func (*ListServicesByNodeIdResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListServicesByNodeIdResponse) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+type UpdateServiceRequest struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url                     string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	RecordingRetentionHours *int32                 `protobuf:"varint,4,opt,name=recording_retention_hours,json=recordingRetentionHours,proto3,oneof" json:"recording_retention_hours,omitempty"` // Optional: 0 resets to server default
+	Analysis                *AnalysisSettings      `protobuf:"bytes,5,opt,name=analysis,proto3" json:"analysis,omitempty"`                                                                       // Optional: replaces all analysis settings
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *UpdateServiceRequest) Reset() {
+	*x = UpdateServiceRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceRequest) ProtoMessage() {}
+
+func (x *UpdateServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateServiceRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateServiceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateServiceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateServiceRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UpdateServiceRequest) GetRecordingRetentionHours() int32 {
+	if x != nil && x.RecordingRetentionHours != nil {
+		return *x.RecordingRetentionHours
+	}
+	return 0
+}
+
+func (x *UpdateServiceRequest) GetAnalysis() *AnalysisSettings {
+	if x != nil {
+		return x.Analysis
+	}
+	return nil
+}
+
+type UpdateServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateServiceResponse) Reset() {
+	*x = UpdateServiceResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServiceResponse) ProtoMessage() {}
+
+func (x *UpdateServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServiceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateServiceResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateServiceResponse) GetService() *Service {
+	if x != nil {
+		return x.Service
+	}
+	return nil
+}
+
+type DeleteServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceRequest) Reset() {
+	*x = DeleteServiceRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceRequest) ProtoMessage() {}
+
+func (x *DeleteServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServiceRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteServiceRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+type DeleteServiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServiceResponse) Reset() {
+	*x = DeleteServiceResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServiceResponse) ProtoMessage() {}
+
+func (x *DeleteServiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServiceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServiceResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteServiceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type DiscoverDevicesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NodeId         string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"` // ONVIF credentials, needed for stream URLs
+	Password       string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	TimeoutSeconds int32                  `protobuf:"varint,4,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // How long the node listens for replies (1-10, default 3)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DiscoverDevicesRequest) Reset() {
+	*x = DiscoverDevicesRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverDevicesRequest) ProtoMessage() {}
+
+func (x *DiscoverDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverDevicesRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DiscoverDevicesRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *DiscoverDevicesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *DiscoverDevicesRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *DiscoverDevicesRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type DiscoverDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*DiscoveredDevice    `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverDevicesResponse) Reset() {
+	*x = DiscoverDevicesResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverDevicesResponse) ProtoMessage() {}
+
+func (x *DiscoverDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverDevicesResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DiscoverDevicesResponse) GetDevices() []*DiscoveredDevice {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type TestServiceURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestServiceURLRequest) Reset() {
+	*x = TestServiceURLRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestServiceURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestServiceURLRequest) ProtoMessage() {}
+
+func (x *TestServiceURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestServiceURLRequest.ProtoReflect.Descriptor instead.
+func (*TestServiceURLRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TestServiceURLRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *TestServiceURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Nothing is saved; the bridge is closed once the test ends
+type TestServiceURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                         // The URL can be used as a service
+	Reachable     bool                   `protobuf:"varint,2,opt,name=reachable,proto3" json:"reachable,omitempty"`                     // The node connected to the camera
+	Responded     bool                   `protobuf:"varint,3,opt,name=responded,proto3" json:"responded,omitempty"`                     // The camera answered RTSP/HTTP
+	AuthFailed    bool                   `protobuf:"varint,4,opt,name=auth_failed,json=authFailed,proto3" json:"auth_failed,omitempty"` // The camera rejected the credentials (or wants some)
+	SourceType    string                 `protobuf:"bytes,5,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`  // "rtsp" or "mjpeg"
+	Codecs        []*StreamCodec         `protobuf:"bytes,6,rep,name=codecs,proto3" json:"codecs,omitempty"`
+	Width         int32                  `protobuf:"varint,7,opt,name=width,proto3" json:"width,omitempty"` // 0 if unknown
+	Height        int32                  `protobuf:"varint,8,opt,name=height,proto3" json:"height,omitempty"`
+	PreviewJpeg   []byte                 `protobuf:"bytes,9,opt,name=preview_jpeg,json=previewJpeg,proto3" json:"preview_jpeg,omitempty"`     // One frame, empty if none could be captured
+	PreviewError  string                 `protobuf:"bytes,10,opt,name=preview_error,json=previewError,proto3" json:"preview_error,omitempty"` // Why there is no preview although the stream works
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`                                   // Why the URL cannot be used
+	ErrorCode     string                 `protobuf:"bytes,12,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`          // Node's refusal code (e.g. "target_denied", "unreachable")
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestServiceURLResponse) Reset() {
+	*x = TestServiceURLResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestServiceURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestServiceURLResponse) ProtoMessage() {}
+
+func (x *TestServiceURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestServiceURLResponse.ProtoReflect.Descriptor instead.
+func (*TestServiceURLResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TestServiceURLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TestServiceURLResponse) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *TestServiceURLResponse) GetResponded() bool {
+	if x != nil {
+		return x.Responded
+	}
+	return false
+}
+
+func (x *TestServiceURLResponse) GetAuthFailed() bool {
+	if x != nil {
+		return x.AuthFailed
+	}
+	return false
+}
+
+func (x *TestServiceURLResponse) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *TestServiceURLResponse) GetCodecs() []*StreamCodec {
+	if x != nil {
+		return x.Codecs
+	}
+	return nil
+}
+
+func (x *TestServiceURLResponse) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *TestServiceURLResponse) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *TestServiceURLResponse) GetPreviewJpeg() []byte {
+	if x != nil {
+		return x.PreviewJpeg
+	}
+	return nil
+}
+
+func (x *TestServiceURLResponse) GetPreviewError() string {
+	if x != nil {
+		return x.PreviewError
+	}
+	return ""
+}
+
+func (x *TestServiceURLResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TestServiceURLResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type GetServiceStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServiceStatusRequest) Reset() {
+	*x = GetServiceStatusRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServiceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServiceStatusRequest) ProtoMessage() {}
+
+func (x *GetServiceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *CreateServiceRequest) GetNodeId() string {
+// Deprecated: Use GetServiceStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetServiceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetServiceStatusRequest) GetServiceId() string {
 	if x != nil {
-		return x.NodeId
+		return x.ServiceId
 	}
 	return ""
 }
 
-type CreateServiceResponse struct {
+type GetServiceStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Status        *ServiceStatus         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateServiceResponse) Reset() {
-	*x = CreateServiceResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[2]
+func (x *GetServiceStatusResponse) Reset() {
+	*x = GetServiceStatusResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateServiceResponse) String() string {
+func (x *GetServiceStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateServiceResponse) ProtoMessage() {}
+func (*GetServiceStatusResponse) ProtoMessage() {}
 
-func (x *CreateServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[2]
+func (x *GetServiceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -198,40 +1620,40 @@ func (x *CreateServiceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateServiceResponse.ProtoReflect.Descriptor instead.
-func (*CreateServiceResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GetServiceStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetServiceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *CreateServiceResponse) GetService() *Service {
+func (x *GetServiceStatusResponse) GetStatus() *ServiceStatus {
 	if x != nil {
-		return x.Service
+		return x.Status
 	}
 	return nil
 }
 
-type ListServicesByNodeIdRequest struct {
+type WatchServiceStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListServicesByNodeIdRequest) Reset() {
-	*x = ListServicesByNodeIdRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[3]
+func (x *WatchServiceStatusRequest) Reset() {
+	*x = WatchServiceStatusRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListServicesByNodeIdRequest) String() string {
+func (x *WatchServiceStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListServicesByNodeIdRequest) ProtoMessage() {}
+func (*WatchServiceStatusRequest) ProtoMessage() {}
 
-func (x *ListServicesByNodeIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[3]
+func (x *WatchServiceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -242,40 +1664,41 @@ func (x *ListServicesByNodeIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListServicesByNodeIdRequest.ProtoReflect.Descriptor instead.
-func (*ListServicesByNodeIdRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use WatchServiceStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchServiceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ListServicesByNodeIdRequest) GetNodeId() string {
+func (x *WatchServiceStatusRequest) GetServiceId() string {
 	if x != nil {
-		return x.NodeId
+		return x.ServiceId
 	}
 	return ""
 }
 
-type ListServicesByNodeIdResponse struct {
+// Sent right away, on every state change and every 5 seconds
+type WatchServiceStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Services      []*Service             `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	Status        *ServiceStatus         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListServicesByNodeIdResponse) Reset() {
-	*x = ListServicesByNodeIdResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[4]
+func (x *WatchServiceStatusResponse) Reset() {
+	*x = WatchServiceStatusResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListServicesByNodeIdResponse) String() string {
+func (x *WatchServiceStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListServicesByNodeIdResponse) ProtoMessage() {}
+func (*WatchServiceStatusResponse) ProtoMessage() {}
 
-func (x *ListServicesByNodeIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[4]
+func (x *WatchServiceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,42 +1709,40 @@ func (x *ListServicesByNodeIdResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListServicesByNodeIdResponse.ProtoReflect.Descriptor instead.
-func (*ListServicesByNodeIdResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use WatchServiceStatusResponse.ProtoReflect.Descriptor instead.
+func (*WatchServiceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ListServicesByNodeIdResponse) GetServices() []*Service {
+func (x *WatchServiceStatusResponse) GetStatus() *ServiceStatus {
 	if x != nil {
-		return x.Services
+		return x.Status
 	}
 	return nil
 }
 
-type UpdateServiceRequest struct {
+type AssociateUserNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateServiceRequest) Reset() {
-	*x = UpdateServiceRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[5]
+func (x *AssociateUserNodeRequest) Reset() {
+	*x = AssociateUserNodeRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateServiceRequest) String() string {
+func (x *AssociateUserNodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateServiceRequest) ProtoMessage() {}
+func (*AssociateUserNodeRequest) ProtoMessage() {}
 
-func (x *UpdateServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[5]
+func (x *AssociateUserNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,54 +1753,40 @@ func (x *UpdateServiceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateServiceRequest.ProtoReflect.Descriptor instead.
-func (*UpdateServiceRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *UpdateServiceRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *UpdateServiceRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// Deprecated: Use AssociateUserNodeRequest.ProtoReflect.Descriptor instead.
+func (*AssociateUserNodeRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *UpdateServiceRequest) GetUrl() string {
+func (x *AssociateUserNodeRequest) GetNodeId() string {
 	if x != nil {
-		return x.Url
+		return x.NodeId
 	}
 	return ""
 }
 
-type UpdateServiceResponse struct {
+type AssociateUserNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Service       *Service               `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateServiceResponse) Reset() {
-	*x = UpdateServiceResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[6]
+func (x *AssociateUserNodeResponse) Reset() {
+	*x = AssociateUserNodeResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateServiceResponse) String() string {
+func (x *AssociateUserNodeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateServiceResponse) ProtoMessage() {}
+func (*AssociateUserNodeResponse) ProtoMessage() {}
 
-func (x *UpdateServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[6]
+func (x *AssociateUserNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,40 +1797,39 @@ func (x *UpdateServiceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateServiceResponse.ProtoReflect.Descriptor instead.
-func (*UpdateServiceResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use AssociateUserNodeResponse.ProtoReflect.Descriptor instead.
+func (*AssociateUserNodeResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *UpdateServiceResponse) GetService() *Service {
+func (x *AssociateUserNodeResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Service
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type DeleteServiceRequest struct {
+type ListUserNodesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteServiceRequest) Reset() {
-	*x = DeleteServiceRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[7]
+func (x *ListUserNodesRequest) Reset() {
+	*x = ListUserNodesRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteServiceRequest) String() string {
+func (x *ListUserNodesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteServiceRequest) ProtoMessage() {}
+func (*ListUserNodesRequest) ProtoMessage() {}
 
-func (x *DeleteServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[7]
+func (x *ListUserNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -434,40 +1840,34 @@ func (x *DeleteServiceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteServiceRequest.ProtoReflect.Descriptor instead.
-func (*DeleteServiceRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *DeleteServiceRequest) GetServiceId() string {
-	if x != nil {
-		return x.ServiceId
-	}
-	return ""
+// Deprecated: Use ListUserNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListUserNodesRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{26}
 }
 
-type DeleteServiceResponse struct {
+type ListUserNodesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	NodeIds       []string               `protobuf:"bytes,1,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	Nodes         []*Node                `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"` // Same nodes as node_ids, with inventory and live state
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteServiceResponse) Reset() {
-	*x = DeleteServiceResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[8]
+func (x *ListUserNodesResponse) Reset() {
+	*x = ListUserNodesResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteServiceResponse) String() string {
+func (x *ListUserNodesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteServiceResponse) ProtoMessage() {}
+func (*ListUserNodesResponse) ProtoMessage() {}
 
-func (x *DeleteServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[8]
+func (x *ListUserNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,40 +1878,48 @@ func (x *DeleteServiceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteServiceResponse.ProtoReflect.Descriptor instead.
-func (*DeleteServiceResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListUserNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListUserNodesResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *DeleteServiceResponse) GetSuccess() bool {
+func (x *ListUserNodesResponse) GetNodeIds() []string {
 	if x != nil {
-		return x.Success
+		return x.NodeIds
 	}
-	return false
+	return nil
 }
 
-type AssociateUserNodeRequest struct {
+func (x *ListUserNodesResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type UpdateNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssociateUserNodeRequest) Reset() {
-	*x = AssociateUserNodeRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[9]
+func (x *UpdateNodeRequest) Reset() {
+	*x = UpdateNodeRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssociateUserNodeRequest) String() string {
+func (x *UpdateNodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssociateUserNodeRequest) ProtoMessage() {}
+func (*UpdateNodeRequest) ProtoMessage() {}
 
-func (x *AssociateUserNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[9]
+func (x *UpdateNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,40 +1930,47 @@ func (x *AssociateUserNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssociateUserNodeRequest.ProtoReflect.Descriptor instead.
-func (*AssociateUserNodeRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use UpdateNodeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNodeRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *AssociateUserNodeRequest) GetNodeId() string {
+func (x *UpdateNodeRequest) GetNodeId() string {
 	if x != nil {
 		return x.NodeId
 	}
 	return ""
 }
 
-type AssociateUserNodeResponse struct {
+func (x *UpdateNodeRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type UpdateNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Node          *Node                  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssociateUserNodeResponse) Reset() {
-	*x = AssociateUserNodeResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[10]
+func (x *UpdateNodeResponse) Reset() {
+	*x = UpdateNodeResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssociateUserNodeResponse) String() string {
+func (x *UpdateNodeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssociateUserNodeResponse) ProtoMessage() {}
+func (*UpdateNodeResponse) ProtoMessage() {}
 
-func (x *AssociateUserNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[10]
+func (x *UpdateNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -566,39 +1981,40 @@ func (x *AssociateUserNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssociateUserNodeResponse.ProtoReflect.Descriptor instead.
-func (*AssociateUserNodeResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use UpdateNodeResponse.ProtoReflect.Descriptor instead.
+func (*UpdateNodeResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *AssociateUserNodeResponse) GetSuccess() bool {
+func (x *UpdateNodeResponse) GetNode() *Node {
 	if x != nil {
-		return x.Success
+		return x.Node
 	}
-	return false
+	return nil
 }
 
-type ListUserNodesRequest struct {
+type RevokeNodeTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserNodesRequest) Reset() {
-	*x = ListUserNodesRequest{}
-	mi := &file_service_v1_service_proto_msgTypes[11]
+func (x *RevokeNodeTokenRequest) Reset() {
+	*x = RevokeNodeTokenRequest{}
+	mi := &file_service_v1_service_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserNodesRequest) String() string {
+func (x *RevokeNodeTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserNodesRequest) ProtoMessage() {}
+func (*RevokeNodeTokenRequest) ProtoMessage() {}
 
-func (x *ListUserNodesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[11]
+func (x *RevokeNodeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -609,33 +2025,41 @@ func (x *ListUserNodesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserNodesRequest.ProtoReflect.Descriptor instead.
-func (*ListUserNodesRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RevokeNodeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeNodeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{30}
 }
 
-type ListUserNodesResponse struct {
+func (x *RevokeNodeTokenRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type RevokeNodeTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NodeIds       []string               `protobuf:"bytes,1,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	RevokedTokens int32                  `protobuf:"varint,1,opt,name=revoked_tokens,json=revokedTokens,proto3" json:"revoked_tokens,omitempty"`
+	Disconnected  bool                   `protobuf:"varint,2,opt,name=disconnected,proto3" json:"disconnected,omitempty"` // The node was online and has been disconnected
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserNodesResponse) Reset() {
-	*x = ListUserNodesResponse{}
-	mi := &file_service_v1_service_proto_msgTypes[12]
+func (x *RevokeNodeTokenResponse) Reset() {
+	*x = RevokeNodeTokenResponse{}
+	mi := &file_service_v1_service_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserNodesResponse) String() string {
+func (x *RevokeNodeTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserNodesResponse) ProtoMessage() {}
+func (*RevokeNodeTokenResponse) ProtoMessage() {}
 
-func (x *ListUserNodesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_service_proto_msgTypes[12]
+func (x *RevokeNodeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_service_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -646,16 +2070,23 @@ func (x *ListUserNodesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserNodesResponse.ProtoReflect.Descriptor instead.
-func (*ListUserNodesResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use RevokeNodeTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeNodeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_service_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ListUserNodesResponse) GetNodeIds() []string {
+func (x *RevokeNodeTokenResponse) GetRevokedTokens() int32 {
 	if x != nil {
-		return x.NodeIds
+		return x.RevokedTokens
 	}
-	return nil
+	return 0
+}
+
+func (x *RevokeNodeTokenResponse) GetDisconnected() bool {
+	if x != nil {
+		return x.Disconnected
+	}
+	return false
 }
 
 var File_service_v1_service_proto protoreflect.FileDescriptor
@@ -663,7 +2094,7 @@ var File_service_v1_service_proto protoreflect.FileDescriptor
 const file_service_v1_service_proto_rawDesc = "" +
 	"\n" +
 	"\x18service/v1/service.proto\x12\n" +
-	"service.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xce\x01\n" +
+	"service.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc4\x02\n" +
 	"\aService\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
@@ -672,7 +2103,87 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"U\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12:\n" +
+	"\x19recording_retention_hours\x18\a \x01(\x05R\x17recordingRetentionHours\x128\n" +
+	"\banalysis\x18\b \x01(\v2\x1c.service.v1.AnalysisSettingsR\banalysis\"\xa8\x02\n" +
+	"\x10AnalysisSettings\x12.\n" +
+	"\x10indexing_enabled\x18\x01 \x01(\bH\x00R\x0findexingEnabled\x88\x01\x01\x124\n" +
+	"\x16frame_interval_seconds\x18\x02 \x01(\x01R\x14frameIntervalSeconds\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12 \n" +
+	"\vinstruction\x18\x05 \x01(\tR\vinstruction\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\x12&\n" +
+	"\x05zones\x18\a \x03(\v2\x10.service.v1.ZoneR\x05zonesB\x13\n" +
+	"\x11_indexing_enabled\"Z\n" +
+	"\x04Zone\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02x1\x18\x02 \x01(\x05R\x02x1\x12\x0e\n" +
+	"\x02y1\x18\x03 \x01(\x05R\x02y1\x12\x0e\n" +
+	"\x02x2\x18\x04 \x01(\x05R\x02x2\x12\x0e\n" +
+	"\x02y2\x18\x05 \x01(\x05R\x02y2\"\xa8\x04\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06online\x18\x04 \x01(\bR\x06online\x127\n" +
+	"\tlast_seen\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12!\n" +
+	"\fnode_version\x18\x06 \x01(\tR\vnodeVersion\x12)\n" +
+	"\x10protocol_version\x18\a \x01(\x05R\x0fprotocolVersion\x12#\n" +
+	"\rmac_addresses\x18\b \x03(\tR\fmacAddresses\x12#\n" +
+	"\rservice_count\x18\t \x01(\x05R\fserviceCount\x12.\n" +
+	"\x13active_bridge_count\x18\n" +
+	" \x01(\x05R\x11activeBridgeCount\x129\n" +
+	"\n" +
+	"first_seen\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tfirstSeen\x12B\n" +
+	"\x0ftoken_issued_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rtokenIssuedAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\"\xfd\x01\n" +
+	"\x10DiscoveredDevice\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\"\n" +
+	"\fmanufacturer\x18\x04 \x01(\tR\fmanufacturer\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12\x1b\n" +
+	"\tonvif_url\x18\x06 \x01(\tR\bonvifUrl\x126\n" +
+	"\astreams\x18\a \x03(\v2\x1c.service.v1.DiscoveredStreamR\astreams\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\x88\x01\n" +
+	"\x10DiscoveredStream\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1a\n" +
+	"\bencoding\x18\x03 \x01(\tR\bencoding\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x05 \x01(\x05R\x06height\"\xbe\x05\n" +
+	"\rServiceStatus\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12;\n" +
+	"\vstate_since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"stateSince\x12>\n" +
+	"\rlast_frame_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vlastFrameAt\x12<\n" +
+	"\flast_data_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastDataAt\x12\x1f\n" +
+	"\vretry_count\x18\x06 \x01(\x05R\n" +
+	"retryCount\x12\x1f\n" +
+	"\vmax_retries\x18\a \x01(\x05R\n" +
+	"maxRetries\x12>\n" +
+	"\rlast_retry_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastRetryAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12&\n" +
+	"\x0flast_error_code\x18\n" +
+	" \x01(\tR\rlastErrorCode\x12#\n" +
+	"\rextractor_fps\x18\v \x01(\x01R\fextractorFps\x12\x1a\n" +
+	"\bindexing\x18\f \x01(\bR\bindexing\x12\x1c\n" +
+	"\trecording\x18\r \x01(\bR\trecording\x122\n" +
+	"\x15bridge_buffered_bytes\x18\x0e \x01(\x03R\x13bridgeBufferedBytes\x123\n" +
+	"\x16bridge_in_flight_bytes\x18\x0f \x01(\x03R\x13bridgeInFlightBytes\x12,\n" +
+	"\x12bridge_send_credit\x18\x10 \x01(\x03R\x10bridgeSendCredit\"p\n" +
+	"\vStreamCodec\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"clock_rate\x18\x03 \x01(\rR\tclockRate\x12\x1a\n" +
+	"\bchannels\x18\x04 \x01(\rR\bchannels\"U\n" +
 	"\x14CreateServiceRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x12\x17\n" +
@@ -682,32 +2193,90 @@ const file_service_v1_service_proto_rawDesc = "" +
 	"\x1bListServicesByNodeIdRequest\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"O\n" +
 	"\x1cListServicesByNodeIdResponse\x12/\n" +
-	"\bservices\x18\x01 \x03(\v2\x13.service.v1.ServiceR\bservices\"L\n" +
+	"\bservices\x18\x01 \x03(\v2\x13.service.v1.ServiceR\bservices\"\xe5\x01\n" +
 	"\x14UpdateServiceRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
-	"\x03url\x18\x03 \x01(\tR\x03url\"F\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12?\n" +
+	"\x19recording_retention_hours\x18\x04 \x01(\x05H\x00R\x17recordingRetentionHours\x88\x01\x01\x128\n" +
+	"\banalysis\x18\x05 \x01(\v2\x1c.service.v1.AnalysisSettingsR\banalysisB\x1c\n" +
+	"\x1a_recording_retention_hours\"F\n" +
 	"\x15UpdateServiceResponse\x12-\n" +
 	"\aservice\x18\x01 \x01(\v2\x13.service.v1.ServiceR\aservice\"5\n" +
 	"\x14DeleteServiceRequest\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\tR\tserviceId\"1\n" +
 	"\x15DeleteServiceResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"3\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x92\x01\n" +
+	"\x16DiscoverDevicesRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12'\n" +
+	"\x0ftimeout_seconds\x18\x04 \x01(\x05R\x0etimeoutSeconds\"Q\n" +
+	"\x17DiscoverDevicesResponse\x126\n" +
+	"\adevices\x18\x01 \x03(\v2\x1c.service.v1.DiscoveredDeviceR\adevices\"B\n" +
+	"\x15TestServiceURLRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"\x8c\x03\n" +
+	"\x16TestServiceURLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1c\n" +
+	"\treachable\x18\x02 \x01(\bR\treachable\x12\x1c\n" +
+	"\tresponded\x18\x03 \x01(\bR\tresponded\x12\x1f\n" +
+	"\vauth_failed\x18\x04 \x01(\bR\n" +
+	"authFailed\x12\x1f\n" +
+	"\vsource_type\x18\x05 \x01(\tR\n" +
+	"sourceType\x12/\n" +
+	"\x06codecs\x18\x06 \x03(\v2\x17.service.v1.StreamCodecR\x06codecs\x12\x14\n" +
+	"\x05width\x18\a \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\b \x01(\x05R\x06height\x12!\n" +
+	"\fpreview_jpeg\x18\t \x01(\fR\vpreviewJpeg\x12#\n" +
+	"\rpreview_error\x18\n" +
+	" \x01(\tR\fpreviewError\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\f \x01(\tR\terrorCode\"8\n" +
+	"\x17GetServiceStatusRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\"M\n" +
+	"\x18GetServiceStatusResponse\x121\n" +
+	"\x06status\x18\x01 \x01(\v2\x19.service.v1.ServiceStatusR\x06status\":\n" +
+	"\x19WatchServiceStatusRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\"O\n" +
+	"\x1aWatchServiceStatusResponse\x121\n" +
+	"\x06status\x18\x01 \x01(\v2\x19.service.v1.ServiceStatusR\x06status\"3\n" +
 	"\x18AssociateUserNodeRequest\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"5\n" +
 	"\x19AssociateUserNodeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x16\n" +
-	"\x14ListUserNodesRequest\"2\n" +
+	"\x14ListUserNodesRequest\"Z\n" +
 	"\x15ListUserNodesResponse\x12\x19\n" +
-	"\bnode_ids\x18\x01 \x03(\tR\anodeIds2\xb5\x04\n" +
+	"\bnode_ids\x18\x01 \x03(\tR\anodeIds\x12&\n" +
+	"\x05nodes\x18\x02 \x03(\v2\x10.service.v1.NodeR\x05nodes\"O\n" +
+	"\x11UpdateNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\":\n" +
+	"\x12UpdateNodeResponse\x12$\n" +
+	"\x04node\x18\x01 \x01(\v2\x10.service.v1.NodeR\x04node\"1\n" +
+	"\x16RevokeNodeTokenRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"d\n" +
+	"\x17RevokeNodeTokenResponse\x12%\n" +
+	"\x0erevoked_tokens\x18\x01 \x01(\x05R\rrevokedTokens\x12\"\n" +
+	"\fdisconnected\x18\x02 \x01(\bR\fdisconnected2\xd9\b\n" +
 	"\x0eServiceService\x12T\n" +
 	"\rCreateService\x12 .service.v1.CreateServiceRequest\x1a!.service.v1.CreateServiceResponse\x12i\n" +
 	"\x14ListServicesByNodeId\x12'.service.v1.ListServicesByNodeIdRequest\x1a(.service.v1.ListServicesByNodeIdResponse\x12T\n" +
 	"\rUpdateService\x12 .service.v1.UpdateServiceRequest\x1a!.service.v1.UpdateServiceResponse\x12T\n" +
-	"\rDeleteService\x12 .service.v1.DeleteServiceRequest\x1a!.service.v1.DeleteServiceResponse\x12`\n" +
+	"\rDeleteService\x12 .service.v1.DeleteServiceRequest\x1a!.service.v1.DeleteServiceResponse\x12Z\n" +
+	"\x0fDiscoverDevices\x12\".service.v1.DiscoverDevicesRequest\x1a#.service.v1.DiscoverDevicesResponse\x12W\n" +
+	"\x0eTestServiceURL\x12!.service.v1.TestServiceURLRequest\x1a\".service.v1.TestServiceURLResponse\x12]\n" +
+	"\x10GetServiceStatus\x12#.service.v1.GetServiceStatusRequest\x1a$.service.v1.GetServiceStatusResponse\x12e\n" +
+	"\x12WatchServiceStatus\x12%.service.v1.WatchServiceStatusRequest\x1a&.service.v1.WatchServiceStatusResponse0\x01\x12`\n" +
 	"\x11AssociateUserNode\x12$.service.v1.AssociateUserNodeRequest\x1a%.service.v1.AssociateUserNodeResponse\x12T\n" +
-	"\rListUserNodes\x12 .service.v1.ListUserNodesRequest\x1a!.service.v1.ListUserNodesResponseB)Z'unblink/server/gen/service/v1;servicev1b\x06proto3"
+	"\rListUserNodes\x12 .service.v1.ListUserNodesRequest\x1a!.service.v1.ListUserNodesResponse\x12K\n" +
+	"\n" +
+	"UpdateNode\x12\x1d.service.v1.UpdateNodeRequest\x1a\x1e.service.v1.UpdateNodeResponse\x12Z\n" +
+	"\x0fRevokeNodeToken\x12\".service.v1.RevokeNodeTokenRequest\x1a#.service.v1.RevokeNodeTokenResponseB)Z'unblink/server/gen/service/v1;servicev1b\x06proto3"
 
 var (
 	file_service_v1_service_proto_rawDescOnce sync.Once
@@ -721,46 +2290,95 @@ func file_service_v1_service_proto_rawDescGZIP() []byte {
 	return file_service_v1_service_proto_rawDescData
 }
 
-var file_service_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_service_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_service_v1_service_proto_goTypes = []any{
 	(*Service)(nil),                      // 0: service.v1.Service
-	(*CreateServiceRequest)(nil),         // 1: service.v1.CreateServiceRequest
-	(*CreateServiceResponse)(nil),        // 2: service.v1.CreateServiceResponse
-	(*ListServicesByNodeIdRequest)(nil),  // 3: service.v1.ListServicesByNodeIdRequest
-	(*ListServicesByNodeIdResponse)(nil), // 4: service.v1.ListServicesByNodeIdResponse
-	(*UpdateServiceRequest)(nil),         // 5: service.v1.UpdateServiceRequest
-	(*UpdateServiceResponse)(nil),        // 6: service.v1.UpdateServiceResponse
-	(*DeleteServiceRequest)(nil),         // 7: service.v1.DeleteServiceRequest
-	(*DeleteServiceResponse)(nil),        // 8: service.v1.DeleteServiceResponse
-	(*AssociateUserNodeRequest)(nil),     // 9: service.v1.AssociateUserNodeRequest
-	(*AssociateUserNodeResponse)(nil),    // 10: service.v1.AssociateUserNodeResponse
-	(*ListUserNodesRequest)(nil),         // 11: service.v1.ListUserNodesRequest
-	(*ListUserNodesResponse)(nil),        // 12: service.v1.ListUserNodesResponse
-	(*timestamppb.Timestamp)(nil),        // 13: google.protobuf.Timestamp
+	(*AnalysisSettings)(nil),             // 1: service.v1.AnalysisSettings
+	(*Zone)(nil),                         // 2: service.v1.Zone
+	(*Node)(nil),                         // 3: service.v1.Node
+	(*DiscoveredDevice)(nil),             // 4: service.v1.DiscoveredDevice
+	(*DiscoveredStream)(nil),             // 5: service.v1.DiscoveredStream
+	(*ServiceStatus)(nil),                // 6: service.v1.ServiceStatus
+	(*StreamCodec)(nil),                  // 7: service.v1.StreamCodec
+	(*CreateServiceRequest)(nil),         // 8: service.v1.CreateServiceRequest
+	(*CreateServiceResponse)(nil),        // 9: service.v1.CreateServiceResponse
+	(*ListServicesByNodeIdRequest)(nil),  // 10: service.v1.ListServicesByNodeIdRequest
+	(*ListServicesByNodeIdResponse)(nil), // 11: service.v1.ListServicesByNodeIdResponse
+	(*UpdateServiceRequest)(nil),         // 12: service.v1.UpdateServiceRequest
+	(*UpdateServiceResponse)(nil),        // 13: service.v1.UpdateServiceResponse
+	(*DeleteServiceRequest)(nil),         // 14: service.v1.DeleteServiceRequest
+	(*DeleteServiceResponse)(nil),        // 15: service.v1.DeleteServiceResponse
+	(*DiscoverDevicesRequest)(nil),       // 16: service.v1.DiscoverDevicesRequest
+	(*DiscoverDevicesResponse)(nil),      // 17: service.v1.DiscoverDevicesResponse
+	(*TestServiceURLRequest)(nil),        // 18: service.v1.TestServiceURLRequest
+	(*TestServiceURLResponse)(nil),       // 19: service.v1.TestServiceURLResponse
+	(*GetServiceStatusRequest)(nil),      // 20: service.v1.GetServiceStatusRequest
+	(*GetServiceStatusResponse)(nil),     // 21: service.v1.GetServiceStatusResponse
+	(*WatchServiceStatusRequest)(nil),    // 22: service.v1.WatchServiceStatusRequest
+	(*WatchServiceStatusResponse)(nil),   // 23: service.v1.WatchServiceStatusResponse
+	(*AssociateUserNodeRequest)(nil),     // 24: service.v1.AssociateUserNodeRequest
+	(*AssociateUserNodeResponse)(nil),    // 25: service.v1.AssociateUserNodeResponse
+	(*ListUserNodesRequest)(nil),         // 26: service.v1.ListUserNodesRequest
+	(*ListUserNodesResponse)(nil),        // 27: service.v1.ListUserNodesResponse
+	(*UpdateNodeRequest)(nil),            // 28: service.v1.UpdateNodeRequest
+	(*UpdateNodeResponse)(nil),           // 29: service.v1.UpdateNodeResponse
+	(*RevokeNodeTokenRequest)(nil),       // 30: service.v1.RevokeNodeTokenRequest
+	(*RevokeNodeTokenResponse)(nil),      // 31: service.v1.RevokeNodeTokenResponse
+	(*timestamppb.Timestamp)(nil),        // 32: google.protobuf.Timestamp
 }
 var file_service_v1_service_proto_depIdxs = []int32{
-	13, // 0: service.v1.Service.created_at:type_name -> google.protobuf.Timestamp
-	13, // 1: service.v1.Service.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 2: service.v1.CreateServiceResponse.service:type_name -> service.v1.Service
-	0,  // 3: service.v1.ListServicesByNodeIdResponse.services:type_name -> service.v1.Service
-	0,  // 4: service.v1.UpdateServiceResponse.service:type_name -> service.v1.Service
-	1,  // 5: service.v1.ServiceService.CreateService:input_type -> service.v1.CreateServiceRequest
-	3,  // 6: service.v1.ServiceService.ListServicesByNodeId:input_type -> service.v1.ListServicesByNodeIdRequest
-	5,  // 7: service.v1.ServiceService.UpdateService:input_type -> service.v1.UpdateServiceRequest
-	7,  // 8: service.v1.ServiceService.DeleteService:input_type -> service.v1.DeleteServiceRequest
-	9,  // 9: service.v1.ServiceService.AssociateUserNode:input_type -> service.v1.AssociateUserNodeRequest
-	11, // 10: service.v1.ServiceService.ListUserNodes:input_type -> service.v1.ListUserNodesRequest
-	2,  // 11: service.v1.ServiceService.CreateService:output_type -> service.v1.CreateServiceResponse
-	4,  // 12: service.v1.ServiceService.ListServicesByNodeId:output_type -> service.v1.ListServicesByNodeIdResponse
-	6,  // 13: service.v1.ServiceService.UpdateService:output_type -> service.v1.UpdateServiceResponse
-	8,  // 14: service.v1.ServiceService.DeleteService:output_type -> service.v1.DeleteServiceResponse
-	10, // 15: service.v1.ServiceService.AssociateUserNode:output_type -> service.v1.AssociateUserNodeResponse
-	12, // 16: service.v1.ServiceService.ListUserNodes:output_type -> service.v1.ListUserNodesResponse
-	11, // [11:17] is the sub-list for method output_type
-	5,  // [5:11] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	32, // 0: service.v1.Service.created_at:type_name -> google.protobuf.Timestamp
+	32, // 1: service.v1.Service.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: service.v1.Service.analysis:type_name -> service.v1.AnalysisSettings
+	2,  // 3: service.v1.AnalysisSettings.zones:type_name -> service.v1.Zone
+	32, // 4: service.v1.Node.last_seen:type_name -> google.protobuf.Timestamp
+	32, // 5: service.v1.Node.first_seen:type_name -> google.protobuf.Timestamp
+	32, // 6: service.v1.Node.token_issued_at:type_name -> google.protobuf.Timestamp
+	32, // 7: service.v1.Node.revoked_at:type_name -> google.protobuf.Timestamp
+	5,  // 8: service.v1.DiscoveredDevice.streams:type_name -> service.v1.DiscoveredStream
+	32, // 9: service.v1.ServiceStatus.state_since:type_name -> google.protobuf.Timestamp
+	32, // 10: service.v1.ServiceStatus.last_frame_at:type_name -> google.protobuf.Timestamp
+	32, // 11: service.v1.ServiceStatus.last_data_at:type_name -> google.protobuf.Timestamp
+	32, // 12: service.v1.ServiceStatus.last_retry_at:type_name -> google.protobuf.Timestamp
+	0,  // 13: service.v1.CreateServiceResponse.service:type_name -> service.v1.Service
+	0,  // 14: service.v1.ListServicesByNodeIdResponse.services:type_name -> service.v1.Service
+	1,  // 15: service.v1.UpdateServiceRequest.analysis:type_name -> service.v1.AnalysisSettings
+	0,  // 16: service.v1.UpdateServiceResponse.service:type_name -> service.v1.Service
+	4,  // 17: service.v1.DiscoverDevicesResponse.devices:type_name -> service.v1.DiscoveredDevice
+	7,  // 18: service.v1.TestServiceURLResponse.codecs:type_name -> service.v1.StreamCodec
+	6,  // 19: service.v1.GetServiceStatusResponse.status:type_name -> service.v1.ServiceStatus
+	6,  // 20: service.v1.WatchServiceStatusResponse.status:type_name -> service.v1.ServiceStatus
+	3,  // 21: service.v1.ListUserNodesResponse.nodes:type_name -> service.v1.Node
+	3,  // 22: service.v1.UpdateNodeResponse.node:type_name -> service.v1.Node
+	8,  // 23: service.v1.ServiceService.CreateService:input_type -> service.v1.CreateServiceRequest
+	10, // 24: service.v1.ServiceService.ListServicesByNodeId:input_type -> service.v1.ListServicesByNodeIdRequest
+	12, // 25: service.v1.ServiceService.UpdateService:input_type -> service.v1.UpdateServiceRequest
+	14, // 26: service.v1.ServiceService.DeleteService:input_type -> service.v1.DeleteServiceRequest
+	16, // 27: service.v1.ServiceService.DiscoverDevices:input_type -> service.v1.DiscoverDevicesRequest
+	18, // 28: service.v1.ServiceService.TestServiceURL:input_type -> service.v1.TestServiceURLRequest
+	20, // 29: service.v1.ServiceService.GetServiceStatus:input_type -> service.v1.GetServiceStatusRequest
+	22, // 30: service.v1.ServiceService.WatchServiceStatus:input_type -> service.v1.WatchServiceStatusRequest
+	24, // 31: service.v1.ServiceService.AssociateUserNode:input_type -> service.v1.AssociateUserNodeRequest
+	26, // 32: service.v1.ServiceService.ListUserNodes:input_type -> service.v1.ListUserNodesRequest
+	28, // 33: service.v1.ServiceService.UpdateNode:input_type -> service.v1.UpdateNodeRequest
+	30, // 34: service.v1.ServiceService.RevokeNodeToken:input_type -> service.v1.RevokeNodeTokenRequest
+	9,  // 35: service.v1.ServiceService.CreateService:output_type -> service.v1.CreateServiceResponse
+	11, // 36: service.v1.ServiceService.ListServicesByNodeId:output_type -> service.v1.ListServicesByNodeIdResponse
+	13, // 37: service.v1.ServiceService.UpdateService:output_type -> service.v1.UpdateServiceResponse
+	15, // 38: service.v1.ServiceService.DeleteService:output_type -> service.v1.DeleteServiceResponse
+	17, // 39: service.v1.ServiceService.DiscoverDevices:output_type -> service.v1.DiscoverDevicesResponse
+	19, // 40: service.v1.ServiceService.TestServiceURL:output_type -> service.v1.TestServiceURLResponse
+	21, // 41: service.v1.ServiceService.GetServiceStatus:output_type -> service.v1.GetServiceStatusResponse
+	23, // 42: service.v1.ServiceService.WatchServiceStatus:output_type -> service.v1.WatchServiceStatusResponse
+	25, // 43: service.v1.ServiceService.AssociateUserNode:output_type -> service.v1.AssociateUserNodeResponse
+	27, // 44: service.v1.ServiceService.ListUserNodes:output_type -> service.v1.ListUserNodesResponse
+	29, // 45: service.v1.ServiceService.UpdateNode:output_type -> service.v1.UpdateNodeResponse
+	31, // 46: service.v1.ServiceService.RevokeNodeToken:output_type -> service.v1.RevokeNodeTokenResponse
+	35, // [35:47] is the sub-list for method output_type
+	23, // [23:35] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_service_v1_service_proto_init() }
@@ -768,13 +2386,15 @@ func file_service_v1_service_proto_init() {
 	if File_service_v1_service_proto != nil {
 		return
 	}
+	file_service_v1_service_proto_msgTypes[1].OneofWrappers = []any{}
+	file_service_v1_service_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_v1_service_proto_rawDesc), len(file_service_v1_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
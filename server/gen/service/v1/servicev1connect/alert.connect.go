@@ -0,0 +1,196 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: service/v1/alert.proto
+
+package servicev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+	v1 "unblink/server/gen/service/v1"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AlertServiceName is the fully-qualified name of the AlertService service.
+	AlertServiceName = "service.v1.AlertService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AlertServiceCreateAlertRuleProcedure is the fully-qualified name of the AlertService's
+	// CreateAlertRule RPC.
+	AlertServiceCreateAlertRuleProcedure = "/service.v1.AlertService/CreateAlertRule"
+	// AlertServiceListAlertRulesProcedure is the fully-qualified name of the AlertService's
+	// ListAlertRules RPC.
+	AlertServiceListAlertRulesProcedure = "/service.v1.AlertService/ListAlertRules"
+	// AlertServiceUpdateAlertRuleProcedure is the fully-qualified name of the AlertService's
+	// UpdateAlertRule RPC.
+	AlertServiceUpdateAlertRuleProcedure = "/service.v1.AlertService/UpdateAlertRule"
+	// AlertServiceDeleteAlertRuleProcedure is the fully-qualified name of the AlertService's
+	// DeleteAlertRule RPC.
+	AlertServiceDeleteAlertRuleProcedure = "/service.v1.AlertService/DeleteAlertRule"
+)
+
+// AlertServiceClient is a client for the service.v1.AlertService service.
+type AlertServiceClient interface {
+	CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error)
+	ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error)
+	UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error)
+	DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error)
+}
+
+// NewAlertServiceClient constructs a client for the service.v1.AlertService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAlertServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AlertServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	alertServiceMethods := v1.File_service_v1_alert_proto.Services().ByName("AlertService").Methods()
+	return &alertServiceClient{
+		createAlertRule: connect.NewClient[v1.CreateAlertRuleRequest, v1.CreateAlertRuleResponse](
+			httpClient,
+			baseURL+AlertServiceCreateAlertRuleProcedure,
+			connect.WithSchema(alertServiceMethods.ByName("CreateAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		listAlertRules: connect.NewClient[v1.ListAlertRulesRequest, v1.ListAlertRulesResponse](
+			httpClient,
+			baseURL+AlertServiceListAlertRulesProcedure,
+			connect.WithSchema(alertServiceMethods.ByName("ListAlertRules")),
+			connect.WithClientOptions(opts...),
+		),
+		updateAlertRule: connect.NewClient[v1.UpdateAlertRuleRequest, v1.UpdateAlertRuleResponse](
+			httpClient,
+			baseURL+AlertServiceUpdateAlertRuleProcedure,
+			connect.WithSchema(alertServiceMethods.ByName("UpdateAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteAlertRule: connect.NewClient[v1.DeleteAlertRuleRequest, v1.DeleteAlertRuleResponse](
+			httpClient,
+			baseURL+AlertServiceDeleteAlertRuleProcedure,
+			connect.WithSchema(alertServiceMethods.ByName("DeleteAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// alertServiceClient implements AlertServiceClient.
+type alertServiceClient struct {
+	createAlertRule *connect.Client[v1.CreateAlertRuleRequest, v1.CreateAlertRuleResponse]
+	listAlertRules  *connect.Client[v1.ListAlertRulesRequest, v1.ListAlertRulesResponse]
+	updateAlertRule *connect.Client[v1.UpdateAlertRuleRequest, v1.UpdateAlertRuleResponse]
+	deleteAlertRule *connect.Client[v1.DeleteAlertRuleRequest, v1.DeleteAlertRuleResponse]
+}
+
+// CreateAlertRule calls service.v1.AlertService.CreateAlertRule.
+func (c *alertServiceClient) CreateAlertRule(ctx context.Context, req *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error) {
+	return c.createAlertRule.CallUnary(ctx, req)
+}
+
+// ListAlertRules calls service.v1.AlertService.ListAlertRules.
+func (c *alertServiceClient) ListAlertRules(ctx context.Context, req *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error) {
+	return c.listAlertRules.CallUnary(ctx, req)
+}
+
+// UpdateAlertRule calls service.v1.AlertService.UpdateAlertRule.
+func (c *alertServiceClient) UpdateAlertRule(ctx context.Context, req *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error) {
+	return c.updateAlertRule.CallUnary(ctx, req)
+}
+
+// DeleteAlertRule calls service.v1.AlertService.DeleteAlertRule.
+func (c *alertServiceClient) DeleteAlertRule(ctx context.Context, req *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error) {
+	return c.deleteAlertRule.CallUnary(ctx, req)
+}
+
+// AlertServiceHandler is an implementation of the service.v1.AlertService service.
+type AlertServiceHandler interface {
+	CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error)
+	ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error)
+	UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error)
+	DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error)
+}
+
+// NewAlertServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAlertServiceHandler(svc AlertServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	alertServiceMethods := v1.File_service_v1_alert_proto.Services().ByName("AlertService").Methods()
+	alertServiceCreateAlertRuleHandler := connect.NewUnaryHandler(
+		AlertServiceCreateAlertRuleProcedure,
+		svc.CreateAlertRule,
+		connect.WithSchema(alertServiceMethods.ByName("CreateAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	alertServiceListAlertRulesHandler := connect.NewUnaryHandler(
+		AlertServiceListAlertRulesProcedure,
+		svc.ListAlertRules,
+		connect.WithSchema(alertServiceMethods.ByName("ListAlertRules")),
+		connect.WithHandlerOptions(opts...),
+	)
+	alertServiceUpdateAlertRuleHandler := connect.NewUnaryHandler(
+		AlertServiceUpdateAlertRuleProcedure,
+		svc.UpdateAlertRule,
+		connect.WithSchema(alertServiceMethods.ByName("UpdateAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	alertServiceDeleteAlertRuleHandler := connect.NewUnaryHandler(
+		AlertServiceDeleteAlertRuleProcedure,
+		svc.DeleteAlertRule,
+		connect.WithSchema(alertServiceMethods.ByName("DeleteAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/service.v1.AlertService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AlertServiceCreateAlertRuleProcedure:
+			alertServiceCreateAlertRuleHandler.ServeHTTP(w, r)
+		case AlertServiceListAlertRulesProcedure:
+			alertServiceListAlertRulesHandler.ServeHTTP(w, r)
+		case AlertServiceUpdateAlertRuleProcedure:
+			alertServiceUpdateAlertRuleHandler.ServeHTTP(w, r)
+		case AlertServiceDeleteAlertRuleProcedure:
+			alertServiceDeleteAlertRuleHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAlertServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAlertServiceHandler struct{}
+
+func (UnimplementedAlertServiceHandler) CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.AlertService.CreateAlertRule is not implemented"))
+}
+
+func (UnimplementedAlertServiceHandler) ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.AlertService.ListAlertRules is not implemented"))
+}
+
+func (UnimplementedAlertServiceHandler) UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.AlertService.UpdateAlertRule is not implemented"))
+}
+
+func (UnimplementedAlertServiceHandler) DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.AlertService.DeleteAlertRule is not implemented"))
+}
@@ -39,6 +39,9 @@ const (
 	// EventServiceCountEventsForUserProcedure is the fully-qualified name of the EventService's
 	// CountEventsForUser RPC.
 	EventServiceCountEventsForUserProcedure = "/service.v1.EventService/CountEventsForUser"
+	// EventServiceSearchEventsProcedure is the fully-qualified name of the EventService's SearchEvents
+	// RPC.
+	EventServiceSearchEventsProcedure = "/service.v1.EventService/SearchEvents"
 	// EventServiceStreamEventsByNodeIdProcedure is the fully-qualified name of the EventService's
 	// StreamEventsByNodeId RPC.
 	EventServiceStreamEventsByNodeIdProcedure = "/service.v1.EventService/StreamEventsByNodeId"
@@ -49,6 +52,8 @@ type EventServiceClient interface {
 	// Event management
 	ListEventsByNodeId(context.Context, *connect.Request[v1.ListEventsByNodeIdRequest]) (*connect.Response[v1.ListEventsByNodeIdResponse], error)
 	CountEventsForUser(context.Context, *connect.Request[v1.CountEventsForUserRequest]) (*connect.Response[v1.CountEventsForUserResponse], error)
+	// Hybrid keyword + semantic search over the VLM descriptions of the user's events
+	SearchEvents(context.Context, *connect.Request[v1.SearchEventsRequest]) (*connect.Response[v1.SearchEventsResponse], error)
 	// Streaming events
 	StreamEventsByNodeId(context.Context, *connect.Request[v1.StreamEventsByNodeIdRequest]) (*connect.ServerStreamForClient[v1.StreamEventsByNodeIdResponse], error)
 }
@@ -76,6 +81,12 @@ func NewEventServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			connect.WithSchema(eventServiceMethods.ByName("CountEventsForUser")),
 			connect.WithClientOptions(opts...),
 		),
+		searchEvents: connect.NewClient[v1.SearchEventsRequest, v1.SearchEventsResponse](
+			httpClient,
+			baseURL+EventServiceSearchEventsProcedure,
+			connect.WithSchema(eventServiceMethods.ByName("SearchEvents")),
+			connect.WithClientOptions(opts...),
+		),
 		streamEventsByNodeId: connect.NewClient[v1.StreamEventsByNodeIdRequest, v1.StreamEventsByNodeIdResponse](
 			httpClient,
 			baseURL+EventServiceStreamEventsByNodeIdProcedure,
@@ -89,6 +100,7 @@ func NewEventServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 type eventServiceClient struct {
 	listEventsByNodeId   *connect.Client[v1.ListEventsByNodeIdRequest, v1.ListEventsByNodeIdResponse]
 	countEventsForUser   *connect.Client[v1.CountEventsForUserRequest, v1.CountEventsForUserResponse]
+	searchEvents         *connect.Client[v1.SearchEventsRequest, v1.SearchEventsResponse]
 	streamEventsByNodeId *connect.Client[v1.StreamEventsByNodeIdRequest, v1.StreamEventsByNodeIdResponse]
 }
 
@@ -102,6 +114,11 @@ func (c *eventServiceClient) CountEventsForUser(ctx context.Context, req *connec
 	return c.countEventsForUser.CallUnary(ctx, req)
 }
 
+// SearchEvents calls service.v1.EventService.SearchEvents.
+func (c *eventServiceClient) SearchEvents(ctx context.Context, req *connect.Request[v1.SearchEventsRequest]) (*connect.Response[v1.SearchEventsResponse], error) {
+	return c.searchEvents.CallUnary(ctx, req)
+}
+
 // StreamEventsByNodeId calls service.v1.EventService.StreamEventsByNodeId.
 func (c *eventServiceClient) StreamEventsByNodeId(ctx context.Context, req *connect.Request[v1.StreamEventsByNodeIdRequest]) (*connect.ServerStreamForClient[v1.StreamEventsByNodeIdResponse], error) {
 	return c.streamEventsByNodeId.CallServerStream(ctx, req)
@@ -112,6 +129,8 @@ type EventServiceHandler interface {
 	// Event management
 	ListEventsByNodeId(context.Context, *connect.Request[v1.ListEventsByNodeIdRequest]) (*connect.Response[v1.ListEventsByNodeIdResponse], error)
 	CountEventsForUser(context.Context, *connect.Request[v1.CountEventsForUserRequest]) (*connect.Response[v1.CountEventsForUserResponse], error)
+	// Hybrid keyword + semantic search over the VLM descriptions of the user's events
+	SearchEvents(context.Context, *connect.Request[v1.SearchEventsRequest]) (*connect.Response[v1.SearchEventsResponse], error)
 	// Streaming events
 	StreamEventsByNodeId(context.Context, *connect.Request[v1.StreamEventsByNodeIdRequest], *connect.ServerStream[v1.StreamEventsByNodeIdResponse]) error
 }
@@ -135,6 +154,12 @@ func NewEventServiceHandler(svc EventServiceHandler, opts ...connect.HandlerOpti
 		connect.WithSchema(eventServiceMethods.ByName("CountEventsForUser")),
 		connect.WithHandlerOptions(opts...),
 	)
+	eventServiceSearchEventsHandler := connect.NewUnaryHandler(
+		EventServiceSearchEventsProcedure,
+		svc.SearchEvents,
+		connect.WithSchema(eventServiceMethods.ByName("SearchEvents")),
+		connect.WithHandlerOptions(opts...),
+	)
 	eventServiceStreamEventsByNodeIdHandler := connect.NewServerStreamHandler(
 		EventServiceStreamEventsByNodeIdProcedure,
 		svc.StreamEventsByNodeId,
@@ -147,6 +172,8 @@ func NewEventServiceHandler(svc EventServiceHandler, opts ...connect.HandlerOpti
 			eventServiceListEventsByNodeIdHandler.ServeHTTP(w, r)
 		case EventServiceCountEventsForUserProcedure:
 			eventServiceCountEventsForUserHandler.ServeHTTP(w, r)
+		case EventServiceSearchEventsProcedure:
+			eventServiceSearchEventsHandler.ServeHTTP(w, r)
 		case EventServiceStreamEventsByNodeIdProcedure:
 			eventServiceStreamEventsByNodeIdHandler.ServeHTTP(w, r)
 		default:
@@ -166,6 +193,10 @@ func (UnimplementedEventServiceHandler) CountEventsForUser(context.Context, *con
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.EventService.CountEventsForUser is not implemented"))
 }
 
+func (UnimplementedEventServiceHandler) SearchEvents(context.Context, *connect.Request[v1.SearchEventsRequest]) (*connect.Response[v1.SearchEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.EventService.SearchEvents is not implemented"))
+}
+
 func (UnimplementedEventServiceHandler) StreamEventsByNodeId(context.Context, *connect.Request[v1.StreamEventsByNodeIdRequest], *connect.ServerStream[v1.StreamEventsByNodeIdResponse]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.EventService.StreamEventsByNodeId is not implemented"))
 }
@@ -45,12 +45,30 @@ const (
 	// ServiceServiceDeleteServiceProcedure is the fully-qualified name of the ServiceService's
 	// DeleteService RPC.
 	ServiceServiceDeleteServiceProcedure = "/service.v1.ServiceService/DeleteService"
+	// ServiceServiceDiscoverDevicesProcedure is the fully-qualified name of the ServiceService's
+	// DiscoverDevices RPC.
+	ServiceServiceDiscoverDevicesProcedure = "/service.v1.ServiceService/DiscoverDevices"
+	// ServiceServiceTestServiceURLProcedure is the fully-qualified name of the ServiceService's
+	// TestServiceURL RPC.
+	ServiceServiceTestServiceURLProcedure = "/service.v1.ServiceService/TestServiceURL"
+	// ServiceServiceGetServiceStatusProcedure is the fully-qualified name of the ServiceService's
+	// GetServiceStatus RPC.
+	ServiceServiceGetServiceStatusProcedure = "/service.v1.ServiceService/GetServiceStatus"
+	// ServiceServiceWatchServiceStatusProcedure is the fully-qualified name of the ServiceService's
+	// WatchServiceStatus RPC.
+	ServiceServiceWatchServiceStatusProcedure = "/service.v1.ServiceService/WatchServiceStatus"
 	// ServiceServiceAssociateUserNodeProcedure is the fully-qualified name of the ServiceService's
 	// AssociateUserNode RPC.
 	ServiceServiceAssociateUserNodeProcedure = "/service.v1.ServiceService/AssociateUserNode"
 	// ServiceServiceListUserNodesProcedure is the fully-qualified name of the ServiceService's
 	// ListUserNodes RPC.
 	ServiceServiceListUserNodesProcedure = "/service.v1.ServiceService/ListUserNodes"
+	// ServiceServiceUpdateNodeProcedure is the fully-qualified name of the ServiceService's UpdateNode
+	// RPC.
+	ServiceServiceUpdateNodeProcedure = "/service.v1.ServiceService/UpdateNode"
+	// ServiceServiceRevokeNodeTokenProcedure is the fully-qualified name of the ServiceService's
+	// RevokeNodeToken RPC.
+	ServiceServiceRevokeNodeTokenProcedure = "/service.v1.ServiceService/RevokeNodeToken"
 )
 
 // ServiceServiceClient is a client for the service.v1.ServiceService service.
@@ -60,9 +78,16 @@ type ServiceServiceClient interface {
 	ListServicesByNodeId(context.Context, *connect.Request[v1.ListServicesByNodeIdRequest]) (*connect.Response[v1.ListServicesByNodeIdResponse], error)
 	UpdateService(context.Context, *connect.Request[v1.UpdateServiceRequest]) (*connect.Response[v1.UpdateServiceResponse], error)
 	DeleteService(context.Context, *connect.Request[v1.DeleteServiceRequest]) (*connect.Response[v1.DeleteServiceResponse], error)
+	DiscoverDevices(context.Context, *connect.Request[v1.DiscoverDevicesRequest]) (*connect.Response[v1.DiscoverDevicesResponse], error)
+	TestServiceURL(context.Context, *connect.Request[v1.TestServiceURLRequest]) (*connect.Response[v1.TestServiceURLResponse], error)
+	// Service health
+	GetServiceStatus(context.Context, *connect.Request[v1.GetServiceStatusRequest]) (*connect.Response[v1.GetServiceStatusResponse], error)
+	WatchServiceStatus(context.Context, *connect.Request[v1.WatchServiceStatusRequest]) (*connect.ServerStreamForClient[v1.WatchServiceStatusResponse], error)
 	// Node access management
 	AssociateUserNode(context.Context, *connect.Request[v1.AssociateUserNodeRequest]) (*connect.Response[v1.AssociateUserNodeResponse], error)
 	ListUserNodes(context.Context, *connect.Request[v1.ListUserNodesRequest]) (*connect.Response[v1.ListUserNodesResponse], error)
+	UpdateNode(context.Context, *connect.Request[v1.UpdateNodeRequest]) (*connect.Response[v1.UpdateNodeResponse], error)
+	RevokeNodeToken(context.Context, *connect.Request[v1.RevokeNodeTokenRequest]) (*connect.Response[v1.RevokeNodeTokenResponse], error)
 }
 
 // NewServiceServiceClient constructs a client for the service.v1.ServiceService service. By
@@ -100,6 +125,30 @@ func NewServiceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(serviceServiceMethods.ByName("DeleteService")),
 			connect.WithClientOptions(opts...),
 		),
+		discoverDevices: connect.NewClient[v1.DiscoverDevicesRequest, v1.DiscoverDevicesResponse](
+			httpClient,
+			baseURL+ServiceServiceDiscoverDevicesProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("DiscoverDevices")),
+			connect.WithClientOptions(opts...),
+		),
+		testServiceURL: connect.NewClient[v1.TestServiceURLRequest, v1.TestServiceURLResponse](
+			httpClient,
+			baseURL+ServiceServiceTestServiceURLProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("TestServiceURL")),
+			connect.WithClientOptions(opts...),
+		),
+		getServiceStatus: connect.NewClient[v1.GetServiceStatusRequest, v1.GetServiceStatusResponse](
+			httpClient,
+			baseURL+ServiceServiceGetServiceStatusProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("GetServiceStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		watchServiceStatus: connect.NewClient[v1.WatchServiceStatusRequest, v1.WatchServiceStatusResponse](
+			httpClient,
+			baseURL+ServiceServiceWatchServiceStatusProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("WatchServiceStatus")),
+			connect.WithClientOptions(opts...),
+		),
 		associateUserNode: connect.NewClient[v1.AssociateUserNodeRequest, v1.AssociateUserNodeResponse](
 			httpClient,
 			baseURL+ServiceServiceAssociateUserNodeProcedure,
@@ -112,6 +161,18 @@ func NewServiceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(serviceServiceMethods.ByName("ListUserNodes")),
 			connect.WithClientOptions(opts...),
 		),
+		updateNode: connect.NewClient[v1.UpdateNodeRequest, v1.UpdateNodeResponse](
+			httpClient,
+			baseURL+ServiceServiceUpdateNodeProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("UpdateNode")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeNodeToken: connect.NewClient[v1.RevokeNodeTokenRequest, v1.RevokeNodeTokenResponse](
+			httpClient,
+			baseURL+ServiceServiceRevokeNodeTokenProcedure,
+			connect.WithSchema(serviceServiceMethods.ByName("RevokeNodeToken")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -121,8 +182,14 @@ type serviceServiceClient struct {
 	listServicesByNodeId *connect.Client[v1.ListServicesByNodeIdRequest, v1.ListServicesByNodeIdResponse]
 	updateService        *connect.Client[v1.UpdateServiceRequest, v1.UpdateServiceResponse]
 	deleteService        *connect.Client[v1.DeleteServiceRequest, v1.DeleteServiceResponse]
+	discoverDevices      *connect.Client[v1.DiscoverDevicesRequest, v1.DiscoverDevicesResponse]
+	testServiceURL       *connect.Client[v1.TestServiceURLRequest, v1.TestServiceURLResponse]
+	getServiceStatus     *connect.Client[v1.GetServiceStatusRequest, v1.GetServiceStatusResponse]
+	watchServiceStatus   *connect.Client[v1.WatchServiceStatusRequest, v1.WatchServiceStatusResponse]
 	associateUserNode    *connect.Client[v1.AssociateUserNodeRequest, v1.AssociateUserNodeResponse]
 	listUserNodes        *connect.Client[v1.ListUserNodesRequest, v1.ListUserNodesResponse]
+	updateNode           *connect.Client[v1.UpdateNodeRequest, v1.UpdateNodeResponse]
+	revokeNodeToken      *connect.Client[v1.RevokeNodeTokenRequest, v1.RevokeNodeTokenResponse]
 }
 
 // CreateService calls service.v1.ServiceService.CreateService.
@@ -145,6 +212,26 @@ func (c *serviceServiceClient) DeleteService(ctx context.Context, req *connect.R
 	return c.deleteService.CallUnary(ctx, req)
 }
 
+// DiscoverDevices calls service.v1.ServiceService.DiscoverDevices.
+func (c *serviceServiceClient) DiscoverDevices(ctx context.Context, req *connect.Request[v1.DiscoverDevicesRequest]) (*connect.Response[v1.DiscoverDevicesResponse], error) {
+	return c.discoverDevices.CallUnary(ctx, req)
+}
+
+// TestServiceURL calls service.v1.ServiceService.TestServiceURL.
+func (c *serviceServiceClient) TestServiceURL(ctx context.Context, req *connect.Request[v1.TestServiceURLRequest]) (*connect.Response[v1.TestServiceURLResponse], error) {
+	return c.testServiceURL.CallUnary(ctx, req)
+}
+
+// GetServiceStatus calls service.v1.ServiceService.GetServiceStatus.
+func (c *serviceServiceClient) GetServiceStatus(ctx context.Context, req *connect.Request[v1.GetServiceStatusRequest]) (*connect.Response[v1.GetServiceStatusResponse], error) {
+	return c.getServiceStatus.CallUnary(ctx, req)
+}
+
+// WatchServiceStatus calls service.v1.ServiceService.WatchServiceStatus.
+func (c *serviceServiceClient) WatchServiceStatus(ctx context.Context, req *connect.Request[v1.WatchServiceStatusRequest]) (*connect.ServerStreamForClient[v1.WatchServiceStatusResponse], error) {
+	return c.watchServiceStatus.CallServerStream(ctx, req)
+}
+
 // AssociateUserNode calls service.v1.ServiceService.AssociateUserNode.
 func (c *serviceServiceClient) AssociateUserNode(ctx context.Context, req *connect.Request[v1.AssociateUserNodeRequest]) (*connect.Response[v1.AssociateUserNodeResponse], error) {
 	return c.associateUserNode.CallUnary(ctx, req)
@@ -155,6 +242,16 @@ func (c *serviceServiceClient) ListUserNodes(ctx context.Context, req *connect.R
 	return c.listUserNodes.CallUnary(ctx, req)
 }
 
+// UpdateNode calls service.v1.ServiceService.UpdateNode.
+func (c *serviceServiceClient) UpdateNode(ctx context.Context, req *connect.Request[v1.UpdateNodeRequest]) (*connect.Response[v1.UpdateNodeResponse], error) {
+	return c.updateNode.CallUnary(ctx, req)
+}
+
+// RevokeNodeToken calls service.v1.ServiceService.RevokeNodeToken.
+func (c *serviceServiceClient) RevokeNodeToken(ctx context.Context, req *connect.Request[v1.RevokeNodeTokenRequest]) (*connect.Response[v1.RevokeNodeTokenResponse], error) {
+	return c.revokeNodeToken.CallUnary(ctx, req)
+}
+
 // ServiceServiceHandler is an implementation of the service.v1.ServiceService service.
 type ServiceServiceHandler interface {
 	// Service management
@@ -162,9 +259,16 @@ type ServiceServiceHandler interface {
 	ListServicesByNodeId(context.Context, *connect.Request[v1.ListServicesByNodeIdRequest]) (*connect.Response[v1.ListServicesByNodeIdResponse], error)
 	UpdateService(context.Context, *connect.Request[v1.UpdateServiceRequest]) (*connect.Response[v1.UpdateServiceResponse], error)
 	DeleteService(context.Context, *connect.Request[v1.DeleteServiceRequest]) (*connect.Response[v1.DeleteServiceResponse], error)
+	DiscoverDevices(context.Context, *connect.Request[v1.DiscoverDevicesRequest]) (*connect.Response[v1.DiscoverDevicesResponse], error)
+	TestServiceURL(context.Context, *connect.Request[v1.TestServiceURLRequest]) (*connect.Response[v1.TestServiceURLResponse], error)
+	// Service health
+	GetServiceStatus(context.Context, *connect.Request[v1.GetServiceStatusRequest]) (*connect.Response[v1.GetServiceStatusResponse], error)
+	WatchServiceStatus(context.Context, *connect.Request[v1.WatchServiceStatusRequest], *connect.ServerStream[v1.WatchServiceStatusResponse]) error
 	// Node access management
 	AssociateUserNode(context.Context, *connect.Request[v1.AssociateUserNodeRequest]) (*connect.Response[v1.AssociateUserNodeResponse], error)
 	ListUserNodes(context.Context, *connect.Request[v1.ListUserNodesRequest]) (*connect.Response[v1.ListUserNodesResponse], error)
+	UpdateNode(context.Context, *connect.Request[v1.UpdateNodeRequest]) (*connect.Response[v1.UpdateNodeResponse], error)
+	RevokeNodeToken(context.Context, *connect.Request[v1.RevokeNodeTokenRequest]) (*connect.Response[v1.RevokeNodeTokenResponse], error)
 }
 
 // NewServiceServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -198,6 +302,30 @@ func NewServiceServiceHandler(svc ServiceServiceHandler, opts ...connect.Handler
 		connect.WithSchema(serviceServiceMethods.ByName("DeleteService")),
 		connect.WithHandlerOptions(opts...),
 	)
+	serviceServiceDiscoverDevicesHandler := connect.NewUnaryHandler(
+		ServiceServiceDiscoverDevicesProcedure,
+		svc.DiscoverDevices,
+		connect.WithSchema(serviceServiceMethods.ByName("DiscoverDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	serviceServiceTestServiceURLHandler := connect.NewUnaryHandler(
+		ServiceServiceTestServiceURLProcedure,
+		svc.TestServiceURL,
+		connect.WithSchema(serviceServiceMethods.ByName("TestServiceURL")),
+		connect.WithHandlerOptions(opts...),
+	)
+	serviceServiceGetServiceStatusHandler := connect.NewUnaryHandler(
+		ServiceServiceGetServiceStatusProcedure,
+		svc.GetServiceStatus,
+		connect.WithSchema(serviceServiceMethods.ByName("GetServiceStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	serviceServiceWatchServiceStatusHandler := connect.NewServerStreamHandler(
+		ServiceServiceWatchServiceStatusProcedure,
+		svc.WatchServiceStatus,
+		connect.WithSchema(serviceServiceMethods.ByName("WatchServiceStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
 	serviceServiceAssociateUserNodeHandler := connect.NewUnaryHandler(
 		ServiceServiceAssociateUserNodeProcedure,
 		svc.AssociateUserNode,
@@ -210,6 +338,18 @@ func NewServiceServiceHandler(svc ServiceServiceHandler, opts ...connect.Handler
 		connect.WithSchema(serviceServiceMethods.ByName("ListUserNodes")),
 		connect.WithHandlerOptions(opts...),
 	)
+	serviceServiceUpdateNodeHandler := connect.NewUnaryHandler(
+		ServiceServiceUpdateNodeProcedure,
+		svc.UpdateNode,
+		connect.WithSchema(serviceServiceMethods.ByName("UpdateNode")),
+		connect.WithHandlerOptions(opts...),
+	)
+	serviceServiceRevokeNodeTokenHandler := connect.NewUnaryHandler(
+		ServiceServiceRevokeNodeTokenProcedure,
+		svc.RevokeNodeToken,
+		connect.WithSchema(serviceServiceMethods.ByName("RevokeNodeToken")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/service.v1.ServiceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ServiceServiceCreateServiceProcedure:
@@ -220,10 +360,22 @@ func NewServiceServiceHandler(svc ServiceServiceHandler, opts ...connect.Handler
 			serviceServiceUpdateServiceHandler.ServeHTTP(w, r)
 		case ServiceServiceDeleteServiceProcedure:
 			serviceServiceDeleteServiceHandler.ServeHTTP(w, r)
+		case ServiceServiceDiscoverDevicesProcedure:
+			serviceServiceDiscoverDevicesHandler.ServeHTTP(w, r)
+		case ServiceServiceTestServiceURLProcedure:
+			serviceServiceTestServiceURLHandler.ServeHTTP(w, r)
+		case ServiceServiceGetServiceStatusProcedure:
+			serviceServiceGetServiceStatusHandler.ServeHTTP(w, r)
+		case ServiceServiceWatchServiceStatusProcedure:
+			serviceServiceWatchServiceStatusHandler.ServeHTTP(w, r)
 		case ServiceServiceAssociateUserNodeProcedure:
 			serviceServiceAssociateUserNodeHandler.ServeHTTP(w, r)
 		case ServiceServiceListUserNodesProcedure:
 			serviceServiceListUserNodesHandler.ServeHTTP(w, r)
+		case ServiceServiceUpdateNodeProcedure:
+			serviceServiceUpdateNodeHandler.ServeHTTP(w, r)
+		case ServiceServiceRevokeNodeTokenProcedure:
+			serviceServiceRevokeNodeTokenHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -249,6 +401,22 @@ func (UnimplementedServiceServiceHandler) DeleteService(context.Context, *connec
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.DeleteService is not implemented"))
 }
 
+func (UnimplementedServiceServiceHandler) DiscoverDevices(context.Context, *connect.Request[v1.DiscoverDevicesRequest]) (*connect.Response[v1.DiscoverDevicesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.DiscoverDevices is not implemented"))
+}
+
+func (UnimplementedServiceServiceHandler) TestServiceURL(context.Context, *connect.Request[v1.TestServiceURLRequest]) (*connect.Response[v1.TestServiceURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.TestServiceURL is not implemented"))
+}
+
+func (UnimplementedServiceServiceHandler) GetServiceStatus(context.Context, *connect.Request[v1.GetServiceStatusRequest]) (*connect.Response[v1.GetServiceStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.GetServiceStatus is not implemented"))
+}
+
+func (UnimplementedServiceServiceHandler) WatchServiceStatus(context.Context, *connect.Request[v1.WatchServiceStatusRequest], *connect.ServerStream[v1.WatchServiceStatusResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.WatchServiceStatus is not implemented"))
+}
+
 func (UnimplementedServiceServiceHandler) AssociateUserNode(context.Context, *connect.Request[v1.AssociateUserNodeRequest]) (*connect.Response[v1.AssociateUserNodeResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.AssociateUserNode is not implemented"))
 }
@@ -256,3 +424,11 @@ func (UnimplementedServiceServiceHandler) AssociateUserNode(context.Context, *co
 func (UnimplementedServiceServiceHandler) ListUserNodes(context.Context, *connect.Request[v1.ListUserNodesRequest]) (*connect.Response[v1.ListUserNodesResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.ListUserNodes is not implemented"))
 }
+
+func (UnimplementedServiceServiceHandler) UpdateNode(context.Context, *connect.Request[v1.UpdateNodeRequest]) (*connect.Response[v1.UpdateNodeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.UpdateNode is not implemented"))
+}
+
+func (UnimplementedServiceServiceHandler) RevokeNodeToken(context.Context, *connect.Request[v1.RevokeNodeTokenRequest]) (*connect.Response[v1.RevokeNodeTokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.ServiceService.RevokeNodeToken is not implemented"))
+}
@@ -39,9 +39,18 @@ const (
 	// StorageServiceGetStorageItemProcedure is the fully-qualified name of the StorageService's
 	// GetStorageItem RPC.
 	StorageServiceGetStorageItemProcedure = "/service.v1.StorageService/GetStorageItem"
+	// StorageServiceGetStorageItemURLProcedure is the fully-qualified name of the StorageService's
+	// GetStorageItemURL RPC.
+	StorageServiceGetStorageItemURLProcedure = "/service.v1.StorageService/GetStorageItemURL"
 	// StorageServiceDeleteOldStorageItemsProcedure is the fully-qualified name of the StorageService's
 	// DeleteOldStorageItems RPC.
 	StorageServiceDeleteOldStorageItemsProcedure = "/service.v1.StorageService/DeleteOldStorageItems"
+	// StorageServiceExportClipProcedure is the fully-qualified name of the StorageService's ExportClip
+	// RPC.
+	StorageServiceExportClipProcedure = "/service.v1.StorageService/ExportClip"
+	// StorageServiceReanalyzeFramesProcedure is the fully-qualified name of the StorageService's
+	// ReanalyzeFrames RPC.
+	StorageServiceReanalyzeFramesProcedure = "/service.v1.StorageService/ReanalyzeFrames"
 )
 
 // StorageServiceClient is a client for the service.v1.StorageService service.
@@ -50,8 +59,14 @@ type StorageServiceClient interface {
 	ListStorageItems(context.Context, *connect.Request[v1.ListStorageItemsRequest]) (*connect.Response[v1.ListStorageItemsResponse], error)
 	// Get storage item metadata
 	GetStorageItem(context.Context, *connect.Request[v1.GetStorageItemRequest]) (*connect.Response[v1.GetStorageItemResponse], error)
+	// Get a short-lived signed URL for fetching a storage item over HTTP
+	GetStorageItemURL(context.Context, *connect.Request[v1.GetStorageItemURLRequest]) (*connect.Response[v1.GetStorageItemURLResponse], error)
 	// Delete old storage items (by time)
 	DeleteOldStorageItems(context.Context, *connect.Request[v1.DeleteOldStorageItemsRequest]) (*connect.Response[v1.DeleteOldStorageItemsResponse], error)
+	// Export a clip from recorded segments (by event or by time range)
+	ExportClip(context.Context, *connect.Request[v1.ExportClipRequest]) (*connect.Response[v1.ExportClipResponse], error)
+	// Ask a new question about the stored frames of a time range
+	ReanalyzeFrames(context.Context, *connect.Request[v1.ReanalyzeFramesRequest]) (*connect.Response[v1.ReanalyzeFramesResponse], error)
 }
 
 // NewStorageServiceClient constructs a client for the service.v1.StorageService service. By
@@ -77,12 +92,30 @@ func NewStorageServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(storageServiceMethods.ByName("GetStorageItem")),
 			connect.WithClientOptions(opts...),
 		),
+		getStorageItemURL: connect.NewClient[v1.GetStorageItemURLRequest, v1.GetStorageItemURLResponse](
+			httpClient,
+			baseURL+StorageServiceGetStorageItemURLProcedure,
+			connect.WithSchema(storageServiceMethods.ByName("GetStorageItemURL")),
+			connect.WithClientOptions(opts...),
+		),
 		deleteOldStorageItems: connect.NewClient[v1.DeleteOldStorageItemsRequest, v1.DeleteOldStorageItemsResponse](
 			httpClient,
 			baseURL+StorageServiceDeleteOldStorageItemsProcedure,
 			connect.WithSchema(storageServiceMethods.ByName("DeleteOldStorageItems")),
 			connect.WithClientOptions(opts...),
 		),
+		exportClip: connect.NewClient[v1.ExportClipRequest, v1.ExportClipResponse](
+			httpClient,
+			baseURL+StorageServiceExportClipProcedure,
+			connect.WithSchema(storageServiceMethods.ByName("ExportClip")),
+			connect.WithClientOptions(opts...),
+		),
+		reanalyzeFrames: connect.NewClient[v1.ReanalyzeFramesRequest, v1.ReanalyzeFramesResponse](
+			httpClient,
+			baseURL+StorageServiceReanalyzeFramesProcedure,
+			connect.WithSchema(storageServiceMethods.ByName("ReanalyzeFrames")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -90,7 +123,10 @@ func NewStorageServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 type storageServiceClient struct {
 	listStorageItems      *connect.Client[v1.ListStorageItemsRequest, v1.ListStorageItemsResponse]
 	getStorageItem        *connect.Client[v1.GetStorageItemRequest, v1.GetStorageItemResponse]
+	getStorageItemURL     *connect.Client[v1.GetStorageItemURLRequest, v1.GetStorageItemURLResponse]
 	deleteOldStorageItems *connect.Client[v1.DeleteOldStorageItemsRequest, v1.DeleteOldStorageItemsResponse]
+	exportClip            *connect.Client[v1.ExportClipRequest, v1.ExportClipResponse]
+	reanalyzeFrames       *connect.Client[v1.ReanalyzeFramesRequest, v1.ReanalyzeFramesResponse]
 }
 
 // ListStorageItems calls service.v1.StorageService.ListStorageItems.
@@ -103,19 +139,40 @@ func (c *storageServiceClient) GetStorageItem(ctx context.Context, req *connect.
 	return c.getStorageItem.CallUnary(ctx, req)
 }
 
+// GetStorageItemURL calls service.v1.StorageService.GetStorageItemURL.
+func (c *storageServiceClient) GetStorageItemURL(ctx context.Context, req *connect.Request[v1.GetStorageItemURLRequest]) (*connect.Response[v1.GetStorageItemURLResponse], error) {
+	return c.getStorageItemURL.CallUnary(ctx, req)
+}
+
 // DeleteOldStorageItems calls service.v1.StorageService.DeleteOldStorageItems.
 func (c *storageServiceClient) DeleteOldStorageItems(ctx context.Context, req *connect.Request[v1.DeleteOldStorageItemsRequest]) (*connect.Response[v1.DeleteOldStorageItemsResponse], error) {
 	return c.deleteOldStorageItems.CallUnary(ctx, req)
 }
 
+// ExportClip calls service.v1.StorageService.ExportClip.
+func (c *storageServiceClient) ExportClip(ctx context.Context, req *connect.Request[v1.ExportClipRequest]) (*connect.Response[v1.ExportClipResponse], error) {
+	return c.exportClip.CallUnary(ctx, req)
+}
+
+// ReanalyzeFrames calls service.v1.StorageService.ReanalyzeFrames.
+func (c *storageServiceClient) ReanalyzeFrames(ctx context.Context, req *connect.Request[v1.ReanalyzeFramesRequest]) (*connect.Response[v1.ReanalyzeFramesResponse], error) {
+	return c.reanalyzeFrames.CallUnary(ctx, req)
+}
+
 // StorageServiceHandler is an implementation of the service.v1.StorageService service.
 type StorageServiceHandler interface {
 	// List storage items for a service
 	ListStorageItems(context.Context, *connect.Request[v1.ListStorageItemsRequest]) (*connect.Response[v1.ListStorageItemsResponse], error)
 	// Get storage item metadata
 	GetStorageItem(context.Context, *connect.Request[v1.GetStorageItemRequest]) (*connect.Response[v1.GetStorageItemResponse], error)
+	// Get a short-lived signed URL for fetching a storage item over HTTP
+	GetStorageItemURL(context.Context, *connect.Request[v1.GetStorageItemURLRequest]) (*connect.Response[v1.GetStorageItemURLResponse], error)
 	// Delete old storage items (by time)
 	DeleteOldStorageItems(context.Context, *connect.Request[v1.DeleteOldStorageItemsRequest]) (*connect.Response[v1.DeleteOldStorageItemsResponse], error)
+	// Export a clip from recorded segments (by event or by time range)
+	ExportClip(context.Context, *connect.Request[v1.ExportClipRequest]) (*connect.Response[v1.ExportClipResponse], error)
+	// Ask a new question about the stored frames of a time range
+	ReanalyzeFrames(context.Context, *connect.Request[v1.ReanalyzeFramesRequest]) (*connect.Response[v1.ReanalyzeFramesResponse], error)
 }
 
 // NewStorageServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -137,20 +194,44 @@ func NewStorageServiceHandler(svc StorageServiceHandler, opts ...connect.Handler
 		connect.WithSchema(storageServiceMethods.ByName("GetStorageItem")),
 		connect.WithHandlerOptions(opts...),
 	)
+	storageServiceGetStorageItemURLHandler := connect.NewUnaryHandler(
+		StorageServiceGetStorageItemURLProcedure,
+		svc.GetStorageItemURL,
+		connect.WithSchema(storageServiceMethods.ByName("GetStorageItemURL")),
+		connect.WithHandlerOptions(opts...),
+	)
 	storageServiceDeleteOldStorageItemsHandler := connect.NewUnaryHandler(
 		StorageServiceDeleteOldStorageItemsProcedure,
 		svc.DeleteOldStorageItems,
 		connect.WithSchema(storageServiceMethods.ByName("DeleteOldStorageItems")),
 		connect.WithHandlerOptions(opts...),
 	)
+	storageServiceExportClipHandler := connect.NewUnaryHandler(
+		StorageServiceExportClipProcedure,
+		svc.ExportClip,
+		connect.WithSchema(storageServiceMethods.ByName("ExportClip")),
+		connect.WithHandlerOptions(opts...),
+	)
+	storageServiceReanalyzeFramesHandler := connect.NewUnaryHandler(
+		StorageServiceReanalyzeFramesProcedure,
+		svc.ReanalyzeFrames,
+		connect.WithSchema(storageServiceMethods.ByName("ReanalyzeFrames")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/service.v1.StorageService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case StorageServiceListStorageItemsProcedure:
 			storageServiceListStorageItemsHandler.ServeHTTP(w, r)
 		case StorageServiceGetStorageItemProcedure:
 			storageServiceGetStorageItemHandler.ServeHTTP(w, r)
+		case StorageServiceGetStorageItemURLProcedure:
+			storageServiceGetStorageItemURLHandler.ServeHTTP(w, r)
 		case StorageServiceDeleteOldStorageItemsProcedure:
 			storageServiceDeleteOldStorageItemsHandler.ServeHTTP(w, r)
+		case StorageServiceExportClipProcedure:
+			storageServiceExportClipHandler.ServeHTTP(w, r)
+		case StorageServiceReanalyzeFramesProcedure:
+			storageServiceReanalyzeFramesHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -168,6 +249,18 @@ func (UnimplementedStorageServiceHandler) GetStorageItem(context.Context, *conne
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.StorageService.GetStorageItem is not implemented"))
 }
 
+func (UnimplementedStorageServiceHandler) GetStorageItemURL(context.Context, *connect.Request[v1.GetStorageItemURLRequest]) (*connect.Response[v1.GetStorageItemURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.StorageService.GetStorageItemURL is not implemented"))
+}
+
 func (UnimplementedStorageServiceHandler) DeleteOldStorageItems(context.Context, *connect.Request[v1.DeleteOldStorageItemsRequest]) (*connect.Response[v1.DeleteOldStorageItemsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.StorageService.DeleteOldStorageItems is not implemented"))
 }
+
+func (UnimplementedStorageServiceHandler) ExportClip(context.Context, *connect.Request[v1.ExportClipRequest]) (*connect.Response[v1.ExportClipResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.StorageService.ExportClip is not implemented"))
+}
+
+func (UnimplementedStorageServiceHandler) ReanalyzeFrames(context.Context, *connect.Request[v1.ReanalyzeFramesRequest]) (*connect.Response[v1.ReanalyzeFramesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("service.v1.StorageService.ReanalyzeFrames is not implemented"))
+}
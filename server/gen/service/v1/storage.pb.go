@@ -27,7 +27,7 @@ type StorageItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	ServiceId     string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // Type of storage item (e.g., "frame", "clip")
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // Type of storage item ("frame", "snapshot", "segment" or "clip")
 	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
 	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -106,6 +106,8 @@ type ListStorageItemsRequest struct {
 	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`      // Optional filter by type (empty = all types)
 	Limit         int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`   // Max items to return (default 100)
 	Offset        int64                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"` // For pagination
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`      // Optional: only items at or after this time
+	To            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`          // Optional: only items before this time
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -168,6 +170,20 @@ func (x *ListStorageItemsRequest) GetOffset() int64 {
 	return 0
 }
 
+func (x *ListStorageItemsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListStorageItemsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
 type ListStorageItemsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Items         []*StorageItem         `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -309,6 +325,111 @@ func (x *GetStorageItemResponse) GetItem() *StorageItem {
 	return nil
 }
 
+// Get a signed URL for a storage item
+type GetStorageItemURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // URL lifetime (default 300, max 3600)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorageItemURLRequest) Reset() {
+	*x = GetStorageItemURLRequest{}
+	mi := &file_service_v1_storage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorageItemURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorageItemURLRequest) ProtoMessage() {}
+
+func (x *GetStorageItemURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorageItemURLRequest.ProtoReflect.Descriptor instead.
+func (*GetStorageItemURLRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetStorageItemURLRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *GetStorageItemURLRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type GetStorageItemURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"` // Relative URL: /storage/{id}?expires=...&sig=...
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorageItemURLResponse) Reset() {
+	*x = GetStorageItemURLResponse{}
+	mi := &file_service_v1_storage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorageItemURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorageItemURLResponse) ProtoMessage() {}
+
+func (x *GetStorageItemURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorageItemURLResponse.ProtoReflect.Descriptor instead.
+func (*GetStorageItemURLResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStorageItemURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GetStorageItemURLResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 // Delete old storage items
 type DeleteOldStorageItemsRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
@@ -321,7 +442,7 @@ type DeleteOldStorageItemsRequest struct {
 
 func (x *DeleteOldStorageItemsRequest) Reset() {
 	*x = DeleteOldStorageItemsRequest{}
-	mi := &file_service_v1_storage_proto_msgTypes[5]
+	mi := &file_service_v1_storage_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -333,7 +454,7 @@ func (x *DeleteOldStorageItemsRequest) String() string {
 func (*DeleteOldStorageItemsRequest) ProtoMessage() {}
 
 func (x *DeleteOldStorageItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_storage_proto_msgTypes[5]
+	mi := &file_service_v1_storage_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -346,7 +467,7 @@ func (x *DeleteOldStorageItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOldStorageItemsRequest.ProtoReflect.Descriptor instead.
 func (*DeleteOldStorageItemsRequest) Descriptor() ([]byte, []int) {
-	return file_service_v1_storage_proto_rawDescGZIP(), []int{5}
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteOldStorageItemsRequest) GetServiceId() string {
@@ -379,7 +500,7 @@ type DeleteOldStorageItemsResponse struct {
 
 func (x *DeleteOldStorageItemsResponse) Reset() {
 	*x = DeleteOldStorageItemsResponse{}
-	mi := &file_service_v1_storage_proto_msgTypes[6]
+	mi := &file_service_v1_storage_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -391,7 +512,7 @@ func (x *DeleteOldStorageItemsResponse) String() string {
 func (*DeleteOldStorageItemsResponse) ProtoMessage() {}
 
 func (x *DeleteOldStorageItemsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_v1_storage_proto_msgTypes[6]
+	mi := &file_service_v1_storage_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -404,7 +525,7 @@ func (x *DeleteOldStorageItemsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOldStorageItemsResponse.ProtoReflect.Descriptor instead.
 func (*DeleteOldStorageItemsResponse) Descriptor() ([]byte, []int) {
-	return file_service_v1_storage_proto_rawDescGZIP(), []int{6}
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteOldStorageItemsResponse) GetDeletedCount() int64 {
@@ -414,6 +535,342 @@ func (x *DeleteOldStorageItemsResponse) GetDeletedCount() int64 {
 	return 0
 }
 
+// Export a clip from recorded segments
+// Either event_id, or service_id with from/to, must be set
+type ExportClipRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EventId         string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                                  // Use the event's service and from_iso/to_iso window
+	ServiceId       string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`                            // Service to export from (when no event_id)
+	From            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                                                       // Start of the clip (when no event_id)
+	To              *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                                                           // End of the clip (when no event_id)
+	PreRollSeconds  *int32                 `protobuf:"varint,5,opt,name=pre_roll_seconds,json=preRollSeconds,proto3,oneof" json:"pre_roll_seconds,omitempty"`    // Extra seconds before the start (default from server config)
+	PostRollSeconds *int32                 `protobuf:"varint,6,opt,name=post_roll_seconds,json=postRollSeconds,proto3,oneof" json:"post_roll_seconds,omitempty"` // Extra seconds after the end (default from server config)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ExportClipRequest) Reset() {
+	*x = ExportClipRequest{}
+	mi := &file_service_v1_storage_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClipRequest) ProtoMessage() {}
+
+func (x *ExportClipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClipRequest.ProtoReflect.Descriptor instead.
+func (*ExportClipRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExportClipRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ExportClipRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ExportClipRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ExportClipRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ExportClipRequest) GetPreRollSeconds() int32 {
+	if x != nil && x.PreRollSeconds != nil {
+		return *x.PreRollSeconds
+	}
+	return 0
+}
+
+func (x *ExportClipRequest) GetPostRollSeconds() int32 {
+	if x != nil && x.PostRollSeconds != nil {
+		return *x.PostRollSeconds
+	}
+	return 0
+}
+
+type ExportClipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *StorageItem           `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"` // The new "clip" storage item, served at /storage/{id}
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportClipResponse) Reset() {
+	*x = ExportClipResponse{}
+	mi := &file_service_v1_storage_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportClipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportClipResponse) ProtoMessage() {}
+
+func (x *ExportClipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportClipResponse.ProtoReflect.Descriptor instead.
+func (*ExportClipResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportClipResponse) GetItem() *StorageItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+// Re-analyze stored frames with a new question
+type ReanalyzeFramesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId     string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Question      string                 `protobuf:"bytes,4,opt,name=question,proto3" json:"question,omitempty"`
+	MaxFrames     int32                  `protobuf:"varint,5,opt,name=max_frames,json=maxFrames,proto3" json:"max_frames,omitempty"` // Frames sampled evenly from the range (default 48, max 120)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReanalyzeFramesRequest) Reset() {
+	*x = ReanalyzeFramesRequest{}
+	mi := &file_service_v1_storage_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReanalyzeFramesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReanalyzeFramesRequest) ProtoMessage() {}
+
+func (x *ReanalyzeFramesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReanalyzeFramesRequest.ProtoReflect.Descriptor instead.
+func (*ReanalyzeFramesRequest) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReanalyzeFramesRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *ReanalyzeFramesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReanalyzeFramesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReanalyzeFramesRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *ReanalyzeFramesRequest) GetMaxFrames() int32 {
+	if x != nil {
+		return x.MaxFrames
+	}
+	return 0
+}
+
+// A frame the answer is based on
+type FrameCitation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FrameId       string                 `protobuf:"bytes,1,opt,name=frame_id,json=frameId,proto3" json:"frame_id,omitempty"` // Storage item ID, served at /storage/{id}
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Observation   string                 `protobuf:"bytes,3,opt,name=observation,proto3" json:"observation,omitempty"` // What the frame shows that bears on the question
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FrameCitation) Reset() {
+	*x = FrameCitation{}
+	mi := &file_service_v1_storage_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FrameCitation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrameCitation) ProtoMessage() {}
+
+func (x *FrameCitation) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrameCitation.ProtoReflect.Descriptor instead.
+func (*FrameCitation) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FrameCitation) GetFrameId() string {
+	if x != nil {
+		return x.FrameId
+	}
+	return ""
+}
+
+func (x *FrameCitation) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *FrameCitation) GetObservation() string {
+	if x != nil {
+		return x.Observation
+	}
+	return ""
+}
+
+type ReanalyzeFramesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Answer         string                 `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Citations      []*FrameCitation       `protobuf:"bytes,2,rep,name=citations,proto3" json:"citations,omitempty"` // Oldest first
+	FramesAnalyzed int32                  `protobuf:"varint,3,opt,name=frames_analyzed,json=framesAnalyzed,proto3" json:"frames_analyzed,omitempty"`
+	FramesInRange  int32                  `protobuf:"varint,4,opt,name=frames_in_range,json=framesInRange,proto3" json:"frames_in_range,omitempty"` // Stored frames in the range (more than analyzed when sampled)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReanalyzeFramesResponse) Reset() {
+	*x = ReanalyzeFramesResponse{}
+	mi := &file_service_v1_storage_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReanalyzeFramesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReanalyzeFramesResponse) ProtoMessage() {}
+
+func (x *ReanalyzeFramesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_v1_storage_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReanalyzeFramesResponse.ProtoReflect.Descriptor instead.
+func (*ReanalyzeFramesResponse) Descriptor() ([]byte, []int) {
+	return file_service_v1_storage_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReanalyzeFramesResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *ReanalyzeFramesResponse) GetCitations() []*FrameCitation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *ReanalyzeFramesResponse) GetFramesAnalyzed() int32 {
+	if x != nil {
+		return x.FramesAnalyzed
+	}
+	return 0
+}
+
+func (x *ReanalyzeFramesResponse) GetFramesInRange() int32 {
+	if x != nil {
+		return x.FramesInRange
+	}
+	return 0
+}
+
 var File_service_v1_storage_proto protoreflect.FileDescriptor
 
 const file_service_v1_storage_proto_rawDesc = "" +
@@ -426,31 +883,74 @@ const file_service_v1_storage_proto_rawDesc = "" +
 	"service_id\x18\x02 \x01(\tR\tserviceId\x12\x12\n" +
 	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
 	"\x04size\x18\x04 \x01(\x03R\x04size\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"z\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd6\x01\n" +
 	"\x17ListStorageItemsRequest\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x03R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x04 \x01(\x03R\x06offset\"_\n" +
+	"\x06offset\x18\x04 \x01(\x03R\x06offset\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"_\n" +
 	"\x18ListStorageItemsResponse\x12-\n" +
 	"\x05items\x18\x01 \x03(\v2\x17.service.v1.StorageItemR\x05items\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x03R\x05total\"0\n" +
 	"\x15GetStorageItemRequest\x12\x17\n" +
 	"\aitem_id\x18\x01 \x01(\tR\x06itemId\"E\n" +
 	"\x16GetStorageItemResponse\x12+\n" +
-	"\x04item\x18\x01 \x01(\v2\x17.service.v1.StorageItemR\x04item\"\x7f\n" +
+	"\x04item\x18\x01 \x01(\v2\x17.service.v1.StorageItemR\x04item\"T\n" +
+	"\x18GetStorageItemURLRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"h\n" +
+	"\x19GetStorageItemURLResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x7f\n" +
 	"\x1cDeleteOldStorageItemsRequest\x12\x1d\n" +
 	"\n" +
 	"service_id\x18\x01 \x01(\tR\tserviceId\x12,\n" +
 	"\x12older_than_seconds\x18\x02 \x01(\x03R\x10olderThanSeconds\x12\x12\n" +
 	"\x04type\x18\x03 \x01(\tR\x04type\"D\n" +
 	"\x1dDeleteOldStorageItemsResponse\x12#\n" +
-	"\rdeleted_count\x18\x01 \x01(\x03R\fdeletedCount2\xb6\x02\n" +
+	"\rdeleted_count\x18\x01 \x01(\x03R\fdeletedCount\"\xb4\x02\n" +
+	"\x11ExportClipRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x02 \x01(\tR\tserviceId\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12-\n" +
+	"\x10pre_roll_seconds\x18\x05 \x01(\x05H\x00R\x0epreRollSeconds\x88\x01\x01\x12/\n" +
+	"\x11post_roll_seconds\x18\x06 \x01(\x05H\x01R\x0fpostRollSeconds\x88\x01\x01B\x13\n" +
+	"\x11_pre_roll_secondsB\x14\n" +
+	"\x12_post_roll_seconds\"A\n" +
+	"\x12ExportClipResponse\x12+\n" +
+	"\x04item\x18\x01 \x01(\v2\x17.service.v1.StorageItemR\x04item\"\xce\x01\n" +
+	"\x16ReanalyzeFramesRequest\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1a\n" +
+	"\bquestion\x18\x04 \x01(\tR\bquestion\x12\x1d\n" +
+	"\n" +
+	"max_frames\x18\x05 \x01(\x05R\tmaxFrames\"\x86\x01\n" +
+	"\rFrameCitation\x12\x19\n" +
+	"\bframe_id\x18\x01 \x01(\tR\aframeId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12 \n" +
+	"\vobservation\x18\x03 \x01(\tR\vobservation\"\xbb\x01\n" +
+	"\x17ReanalyzeFramesResponse\x12\x16\n" +
+	"\x06answer\x18\x01 \x01(\tR\x06answer\x127\n" +
+	"\tcitations\x18\x02 \x03(\v2\x19.service.v1.FrameCitationR\tcitations\x12'\n" +
+	"\x0fframes_analyzed\x18\x03 \x01(\x05R\x0eframesAnalyzed\x12&\n" +
+	"\x0fframes_in_range\x18\x04 \x01(\x05R\rframesInRange2\xc1\x04\n" +
 	"\x0eStorageService\x12]\n" +
 	"\x10ListStorageItems\x12#.service.v1.ListStorageItemsRequest\x1a$.service.v1.ListStorageItemsResponse\x12W\n" +
-	"\x0eGetStorageItem\x12!.service.v1.GetStorageItemRequest\x1a\".service.v1.GetStorageItemResponse\x12l\n" +
-	"\x15DeleteOldStorageItems\x12(.service.v1.DeleteOldStorageItemsRequest\x1a).service.v1.DeleteOldStorageItemsResponseB)Z'unblink/server/gen/service/v1;servicev1b\x06proto3"
+	"\x0eGetStorageItem\x12!.service.v1.GetStorageItemRequest\x1a\".service.v1.GetStorageItemResponse\x12`\n" +
+	"\x11GetStorageItemURL\x12$.service.v1.GetStorageItemURLRequest\x1a%.service.v1.GetStorageItemURLResponse\x12l\n" +
+	"\x15DeleteOldStorageItems\x12(.service.v1.DeleteOldStorageItemsRequest\x1a).service.v1.DeleteOldStorageItemsResponse\x12K\n" +
+	"\n" +
+	"ExportClip\x12\x1d.service.v1.ExportClipRequest\x1a\x1e.service.v1.ExportClipResponse\x12Z\n" +
+	"\x0fReanalyzeFrames\x12\".service.v1.ReanalyzeFramesRequest\x1a#.service.v1.ReanalyzeFramesResponseB)Z'unblink/server/gen/service/v1;servicev1b\x06proto3"
 
 var (
 	file_service_v1_storage_proto_rawDescOnce sync.Once
@@ -464,32 +964,55 @@ func file_service_v1_storage_proto_rawDescGZIP() []byte {
 	return file_service_v1_storage_proto_rawDescData
 }
 
-var file_service_v1_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_service_v1_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_service_v1_storage_proto_goTypes = []any{
 	(*StorageItem)(nil),                   // 0: service.v1.StorageItem
 	(*ListStorageItemsRequest)(nil),       // 1: service.v1.ListStorageItemsRequest
 	(*ListStorageItemsResponse)(nil),      // 2: service.v1.ListStorageItemsResponse
 	(*GetStorageItemRequest)(nil),         // 3: service.v1.GetStorageItemRequest
 	(*GetStorageItemResponse)(nil),        // 4: service.v1.GetStorageItemResponse
-	(*DeleteOldStorageItemsRequest)(nil),  // 5: service.v1.DeleteOldStorageItemsRequest
-	(*DeleteOldStorageItemsResponse)(nil), // 6: service.v1.DeleteOldStorageItemsResponse
-	(*timestamppb.Timestamp)(nil),         // 7: google.protobuf.Timestamp
+	(*GetStorageItemURLRequest)(nil),      // 5: service.v1.GetStorageItemURLRequest
+	(*GetStorageItemURLResponse)(nil),     // 6: service.v1.GetStorageItemURLResponse
+	(*DeleteOldStorageItemsRequest)(nil),  // 7: service.v1.DeleteOldStorageItemsRequest
+	(*DeleteOldStorageItemsResponse)(nil), // 8: service.v1.DeleteOldStorageItemsResponse
+	(*ExportClipRequest)(nil),             // 9: service.v1.ExportClipRequest
+	(*ExportClipResponse)(nil),            // 10: service.v1.ExportClipResponse
+	(*ReanalyzeFramesRequest)(nil),        // 11: service.v1.ReanalyzeFramesRequest
+	(*FrameCitation)(nil),                 // 12: service.v1.FrameCitation
+	(*ReanalyzeFramesResponse)(nil),       // 13: service.v1.ReanalyzeFramesResponse
+	(*timestamppb.Timestamp)(nil),         // 14: google.protobuf.Timestamp
 }
 var file_service_v1_storage_proto_depIdxs = []int32{
-	7, // 0: service.v1.StorageItem.timestamp:type_name -> google.protobuf.Timestamp
-	0, // 1: service.v1.ListStorageItemsResponse.items:type_name -> service.v1.StorageItem
-	0, // 2: service.v1.GetStorageItemResponse.item:type_name -> service.v1.StorageItem
-	1, // 3: service.v1.StorageService.ListStorageItems:input_type -> service.v1.ListStorageItemsRequest
-	3, // 4: service.v1.StorageService.GetStorageItem:input_type -> service.v1.GetStorageItemRequest
-	5, // 5: service.v1.StorageService.DeleteOldStorageItems:input_type -> service.v1.DeleteOldStorageItemsRequest
-	2, // 6: service.v1.StorageService.ListStorageItems:output_type -> service.v1.ListStorageItemsResponse
-	4, // 7: service.v1.StorageService.GetStorageItem:output_type -> service.v1.GetStorageItemResponse
-	6, // 8: service.v1.StorageService.DeleteOldStorageItems:output_type -> service.v1.DeleteOldStorageItemsResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	14, // 0: service.v1.StorageItem.timestamp:type_name -> google.protobuf.Timestamp
+	14, // 1: service.v1.ListStorageItemsRequest.from:type_name -> google.protobuf.Timestamp
+	14, // 2: service.v1.ListStorageItemsRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 3: service.v1.ListStorageItemsResponse.items:type_name -> service.v1.StorageItem
+	0,  // 4: service.v1.GetStorageItemResponse.item:type_name -> service.v1.StorageItem
+	14, // 5: service.v1.GetStorageItemURLResponse.expires_at:type_name -> google.protobuf.Timestamp
+	14, // 6: service.v1.ExportClipRequest.from:type_name -> google.protobuf.Timestamp
+	14, // 7: service.v1.ExportClipRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 8: service.v1.ExportClipResponse.item:type_name -> service.v1.StorageItem
+	14, // 9: service.v1.ReanalyzeFramesRequest.from:type_name -> google.protobuf.Timestamp
+	14, // 10: service.v1.ReanalyzeFramesRequest.to:type_name -> google.protobuf.Timestamp
+	14, // 11: service.v1.FrameCitation.timestamp:type_name -> google.protobuf.Timestamp
+	12, // 12: service.v1.ReanalyzeFramesResponse.citations:type_name -> service.v1.FrameCitation
+	1,  // 13: service.v1.StorageService.ListStorageItems:input_type -> service.v1.ListStorageItemsRequest
+	3,  // 14: service.v1.StorageService.GetStorageItem:input_type -> service.v1.GetStorageItemRequest
+	5,  // 15: service.v1.StorageService.GetStorageItemURL:input_type -> service.v1.GetStorageItemURLRequest
+	7,  // 16: service.v1.StorageService.DeleteOldStorageItems:input_type -> service.v1.DeleteOldStorageItemsRequest
+	9,  // 17: service.v1.StorageService.ExportClip:input_type -> service.v1.ExportClipRequest
+	11, // 18: service.v1.StorageService.ReanalyzeFrames:input_type -> service.v1.ReanalyzeFramesRequest
+	2,  // 19: service.v1.StorageService.ListStorageItems:output_type -> service.v1.ListStorageItemsResponse
+	4,  // 20: service.v1.StorageService.GetStorageItem:output_type -> service.v1.GetStorageItemResponse
+	6,  // 21: service.v1.StorageService.GetStorageItemURL:output_type -> service.v1.GetStorageItemURLResponse
+	8,  // 22: service.v1.StorageService.DeleteOldStorageItems:output_type -> service.v1.DeleteOldStorageItemsResponse
+	10, // 23: service.v1.StorageService.ExportClip:output_type -> service.v1.ExportClipResponse
+	13, // 24: service.v1.StorageService.ReanalyzeFrames:output_type -> service.v1.ReanalyzeFramesResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_service_v1_storage_proto_init() }
@@ -497,13 +1020,14 @@ func file_service_v1_storage_proto_init() {
 	if File_service_v1_storage_proto != nil {
 		return
 	}
+	file_service_v1_storage_proto_msgTypes[9].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_v1_storage_proto_rawDesc), len(file_service_v1_storage_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,136 @@
+// Package netguard keeps outgoing requests to user-supplied URLs (webhooks,
+// web pages) from reaching the server's own host and networks.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for connections to non-public addresses
+var ErrForbiddenAddress = errors.New("address is not public")
+
+// dialTimeout bounds connecting, like the default transport
+const dialTimeout = 30 * time.Second
+
+// forbiddenNets are the non-public ranges the net.IP methods do not cover
+var forbiddenNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "This" network
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved, including broadcast
+	mustParseCIDR("64:ff9b::/96"),  // NAT64, may map to private IPv4
+}
+
+// Forbidden reports whether ip is loopback, private, link-local, multicast,
+// unspecified or otherwise not publicly routable
+func Forbidden(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, ipNet := range forbiddenNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Control is a net.Dialer Control function refusing forbidden addresses. It
+// runs after name resolution, for every connection, so redirects and names
+// resolving differently later (DNS rebinding) are checked too.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || Forbidden(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewTransport returns an HTTP transport that only connects to public
+// addresses. The hosts of the allowed URLs (like a local stub server) are
+// exempt, matched by host and port as written in the URL.
+func NewTransport(allowed ...string) *http.Transport {
+	var allowedHosts []string
+	for _, rawURL := range allowed {
+		if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+			allowedHosts = append(allowedHosts, hostPort(u))
+		}
+	}
+
+	guarded := &net.Dialer{Timeout: dialTimeout, KeepAlive: dialTimeout, Control: Control}
+	plain := &net.Dialer{Timeout: dialTimeout, KeepAlive: dialTimeout}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would connect on our behalf, unchecked
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if slices.Contains(allowedHosts, addr) {
+			return plain.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+	return transport
+}
+
+// CheckURL resolves the host of a URL and returns an error wrapping
+// ErrForbiddenAddress if any of its addresses is forbidden. It rejects bad
+// URLs when they are saved; connections are still checked when made.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+
+	if ip := net.ParseIP(host); ip != nil {
+		if Forbidden(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if Forbidden(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// hostPort returns the host:port a transport dials for a URL
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
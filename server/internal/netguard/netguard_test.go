@@ -0,0 +1,109 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForbidden(t *testing.T) {
+	tests := []struct {
+		ip        string
+		forbidden bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.10", true},
+		{"169.254.169.254", true}, // Cloud metadata
+		{"0.0.0.0", true},
+		{"100.64.0.1", true},
+		{"224.0.0.1", true},
+		{"255.255.255.255", true},
+		{"::1", true},
+		{"::", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true}, // IPv4-mapped
+		{"64:ff9b::a00:1", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700:4700::1111", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.forbidden, Forbidden(net.ParseIP(tt.ip)), tt.ip)
+	}
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, Control("tcp", "8.8.8.8:443", nil))
+	assert.ErrorIs(t, Control("tcp", "127.0.0.1:80", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, Control("tcp6", "[::1]:80", nil), ErrForbiddenAddress)
+	assert.Error(t, Control("tcp", "no-port", nil))
+}
+
+func TestCheckURL(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, CheckURL(ctx, "https://8.8.8.8/hook"))
+	assert.ErrorIs(t, CheckURL(ctx, "http://127.0.0.1:8080/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL(ctx, "http://[::1]/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL(ctx, "http://169.254.169.254/latest/meta-data"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL(ctx, "http://localhost:8080/hook"), ErrForbiddenAddress)
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback
+	client := &http.Client{Transport: NewTransport()}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress), "got %v", err)
+
+	// Unless allowed explicitly
+	client = &http.Client{Transport: NewTransport(server.URL + "/base")}
+	resp, err := client.Get(server.URL + "/page")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestTransportChecksRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	// An allowed server redirecting to a forbidden one
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirector.Close()
+
+	client := &http.Client{Transport: NewTransport(redirector.URL)}
+	_, err := client.Get(redirector.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
+
+func TestHostPort(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"http://example.com/x":      "example.com:80",
+		"https://example.com":       "example.com:443",
+		"http://127.0.0.1:8089/api": "127.0.0.1:8089",
+		"http://[::1]:9000":         "[::1]:9000",
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, want, hostPort(u), rawURL)
+	}
+}
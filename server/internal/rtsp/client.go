@@ -20,6 +20,12 @@ import (
 
 var Timeout = time.Second * 5
 
+// Errors for requests the server answers with 401 Unauthorized
+var (
+	ErrNoCredentials    = errors.New("user/pass not provided")
+	ErrWrongCredentials = errors.New("wrong user/pass")
+)
+
 func NewClient(uri string) *Conn {
 	return &Conn{
 		Connection: core.Connection{
@@ -141,13 +147,13 @@ func (c *Conn) Do(req *tcp.Request) (*tcp.Response, error) {
 			if c.auth.ReadNone(res) {
 				return c.Do(req)
 			}
-			return nil, errors.New("user/pass not provided")
+			return nil, ErrNoCredentials
 		case tcp.AuthUnknown:
 			if c.auth.Read(res) {
 				return c.Do(req)
 			}
 		default:
-			return nil, errors.New("wrong user/pass")
+			return nil, ErrWrongCredentials
 		}
 	}
 
@@ -269,7 +275,12 @@ func (c *Conn) SetupMedia(media *core.Media) (byte, error) {
 	var transport string
 
 	if c.Transport == "udp" {
-		conn1, conn2, err := ListenUDPPair()
+		listen := c.ListenPacketPair
+		if listen == nil {
+			listen = listenUDPPair
+		}
+
+		conn1, conn2, err := listen()
 		if err != nil {
 			return 0, err
 		}
@@ -362,7 +373,11 @@ func (c *Conn) SetupMedia(media *core.Media) (byte, error) {
 			port2 := core.Atoi(s2)
 			// TODO: more smart handling empty server ports
 			if port1 > 0 && port2 > 0 {
-				remoteIP := c.conn.RemoteAddr().(*net.TCPAddr).IP
+				// Custom transports have no IP; they send to their own remote host
+				var remoteIP net.IP
+				if addr, ok := c.conn.RemoteAddr().(*net.TCPAddr); ok {
+					remoteIP = addr.IP
+				}
 				c.udpAddr = append(c.udpAddr,
 					&net.UDPAddr{IP: remoteIP, Port: port1},
 					&net.UDPAddr{IP: remoteIP, Port: port2},
@@ -422,7 +437,15 @@ func (c *Conn) Close() error {
 }
 
 func (c *Conn) WriteToUDP(b []byte, channel byte) (int, error) {
-	return c.udpConn[channel].WriteToUDP(b, c.udpAddr[channel])
+	return c.udpConn[channel].WriteTo(b, c.udpAddr[channel])
+}
+
+func listenUDPPair() (net.PacketConn, net.PacketConn, error) {
+	conn1, conn2, err := ListenUDPPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn1, conn2, nil
 }
 
 const listenUDPAttemps = 10
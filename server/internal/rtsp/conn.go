@@ -49,7 +49,11 @@ type Conn struct {
 	state   State
 	stateMu sync.Mutex
 
-	udpConn []*net.UDPConn
+	// ListenPacketPair opens the RTP/RTCP sockets for the "udp" transport.
+	// Defaults to ListenUDPPair; set it to carry UDP over a custom transport, ex. bridges
+	ListenPacketPair func() (net.PacketConn, net.PacketConn, error)
+
+	udpConn []net.PacketConn
 	udpAddr []*net.UDPAddr
 }
 
@@ -174,7 +178,7 @@ func (c *Conn) handleUDPData(channel byte) {
 		// TP-Link Tapo camera has crazy 10000 bytes packet size
 		buf := make([]byte, 10240)
 
-		n, _, err := conn.ReadFromUDP(buf)
+		n, _, err := conn.ReadFrom(buf)
 		if err != nil {
 			return
 		}
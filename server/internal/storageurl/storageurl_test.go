@@ -0,0 +1,72 @@
+package storageurl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signedQuery signs a URL for an item and returns its query parameters
+func signedQuery(t *testing.T, key []byte, itemID string, expiresAt time.Time) url.Values {
+	signed, err := url.Parse(Sign(key, itemID, expiresAt))
+	require.NoError(t, err)
+	assert.Equal(t, "/storage/"+itemID, signed.Path)
+	return signed.Query()
+}
+
+func TestVerify(t *testing.T) {
+	key := []byte("signing-key")
+	valid := signedQuery(t, key, "item1", time.Now().Add(time.Minute))
+
+	with := func(name, value string) url.Values {
+		query := url.Values{}
+		for k, v := range valid {
+			query[k] = v
+		}
+		query.Set(name, value)
+		return query
+	}
+	without := func(name string) url.Values {
+		query := with(name, "")
+		query.Del(name)
+		return query
+	}
+
+	tests := []struct {
+		name   string
+		key    []byte
+		itemID string
+		query  url.Values
+		err    string
+	}{
+		{"valid", key, "item1", valid, ""},
+		{"expired", key, "item1", signedQuery(t, key, "item1", time.Now().Add(-time.Minute)), "signed URL expired"},
+		{"other item", key, "item2", valid, "invalid signature"},
+		{"other key", []byte("other-key"), "item1", valid, "invalid signature"},
+		{"tampered signature", key, "item1", with("sig", strings.Repeat("0", 64)), "invalid signature"},
+		{"extended expiry", key, "item1", with("expires", fmt.Sprint(time.Now().Add(time.Hour).Unix())), "invalid signature"},
+		{"invalid expiry", key, "item1", with("expires", "soon"), "invalid expiry"},
+		{"no signature", key, "item1", without("sig"), "missing signature"},
+		{"no expiry", key, "item1", without("expires"), "missing signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.key, tt.itemID, tt.query)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSignEscapesItemID(t *testing.T) {
+	signed := Sign([]byte("signing-key"), "a/b c", time.Unix(1800000000, 0))
+	assert.True(t, strings.HasPrefix(signed, "/storage/a%2Fb%20c?expires=1800000000&sig="), signed)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // NodeJWTClaims represents JWT claims structure for node tokens
@@ -14,27 +15,32 @@ type NodeJWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateNodeToken creates a new JWT token for a node
-func GenerateNodeToken(nodeID, secret string) (string, error) {
+// GenerateNodeToken creates a new JWT token for a node. Each token has a
+// unique ID (jti) so it can be recorded and revoked; ttl 0 means it never expires.
+func GenerateNodeToken(nodeID, secret string, ttl time.Duration) (string, *NodeJWTClaims, error) {
 	if secret == "" {
-		return "", errors.New("JWT secret not configured")
+		return "", nil, errors.New("JWT secret not configured")
 	}
 
-	// Create claims with no expiration (token is valid indefinitely)
-	claims := NodeJWTClaims{
+	now := time.Now()
+	claims := &NodeJWTClaims{
 		NodeID: nodeID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt: jwt.NewNumericDate(time.Now()),
+			ID:       uuid.New().String(),
+			IssuedAt: jwt.NewNumericDate(now),
 		},
 	}
+	if ttl > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(secret))
 	if err != nil {
-		return "", fmt.Errorf("failed to sign token: %w", err)
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
 // ValidateNodeToken validates a JWT token and returns the node claims
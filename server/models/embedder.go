@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+const (
+	// embedTimeout bounds one embeddings request
+	embedTimeout = 30 * time.Second
+
+	// probeTimeout bounds the dimension probe, which runs at startup
+	probeTimeout = 10 * time.Second
+)
+
+// Embedder turns text into vectors through an OpenAI-compatible /embeddings endpoint
+type Embedder struct {
+	client openai.Client
+	model  string
+}
+
+// NewEmbedder creates an embedder for a model
+func NewEmbedder(cfg ModelConfig) *Embedder {
+	opts := []option.RequestOption{option.WithAPIKey(cfg.APIKey)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, option.WithBaseURL(cfg.BaseURL))
+	}
+
+	return &Embedder{
+		client: openai.NewClient(opts...),
+		model:  cfg.ModelID,
+	}
+}
+
+// Model returns the embedding model ID
+func (e *Embedder) Model() string {
+	return e.model
+}
+
+// Embed returns one vector per text, in order
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, embedTimeout)
+	defer cancel()
+
+	resp, err := e.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model:          openai.EmbeddingModel(e.model),
+		Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || int(d.Index) >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vector := make([]float32, len(d.Embedding))
+		for i, v := range d.Embedding {
+			vector[i] = float32(v)
+		}
+		vectors[d.Index] = vector
+	}
+	return vectors, nil
+}
+
+// EmbedOne returns the vector of a single text
+func (e *Embedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// Dimensions probes the vector size of the model. The probe gives up after
+// probeTimeout, so an unreachable endpoint does not hold up startup.
+func (e *Embedder) Dimensions(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	vector, err := e.EmbedOne(ctx, "dimension probe")
+	if err != nil {
+		return 0, err
+	}
+	if len(vector) == 0 {
+		return 0, fmt.Errorf("model returned an empty embedding")
+	}
+	return len(vector), nil
+}
@@ -23,6 +23,19 @@ func NewTrimmer(maxTokens, safetyMargin int) *Trimmer {
 	}
 }
 
+// TrimNewsContent trims tool output in the news format ("## title",
+// "**Source:** url", articles separated by "---") to at most maxTokens, capped
+// at half of the context left for the conversation. Whole articles are kept
+// first, so trimming drops the last articles before cutting into one.
+func (t *Trimmer) TrimNewsContent(content string, maxTokens int) string {
+	availableTokens := t.maxTokens - (t.maxTokens*t.safetyMargin)/100 - 2000
+	maxChars := max(min(maxTokens, availableTokens/2), 100) * 3
+	if len(content) <= maxChars {
+		return content
+	}
+	return truncateNewsContent(content, maxChars)
+}
+
 // TrimToFit trims messages to fit within the model's context window using greedy tail-to-head algorithm
 func (t *Trimmer) TrimToFit(messages []openai.ChatCompletionMessageParamUnion) ([]openai.ChatCompletionMessageParamUnion, error) {
 	currentTokens := EstimateConversationTokens(messages)
@@ -2,8 +2,10 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,22 +23,59 @@ type Bridge struct {
 	CreatedAt time.Time
 }
 
+// bridgeFlow holds the flow control state of a bridge.
+// It is registered together with the data channel so data arriving right
+// after the open response is already accounted for.
+type bridgeFlow struct {
+	serviceID     string
+	datagram      bool                  // UDP bridge: no flow control or retransmission
+	sendWindow    *shared.FlowWindow    // Credit for server -> node data
+	recvCredit    *shared.CreditTracker // Batches window updates for node -> server data
+	bufferedBytes int64                 // atomic: received but not yet read by the BridgeConn
+
+	// Resume state, guarded by mu
+	mu           sync.Mutex
+	paused       bool // Holding data until the node resumes the session
+	sendSeq      uint64
+	lastReceived uint64
+	retransmit   shared.RetransmitBuffer
+
+	// Delivery state, guarded by mu
+	closed  bool   // The data channel is closed or about to be; nothing more is sent on it
+	pending []byte // Data that did not fit in the data channel, delivered before anything newer
+}
+
 // NodeConn handles a single node connection using CBOR messages
 type NodeConn struct {
 	wsConn       *websocket.Conn
 	transport    MessageTransport
+	transportMu  sync.RWMutex // Guards wsConn and transport, which are swapped on resume
 	server       *Server
 	nodeID       string
 	hostname     string
 	macAddresses []string
+	nodeVersion  string
 	shutdown     chan struct{}
 	closed       bool
 	closeMu      sync.Mutex
 	ready        int32 // atomic: 0 = not ready, 1 = ready
 
+	// Negotiated at registration, fixed afterwards
+	protocolVersion int
+	capabilities    []string
+
+	// Token the node registered with, replaced on rotation (guarded by closeMu)
+	tokenID string
+
+	// Session resume (guarded by closeMu)
+	sessionID   string
+	detached    bool        // WebSocket dropped, waiting for the node to resume
+	resumeTimer *time.Timer // Closes the connection when the grace period ends
+
 	// Bridge management
 	bridges     map[string]*Bridge     // bridgeID -> Bridge
 	bridgeChans map[string]chan []byte // bridgeID -> data channel
+	bridgeFlows map[string]*bridgeFlow // bridgeID -> flow control state
 	bridgeMu    sync.RWMutex
 
 	// Request/response correlation
@@ -55,6 +94,7 @@ func NewNodeConn(wsConn *websocket.Conn, server *Server) *NodeConn {
 		shutdown:        make(chan struct{}),
 		bridges:         make(map[string]*Bridge),
 		bridgeChans:     make(map[string]chan []byte),
+		bridgeFlows:     make(map[string]*bridgeFlow),
 		pendingRequests: make(map[uint64]chan any),
 	}
 }
@@ -63,7 +103,7 @@ func NewNodeConn(wsConn *websocket.Conn, server *Server) *NodeConn {
 func (nc *NodeConn) Run() error {
 	log.Printf("[NodeConn] Starting message loop...")
 
-	go nc.messageLoop()
+	go nc.messageLoop(nc.transport)
 	go nc.monitorConnection()
 
 	return nil
@@ -77,7 +117,7 @@ func (nc *NodeConn) monitorConnection() {
 	}
 }
 
-func (nc *NodeConn) messageLoop() {
+func (nc *NodeConn) messageLoop(transport MessageTransport) {
 	for {
 		select {
 		case <-nc.shutdown:
@@ -85,10 +125,10 @@ func (nc *NodeConn) messageLoop() {
 		default:
 		}
 
-		msg, err := nc.transport.ReadMessage()
+		msg, err := transport.ReadMessage()
 		if err != nil {
 			log.Printf("[NodeConn] Read error: %v", err)
-			nc.Close()
+			nc.handleTransportError(transport)
 			return
 		}
 
@@ -119,6 +159,9 @@ func (nc *NodeConn) handleMessage(msg any) error {
 	case shared.MessageTypeNodeReady:
 		return nc.handleNodeReady(msg.(*shared.NodeReadyMessage))
 
+	case shared.MessageTypeRotateTokenRequest:
+		return nc.handleRotateTokenRequest(msg.(*shared.RotateTokenRequest))
+
 	case shared.MessageTypeOpenBridgeResponse:
 		return nc.handleOpenBridgeResponse(msg.(*shared.OpenBridgeResponse))
 
@@ -128,6 +171,12 @@ func (nc *NodeConn) handleMessage(msg any) error {
 	case shared.MessageTypeBridgeData:
 		return nc.handleBridgeData(msg.(*shared.BridgeDataMessage))
 
+	case shared.MessageTypeBridgeWindowUpdate:
+		return nc.handleBridgeWindowUpdate(msg.(*shared.BridgeWindowUpdateMessage))
+
+	case shared.MessageTypeDiscoverResponse:
+		return nc.handleDiscoverResponse(msg.(*shared.DiscoverResponse))
+
 	default:
 		return fmt.Errorf("unknown message type: %s", msgType)
 	}
@@ -141,8 +190,8 @@ func (nc *NodeConn) handleTokenCheckRequest(req *shared.TokenCheckRequest) error
 	log.Printf("[NodeConn] Token check request: nodeID=%s", req.NodeID)
 
 	// Validate the token
-	claims, err := ValidateNodeToken(req.Token, nc.server.config.JWTSecret)
-	valid := err == nil && claims != nil && claims.NodeID == req.NodeID
+	_, err := nc.server.authenticateNode(req.NodeID, req.Token)
+	valid := err == nil
 
 	if valid {
 		log.Printf("[NodeConn] Token valid for node %s", req.NodeID)
@@ -158,13 +207,13 @@ func (nc *NodeConn) handleTokenCheckRequest(req *shared.TokenCheckRequest) error
 		Valid: valid,
 	}
 
-	return nc.transport.WriteMessage(resp)
+	return nc.writeMessage(resp)
 }
 
 func (nc *NodeConn) handleNewTokenRequest(req *shared.NewTokenRequest) error {
 	log.Printf("[NodeConn] New token request: nodeID=%s, hostname=%q", req.NodeID, req.Hostname)
 
-	token, err := GenerateNodeToken(req.NodeID, nc.server.config.JWTSecret)
+	token, _, err := nc.server.issueNodeToken(req.NodeID, "")
 	if err != nil {
 		log.Printf("[NodeConn] Failed to generate token: %v", err)
 		resp := &shared.NewTokenResponse{
@@ -174,7 +223,7 @@ func (nc *NodeConn) handleNewTokenRequest(req *shared.NewTokenRequest) error {
 				Error: err.Error(),
 			},
 		}
-		return nc.transport.WriteMessage(resp)
+		return nc.writeMessage(resp)
 	}
 
 	log.Printf("[NodeConn] Generated new token for node %s", req.NodeID)
@@ -187,7 +236,50 @@ func (nc *NodeConn) handleNewTokenRequest(req *shared.NewTokenRequest) error {
 		Token: token,
 	}
 
-	return nc.transport.WriteMessage(resp)
+	return nc.writeMessage(resp)
+}
+
+// handleRotateTokenRequest replaces the token of a registered node. The node
+// saves the new token; the one it registered with stops working once the node
+// uses the new one, or after nodeTokenOverlap.
+func (nc *NodeConn) handleRotateTokenRequest(req *shared.RotateTokenRequest) error {
+	if nc.nodeID == "" || !nc.HasCapability(shared.CapabilityTokenRotation) {
+		return fmt.Errorf("unexpected rotate_token_request")
+	}
+
+	nc.closeMu.Lock()
+	oldTokenID := nc.tokenID
+	nc.closeMu.Unlock()
+
+	token, claims, err := nc.server.issueNodeToken(nc.nodeID, oldTokenID)
+	if err != nil {
+		log.Printf("[NodeConn %s] Failed to rotate token: %v", nc.nodeID, err)
+		resp := &shared.RotateTokenResponse{
+			Message: shared.Message{
+				Type:  shared.MessageTypeRotateTokenResponse,
+				ID:    req.ID,
+				Error: err.Error(),
+			},
+		}
+		return nc.writeMessage(resp)
+	}
+
+	nc.closeMu.Lock()
+	nc.tokenID = claims.ID
+	nc.closeMu.Unlock()
+
+	log.Printf("[NodeConn %s] Rotated token", nc.nodeID)
+
+	resp := &shared.RotateTokenResponse{
+		Message: shared.Message{
+			Type: shared.MessageTypeRotateTokenResponse,
+			ID:   req.ID,
+		},
+		Token:    token,
+		RotateAt: nc.server.tokenRotateAt(claims).Unix(),
+	}
+
+	return nc.writeMessage(resp)
 }
 
 // ============================================================
@@ -198,34 +290,81 @@ func (nc *NodeConn) handleRegisterRequest(req *shared.RegisterRequest) error {
 	log.Printf("[NodeConn] Register request: nodeID=%s, hostname=%q", req.NodeID, req.Hostname)
 
 	// Validate the token
-	claims, err := ValidateNodeToken(req.Token, nc.server.config.JWTSecret)
-	if err != nil || claims == nil || claims.NodeID != req.NodeID {
+	claims, err := nc.server.authenticateNode(req.NodeID, req.Token)
+	if err != nil {
 		log.Printf("[NodeConn] Invalid token for registration: %v", err)
+		reason := "invalid token"
+		if errors.Is(err, ErrNodeRevoked) {
+			reason = err.Error()
+		}
 		resp := &shared.RegisterResponse{
 			Message: shared.Message{
 				Type:  shared.MessageTypeRegisterResponse,
 				ID:    req.ID,
-				Error: "invalid token",
+				Error: reason,
 			},
 			Success: false,
+			Error:   reason,
+		}
+		return nc.writeMessage(resp)
+	}
+
+	// Refuse nodes we cannot talk to, telling them which side to upgrade
+	if err := shared.CheckVersion(req.ProtocolVersion, req.MinProtocolVersion, "node"); err != nil {
+		log.Printf("[NodeConn] Refusing node %s: %v", req.NodeID, err)
+		resp := &shared.RegisterResponse{
+			Message: shared.Message{
+				Type:  shared.MessageTypeRegisterResponse,
+				ID:    req.ID,
+				Error: err.Error(),
+			},
+			Success:         false,
+			Error:           err.Error(),
+			ProtocolVersion: shared.ProtocolVersion,
+		}
+		return nc.writeMessage(resp)
+	}
+
+	// Hand this WebSocket over to the previous connection if the node is resuming
+	prev, hasPrev := nc.server.GetNodeConnection(req.NodeID)
+	if req.SessionID != "" {
+		if hasPrev && prev != nc && prev.resume(nc, req, claims) {
+			nc.handOff()
+			return nil
 		}
-		return nc.transport.WriteMessage(resp)
+		log.Printf("[NodeConn] Session %s for node %s cannot be resumed, registering fresh", req.SessionID, req.NodeID)
+	}
+
+	// A fresh registration replaces whatever the previous connection held
+	if hasPrev && prev != nc {
+		prev.Close()
 	}
 
 	// Store node info
 	nc.nodeID = req.NodeID
 	nc.hostname = req.Hostname
 	nc.macAddresses = req.MACAddresses
+	nc.nodeVersion = req.NodeVersion
+	nc.tokenID = claims.ID
+	nc.protocolVersion = shared.EffectiveVersion(req.ProtocolVersion)
+	nc.capabilities = shared.NegotiateCapabilities(shared.SupportedCapabilities, req.Capabilities)
 
 	// Register in server's node map
 	nc.server.RegisterNodeConnection(req.NodeID, nc)
 
-	// Store in node store
-	if err := nc.server.nodeStore.RegisterNode(req.NodeID, req.Hostname, req.MACAddresses); err != nil {
+	// Store in node store (and the persistent inventory)
+	info := &NodeInfo{
+		NodeID:          req.NodeID,
+		Hostname:        req.Hostname,
+		MACAddresses:    req.MACAddresses,
+		NodeVersion:     req.NodeVersion,
+		ProtocolVersion: nc.protocolVersion,
+	}
+	if err := nc.server.nodeStore.RegisterNode(info); err != nil {
 		log.Printf("[NodeConn] Failed to register node: %v", err)
 	}
 
-	log.Printf("[NodeConn] Node %s registered successfully", req.NodeID)
+	log.Printf("[NodeConn] Node %s registered successfully (protocol v%d, capabilities=%v)", req.NodeID, nc.protocolVersion, nc.capabilities)
 
 	dashboardURL := fmt.Sprintf("%s/node/%s", nc.server.config.DashboardURL, req.NodeID)
 	log.Printf("[NodeConn] Dashboard URL: %s", dashboardURL)
@@ -235,11 +374,19 @@ func (nc *NodeConn) handleRegisterRequest(req *shared.RegisterRequest) error {
 			Type: shared.MessageTypeRegisterResponse,
 			ID:   req.ID,
 		},
-		Success:      true,
-		DashboardURL: dashboardURL,
+		Success:         true,
+		DashboardURL:    dashboardURL,
+		ProtocolVersion: shared.ProtocolVersion,
+		Capabilities:    nc.capabilities,
+	}
+	if nc.HasCapability(shared.CapabilityResume) {
+		resp.SessionID = nc.newSession()
+	}
+	if nc.HasCapability(shared.CapabilityTokenRotation) {
+		resp.TokenRotateAt = nc.server.tokenRotateAt(claims).Unix()
 	}
 
-	if err := nc.transport.WriteMessage(resp); err != nil {
+	if err := nc.writeMessage(resp); err != nil {
 		return err
 	}
 
@@ -312,6 +459,7 @@ func (nc *NodeConn) handleCloseBridgeResponse(resp *shared.CloseBridgeResponse)
 func (nc *NodeConn) handleBridgeData(msg *shared.BridgeDataMessage) error {
 	nc.bridgeMu.RLock()
 	dataChan, exists := nc.bridgeChans[msg.BridgeID]
+	flow := nc.bridgeFlows[msg.BridgeID]
 	nc.bridgeMu.RUnlock()
 
 	if !exists {
@@ -319,21 +467,147 @@ func (nc *NodeConn) handleBridgeData(msg *shared.BridgeDataMessage) error {
 		return nil
 	}
 
-	// Non-blocking send to prevent blocking the message loop
+	flow.mu.Lock()
+	defer flow.mu.Unlock()
+
+	if flow.closed {
+		return nil
+	}
+
+	if flow.datagram {
+		// Datagrams are not credited; when the reader falls behind they are dropped like UDP
+		select {
+		case dataChan <- msg.Data:
+			atomic.AddInt64(&flow.bufferedBytes, int64(len(msg.Data)))
+		default:
+		}
+		return nil
+	}
+
+	// Drop data the node replayed after a resume but we already have
+	if msg.Sequence != 0 && msg.Sequence <= flow.lastReceived {
+		return nil
+	}
+	if msg.Sequence != 0 {
+		flow.lastReceived = msg.Sequence
+	}
+
+	// Nodes without flow control don't expect window updates
+	if nc.HasCapability(shared.CapabilityFlowControl) {
+		flow.recvCredit.Received(len(msg.Data))
+	}
+
+	// Never block the message loop. Data that doesn't fit in the channel (many
+	// tiny messages) waits in pending, which the node's window keeps within
+	// DefaultBridgeWindow bytes. Nodes that overrun it get the bridge closed,
+	// since dropping data would corrupt the stream.
+	if len(flow.pending) == 0 {
+		select {
+		case dataChan <- msg.Data:
+			atomic.AddInt64(&flow.bufferedBytes, int64(len(msg.Data)))
+			return nil
+		default:
+		}
+	}
+	if len(flow.pending)+len(msg.Data) > shared.DefaultBridgeWindow {
+		log.Printf("[NodeConn %s] Bridge %s has more than %d unread bytes, closing it", nc.nodeID, msg.BridgeID, shared.DefaultBridgeWindow)
+		flow.closed = true
+		go nc.CloseBridge(context.Background(), msg.BridgeID)
+		return nil
+	}
+	flow.pending = append(flow.pending, msg.Data...)
+	atomic.AddInt64(&flow.bufferedBytes, int64(len(msg.Data)))
+	flushPendingLocked(flow, dataChan)
+
+	return nil
+}
+
+// flushPendingLocked moves pending data into the data channel if there is room.
+// flow.mu must be held.
+func flushPendingLocked(flow *bridgeFlow, dataChan chan []byte) {
+	if flow.closed || len(flow.pending) == 0 {
+		return
+	}
 	select {
-	case dataChan <- msg.Data:
-		// Data sent successfully
-		// if len(dataChan) > 1500 {
-		// 	log.Printf("[NodeConn] Bridge %s channel high utilization: %d/2000", msg.BridgeID, len(dataChan))
-		// }
+	case dataChan <- flow.pending:
+		flow.pending = nil
 	default:
-		// Log suppressed to avoid flooding
-		// log.Printf("[NodeConn] Bridge %s data channel full, dropping data", msg.BridgeID)
 	}
+}
+
+func (nc *NodeConn) handleBridgeWindowUpdate(msg *shared.BridgeWindowUpdateMessage) error {
+	nc.bridgeMu.RLock()
+	flow, exists := nc.bridgeFlows[msg.BridgeID]
+	nc.bridgeMu.RUnlock()
+
+	if !exists {
+		// Bridge already closed, late updates are expected
+		return nil
+	}
+
+	flow.mu.Lock()
+	flow.retransmit.Ack(msg.Ack)
+	flow.mu.Unlock()
 
+	flow.sendWindow.Release(msg.Credit)
 	return nil
 }
 
+// bridgeConsumed is called by BridgeConn as it reads data, and grants the node more credit
+func (nc *NodeConn) bridgeConsumed(bridgeID string, n int) {
+	nc.bridgeMu.RLock()
+	flow, exists := nc.bridgeFlows[bridgeID]
+	dataChan := nc.bridgeChans[bridgeID]
+	nc.bridgeMu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	atomic.AddInt64(&flow.bufferedBytes, -int64(n))
+
+	// Hold the flow lock so a resume snapshot sees either none or all of this update
+	flow.mu.Lock()
+	defer flow.mu.Unlock()
+
+	// The reader made room in the channel
+	flushPendingLocked(flow, dataChan)
+
+	if flow.datagram || !nc.HasCapability(shared.CapabilityFlowControl) {
+		return
+	}
+
+	credit := flow.recvCredit.Consume(n)
+	if credit == 0 {
+		return
+	}
+
+	msg := &shared.BridgeWindowUpdateMessage{
+		Message: shared.Message{
+			Type: shared.MessageTypeBridgeWindowUpdate,
+			ID:   nc.getNextMessageID(),
+		},
+		BridgeID: bridgeID,
+		Credit:   credit,
+		Ack:      flow.lastReceived,
+	}
+	if err := nc.writeMessage(msg); err != nil {
+		log.Printf("[NodeConn %s] Failed to send window update for bridge %s: %v", nc.nodeID, bridgeID, err)
+	}
+}
+
+// removeBridgeFlowLocked unregisters a bridge's flow control state and wakes blocked writers.
+// Caller must hold bridgeMu.
+func (nc *NodeConn) removeBridgeFlowLocked(bridgeID string) {
+	if flow, exists := nc.bridgeFlows[bridgeID]; exists {
+		flow.sendWindow.Close()
+		flow.mu.Lock()
+		flow.closed = true
+		flow.mu.Unlock()
+		delete(nc.bridgeFlows, bridgeID)
+	}
+}
+
 // ============================================================
 // Bridge Operations
 // ============================================================
@@ -347,21 +621,48 @@ func (nc *NodeConn) getNextMessageID() uint64 {
 
 // OpenBridge opens a bridge to a service on the node
 func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string) (string, chan []byte, error) {
+	bridgeID, dataChan, _, err := nc.openBridge(ctx, serviceID, serviceURL, shared.BridgeTransportTCP, 0)
+	return bridgeID, dataChan, err
+}
+
+// OpenPacketBridge opens a datagram bridge to the host of a service. The node
+// binds a UDP socket on localPort (any port when 0) and relays packets between
+// it and the service host.
+func (nc *NodeConn) OpenPacketBridge(ctx context.Context, serviceID, serviceURL string, localPort int) (*BridgePacketConn, error) {
+	if !nc.HasCapability(shared.CapabilityUDPBridges) {
+		return nil, fmt.Errorf("node %s does not support UDP bridges", nc.nodeID)
+	}
+
+	bridgeID, dataChan, resp, err := nc.openBridge(ctx, serviceID, serviceURL, shared.BridgeTransportUDP, localPort)
+	if err != nil {
+		return nil, err
+	}
+	return NewBridgePacketConn(nc, bridgeID, dataChan, resp.LocalPort), nil
+}
+
+func (nc *NodeConn) openBridge(ctx context.Context, serviceID, serviceURL, transport string, localPort int) (string, chan []byte, *shared.OpenBridgeResponse, error) {
 	if !nc.isReady() {
-		return "", nil, fmt.Errorf("node %s not ready", nc.nodeID)
+		return "", nil, nil, fmt.Errorf("node %s not ready", nc.nodeID)
 	}
 
 	// Generate unique bridge ID
 	bridgeID := uuid.New().String()
 
 	// Create buffered data channel
-	// Increased buffer size to handle video bursts and prevent drops
-	// 2000 chunks allows for significant buffering even with small packets
+	// The byte volume is bounded by the flow window; 2000 chunks leaves
+	// room for the node's reads to come in small packets, and anything
+	// beyond that waits in the flow's pending buffer
 	dataChan := make(chan []byte, 2000)
 
-	// Register the data channel
+	// Register the data channel and flow control state
 	nc.bridgeMu.Lock()
 	nc.bridgeChans[bridgeID] = dataChan
+	nc.bridgeFlows[bridgeID] = &bridgeFlow{
+		serviceID:  serviceID,
+		datagram:   transport == shared.BridgeTransportUDP,
+		sendWindow: shared.NewFlowWindow(shared.DefaultBridgeWindow),
+		recvCredit: shared.NewCreditTracker(shared.DefaultBridgeWindow),
+	}
 	nc.bridgeMu.Unlock()
 
 	// Create response channel for correlation
@@ -384,18 +685,23 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 			ServiceURL: serviceURL,
 		},
 	}
+	if transport != shared.BridgeTransportTCP {
+		req.Transport = transport
+		req.LocalPort = localPort
+	}
 
-	if err := nc.transport.WriteMessage(req); err != nil {
+	if err := nc.writeMessage(req); err != nil {
 		nc.pendingRequestsMu.Lock()
 		delete(nc.pendingRequests, msgID)
 		nc.pendingRequestsMu.Unlock()
 
 		nc.bridgeMu.Lock()
 		delete(nc.bridgeChans, bridgeID)
+		nc.removeBridgeFlowLocked(bridgeID)
 		nc.bridgeMu.Unlock()
 		close(dataChan)
 
-		return "", nil, fmt.Errorf("send OpenBridgeRequest: %w", err)
+		return "", nil, nil, fmt.Errorf("send OpenBridgeRequest: %w", err)
 	}
 
 	// Wait for response with timeout
@@ -407,12 +713,13 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 	case resp := <-respChan:
 		openResp, ok := resp.(*shared.OpenBridgeResponse)
 		if !ok {
-			return "", nil, fmt.Errorf("invalid response type: %T", resp)
+			return "", nil, nil, fmt.Errorf("invalid response type: %T", resp)
 		}
 
 		if !openResp.Success {
 			nc.bridgeMu.Lock()
 			delete(nc.bridgeChans, bridgeID)
+			nc.removeBridgeFlowLocked(bridgeID)
 			nc.bridgeMu.Unlock()
 			close(dataChan)
 
@@ -420,7 +727,7 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 			if errMsg == "" {
 				errMsg = "bridge open failed"
 			}
-			return "", nil, fmt.Errorf("%s", errMsg)
+			return "", nil, nil, &shared.BridgeError{Code: openResp.Code, Message: errMsg}
 		}
 
 		// Store bridge info
@@ -432,8 +739,8 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 		}
 		nc.bridgeMu.Unlock()
 
-		log.Printf("[NodeConn %s] Bridge %s opened successfully for service %s", nc.nodeID, bridgeID, serviceID)
-		return bridgeID, dataChan, nil
+		log.Printf("[NodeConn %s] Bridge %s (%s) opened successfully for service %s", nc.nodeID, bridgeID, transport, serviceID)
+		return bridgeID, dataChan, openResp, nil
 
 	case <-timer.C:
 		nc.pendingRequestsMu.Lock()
@@ -442,10 +749,11 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 
 		nc.bridgeMu.Lock()
 		delete(nc.bridgeChans, bridgeID)
+		nc.removeBridgeFlowLocked(bridgeID)
 		nc.bridgeMu.Unlock()
 		close(dataChan)
 
-		return "", nil, fmt.Errorf("timeout waiting for OpenBridge response")
+		return "", nil, nil, fmt.Errorf("timeout waiting for OpenBridge response")
 
 	case <-ctx.Done():
 		nc.pendingRequestsMu.Lock()
@@ -454,10 +762,11 @@ func (nc *NodeConn) OpenBridge(ctx context.Context, serviceID, serviceURL string
 
 		nc.bridgeMu.Lock()
 		delete(nc.bridgeChans, bridgeID)
+		nc.removeBridgeFlowLocked(bridgeID)
 		nc.bridgeMu.Unlock()
 		close(dataChan)
 
-		return "", nil, ctx.Err()
+		return "", nil, nil, ctx.Err()
 	}
 }
 
@@ -470,6 +779,7 @@ func (nc *NodeConn) CloseBridge(ctx context.Context, bridgeID string) error {
 	if exists {
 		delete(nc.bridgeChans, bridgeID)
 	}
+	nc.removeBridgeFlowLocked(bridgeID)
 	nc.bridgeMu.Unlock()
 
 	if exists {
@@ -492,7 +802,7 @@ func (nc *NodeConn) CloseBridge(ctx context.Context, bridgeID string) error {
 		BridgeID: bridgeID,
 	}
 
-	if err := nc.transport.WriteMessage(req); err != nil {
+	if err := nc.writeMessage(req); err != nil {
 		nc.pendingRequestsMu.Lock()
 		delete(nc.pendingRequests, msgID)
 		nc.pendingRequestsMu.Unlock()
@@ -536,20 +846,216 @@ func (nc *NodeConn) CloseBridge(ctx context.Context, bridgeID string) error {
 	}
 }
 
-// SendData sends data through a bridge
-func (nc *NodeConn) SendData(bridgeID string, data []byte) error {
+// ============================================================
+// Discovery
+// ============================================================
+
+func (nc *NodeConn) handleDiscoverResponse(resp *shared.DiscoverResponse) error {
+	log.Printf("[NodeConn] Discover response: success=%v, devices=%d, msgID=%d", resp.Success, len(resp.Devices), resp.ID)
+
+	nc.pendingRequestsMu.Lock()
+	respChan, exists := nc.pendingRequests[resp.ID]
+	if exists {
+		delete(nc.pendingRequests, resp.ID)
+	}
+	nc.pendingRequestsMu.Unlock()
+
+	if exists {
+		select {
+		case respChan <- resp:
+		default:
+			log.Printf("[NodeConn] Failed to send DiscoverResponse to waiting channel")
+		}
+	}
+
+	return nil
+}
+
+// Discover asks the node to look for cameras on its LAN. The node listens for
+// replies for req.TimeoutMs, then queries each ONVIF device it found.
+func (nc *NodeConn) Discover(ctx context.Context, req *shared.DiscoverRequest) ([]shared.DiscoveredDevice, error) {
+	if !nc.isReady() {
+		return nil, fmt.Errorf("node %s not ready", nc.nodeID)
+	}
+	if !nc.HasCapability(shared.CapabilityDiscovery) {
+		return nil, fmt.Errorf("node %s does not support discovery", nc.nodeID)
+	}
+
 	msgID := nc.getNextMessageID()
+	respChan := make(chan any, 1)
+
+	nc.pendingRequestsMu.Lock()
+	nc.pendingRequests[msgID] = respChan
+	nc.pendingRequestsMu.Unlock()
 
-	msg := &shared.BridgeDataMessage{
+	req.Message = shared.Message{
+		Type: shared.MessageTypeDiscoverRequest,
+		ID:   msgID,
+	}
+
+	if err := nc.writeMessage(req); err != nil {
+		nc.pendingRequestsMu.Lock()
+		delete(nc.pendingRequests, msgID)
+		nc.pendingRequestsMu.Unlock()
+		return nil, fmt.Errorf("send DiscoverRequest: %w", err)
+	}
+
+	// Listening for replies, then up to 20s of ONVIF queries on the node
+	timeout := time.Duration(req.TimeoutMs)*time.Millisecond + 30*time.Second
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-respChan:
+		discoverResp, ok := resp.(*shared.DiscoverResponse)
+		if !ok {
+			return nil, fmt.Errorf("invalid response type: %T", resp)
+		}
+
+		if !discoverResp.Success {
+			errMsg := discoverResp.Error
+			if errMsg == "" {
+				errMsg = "discovery failed"
+			}
+			return nil, &shared.BridgeError{Code: discoverResp.Code, Message: errMsg}
+		}
+
+		return discoverResp.Devices, nil
+
+	case <-timer.C:
+		nc.pendingRequestsMu.Lock()
+		delete(nc.pendingRequests, msgID)
+		nc.pendingRequestsMu.Unlock()
+		return nil, fmt.Errorf("timeout waiting for Discover response")
+
+	case <-ctx.Done():
+		nc.pendingRequestsMu.Lock()
+		delete(nc.pendingRequests, msgID)
+		nc.pendingRequestsMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// SendData sends data through a bridge, waiting for window credit from the node.
+// A zero deadline waits until the bridge closes.
+func (nc *NodeConn) SendData(bridgeID string, data []byte, deadline time.Time) error {
+	nc.bridgeMu.RLock()
+	flow, exists := nc.bridgeFlows[bridgeID]
+	nc.bridgeMu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("bridge not found: %s", bridgeID)
+	}
+
+	// Nodes without flow control take everything at once
+	if !nc.HasCapability(shared.CapabilityFlowControl) {
+		nc.sendFlowData(bridgeID, flow, data)
+		return nil
+	}
+
+	for len(data) > 0 {
+		n, err := flow.sendWindow.Acquire(len(data), nc.shutdown, deadline)
+		if err != nil {
+			return err
+		}
+
+		nc.sendFlowData(bridgeID, flow, data[:n])
+		data = data[n:]
+	}
+
+	return nil
+}
+
+// sendFlowData sequences data and keeps it for retransmission. It is sent right
+// away unless the node is disconnected; then it waits for the session to resume.
+func (nc *NodeConn) sendFlowData(bridgeID string, flow *bridgeFlow, data []byte) {
+	flow.mu.Lock()
+	defer flow.mu.Unlock()
+
+	flow.sendSeq++
+	if nc.HasCapability(shared.CapabilityResume) {
+		flow.retransmit.Add(flow.sendSeq, data)
+	}
+
+	if flow.paused {
+		return
+	}
+
+	if err := nc.writeMessage(newBridgeData(nc.getNextMessageID(), bridgeID, flow.sendSeq, data)); err != nil {
+		log.Printf("[NodeConn %s] Failed to send data for bridge %s, holding it for resume: %v", nc.nodeID, bridgeID, err)
+		flow.paused = true
+	}
+}
+
+// SendDatagram sends one datagram through a UDP bridge to a port on the service host.
+// It never waits for credit; datagrams are dropped while the node is disconnected.
+func (nc *NodeConn) SendDatagram(bridgeID string, port int, data []byte) error {
+	nc.bridgeMu.RLock()
+	flow, exists := nc.bridgeFlows[bridgeID]
+	nc.bridgeMu.RUnlock()
+
+	if !exists || !flow.datagram {
+		return fmt.Errorf("UDP bridge not found: %s", bridgeID)
+	}
+
+	flow.mu.Lock()
+	defer flow.mu.Unlock()
+
+	if flow.paused {
+		return nil
+	}
+
+	flow.sendSeq++
+	msg := newBridgeData(nc.getNextMessageID(), bridgeID, flow.sendSeq, data)
+	msg.Port = port
+	return nc.writeMessage(msg)
+}
+
+func newBridgeData(msgID uint64, bridgeID string, seq uint64, data []byte) *shared.BridgeDataMessage {
+	return &shared.BridgeDataMessage{
 		Message: shared.Message{
 			Type: shared.MessageTypeBridgeData,
 			ID:   msgID,
 		},
 		BridgeID: bridgeID,
+		Sequence: seq,
 		Data:     data,
 	}
+}
+
+// BridgeStats returns flow control diagnostics for all open bridges
+func (nc *NodeConn) BridgeStats() []shared.BridgeStats {
+	nc.bridgeMu.RLock()
+	defer nc.bridgeMu.RUnlock()
+
+	stats := make([]shared.BridgeStats, 0, len(nc.bridgeFlows))
+	for bridgeID, flow := range nc.bridgeFlows {
+		stats = append(stats, flow.stats(bridgeID))
+	}
+	return stats
+}
+
+// BridgeStat returns flow control diagnostics for one bridge, or false if it is not open
+func (nc *NodeConn) BridgeStat(bridgeID string) (shared.BridgeStats, bool) {
+	nc.bridgeMu.RLock()
+	defer nc.bridgeMu.RUnlock()
+
+	flow, exists := nc.bridgeFlows[bridgeID]
+	if !exists {
+		return shared.BridgeStats{}, false
+	}
+	return flow.stats(bridgeID), true
+}
 
-	return nc.transport.WriteMessage(msg)
+func (f *bridgeFlow) stats(bridgeID string) shared.BridgeStats {
+	credit := f.sendWindow.Credit()
+	return shared.BridgeStats{
+		BridgeID:      bridgeID,
+		ServiceID:     f.serviceID,
+		BufferedBytes: atomic.LoadInt64(&f.bufferedBytes),
+		InFlightBytes: shared.DefaultBridgeWindow - credit,
+		SendCredit:    credit,
+	}
 }
 
 // ============================================================
@@ -569,8 +1075,15 @@ func (nc *NodeConn) Close() {
 
 	close(nc.shutdown)
 
+	if nc.resumeTimer != nil {
+		nc.resumeTimer.Stop()
+	}
+
 	// Close all bridge data channels
 	nc.bridgeMu.Lock()
+	for bridgeID := range nc.bridgeFlows {
+		nc.removeBridgeFlowLocked(bridgeID)
+	}
 	for bridgeID, dataChan := range nc.bridgeChans {
 		close(dataChan)
 		log.Printf("[NodeConn %s] Closed bridge channel: %s", nc.nodeID, bridgeID)
@@ -589,12 +1102,34 @@ func (nc *NodeConn) Close() {
 	nc.pendingRequestsMu.Unlock()
 
 	if nc.server != nil && nc.nodeID != "" {
-		nc.server.UnregisterNodeConnection(nc.nodeID)
+		nc.server.UnregisterNodeConnection(nc.nodeID, nc)
 		nc.server.nodeStore.RemoveNode(nc.nodeID)
 		// Notify server that node is offline
 		nc.server.notifyNodeOffline(nc.nodeID)
 	}
 
+	nc.closeTransport()
+
+	log.Printf("[NodeConn %s] Connection closed", nc.nodeID)
+}
+
+// writeMessage writes to the current WebSocket
+func (nc *NodeConn) writeMessage(msg any) error {
+	nc.transportMu.RLock()
+	transport := nc.transport
+	nc.transportMu.RUnlock()
+
+	if transport == nil {
+		return fmt.Errorf("node %s disconnected", nc.nodeID)
+	}
+	return transport.WriteMessage(msg)
+}
+
+// closeTransport closes the current WebSocket
+func (nc *NodeConn) closeTransport() {
+	nc.transportMu.Lock()
+	defer nc.transportMu.Unlock()
+
 	if nc.transport != nil {
 		nc.transport.Close()
 	}
@@ -602,14 +1137,229 @@ func (nc *NodeConn) Close() {
 	if nc.wsConn != nil {
 		nc.wsConn.Close()
 	}
+}
 
-	log.Printf("[NodeConn %s] Connection closed", nc.nodeID)
+// ============================================================
+// Session Resume
+// ============================================================
+
+// newSession assigns the session ID a node presents when it reconnects
+func (nc *NodeConn) newSession() string {
+	nc.closeMu.Lock()
+	defer nc.closeMu.Unlock()
+
+	nc.sessionID = uuid.New().String()
+	return nc.sessionID
+}
+
+// handleTransportError keeps a registered node's bridges for shared.ResumeGracePeriod
+// after its WebSocket drops, so a quick reconnect can resume them.
+// Connections without a session close right away.
+func (nc *NodeConn) handleTransportError(transport MessageTransport) {
+	nc.closeMu.Lock()
+
+	nc.transportMu.RLock()
+	current := nc.transport
+	nc.transportMu.RUnlock()
+
+	if current != transport {
+		// Superseded by a resumed WebSocket
+		nc.closeMu.Unlock()
+		return
+	}
+
+	if nc.closed || nc.sessionID == "" || !nc.isReady() {
+		nc.closeMu.Unlock()
+		nc.Close()
+		return
+	}
+	nc.detachLocked()
+	nc.resumeTimer = time.AfterFunc(shared.ResumeGracePeriod, func() {
+		log.Printf("[NodeConn %s] Node did not resume within %v", nc.nodeID, shared.ResumeGracePeriod)
+		nc.Close()
+	})
+	nc.closeMu.Unlock()
+
+	log.Printf("[NodeConn %s] WebSocket dropped, holding bridges for %v", nc.nodeID, shared.ResumeGracePeriod)
+}
+
+// detachLocked pauses all bridges and drops the current WebSocket (caller holds closeMu)
+func (nc *NodeConn) detachLocked() {
+	if nc.detached {
+		return
+	}
+	nc.detached = true
+
+	nc.bridgeMu.RLock()
+	for _, flow := range nc.bridgeFlows {
+		flow.mu.Lock()
+		flow.paused = true
+		flow.mu.Unlock()
+	}
+	nc.bridgeMu.RUnlock()
+
+	nc.closeTransport()
+}
+
+// resume moves the WebSocket of a reconnecting node (held by next) onto this
+// connection, replays unacknowledged data and keeps the bridges both sides still have.
+// It returns false if the session is gone and the node has to register fresh.
+func (nc *NodeConn) resume(next *NodeConn, req *shared.RegisterRequest, claims *NodeJWTClaims) bool {
+	nc.closeMu.Lock()
+	defer nc.closeMu.Unlock()
+
+	if nc.closed || nc.sessionID != req.SessionID {
+		return false
+	}
+
+	// The node may notice a dead WebSocket before we do
+	nc.detachLocked()
+	if nc.resumeTimer != nil {
+		nc.resumeTimer.Stop()
+		nc.resumeTimer = nil
+	}
+
+	peer := make(map[string]shared.BridgeResumeState, len(req.Bridges))
+	for _, state := range req.Bridges {
+		peer[state.BridgeID] = state
+	}
+
+	// Lock every kept flow so the snapshot, the response and the replay are
+	// ordered before any new data or window updates on the new WebSocket
+	nc.bridgeMu.Lock()
+	var dropped []string
+	kept := make(map[string]*bridgeFlow)
+	states := make([]shared.BridgeResumeState, 0, len(nc.bridgeFlows))
+	for bridgeID, flow := range nc.bridgeFlows {
+		if _, ok := peer[bridgeID]; !ok {
+			dropped = append(dropped, bridgeID)
+			continue
+		}
+		flow.mu.Lock()
+		kept[bridgeID] = flow
+		states = append(states, shared.BridgeResumeState{
+			BridgeID:     bridgeID,
+			LastReceived: flow.lastReceived,
+			Outstanding:  flow.recvCredit.Outstanding(),
+		})
+	}
+	for _, bridgeID := range dropped {
+		delete(nc.bridges, bridgeID)
+		nc.removeBridgeFlowLocked(bridgeID)
+		if dataChan, ok := nc.bridgeChans[bridgeID]; ok {
+			close(dataChan)
+			delete(nc.bridgeChans, bridgeID)
+		}
+	}
+	nc.bridgeMu.Unlock()
+
+	defer func() {
+		for _, flow := range kept {
+			flow.mu.Unlock()
+		}
+	}()
+
+	resp := &shared.RegisterResponse{
+		Message: shared.Message{
+			Type: shared.MessageTypeRegisterResponse,
+			ID:   req.ID,
+		},
+		Success:         true,
+		DashboardURL:    fmt.Sprintf("%s/node/%s", nc.server.config.DashboardURL, nc.nodeID),
+		ProtocolVersion: shared.ProtocolVersion,
+		Capabilities:    nc.capabilities,
+		SessionID:       nc.sessionID,
+		Resumed:         true,
+		Bridges:         states,
+	}
+	if nc.HasCapability(shared.CapabilityTokenRotation) {
+		resp.TokenRotateAt = nc.server.tokenRotateAt(claims).Unix()
+	}
+	if err := next.transport.WriteMessage(resp); err != nil {
+		log.Printf("[NodeConn %s] Failed to send resume response: %v", nc.nodeID, err)
+		return false
+	}
+
+	nc.transportMu.Lock()
+	nc.wsConn = next.wsConn
+	nc.transport = next.transport
+	nc.transportMu.Unlock()
+	nc.detached = false
+	nc.tokenID = claims.ID
+
+	// Replay what the node has not seen
+	replayed := 0
+	for bridgeID, flow := range kept {
+		state := peer[bridgeID]
+		flow.retransmit.Ack(state.LastReceived)
+		// Window updates may have been lost with the old WebSocket, so start over
+		flow.sendWindow.Reset(shared.DefaultBridgeWindow - state.Outstanding - flow.retransmit.Bytes())
+		for _, entry := range flow.retransmit.Entries() {
+			if err := nc.writeMessage(newBridgeData(nc.getNextMessageID(), bridgeID, entry.Sequence, entry.Data)); err != nil {
+				log.Printf("[NodeConn %s] Failed to replay data for bridge %s: %v", nc.nodeID, bridgeID, err)
+				break
+			}
+			replayed++
+		}
+		flow.paused = false
+	}
+
+	go nc.messageLoop(next.transport)
+
+	log.Printf("[NodeConn %s] Resumed session %s: %d bridges kept, %d dropped, %d messages replayed",
+		nc.nodeID, nc.sessionID, len(kept), len(dropped), replayed)
+	return true
+}
+
+// handOff stops this connection after its WebSocket was moved to a resumed
+// connection, without closing the WebSocket
+func (nc *NodeConn) handOff() {
+	nc.closeMu.Lock()
+	defer nc.closeMu.Unlock()
+
+	if nc.closed {
+		return
+	}
+	nc.closed = true
+	close(nc.shutdown)
 }
 
 func (nc *NodeConn) NodeID() string {
 	return nc.nodeID
 }
 
+// Hostname returns the hostname the node registered with
+func (nc *NodeConn) Hostname() string {
+	return nc.hostname
+}
+
+// NodeVersion returns the software version the node registered with ("" for old nodes)
+func (nc *NodeConn) NodeVersion() string {
+	return nc.nodeVersion
+}
+
+// BridgeCount returns the number of open bridges
+func (nc *NodeConn) BridgeCount() int {
+	nc.bridgeMu.RLock()
+	defer nc.bridgeMu.RUnlock()
+	return len(nc.bridges)
+}
+
+// ProtocolVersion returns the protocol version the node registered with
+func (nc *NodeConn) ProtocolVersion() int {
+	return nc.protocolVersion
+}
+
+// Capabilities returns the capabilities negotiated with the node
+func (nc *NodeConn) Capabilities() []string {
+	return nc.capabilities
+}
+
+// HasCapability reports whether a protocol feature may be used with this node
+func (nc *NodeConn) HasCapability(capability string) bool {
+	return slices.Contains(nc.capabilities, capability)
+}
+
 // IsReady returns true if the node is ready for bridges
 func (nc *NodeConn) IsReady() bool {
 	return atomic.LoadInt32(&nc.ready) == 1
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"unblink/shared"
+)
+
+// fakeMessageTransport records the messages written to the node
+type fakeMessageTransport struct {
+	mu      sync.Mutex
+	written []any
+}
+
+func (t *fakeMessageTransport) ReadMessage() (any, error) { return nil, io.EOF }
+func (t *fakeMessageTransport) Close() error              { return nil }
+
+func (t *fakeMessageTransport) WriteMessage(msg any) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.written = append(t.written, msg)
+	return nil
+}
+
+// closeRequests returns the bridges the server asked the node to close
+func (t *fakeMessageTransport) closeRequests() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []string
+	for _, msg := range t.written {
+		if req, ok := msg.(*shared.CloseBridgeRequest); ok {
+			result = append(result, req.BridgeID)
+		}
+	}
+	return result
+}
+
+// windowUpdates returns the window updates sent to the node
+func (t *fakeMessageTransport) windowUpdates() []*shared.BridgeWindowUpdateMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []*shared.BridgeWindowUpdateMessage
+	for _, msg := range t.written {
+		if update, ok := msg.(*shared.BridgeWindowUpdateMessage); ok {
+			result = append(result, update)
+		}
+	}
+	return result
+}
+
+// newTestNodeConn creates a registered node connection with the capabilities
+// and a TCP bridge whose data channel holds chanSize chunks
+func newTestNodeConn(chanSize int, capabilities ...string) (*NodeConn, *fakeMessageTransport, *BridgeConn) {
+	transport := &fakeMessageTransport{}
+	nc := &NodeConn{
+		transport:       transport,
+		nodeID:          "node1",
+		shutdown:        make(chan struct{}),
+		capabilities:    capabilities,
+		bridges:         make(map[string]*Bridge),
+		bridgeChans:     make(map[string]chan []byte),
+		bridgeFlows:     make(map[string]*bridgeFlow),
+		pendingRequests: make(map[uint64]chan any),
+	}
+
+	dataChan := make(chan []byte, chanSize)
+	nc.bridges["b1"] = &Bridge{BridgeID: "b1", ServiceID: "cam1", CreatedAt: time.Now()}
+	nc.bridgeChans["b1"] = dataChan
+	nc.bridgeFlows["b1"] = &bridgeFlow{
+		serviceID:  "cam1",
+		sendWindow: shared.NewFlowWindow(shared.DefaultBridgeWindow),
+		recvCredit: shared.NewCreditTracker(shared.DefaultBridgeWindow),
+	}
+	return nc, transport, NewBridgeConn(nc, "b1", dataChan)
+}
+
+func TestBridgeDataKeepsOverflow(t *testing.T) {
+	nc, _, conn := newTestNodeConn(2, shared.CapabilityFlowControl)
+
+	// More messages than the channel holds
+	var want []byte
+	for i := range 10 {
+		data := []byte{byte('a' + i)}
+		want = append(want, data...)
+		require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", uint64(i+1), data)))
+	}
+	assert.Equal(t, int64(10), nc.BridgeStats()[0].BufferedBytes)
+
+	got := make([]byte, len(want))
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err := io.ReadFull(conn, got)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Zero(t, nc.BridgeStats()[0].BufferedBytes)
+
+	// Data arriving after the overflow was read goes straight to the channel
+	require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", 11, []byte("k"))))
+	buf := make([]byte, 8)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "k", string(buf[:n]))
+}
+
+func TestBridgeDataOverrunClosesBridge(t *testing.T) {
+	nc, transport, conn := newTestNodeConn(1, shared.CapabilityFlowControl)
+
+	// The node sends more than its window while the reader is stalled
+	require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", 1, []byte("first"))))
+	require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", 2, bytes.Repeat([]byte("x"), shared.DefaultBridgeWindow+1))))
+
+	require.Eventually(t, func() bool {
+		return len(transport.closeRequests()) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"b1"}, transport.closeRequests())
+
+	// The reader gets what was delivered, then the end of the stream
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+}
+
+func TestBridgeDataWithoutFlowControl(t *testing.T) {
+	nc, transport, conn := newTestNodeConn(4)
+
+	chunk := bytes.Repeat([]byte("x"), shared.DefaultBridgeWindow/2)
+	buf := make([]byte, len(chunk))
+	for i := range 4 {
+		require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", 0, chunk)), "chunk %d", i)
+		_, err := io.ReadFull(conn, buf)
+		require.NoError(t, err)
+	}
+
+	// No credit is tracked or granted for nodes that don't use it
+	assert.Zero(t, nc.bridgeFlows["b1"].recvCredit.Outstanding())
+	assert.Empty(t, transport.windowUpdates())
+}
+
+func TestBridgeDataGrantsCredit(t *testing.T) {
+	nc, transport, conn := newTestNodeConn(4, shared.CapabilityFlowControl)
+
+	chunk := bytes.Repeat([]byte("x"), shared.DefaultBridgeWindow/2)
+	require.NoError(t, nc.handleBridgeData(newBridgeData(0, "b1", 1, chunk)))
+	assert.Equal(t, int64(len(chunk)), nc.bridgeFlows["b1"].recvCredit.Outstanding())
+
+	_, err := io.ReadFull(conn, make([]byte, len(chunk)))
+	require.NoError(t, err)
+
+	updates := transport.windowUpdates()
+	require.NotEmpty(t, updates)
+	var credit int64
+	for _, update := range updates {
+		credit += update.Credit
+	}
+	assert.Equal(t, int64(len(chunk)), credit)
+	assert.Equal(t, uint64(1), updates[len(updates)-1].Ack)
+	assert.Zero(t, nc.bridgeFlows["b1"].recvCredit.Outstanding())
+}
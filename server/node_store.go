@@ -2,23 +2,35 @@ package server
 
 import (
 	"fmt"
+	"log"
 	"sync"
 	"time"
 )
 
 // NodeInfo holds information about a connected node
 type NodeInfo struct {
-	NodeID       string
-	Hostname     string
-	MACAddresses []string
-	ConnectedAt  time.Time
-	LastSeen     time.Time
+	NodeID          string
+	Hostname        string
+	MACAddresses    []string
+	NodeVersion     string
+	ProtocolVersion int
+	ConnectedAt     time.Time
+	LastSeen        time.Time
 }
 
-// NodeStore provides in-memory node storage
+// NodeRepository persists the node inventory so it survives relay restarts
+// (implemented by database.Client)
+type NodeRepository interface {
+	UpsertNode(id, hostname string, macAddresses []string, nodeVersion string, protocolVersion int) error
+	TouchNode(id string) error
+}
+
+// NodeStore provides in-memory storage of connected nodes, written through
+// to a NodeRepository when one is set
 type NodeStore struct {
 	nodes map[string]*NodeInfo
 	mu    sync.RWMutex
+	repo  NodeRepository
 }
 
 // NewNodeStore creates a new node store
@@ -37,31 +49,41 @@ func (ns *NodeStore) ValidateToken(jwtSecret, tokenString string) (string, error
 	return claims.NodeID, nil
 }
 
-// RegisterNode stores or updates node information
-func (ns *NodeStore) RegisterNode(nodeID, hostname string, macs []string) error {
+// SetRepository sets where node registrations are persisted
+func (ns *NodeStore) SetRepository(repo NodeRepository) {
 	ns.mu.Lock()
 	defer ns.mu.Unlock()
+	ns.repo = repo
+}
 
+// RegisterNode stores or updates node information
+func (ns *NodeStore) RegisterNode(info *NodeInfo) error {
+	ns.mu.Lock()
 	now := time.Now()
 
-	// Check if node already exists
-	if existing, ok := ns.nodes[nodeID]; ok {
+	if existing, ok := ns.nodes[info.NodeID]; ok {
 		// Update existing node
-		existing.Hostname = hostname
-		existing.MACAddresses = macs
+		existing.Hostname = info.Hostname
+		existing.MACAddresses = info.MACAddresses
+		existing.NodeVersion = info.NodeVersion
+		existing.ProtocolVersion = info.ProtocolVersion
 		existing.LastSeen = now
-		return nil
+	} else {
+		// Create new node entry
+		node := *info
+		node.ConnectedAt = now
+		node.LastSeen = now
+		ns.nodes[info.NodeID] = &node
 	}
+	repo := ns.repo
+	ns.mu.Unlock()
 
-	// Create new node entry
-	ns.nodes[nodeID] = &NodeInfo{
-		NodeID:       nodeID,
-		Hostname:     hostname,
-		MACAddresses: macs,
-		ConnectedAt:  now,
-		LastSeen:     now,
+	if repo == nil {
+		return nil
+	}
+	if err := repo.UpsertNode(info.NodeID, info.Hostname, info.MACAddresses, info.NodeVersion, info.ProtocolVersion); err != nil {
+		return fmt.Errorf("persist node: %w", err)
 	}
-
 	return nil
 }
 
@@ -94,11 +116,19 @@ func (ns *NodeStore) UpdateLastSeen(nodeID string) error {
 	return nil
 }
 
-// RemoveNode removes a node from the store
+// RemoveNode removes a node from the store, recording when it was last seen
 func (ns *NodeStore) RemoveNode(nodeID string) {
 	ns.mu.Lock()
-	defer ns.mu.Unlock()
 	delete(ns.nodes, nodeID)
+	repo := ns.repo
+	ns.mu.Unlock()
+
+	if repo == nil {
+		return
+	}
+	if err := repo.TouchNode(nodeID); err != nil {
+		log.Printf("[NodeStore] Failed to record last seen for node %s: %v", nodeID, err)
+	}
 }
 
 // GetAllNodes returns all registered nodes
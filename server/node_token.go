@@ -0,0 +1,151 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// nodeTokenOverlap is how long a replaced token keeps working, unless the
+// node connects with its new token first. It covers nodes that could not store
+// the new token and retry the rotation later.
+const nodeTokenOverlap = 24 * time.Hour
+
+// ErrNodeRevoked is returned when a node's tokens were revoked by its owner.
+// A revoked node cannot get a new token; it has to be set up again with a new node ID.
+var ErrNodeRevoked = errors.New("node revoked")
+
+// NodeTokenRepository records issued node tokens so they can be revoked
+// (implemented by database.Client)
+type NodeTokenRepository interface {
+	CreateNodeToken(id, nodeID string, issuedAt, expiresAt time.Time) error
+	RotateNodeToken(oldID, newID, nodeID string, issuedAt, expiresAt time.Time, overlap time.Duration) error
+	RevokeReplacedNodeTokens(id, nodeID string) error
+	IsNodeTokenActive(id, nodeID string) (bool, error)
+	FirstNodeTokenIssuedAt(nodeID string) (time.Time, error)
+	IsNodeRevoked(nodeID string) (bool, error)
+}
+
+// SetNodeTokenRepository records issued node tokens and checks them on every
+// connection. Without one, any token signed with the JWT secret is accepted
+// until it expires. Must be called before the server accepts nodes.
+func (s *Server) SetNodeTokenRepository(repo NodeTokenRepository) {
+	s.tokenRepo = repo
+}
+
+// issueNodeToken creates a token for a node and records it. When replaces is
+// set, that token is revoked in favor of the new one after nodeTokenOverlap.
+func (s *Server) issueNodeToken(nodeID, replaces string) (string, *NodeJWTClaims, error) {
+	if s.tokenRepo != nil {
+		revoked, err := s.tokenRepo.IsNodeRevoked(nodeID)
+		if err != nil {
+			return "", nil, fmt.Errorf("check node: %w", err)
+		}
+		if revoked {
+			return "", nil, ErrNodeRevoked
+		}
+	}
+
+	token, claims, err := GenerateNodeToken(nodeID, s.config.JWTSecret, s.config.NodeTokenTTL())
+	if err != nil {
+		return "", nil, err
+	}
+	if s.tokenRepo == nil {
+		return token, claims, nil
+	}
+
+	issuedAt := claims.IssuedAt.Time
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if replaces != "" {
+		err = s.tokenRepo.RotateNodeToken(replaces, claims.ID, nodeID, issuedAt, expiresAt, nodeTokenOverlap)
+	} else {
+		err = s.tokenRepo.CreateNodeToken(claims.ID, nodeID, issuedAt, expiresAt)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("record token: %w", err)
+	}
+
+	return token, claims, nil
+}
+
+// authenticateNode checks that a token is valid, belongs to nodeID and has
+// not been revoked. Tokens issued before tokens were recorded (no ID) are
+// accepted, however old, until nodeTokenOverlap after the node got its first
+// recorded token, unless the node was revoked; they are rotated right away.
+// Using a rotated token ends the overlap of the token it replaced.
+func (s *Server) authenticateNode(nodeID, token string) (*NodeJWTClaims, error) {
+	claims, err := ValidateNodeToken(token, s.config.JWTSecret)
+	if err != nil {
+		return nil, err
+	}
+	if claims.NodeID != nodeID {
+		return nil, errors.New("token belongs to another node")
+	}
+
+	if s.tokenRepo == nil {
+		return claims, nil
+	}
+
+	revoked, err := s.tokenRepo.IsNodeRevoked(nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("check node: %w", err)
+	}
+	if revoked {
+		return nil, ErrNodeRevoked
+	}
+
+	if claims.ID == "" {
+		replacedAt, err := s.tokenRepo.FirstNodeTokenIssuedAt(nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("check token: %w", err)
+		}
+		if !replacedAt.IsZero() && time.Since(replacedAt) > nodeTokenOverlap {
+			return nil, errors.New("unrecorded token replaced")
+		}
+		log.Printf("[NodeConn] Node %s uses an unrecorded token, it will be rotated", nodeID)
+		return claims, nil
+	}
+
+	active, err := s.tokenRepo.IsNodeTokenActive(claims.ID, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("check token: %w", err)
+	}
+	if !active {
+		return nil, errors.New("token revoked")
+	}
+
+	if err := s.tokenRepo.RevokeReplacedNodeTokens(claims.ID, nodeID); err != nil {
+		return nil, fmt.Errorf("revoke replaced tokens: %w", err)
+	}
+
+	return claims, nil
+}
+
+// tokenRotateAt returns when a node should replace its token: halfway through
+// its lifetime, or right away for tokens that never expire or were never recorded
+func (s *Server) tokenRotateAt(claims *NodeJWTClaims) time.Time {
+	if claims.ID == "" || claims.ExpiresAt == nil || claims.IssuedAt == nil {
+		return time.Now()
+	}
+
+	issued, expires := claims.IssuedAt.Time, claims.ExpiresAt.Time
+	return issued.Add(expires.Sub(issued) / 2)
+}
+
+// DisconnectNode closes a node's connection and its bridges without allowing
+// the session to be resumed. Used when the node's tokens are revoked.
+func (s *Server) DisconnectNode(nodeID string) bool {
+	conn, ok := s.GetNodeConnection(nodeID)
+	if !ok {
+		return false
+	}
+
+	log.Printf("[Server] Disconnecting node %s", nodeID)
+	conn.Close()
+	return true
+}
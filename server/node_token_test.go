@@ -0,0 +1,330 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+// fakeTokenRepo keeps node tokens in memory, like the node_tokens table
+type fakeTokenRepo struct {
+	tokens  map[string]*fakeToken
+	revoked map[string]bool // Revoked nodes
+	err     error           // Returned by every call when set
+}
+
+type fakeToken struct {
+	nodeID     string
+	issuedAt   time.Time
+	revokedAt  time.Time // Zero while active; may lie in the future during an overlap
+	replacedBy string
+}
+
+func newFakeTokenRepo() *fakeTokenRepo {
+	return &fakeTokenRepo{
+		tokens:  make(map[string]*fakeToken),
+		revoked: make(map[string]bool),
+	}
+}
+
+func (r *fakeTokenRepo) CreateNodeToken(id, nodeID string, issuedAt, expiresAt time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.tokens[id] = &fakeToken{nodeID: nodeID, issuedAt: issuedAt}
+	return nil
+}
+
+func (r *fakeTokenRepo) RotateNodeToken(oldID, newID, nodeID string, issuedAt, expiresAt time.Time, overlap time.Duration) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.tokens[newID] = &fakeToken{nodeID: nodeID, issuedAt: issuedAt}
+	if old, ok := r.tokens[oldID]; ok && old.nodeID == nodeID && r.active(old) {
+		old.revokedAt = time.Now().Add(overlap)
+		old.replacedBy = newID
+	}
+	return nil
+}
+
+func (r *fakeTokenRepo) RevokeReplacedNodeTokens(id, nodeID string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, token := range r.tokens {
+		if token.replacedBy == id && token.nodeID == nodeID && token.revokedAt.After(time.Now()) {
+			token.revokedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepo) IsNodeTokenActive(id, nodeID string) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	token, ok := r.tokens[id]
+	return ok && token.nodeID == nodeID && r.active(token), nil
+}
+
+func (r *fakeTokenRepo) FirstNodeTokenIssuedAt(nodeID string) (time.Time, error) {
+	if r.err != nil {
+		return time.Time{}, r.err
+	}
+	var first time.Time
+	for _, token := range r.tokens {
+		if token.nodeID == nodeID && (first.IsZero() || token.issuedAt.Before(first)) {
+			first = token.issuedAt
+		}
+	}
+	return first, nil
+}
+
+func (r *fakeTokenRepo) IsNodeRevoked(nodeID string) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	return r.revoked[nodeID], nil
+}
+
+func (r *fakeTokenRepo) active(token *fakeToken) bool {
+	return token.revokedAt.IsZero() || token.revokedAt.After(time.Now())
+}
+
+func newTestServer(repo NodeTokenRepository) *Server {
+	s := NewServer(&Config{JWTSecret: testSecret})
+	if repo != nil {
+		s.SetNodeTokenRepository(repo)
+	}
+	return s
+}
+
+// signClaims signs claims as-is, for tokens GenerateNodeToken no longer creates
+func signClaims(t *testing.T, claims *NodeJWTClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticateNode(t *testing.T) {
+	repo := newFakeTokenRepo()
+	s := newTestServer(repo)
+
+	recorded, _, err := s.issueNodeToken("node-1", "")
+	require.NoError(t, err)
+
+	unrecorded, _, err := GenerateNodeToken("node-1", testSecret, time.Hour)
+	require.NoError(t, err)
+
+	revokedNode, _, err := s.issueNodeToken("node-2", "")
+	require.NoError(t, err)
+	repo.revoked["node-2"] = true
+
+	legacy := func(issuedAt *jwt.NumericDate) string {
+		return signClaims(t, &NodeJWTClaims{
+			NodeID:           "node-1",
+			RegisteredClaims: jwt.RegisteredClaims{IssuedAt: issuedAt},
+		}, testSecret)
+	}
+
+	expired := signClaims(t, &NodeJWTClaims{
+		NodeID: "node-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "expired",
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}, testSecret)
+
+	wrongSecret, _, err := GenerateNodeToken("node-1", "other-secret", time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		nodeID  string
+		token   string
+		wantErr string
+	}{
+		{"recorded token", "node-1", recorded, ""},
+		{"token of another node", "node-3", recorded, "another node"},
+		{"unrecorded token with ID", "node-1", unrecorded, "token revoked"},
+		{"revoked node", "node-2", revokedNode, ErrNodeRevoked.Error()},
+		{"legacy token", "node-1", legacy(jwt.NewNumericDate(time.Now().Add(-24 * time.Hour))), ""},
+		{"legacy token past its lifetime", "node-1", legacy(jwt.NewNumericDate(time.Now().Add(-31 * 24 * time.Hour))), ""},
+		{"legacy token without issue time", "node-1", legacy(nil), ""},
+		{"expired token", "node-1", expired, "expired"},
+		{"wrong secret", "node-1", wrongSecret, "invalid token"},
+		{"garbage", "node-1", "not-a-jwt", "invalid token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := s.authenticateNode(tt.nodeID, tt.token)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.nodeID, claims.NodeID)
+		})
+	}
+}
+
+func TestAuthenticateNodeRepositoryError(t *testing.T) {
+	repo := newFakeTokenRepo()
+	s := newTestServer(repo)
+
+	token, _, err := s.issueNodeToken("node-1", "")
+	require.NoError(t, err)
+
+	repo.err = errors.New("database down")
+	_, err = s.authenticateNode("node-1", token)
+	assert.ErrorContains(t, err, "database down")
+}
+
+func TestAuthenticateNodeWithoutRepository(t *testing.T) {
+	s := newTestServer(nil)
+
+	token, _, err := GenerateNodeToken("node-1", testSecret, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := s.authenticateNode("node-1", token)
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", claims.NodeID)
+}
+
+func TestRotatedTokenOverlap(t *testing.T) {
+	repo := newFakeTokenRepo()
+	s := newTestServer(repo)
+
+	oldToken, oldClaims, err := s.issueNodeToken("node-1", "")
+	require.NoError(t, err)
+	newToken, newClaims, err := s.issueNodeToken("node-1", oldClaims.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newClaims.ID, repo.tokens[oldClaims.ID].replacedBy)
+
+	// The node may not have stored the new token yet
+	_, err = s.authenticateNode("node-1", oldToken)
+	require.NoError(t, err, "old token works during the overlap")
+
+	// Using the new token ends the overlap
+	_, err = s.authenticateNode("node-1", newToken)
+	require.NoError(t, err)
+	_, err = s.authenticateNode("node-1", oldToken)
+	assert.ErrorContains(t, err, "token revoked")
+}
+
+func TestRotatedTokenOverlapExpires(t *testing.T) {
+	repo := newFakeTokenRepo()
+	s := newTestServer(repo)
+
+	oldToken, oldClaims, err := s.issueNodeToken("node-1", "")
+	require.NoError(t, err)
+	_, _, err = s.issueNodeToken("node-1", oldClaims.ID)
+	require.NoError(t, err)
+
+	repo.tokens[oldClaims.ID].revokedAt = time.Now().Add(-time.Second)
+	_, err = s.authenticateNode("node-1", oldToken)
+	assert.ErrorContains(t, err, "token revoked")
+}
+
+func TestIssueNodeTokenRevokedNode(t *testing.T) {
+	repo := newFakeTokenRepo()
+	repo.revoked["node-1"] = true
+	s := newTestServer(repo)
+
+	_, _, err := s.issueNodeToken("node-1", "")
+	assert.ErrorIs(t, err, ErrNodeRevoked)
+}
+
+func TestTokenRotateAt(t *testing.T) {
+	s := newTestServer(nil)
+	issued := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		claims *NodeJWTClaims
+		want   time.Time // Zero = now
+	}{
+		{
+			name: "halfway through the lifetime",
+			claims: &NodeJWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "id",
+				IssuedAt:  jwt.NewNumericDate(issued),
+				ExpiresAt: jwt.NewNumericDate(issued.Add(30 * 24 * time.Hour)),
+			}},
+			want: issued.Add(15 * 24 * time.Hour),
+		},
+		{
+			name: "unrecorded token",
+			claims: &NodeJWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(issued),
+				ExpiresAt: jwt.NewNumericDate(issued.Add(time.Hour)),
+			}},
+		},
+		{
+			name: "never expires",
+			claims: &NodeJWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+				ID:       "id",
+				IssuedAt: jwt.NewNumericDate(issued),
+			}},
+		},
+		{
+			name: "no issue time",
+			claims: &NodeJWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "id",
+				ExpiresAt: jwt.NewNumericDate(issued),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.tokenRotateAt(tt.claims)
+			if tt.want.IsZero() {
+				assert.WithinDuration(t, time.Now(), got, time.Second)
+			} else {
+				assert.True(t, tt.want.Equal(got), "got %v", got)
+			}
+		})
+	}
+}
+
+func TestOldLegacyToken(t *testing.T) {
+	repo := newFakeTokenRepo()
+	s := newTestServer(repo)
+
+	// Set up long before tokens were recorded
+	legacy := signClaims(t, &NodeJWTClaims{
+		NodeID:           "node-1",
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(time.Now().Add(-400 * 24 * time.Hour))},
+	}, testSecret)
+
+	claims, err := s.authenticateNode("node-1", legacy)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), s.tokenRotateAt(claims), time.Second, "rotated right away")
+
+	// Rotation records the node's first token; the old one works during the overlap
+	_, _, err = s.issueNodeToken("node-1", claims.ID)
+	require.NoError(t, err)
+	_, err = s.authenticateNode("node-1", legacy)
+	require.NoError(t, err)
+
+	for _, token := range repo.tokens {
+		token.issuedAt = token.issuedAt.Add(-nodeTokenOverlap - time.Minute)
+	}
+	_, err = s.authenticateNode("node-1", legacy)
+	assert.ErrorContains(t, err, "unrecorded token replaced")
+
+	// Revoked nodes are refused however they authenticate
+	legacyRevoked := signClaims(t, &NodeJWTClaims{NodeID: "node-2"}, testSecret)
+	repo.revoked["node-2"] = true
+	_, err = s.authenticateNode("node-2", legacyRevoked)
+	assert.ErrorIs(t, err, ErrNodeRevoked)
+}
@@ -16,6 +16,7 @@ type NodeEventCallback func(nodeID string)
 type Server struct {
 	config    *Config
 	nodeStore *NodeStore
+	tokenRepo NodeTokenRepository // Issued node tokens (nil = not recorded)
 	nodes     map[string]*NodeConn
 	nodesMu   sync.Mutex
 
@@ -33,6 +34,11 @@ func NewServer(cfg *Config) *Server {
 	}
 }
 
+// SetNodeRepository persists node registrations (hostname, version, last seen)
+func (s *Server) SetNodeRepository(repo NodeRepository) {
+	s.nodeStore.SetRepository(repo)
+}
+
 // GetConfig returns the server configuration
 func (s *Server) GetConfig() *Config {
 	return s.config
@@ -46,10 +52,14 @@ func (s *Server) RegisterNodeConnection(nodeID string, conn *NodeConn) {
 	log.Printf("[Server] Node %s connected", nodeID)
 }
 
-// UnregisterNodeConnection unregisters a node connection
-func (s *Server) UnregisterNodeConnection(nodeID string) {
+// UnregisterNodeConnection unregisters a node connection.
+// It is a no-op if the node has already registered a newer connection.
+func (s *Server) UnregisterNodeConnection(nodeID string, conn *NodeConn) {
 	s.nodesMu.Lock()
 	defer s.nodesMu.Unlock()
+	if s.nodes[nodeID] != conn {
+		return
+	}
 	delete(s.nodes, nodeID)
 	log.Printf("[Server] Node %s disconnected", nodeID)
 }
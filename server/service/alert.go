@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/gen/service/v1/servicev1connect"
+	"unblink/server/internal/ctxutil"
+)
+
+// Limits for user-written alert rules
+const (
+	maxAlertRuleNameLength       = 100
+	maxAlertRuleExpressionLength = 500
+	maxAlertCooldownSeconds      = 24 * 60 * 60
+)
+
+type AlertService struct {
+	db AlertDatabase
+}
+
+func NewAlertService(db AlertDatabase) *AlertService {
+	return &AlertService{db: db}
+}
+
+// CreateAlertRule creates a rule for a node or one of its services
+func (s *AlertService) CreateAlertRule(ctx context.Context, req *connect.Request[servicev1.CreateAlertRuleRequest]) (*connect.Response[servicev1.CreateAlertRuleResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	if req.Msg.ServiceId != "" {
+		svc, err := s.db.GetService(req.Msg.ServiceId)
+		if err != nil || svc == nil || svc.NodeId != req.Msg.NodeId {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("service %s not found on node %s", req.Msg.ServiceId, req.Msg.NodeId))
+		}
+	}
+
+	now := time.Now()
+	rule := &servicev1.AlertRule{
+		Id:              generateID(),
+		NodeId:          req.Msg.NodeId,
+		ServiceId:       req.Msg.ServiceId,
+		Name:            strings.TrimSpace(req.Msg.Name),
+		Expression:      strings.TrimSpace(req.Msg.Expression),
+		Enabled:         true,
+		CooldownSeconds: req.Msg.CooldownSeconds,
+		CreatedAt:       timestamppb.New(now),
+		UpdatedAt:       timestamppb.New(now),
+	}
+	if err := validateAlertRule(rule); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if err := s.db.CreateAlertRule(rule); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to create alert rule: %w", err))
+	}
+
+	log.Printf("[AlertService] Created alert rule: id=%s, node_id=%s, service_id=%s, kind=%s", rule.Id, rule.NodeId, rule.ServiceId, rule.Kind)
+
+	return connect.NewResponse(&servicev1.CreateAlertRuleResponse{
+		Rule: rule,
+	}), nil
+}
+
+// ListAlertRules lists the rules of a node, optionally only those covering one service
+func (s *AlertService) ListAlertRules(ctx context.Context, req *connect.Request[servicev1.ListAlertRulesRequest]) (*connect.Response[servicev1.ListAlertRulesResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.db.ListAlertRulesByNodeId(req.Msg.NodeId, req.Msg.ServiceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list alert rules: %w", err))
+	}
+
+	return connect.NewResponse(&servicev1.ListAlertRulesResponse{
+		Rules: rules,
+	}), nil
+}
+
+// UpdateAlertRule changes the text, cooldown or enabled state of a rule
+func (s *AlertService) UpdateAlertRule(ctx context.Context, req *connect.Request[servicev1.UpdateAlertRuleRequest]) (*connect.Response[servicev1.UpdateAlertRuleResponse], error) {
+	rule, err := s.getRule(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Name = strings.TrimSpace(req.Msg.Name)
+	rule.Expression = strings.TrimSpace(req.Msg.Expression)
+	rule.Enabled = req.Msg.Enabled
+	rule.CooldownSeconds = req.Msg.CooldownSeconds
+	if err := validateAlertRule(rule); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if err := s.db.UpdateAlertRule(rule); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update alert rule: %w", err))
+	}
+	rule.UpdatedAt = timestamppb.New(time.Now())
+
+	log.Printf("[AlertService] Updated alert rule: id=%s, kind=%s, enabled=%v", rule.Id, rule.Kind, rule.Enabled)
+
+	return connect.NewResponse(&servicev1.UpdateAlertRuleResponse{
+		Rule: rule,
+	}), nil
+}
+
+// DeleteAlertRule deletes a rule; its past alert events are kept
+func (s *AlertService) DeleteAlertRule(ctx context.Context, req *connect.Request[servicev1.DeleteAlertRuleRequest]) (*connect.Response[servicev1.DeleteAlertRuleResponse], error) {
+	rule, err := s.getRule(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.DeleteAlertRule(rule.Id); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete alert rule: %w", err))
+	}
+
+	log.Printf("[AlertService] Deleted alert rule: id=%s", rule.Id)
+
+	return connect.NewResponse(&servicev1.DeleteAlertRuleResponse{
+		Success: true,
+	}), nil
+}
+
+// getRule loads a rule and verifies access to its node
+func (s *AlertService) getRule(ctx context.Context, id string) (*servicev1.AlertRule, error) {
+	if id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id is required"))
+	}
+
+	rule, err := s.db.GetAlertRule(id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get alert rule: %w", err))
+	}
+	if rule == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("alert rule not found"))
+	}
+
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, rule.NodeId); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// validateAlertRule checks the user-supplied fields of a rule and sets its kind
+func validateAlertRule(rule *servicev1.AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(rule.Name) > maxAlertRuleNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxAlertRuleNameLength)
+	}
+	if rule.Expression == "" {
+		return fmt.Errorf("expression is required")
+	}
+	if len(rule.Expression) > maxAlertRuleExpressionLength {
+		return fmt.Errorf("expression must be at most %d characters", maxAlertRuleExpressionLength)
+	}
+	if rule.CooldownSeconds < 0 || rule.CooldownSeconds > maxAlertCooldownSeconds {
+		return fmt.Errorf("cooldown_seconds must be between 0 and %d", maxAlertCooldownSeconds)
+	}
+
+	_, isPredicate, err := parseAlertPredicate(rule.Expression)
+	if err != nil {
+		return fmt.Errorf("invalid expression: %w", err)
+	}
+	rule.Kind = AlertRuleKindNaturalLanguage
+	if isPredicate {
+		rule.Kind = AlertRuleKindPredicate
+	}
+	return nil
+}
+
+// Ensure AlertService implements interface
+var _ servicev1connect.AlertServiceHandler = (*AlertService)(nil)
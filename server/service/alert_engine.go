@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/invopop/jsonschema"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/timeutil"
+	"unblink/server/models"
+	"unblink/server/webrtc"
+)
+
+const (
+	// alertEventType is the payload type of events created by alert rules
+	alertEventType = "alert"
+
+	// defaultAlertCooldown applies to rules without a cooldown of their own
+	defaultAlertCooldown = 5 * time.Minute
+
+	// alertModelTimeout bounds one fast-model call for free-text rules
+	alertModelTimeout = 30 * time.Second
+)
+
+// AlertDatabase defines the database operations for alert rules
+type AlertDatabase interface {
+	GetService(id string) (*servicev1.Service, error)
+	CheckNodeAccess(nodeID, userID string) (bool, error)
+	CreateEvent(id, serviceID string, payload *structpb.Struct) error
+	CreateAlertRule(rule *servicev1.AlertRule) error
+	UpdateAlertRule(rule *servicev1.AlertRule) error
+	GetAlertRule(id string) (*servicev1.AlertRule, error)
+	DeleteAlertRule(id string) error
+	ListAlertRulesByNodeId(nodeID, serviceID string) ([]*servicev1.AlertRule, error)
+	ListActiveAlertRulesForService(serviceID, nodeID string) ([]*servicev1.AlertRule, error)
+	SetAlertRuleTriggered(id string, at time.Time) error
+	TimeSinceLastAlert(ruleID, serviceID string) (time.Duration, bool, error)
+}
+
+// AlertEngine checks every VLM result against the alert rules covering its
+// service and records matches as "alert" events
+type AlertEngine struct {
+	db          AlertDatabase
+	broadcaster *EventBroadcaster
+
+	// Fast model for free-text rules (nil = free-text rules never match)
+	llm   *openai.Client
+	model string
+
+	queue chan *webrtc.BatchResult
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // ruleID/serviceID -> last alert (zero = never)
+}
+
+// NewAlertEngine creates an alert engine and starts its worker. The fast model
+// is looked up in the model registry; without it only predicate rules work.
+func NewAlertEngine(db AlertDatabase, broadcaster *EventBroadcaster, modelRegistry *models.Registry, fastModel string) *AlertEngine {
+	e := &AlertEngine{
+		db:          db,
+		broadcaster: broadcaster,
+		model:       fastModel,
+		queue:       make(chan *webrtc.BatchResult, 100),
+		lastAlert:   make(map[string]time.Time),
+	}
+
+	if cfg, err := modelRegistry.GetConfig(fastModel); err == nil && cfg.APIKey != "" {
+		opts := []option.RequestOption{option.WithAPIKey(cfg.APIKey)}
+		if cfg.BaseURL != "" {
+			opts = append(opts, option.WithBaseURL(cfg.BaseURL))
+		}
+		client := openai.NewClient(opts...)
+		e.llm = &client
+	} else {
+		log.Printf("[AlertEngine] Fast model %q not available, free-text rules are disabled", fastModel)
+	}
+
+	go e.worker()
+	return e
+}
+
+// HandleResult queues a batch result for evaluation (a webrtc.ResultListener)
+func (e *AlertEngine) HandleResult(result *webrtc.BatchResult) {
+	select {
+	case e.queue <- result:
+	default:
+		log.Printf("[AlertEngine] Queue full, skipping result %s of service %s", result.EventID, result.ServiceID)
+	}
+}
+
+func (e *AlertEngine) worker() {
+	for result := range e.queue {
+		if err := e.evaluate(result); err != nil {
+			log.Printf("[AlertEngine] Failed to evaluate result %s of service %s: %v", result.EventID, result.ServiceID, err)
+		}
+	}
+}
+
+// alertMatch is a rule that matched a result
+type alertMatch struct {
+	rule   *servicev1.AlertRule
+	labels []string // Objects that satisfied a predicate rule
+	reason string
+}
+
+// evaluate checks one result against the rules of its service
+func (e *AlertEngine) evaluate(result *webrtc.BatchResult) error {
+	rules, err := e.db.ListActiveAlertRulesForService(result.ServiceID, result.NodeID)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	svc, err := e.db.GetService(result.ServiceID)
+	if err != nil || svc == nil {
+		return fmt.Errorf("get service: %v", err)
+	}
+	zones := svc.GetAnalysis().GetZones()
+
+	now := time.Now()
+	var matches []alertMatch
+	var freeText []*servicev1.AlertRule
+	for _, rule := range rules {
+		if e.inCooldown(rule, result.ServiceID, now) {
+			continue
+		}
+
+		if rule.Kind == AlertRuleKindNaturalLanguage {
+			freeText = append(freeText, rule)
+			continue
+		}
+
+		predicate, ok, err := parseAlertPredicate(rule.Expression)
+		if err != nil || !ok {
+			log.Printf("[AlertEngine] Rule %s is not a valid predicate, skipping: %v", rule.Id, err)
+			continue
+		}
+		if matched, labels := predicate.match(result.Response, zones, result.To); matched {
+			matches = append(matches, alertMatch{
+				rule:   rule,
+				labels: labels,
+				reason: fmt.Sprintf("Matched %q", rule.Expression),
+			})
+		}
+	}
+
+	if len(freeText) > 0 {
+		judged, err := e.judge(svc.Name, result, freeText)
+		if err != nil {
+			log.Printf("[AlertEngine] Fast model failed for service %s: %v", result.ServiceID, err)
+		}
+		matches = append(matches, judged...)
+	}
+
+	for _, match := range matches {
+		e.fire(match, result, now)
+	}
+	return nil
+}
+
+// inCooldown reports whether a rule alerted for a service within its cooldown
+func (e *AlertEngine) inCooldown(rule *servicev1.AlertRule, serviceID string, now time.Time) bool {
+	cooldown := defaultAlertCooldown
+	if rule.CooldownSeconds > 0 {
+		cooldown = time.Duration(rule.CooldownSeconds) * time.Second
+	}
+
+	return now.Sub(e.lastAlertTime(rule.Id, serviceID, now)) < cooldown
+}
+
+// lastAlertTime returns when a rule last alerted for a service. The first
+// lookup after a restart reads it from the alert events, so rules covering
+// several services keep a cooldown for each of them.
+func (e *AlertEngine) lastAlertTime(ruleID, serviceID string, now time.Time) time.Time {
+	key := ruleID + "/" + serviceID
+
+	e.mu.Lock()
+	last, ok := e.lastAlert[key]
+	e.mu.Unlock()
+	if ok {
+		return last
+	}
+
+	since, found, err := e.db.TimeSinceLastAlert(ruleID, serviceID)
+	if err != nil {
+		log.Printf("[AlertEngine] Failed to look up last alert of rule %s for service %s: %v", ruleID, serviceID, err)
+		return time.Time{}
+	}
+	if found {
+		last = now.Add(-since)
+	}
+
+	e.mu.Lock()
+	if _, ok := e.lastAlert[key]; !ok {
+		e.lastAlert[key] = last
+	}
+	e.mu.Unlock()
+	return last
+}
+
+// fire records a match as an alert event and broadcasts it
+func (e *AlertEngine) fire(match alertMatch, result *webrtc.BatchResult, now time.Time) {
+	e.mu.Lock()
+	e.lastAlert[match.rule.Id+"/"+result.ServiceID] = now
+	e.mu.Unlock()
+
+	labels := make([]any, len(match.labels))
+	for i, label := range match.labels {
+		labels[i] = label
+	}
+
+	payload, err := structpb.NewStruct(map[string]any{
+		"type":            alertEventType,
+		"rule_id":         match.rule.Id,
+		"rule_name":       match.rule.Name,
+		"expression":      match.rule.Expression,
+		"kind":            match.rule.Kind,
+		"reason":          match.reason,
+		"labels":          labels,
+		"source_event_id": result.EventID,
+		"from_iso":        timeutil.FormatToISO(result.From),
+		"to_iso":          timeutil.FormatToISO(result.To),
+	})
+	if err != nil {
+		log.Printf("[AlertEngine] Failed to create alert payload: %v", err)
+		return
+	}
+
+	eventID := uuid.New().String()
+	if err := e.db.CreateEvent(eventID, result.ServiceID, payload); err != nil {
+		log.Printf("[AlertEngine] Failed to create alert event: %v", err)
+		return
+	}
+	if err := e.db.SetAlertRuleTriggered(match.rule.Id, now); err != nil {
+		log.Printf("[AlertEngine] Failed to record trigger of rule %s: %v", match.rule.Id, err)
+	}
+
+	log.Printf("[AlertEngine] Rule %s (%s) matched on service %s: %s", match.rule.Id, match.rule.Name, result.ServiceID, match.reason)
+
+	if e.broadcaster != nil {
+		e.broadcaster.Broadcast(&servicev1.Event{
+			Id:        eventID,
+			ServiceId: result.ServiceID,
+			Payload:   payload,
+			CreatedAt: timestamppb.New(now),
+		}, result.NodeID)
+	}
+}
+
+// alertJudgement is the fast model's verdict on a set of free-text rules
+type alertJudgement struct {
+	Matches []struct {
+		Rule   int    `json:"rule" jsonschema_description:"Number of the matching rule"`
+		Reason string `json:"reason" jsonschema_description:"One sentence on what was seen that matches the rule"`
+	} `json:"matches" jsonschema_description:"Rules the observation clearly satisfies; empty if none"`
+}
+
+// judge asks the fast model which free-text rules an observation satisfies
+func (e *AlertEngine) judge(serviceName string, result *webrtc.BatchResult, rules []*servicev1.AlertRule) ([]alertMatch, error) {
+	if e.llm == nil {
+		return nil, nil
+	}
+
+	labels := make([]string, 0, len(result.Response.Objects))
+	for _, obj := range result.Response.Objects {
+		labels = append(labels, obj.Label)
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Camera: %s\n", serviceName)
+	fmt.Fprintf(&prompt, "Local time: %s\n", result.To.Local().Format("Monday 2006-01-02 15:04"))
+	fmt.Fprintf(&prompt, "Objects: %s\n", strings.Join(labels, ", "))
+	fmt.Fprintf(&prompt, "Description: %s\n\nRules:\n", result.Response.Description)
+	for i, rule := range rules {
+		fmt.Fprintf(&prompt, "%d. %s\n", i+1, rule.Expression)
+	}
+
+	reflector := jsonschema.Reflector{AllowAdditionalProperties: false, DoNotReference: true}
+	params := openai.ChatCompletionNewParams{
+		Model: openai.ChatModel(e.model),
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage("You check what a security camera saw against alert rules written by its owner. " +
+				"Report a rule only when the observation clearly satisfies all of it, including any time of day it mentions."),
+			openai.UserMessage(prompt.String()),
+		},
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
+				JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   "alert_judgement",
+					Schema: reflector.Reflect(alertJudgement{}),
+					Strict: openai.Bool(true),
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertModelTimeout)
+	defer cancel()
+
+	response, err := e.llm.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+
+	var judgement alertJudgement
+	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &judgement); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	var matches []alertMatch
+	seen := make(map[int]bool)
+	for _, m := range judgement.Matches {
+		if m.Rule < 1 || m.Rule > len(rules) || seen[m.Rule] {
+			continue
+		}
+		seen[m.Rule] = true
+		matches = append(matches, alertMatch{rule: rules[m.Rule-1], reason: m.Reason})
+	}
+	return matches, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/webrtc"
+)
+
+// Alert rule kinds (AlertRule.kind)
+const (
+	AlertRuleKindPredicate       = "predicate"        // Evaluated directly on the VLM objects
+	AlertRuleKindNaturalLanguage = "natural_language" // Judged by the fast model
+)
+
+// alertPredicate is a parsed predicate rule; every set condition must hold
+type alertPredicate struct {
+	label       string // Object label, lowercase
+	zone        string // Zone name, lowercase
+	countOp     string // Comparison for the number of matching objects
+	count       int
+	description string // Substring of the description, lowercase
+
+	// Local time window in minutes since midnight (-1 = open)
+	after  int
+	before int
+}
+
+// parseAlertPredicate parses a predicate expression. It returns false when the
+// expression is not made only of predicates, i.e. it is free text; malformed
+// predicates (e.g. "count>=x") are errors.
+func parseAlertPredicate(expression string) (*alertPredicate, bool, error) {
+	p := &alertPredicate{after: -1, before: -1}
+	tokens := strings.Fields(strings.ToLower(expression))
+	if len(tokens) == 0 {
+		return nil, false, nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "and":
+			continue
+
+		case strings.HasPrefix(token, "label="):
+			p.label = strings.TrimPrefix(token, "label=")
+			if p.label == "" {
+				return nil, true, fmt.Errorf("label= needs a value")
+			}
+
+		case strings.HasPrefix(token, "zone="):
+			p.zone = strings.TrimPrefix(token, "zone=")
+			if p.zone == "" {
+				return nil, true, fmt.Errorf("zone= needs a value")
+			}
+
+		case token == "in" && i+2 < len(tokens) && tokens[i+1] == "zone":
+			p.zone = tokens[i+2]
+			i += 2
+
+		case strings.HasPrefix(token, "count"):
+			op, value := splitComparison(strings.TrimPrefix(token, "count"))
+			if op == "" {
+				return nil, false, nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, true, fmt.Errorf("invalid count %q", value)
+			}
+			p.countOp, p.count = op, n
+
+		case strings.HasPrefix(token, "description~"):
+			p.description = strings.TrimPrefix(token, "description~")
+			if p.description == "" {
+				return nil, true, fmt.Errorf("description~ needs a value")
+			}
+
+		case (token == "after" || token == "before") && i+1 < len(tokens):
+			minutes, err := parseClock(tokens[i+1])
+			if err != nil {
+				return nil, false, nil
+			}
+			if token == "after" {
+				p.after = minutes
+			} else {
+				p.before = minutes
+			}
+			i++
+
+		default:
+			return nil, false, nil
+		}
+	}
+
+	if p.label == "" && p.zone == "" && p.countOp == "" && p.description == "" && p.after < 0 && p.before < 0 {
+		return nil, false, nil
+	}
+	return p, true, nil
+}
+
+// splitComparison splits ">=2" into its operator and value
+func splitComparison(s string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if value, ok := strings.CutPrefix(s, op); ok {
+			return op, value
+		}
+	}
+	return "", ""
+}
+
+// parseClock parses "22:00", "22", "10pm" or "10:30pm" into minutes since midnight
+func parseClock(s string) (int, error) {
+	pm := strings.HasSuffix(s, "pm")
+	am := strings.HasSuffix(s, "am")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "pm"), "am")
+
+	hourStr, minuteStr, _ := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil {
+		return 0, err
+	}
+	minute := 0
+	if minuteStr != "" {
+		if minute, err = strconv.Atoi(minuteStr); err != nil {
+			return 0, err
+		}
+	}
+
+	if am || pm {
+		if hour < 1 || hour > 12 {
+			return 0, fmt.Errorf("invalid hour %d", hour)
+		}
+		hour %= 12
+		if pm {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// match checks a VLM result against the predicate and returns the labels of
+// the objects that satisfied it
+func (p *alertPredicate) match(resp *webrtc.VLMResponse, zones []*servicev1.Zone, at time.Time) (bool, []string) {
+	if !p.inWindow(at) {
+		return false, nil
+	}
+	if p.description != "" && !strings.Contains(strings.ToLower(resp.Description), p.description) {
+		return false, nil
+	}
+
+	if p.label == "" && p.zone == "" && p.countOp == "" {
+		return true, nil
+	}
+
+	var zone *servicev1.Zone
+	if p.zone != "" {
+		for _, z := range zones {
+			if strings.EqualFold(z.Name, p.zone) {
+				zone = z
+				break
+			}
+		}
+		if zone == nil {
+			return false, nil // The service has no such zone
+		}
+	}
+
+	var labels []string
+	for _, obj := range resp.Objects {
+		if p.label != "" && !labelMatches(obj.Label, p.label) {
+			continue
+		}
+		if zone != nil && !inZone(obj.BBox, zone) {
+			continue
+		}
+		labels = append(labels, obj.Label)
+	}
+
+	op, count := p.countOp, p.count
+	if op == "" {
+		op, count = ">=", 1
+	}
+	if !compareCount(len(labels), op, count) {
+		return false, nil
+	}
+	return true, labels
+}
+
+// inWindow checks the local time of the frames against after/before
+func (p *alertPredicate) inWindow(at time.Time) bool {
+	local := at.Local()
+	minutes := local.Hour()*60 + local.Minute()
+
+	switch {
+	case p.after >= 0 && p.before >= 0:
+		if p.after <= p.before {
+			return minutes >= p.after && minutes < p.before
+		}
+		return minutes >= p.after || minutes < p.before // Wraps past midnight
+	case p.after >= 0:
+		return minutes >= p.after
+	case p.before >= 0:
+		return minutes < p.before
+	}
+	return true
+}
+
+// labelMatches compares labels case-insensitively, ignoring a plural "s"
+// and matching on words ("red car" matches "car")
+func labelMatches(label, want string) bool {
+	label = strings.ToLower(label)
+	if label == want || strings.TrimSuffix(label, "s") == strings.TrimSuffix(want, "s") {
+		return true
+	}
+	for _, word := range strings.Fields(label) {
+		if word == want || strings.TrimSuffix(word, "s") == strings.TrimSuffix(want, "s") {
+			return true
+		}
+	}
+	return false
+}
+
+// inZone checks whether the center of a normalized box lies in a zone
+func inZone(bbox []float64, zone *servicev1.Zone) bool {
+	if len(bbox) != 4 {
+		return false
+	}
+	cx := (bbox[0] + bbox[2]) / 2
+	cy := (bbox[1] + bbox[3]) / 2
+	return cx >= float64(zone.X1) && cx <= float64(zone.X2) && cy >= float64(zone.Y1) && cy <= float64(zone.Y2)
+}
+
+func compareCount(n int, op string, want int) bool {
+	switch op {
+	case ">=":
+		return n >= want
+	case "<=":
+		return n <= want
+	case ">":
+		return n > want
+	case "<":
+		return n < want
+	default:
+		return n == want
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/webrtc"
+)
+
+func TestParseAlertPredicate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       *alertPredicate // nil with ok = free text
+		free       bool
+		wantErr    bool
+	}{
+		{expression: "label=person", want: &alertPredicate{label: "person", after: -1, before: -1}},
+		{
+			expression: "label=Car and in zone Driveway count>=2",
+			want:       &alertPredicate{label: "car", zone: "driveway", countOp: ">=", count: 2, after: -1, before: -1},
+		},
+		{
+			expression: "zone=yard description~running after 22:00 before 6am",
+			want:       &alertPredicate{zone: "yard", description: "running", after: 22 * 60, before: 6 * 60},
+		},
+		{expression: "count=0", want: &alertPredicate{countOp: "=", count: 0, after: -1, before: -1}},
+		{expression: "after 10:30pm", want: &alertPredicate{after: 22*60 + 30, before: -1}},
+		{expression: "", free: true},
+		{expression: "and", free: true},
+		{expression: "person at the front door after 10pm", free: true},
+		{expression: "label=person near the gate", free: true},
+		{expression: "counting sheep", free: true},
+		{expression: "after midnight", free: true},
+		{expression: "label=", wantErr: true},
+		{expression: "zone=", wantErr: true},
+		{expression: "description~", wantErr: true},
+		{expression: "count>=x", wantErr: true},
+		{expression: "count<-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			p, ok, err := parseAlertPredicate(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, ok, "malformed predicates are not free text")
+				return
+			}
+			require.NoError(t, err)
+			if tt.free {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "22", want: 22 * 60},
+		{in: "22:00", want: 22 * 60},
+		{in: "7:05", want: 7*60 + 5},
+		{in: "23:59", want: 23*60 + 59},
+		{in: "10pm", want: 22 * 60},
+		{in: "10:30pm", want: 22*60 + 30},
+		{in: "12am", want: 0},
+		{in: "12pm", want: 12 * 60},
+		{in: "1am", want: 60},
+		{in: "24", wantErr: true},
+		{in: "13pm", wantErr: true},
+		{in: "0am", wantErr: true},
+		{in: "10:60", wantErr: true},
+		{in: "10:-1", wantErr: true},
+		{in: "noon", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseClock(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLabelMatches(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+		match bool
+	}{
+		{"person", "person", true},
+		{"Person", "person", true},
+		{"cars", "car", true},
+		{"car", "cars", true},
+		{"red car", "car", true},
+		{"red cars", "car", true},
+		{"Delivery Truck", "truck", true},
+		{"carpet", "car", false},
+		{"cat", "car", false},
+		{"", "car", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, labelMatches(tt.label, tt.want), "%q ~ %q", tt.label, tt.want)
+	}
+}
+
+func TestInZone(t *testing.T) {
+	zone := &servicev1.Zone{Name: "driveway", X1: 0, Y1: 500, X2: 400, Y2: 1000}
+
+	tests := []struct {
+		name string
+		bbox []float64
+		want bool
+	}{
+		{"center inside", []float64{100, 600, 300, 900}, true},
+		{"center on the edge", []float64{300, 500, 500, 700}, true},
+		{"overlaps but center outside", []float64{300, 400, 700, 600}, false},
+		{"outside", []float64{600, 0, 900, 300}, false},
+		{"no box", nil, false},
+		{"malformed box", []float64{100, 600}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inZone(tt.bbox, zone))
+		})
+	}
+}
+
+func TestAlertPredicateMatch(t *testing.T) {
+	zones := []*servicev1.Zone{{Name: "Driveway", X1: 0, Y1: 500, X2: 400, Y2: 1000}}
+	resp := &webrtc.VLMResponse{
+		Description: "A person [1] walks past a parked car [2]",
+		Objects: []webrtc.VLMObject{
+			{Label: "person", BBox: []float64{100, 600, 200, 900}},
+			{Label: "red car", BBox: []float64{600, 600, 900, 900}},
+		},
+	}
+	noon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		expression string
+		match      bool
+		labels     []string
+	}{
+		{"label=person", true, []string{"person"}},
+		{"label=car in zone driveway", false, nil},
+		{"in zone driveway", true, []string{"person"}},
+		{"zone=garden", false, nil},
+		{"count>=2", true, []string{"person", "red car"}},
+		{"label=dog count=0", true, nil},
+		{"description~parked", true, nil},
+		{"description~running", false, nil},
+		{"label=person after 22:00", false, nil},
+		{"label=person after 11 before 13", true, []string{"person"}},
+		{"label=person after 22 before 6", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			p, ok, err := parseAlertPredicate(tt.expression)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			match, labels := p.match(resp, zones, noon)
+			assert.Equal(t, tt.match, match)
+			assert.Equal(t, tt.labels, labels)
+		})
+	}
+}
+
+// fakeAlertDatabase answers last-alert lookups; other calls are not expected
+type fakeAlertDatabase struct {
+	AlertDatabase
+	lastAlert map[string]time.Duration // ruleID/serviceID -> time since
+	lookups   int
+}
+
+func (db *fakeAlertDatabase) TimeSinceLastAlert(ruleID, serviceID string) (time.Duration, bool, error) {
+	db.lookups++
+	since, ok := db.lastAlert[ruleID+"/"+serviceID]
+	return since, ok, nil
+}
+
+func TestInCooldownAfterRestart(t *testing.T) {
+	db := &fakeAlertDatabase{lastAlert: map[string]time.Duration{
+		"rule/cam1": time.Minute,
+		"rule/cam2": time.Hour,
+	}}
+	e := &AlertEngine{db: db, lastAlert: make(map[string]time.Time)}
+
+	// A node-wide rule keeps a cooldown per service
+	rule := &servicev1.AlertRule{Id: "rule", CooldownSeconds: 600}
+	now := time.Now()
+
+	assert.True(t, e.inCooldown(rule, "cam1", now))
+	assert.False(t, e.inCooldown(rule, "cam2", now))
+	assert.False(t, e.inCooldown(rule, "cam3", now), "never alerted")
+
+	// Looked up once per service, then remembered
+	assert.True(t, e.inCooldown(rule, "cam1", now))
+	assert.False(t, e.inCooldown(rule, "cam3", now))
+	assert.Equal(t, 3, db.lookups)
+
+	assert.False(t, e.inCooldown(rule, "cam1", now.Add(10*time.Minute)))
+}
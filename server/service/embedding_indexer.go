@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/models"
+)
+
+// embeddingBatchSize is the number of events embedded per request
+const embeddingBatchSize = 32
+
+// EmbeddingDatabase defines the database operations of the embedding indexer
+type EmbeddingDatabase interface {
+	StoreEventEmbedding(eventID, model string, embedding []float32) error
+	ListEventsWithoutEmbedding(limit int) ([]*database.EventText, error)
+}
+
+// EmbeddingIndexer embeds the description of every VLM event for semantic search
+type EmbeddingIndexer struct {
+	db       EmbeddingDatabase
+	embedder *models.Embedder
+	queue    chan *database.EventText
+}
+
+// NewEmbeddingIndexer creates an indexer; call Start to begin embedding
+func NewEmbeddingIndexer(db EmbeddingDatabase, embedder *models.Embedder) *EmbeddingIndexer {
+	return &EmbeddingIndexer{
+		db:       db,
+		embedder: embedder,
+		queue:    make(chan *database.EventText, 500),
+	}
+}
+
+// Start embeds new events as they arrive and, in the background, older
+// events that have no embedding yet
+func (x *EmbeddingIndexer) Start() {
+	go x.worker()
+	go x.backfill()
+}
+
+// HandleEvent queues a broadcast VLM event, including summary rollups (an
+// EventListener)
+func (x *EmbeddingIndexer) HandleEvent(event *servicev1.Event, nodeID string) {
+	fields := event.GetPayload().GetFields()
+	if fields["type"].GetStringValue() != "vlm-indexing" {
+		return
+	}
+
+	response := fields["response"].GetStructValue().GetFields()
+	description := response["description"].GetStringValue()
+	if description == "" {
+		return
+	}
+
+	var labels []string
+	for _, obj := range response["objects"].GetListValue().GetValues() {
+		if label := obj.GetStructValue().GetFields()["label"].GetStringValue(); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	select {
+	case x.queue <- &database.EventText{ID: event.Id, Description: description, Labels: labels}:
+	default:
+		// The backfill picks it up after the next restart
+		log.Printf("[EmbeddingIndexer] Queue full, skipping event %s", event.Id)
+	}
+}
+
+func (x *EmbeddingIndexer) worker() {
+	for text := range x.queue {
+		// Embed whatever else is waiting in the same request
+		batch := []*database.EventText{text}
+	drain:
+		for len(batch) < embeddingBatchSize {
+			select {
+			case next := <-x.queue:
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+
+		if err := x.index(batch); err != nil {
+			log.Printf("[EmbeddingIndexer] Failed to embed %d events: %v", len(batch), err)
+		}
+	}
+}
+
+// backfill embeds stored events without an embedding, newest first
+func (x *EmbeddingIndexer) backfill() {
+	total := 0
+	for {
+		texts, err := x.db.ListEventsWithoutEmbedding(embeddingBatchSize)
+		if err != nil {
+			log.Printf("[EmbeddingIndexer] Backfill stopped: %v", err)
+			return
+		}
+		if len(texts) == 0 {
+			break
+		}
+
+		if err := x.index(texts); err != nil {
+			log.Printf("[EmbeddingIndexer] Backfill stopped after %d events: %v", total, err)
+			return
+		}
+		total += len(texts)
+	}
+
+	if total > 0 {
+		log.Printf("[EmbeddingIndexer] Backfilled embeddings for %d events", total)
+	}
+}
+
+// index embeds and stores a batch of events
+func (x *EmbeddingIndexer) index(texts []*database.EventText) error {
+	inputs := make([]string, len(texts))
+	for i, text := range texts {
+		inputs[i] = embeddingText(text.Description, text.Labels)
+	}
+
+	vectors, err := x.embedder.Embed(context.Background(), inputs)
+	if err != nil {
+		return err
+	}
+
+	for i, text := range texts {
+		if err := x.db.StoreEventEmbedding(text.ID, x.embedder.Model(), vectors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embeddingText is the text embedded for an event: its description and the
+// labels of the detected objects
+func embeddingText(description string, labels []string) string {
+	if len(labels) == 0 {
+		return description
+	}
+	return description + "\nObjects: " + strings.Join(labels, ", ")
+}
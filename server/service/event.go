@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
 
+	"unblink/database"
 	servicev1 "unblink/server/gen/service/v1"
 	"unblink/server/gen/service/v1/servicev1connect"
 	"unblink/server/internal/ctxutil"
+	"unblink/server/models"
 )
 
 // EventDatabase defines the interface for event database operations
@@ -19,11 +22,19 @@ type EventDatabase interface {
 	ListEventsByNodeId(nodeID string, pageSize, pageOffset int32) ([]*servicev1.Event, int32, error)
 	CheckNodeAccess(nodeID, userID string) (bool, error)
 	CountEventsForUser(userID string) (int64, error)
+	SearchEvents(params database.EventSearchParams) ([]*servicev1.EventSearchResult, error)
 }
 
+// Limits for event search requests
+const (
+	maxSearchQueryLength = 500
+	maxSearchLimit       = 100
+)
+
 type EventService struct {
-	db         EventDatabase
+	db          EventDatabase
 	broadcaster *EventBroadcaster
+	embedder    *models.Embedder // Embeds search queries (nil = keyword search only)
 }
 
 func NewEventService(db EventDatabase) *EventService {
@@ -38,6 +49,11 @@ func (s *EventService) GetBroadcaster() *EventBroadcaster {
 	return s.broadcaster
 }
 
+// SetEmbedder enables semantic search with the model that embeds event descriptions
+func (s *EventService) SetEmbedder(embedder *models.Embedder) {
+	s.embedder = embedder
+}
+
 // ListEventsByNodeId retrieves events for all services in a node with pagination
 func (s *EventService) ListEventsByNodeId(ctx context.Context, req *connect.Request[servicev1.ListEventsByNodeIdRequest]) (*connect.Response[servicev1.ListEventsByNodeIdResponse], error) {
 	if req.Msg.NodeId == "" {
@@ -81,6 +97,59 @@ func (s *EventService) CountEventsForUser(ctx context.Context, req *connect.Requ
 	}), nil
 }
 
+// SearchEvents ranks the user's VLM events by keyword match and semantic similarity to a query
+func (s *EventService) SearchEvents(ctx context.Context, req *connect.Request[servicev1.SearchEventsRequest]) (*connect.Response[servicev1.SearchEventsResponse], error) {
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("not authenticated"))
+	}
+
+	query := strings.TrimSpace(req.Msg.Query)
+	if query == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("query is required"))
+	}
+	if len(query) > maxSearchQueryLength {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("query must be at most %d characters", maxSearchQueryLength))
+	}
+
+	if req.Msg.NodeId != "" {
+		if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := int(req.Msg.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+	limit = min(limit, maxSearchLimit)
+
+	params := database.EventSearchParams{
+		Query:  query,
+		UserID: userID,
+		NodeID: req.Msg.NodeId,
+		Limit:  limit,
+	}
+	if s.embedder != nil {
+		embedding, err := s.embedder.EmbedOne(ctx, query)
+		if err != nil {
+			log.Printf("[EventService] Failed to embed search query, using keywords only: %v", err)
+		} else {
+			params.Embedding = embedding
+		}
+	}
+
+	results, err := s.db.SearchEvents(params)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to search events: %w", err))
+	}
+
+	return connect.NewResponse(&servicev1.SearchEventsResponse{
+		Results:  results,
+		Semantic: params.Embedding != nil,
+	}), nil
+}
+
 // StreamEventsByNodeId streams events in real-time for a node
 func (s *EventService) StreamEventsByNodeId(ctx context.Context, req *connect.Request[servicev1.StreamEventsByNodeIdRequest], stream *connect.ServerStream[servicev1.StreamEventsByNodeIdResponse]) error {
 	nodeID := req.Msg.NodeId
@@ -16,11 +16,15 @@ type EventSubscription struct {
 	CancelFunc context.CancelFunc
 }
 
+// EventListener receives every broadcast event; it must not block
+type EventListener func(event *servicev1.Event, nodeID string)
+
 // EventBroadcaster manages event broadcasting to connected clients
 type EventBroadcaster struct {
 	mu            sync.RWMutex
 	subscriptions map[string][]*EventSubscription // nodeID -> subscriptions
 	allSubs       map[*EventSubscription]string    // reverse lookup: sub -> nodeID
+	listeners     []EventListener                  // Server-side consumers of all events
 }
 
 // NewEventBroadcaster creates a new event broadcaster
@@ -79,11 +83,22 @@ func (b *EventBroadcaster) Unsubscribe(sub *EventSubscription) {
 	log.Printf("[EventBroadcaster] Subscription removed: node=%s", nodeID)
 }
 
+// AddListener registers a function called with every broadcast event
+func (b *EventBroadcaster) AddListener(listener EventListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
 // Broadcast sends an event to all matching subscriptions
 func (b *EventBroadcaster) Broadcast(event *servicev1.Event, nodeID string) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
+	for _, listener := range b.listeners {
+		listener(event, nodeID)
+	}
+
 	subs := b.subscriptions[nodeID]
 	if len(subs) == 0 {
 		return
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/timeutil"
+	"unblink/server/models"
+)
+
+const (
+	// summarizerInterval is how often finished periods are rolled up
+	summarizerInterval = 5 * time.Minute
+
+	// summarizerGrace is how long after a period ends its last events may still arrive
+	summarizerGrace = 5 * time.Minute
+
+	// summarizerLookback limits how far back periods are rolled up. Each level
+	// builds on the one below, so all levels share it.
+	summarizerLookback = 9 * 24 * time.Hour
+
+	// summarizerModelTimeout bounds one fast-model call
+	summarizerModelTimeout = 60 * time.Second
+
+	// summarizerMaxLabels is the number of object labels kept in a summary
+	summarizerMaxLabels = 20
+
+	// defaultSummarizerTokenBudget is the input budget per call when the fast
+	// model's context length is unknown
+	defaultSummarizerTokenBudget = 6000
+)
+
+const summarizerSystemPrompt = "You summarize what a security camera saw over a period, from descriptions of shorter periods in time order. " +
+	"Keep what matters: people, vehicles and animals, arrivals and departures, deliveries, and anything unusual, with their local times. " +
+	"Merge repeated observations of the same scene. Do not invent anything. Answer with the summary only, in at most two paragraphs."
+
+// rollupLevel is one level of the summary hierarchy: its periods are merged
+// from the events of the level below
+type rollupLevel struct {
+	granularity timeutil.GranularityLevel
+	child       timeutil.GranularityLevel // "" = events written by the batch manager
+	start       func(time.Time) time.Time // Start of the period containing a time (local time)
+	next        func(time.Time) time.Time // Start of the following period
+	timeLayout  string                    // How child times are shown to the model
+}
+
+// rollupLevels are processed in order, so each level sees the summaries just written below it
+var rollupLevels = []rollupLevel{
+	{
+		granularity: timeutil.GranularityHour,
+		// Stepping back from t instead of time.Date keeps the two 02:00 hours
+		// apart when clocks go back; time.Date would pick one of them
+		start: func(t time.Time) time.Time {
+			t = t.Local()
+			return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+		},
+		next: func(t time.Time) time.Time {
+			return t.Add(time.Hour)
+		},
+		timeLayout: "15:04:05",
+	},
+	{
+		granularity: timeutil.GranularityDay,
+		child:       timeutil.GranularityHour,
+		start:       startOfDay,
+		next: func(t time.Time) time.Time {
+			return t.AddDate(0, 0, 1)
+		},
+		timeLayout: "15:04",
+	},
+	{
+		granularity: timeutil.GranularityWeek,
+		child:       timeutil.GranularityDay,
+		start: func(t time.Time) time.Time {
+			day := startOfDay(t)
+			return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // Weeks start on Monday
+		},
+		next: func(t time.Time) time.Time {
+			return t.AddDate(0, 0, 7)
+		},
+		timeLayout: "Monday 2006-01-02",
+	},
+}
+
+// startOfDay returns local midnight of the day containing t
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// SummarizerDatabase defines the database operations of the event summarizer
+type SummarizerDatabase interface {
+	ListAllServices() ([]*servicev1.Service, error)
+	ListVLMEventStarts(serviceID string, from, to time.Time, rollup string) ([]time.Time, error)
+	ListRollupChildren(serviceID string, from, to time.Time, rollup string) ([]*database.RollupChild, error)
+	CreateEvent(id, serviceID string, payload *structpb.Struct) error
+}
+
+// EventSummarizer periodically merges VLM events into hour, day and week
+// summary events with the fast model
+type EventSummarizer struct {
+	db          SummarizerDatabase
+	broadcaster *EventBroadcaster
+
+	llm         *openai.Client // nil = summaries are disabled
+	model       string
+	tokenBudget int // Estimated input tokens per call
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEventSummarizer creates a summarizer; call Start to begin rolling up. The
+// fast model is looked up in the model registry; without it nothing happens.
+func NewEventSummarizer(db SummarizerDatabase, broadcaster *EventBroadcaster, modelRegistry *models.Registry, fastModel string) *EventSummarizer {
+	s := &EventSummarizer{
+		db:          db,
+		broadcaster: broadcaster,
+		model:       fastModel,
+		tokenBudget: defaultSummarizerTokenBudget,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	if cfg, err := modelRegistry.GetConfig(fastModel); err == nil && cfg.APIKey != "" {
+		opts := []option.RequestOption{option.WithAPIKey(cfg.APIKey)}
+		if cfg.BaseURL != "" {
+			opts = append(opts, option.WithBaseURL(cfg.BaseURL))
+		}
+		client := openai.NewClient(opts...)
+		s.llm = &client
+	}
+
+	// Leave half of the context for the prompt overhead and the answer
+	if maxTokens, err := modelRegistry.GetMaxTokens(fastModel); err == nil && maxTokens > 0 {
+		s.tokenBudget = maxTokens / 2
+	}
+
+	return s
+}
+
+// Start rolls up finished periods now and every summarizerInterval
+func (s *EventSummarizer) Start() {
+	if s.llm == nil {
+		log.Printf("[EventSummarizer] Fast model %q not available, summaries are disabled", s.model)
+		close(s.done)
+		return
+	}
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(summarizerInterval)
+		defer ticker.Stop()
+
+		for {
+			s.runOnce()
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the summarizer and waits for the current run to finish
+func (s *EventSummarizer) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// runOnce rolls up the finished periods of every service
+func (s *EventSummarizer) runOnce() {
+	services, err := s.db.ListAllServices()
+	if err != nil {
+		log.Printf("[EventSummarizer] Failed to list services: %v", err)
+		return
+	}
+
+	total := 0
+	for _, svc := range services {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		created, err := s.rollupService(svc)
+		total += created
+		if err != nil {
+			log.Printf("[EventSummarizer] Failed to summarize service %s: %v", svc.Id, err)
+		}
+	}
+
+	if total > 0 {
+		log.Printf("[EventSummarizer] Created %d summaries", total)
+	}
+}
+
+// rollupService writes the missing summaries of one service, level by level.
+// A failure stops the levels above, so no summary is built on a missing child.
+func (s *EventSummarizer) rollupService(svc *servicev1.Service) (int, error) {
+	now := time.Now()
+	horizon := now.Add(-summarizerLookback)
+	created := 0
+
+	for _, level := range rollupLevels {
+		periods, err := s.pendingPeriods(svc.Id, level, horizon, now)
+		if err != nil {
+			return created, err
+		}
+
+		for _, start := range periods {
+			end := level.next(start)
+			if err := s.summarizePeriod(svc, level, start, end); err != nil {
+				return created, fmt.Errorf("%s from %s: %w", level.granularity, timeutil.FormatToISO(start), err)
+			}
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// pendingPeriods lists the finished periods of a level that have child events
+// but no summary yet, oldest first
+func (s *EventSummarizer) pendingPeriods(serviceID string, level rollupLevel, horizon, now time.Time) ([]time.Time, error) {
+	childStarts, err := s.db.ListVLMEventStarts(serviceID, horizon, now, string(level.child))
+	if err != nil {
+		return nil, err
+	}
+	if len(childStarts) == 0 {
+		return nil, nil
+	}
+
+	summarized, err := s.db.ListVLMEventStarts(serviceID, horizon, now, string(level.granularity))
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int64]bool, len(summarized))
+	for _, t := range summarized {
+		done[t.Unix()] = true
+	}
+
+	seen := make(map[int64]bool)
+	var periods []time.Time
+	for _, t := range childStarts {
+		start := level.start(t)
+		if seen[start.Unix()] || done[start.Unix()] {
+			continue
+		}
+		seen[start.Unix()] = true
+
+		// Skip periods that are still open or reach past the lookback, whose
+		// children may be incomplete
+		if start.Before(horizon) || level.next(start).Add(summarizerGrace).After(now) {
+			continue
+		}
+		periods = append(periods, start)
+	}
+
+	slices.SortFunc(periods, func(a, b time.Time) int { return a.Compare(b) })
+	return periods, nil
+}
+
+// summarizePeriod merges the child events of one period into a summary event
+func (s *EventSummarizer) summarizePeriod(svc *servicev1.Service, level rollupLevel, start, end time.Time) error {
+	children, err := s.db.ListRollupChildren(svc.Id, start, end, string(level.child))
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(children))
+	labelCounts := make(map[string]int)
+	for _, child := range children {
+		line := fmt.Sprintf("[%s] %s", child.From.Local().Format(level.timeLayout), child.Description)
+		if len(child.Labels) > 0 {
+			line += " (objects: " + strings.Join(child.Labels, ", ") + ")"
+		}
+		lines = append(lines, line)
+
+		for _, label := range child.Labels {
+			labelCounts[strings.ToLower(label)]++
+		}
+	}
+
+	period := fmt.Sprintf("%s, %s to %s", level.granularity, start.Format("Monday 2006-01-02 15:04"), end.Format("Monday 2006-01-02 15:04"))
+	description, err := s.summarize(svc.Name, period, lines)
+	if err != nil {
+		return err
+	}
+
+	objects := make([]any, 0, summarizerMaxLabels)
+	for i, label := range topLabels(labelCounts, summarizerMaxLabels) {
+		objects = append(objects, map[string]any{"id": i + 1, "label": label, "bbox": []any{}})
+	}
+
+	// Same shape as the events of the batch manager, marked as a rollup
+	payload, err := structpb.NewStruct(map[string]any{
+		"type":        "vlm-indexing",
+		"granularity": string(level.granularity),
+		"from_iso":    timeutil.FormatToISO(start),
+		"to_iso":      timeutil.FormatToISO(end),
+		"rollup":      true,
+		"child_count": len(children),
+		"response": map[string]any{
+			"description": description,
+			"objects":     objects,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create payload: %w", err)
+	}
+
+	eventID := uuid.New().String()
+	if err := s.db.CreateEvent(eventID, svc.Id, payload); err != nil {
+		return err
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.Broadcast(&servicev1.Event{
+			Id:        eventID,
+			ServiceId: svc.Id,
+			Payload:   payload,
+			CreatedAt: timestamppb.New(time.Now()),
+		}, svc.NodeId)
+	}
+	return nil
+}
+
+// summarize merges descriptions into one summary. Descriptions beyond the
+// token budget are summarized in chunks first, then the chunk summaries merged.
+func (s *EventSummarizer) summarize(camera, period string, lines []string) (string, error) {
+	for {
+		chunks := chunkLines(lines, s.tokenBudget)
+		if len(chunks) == 1 {
+			return s.complete(camera, period, chunks[0])
+		}
+
+		partials := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			partial, err := s.complete(camera, fmt.Sprintf("%s (part %d of %d)", period, i+1, len(chunks)), chunk)
+			if err != nil {
+				return "", err
+			}
+			partials = append(partials, fmt.Sprintf("[part %d] %s", i+1, partial))
+		}
+		lines = partials
+	}
+}
+
+// complete asks the fast model for the summary of one chunk of descriptions
+func (s *EventSummarizer) complete(camera, period string, lines []string) (string, error) {
+	prompt := fmt.Sprintf("Camera: %s\nPeriod: %s\n\nDescriptions:\n%s", camera, period, strings.Join(lines, "\n"))
+
+	params := openai.ChatCompletionNewParams{
+		Model: openai.ChatModel(s.model),
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(summarizerSystemPrompt),
+			openai.UserMessage(prompt),
+		},
+		MaxTokens: openai.Int(1000),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), summarizerModelTimeout)
+	defer cancel()
+
+	response, err := s.llm.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("empty response")
+	}
+
+	summary := strings.TrimSpace(response.Choices[0].Message.Content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
+
+// chunkLines splits lines into chunks of at most budget estimated tokens each.
+// Every chunk holds at least two lines, so repeated chunking always converges.
+func chunkLines(lines []string, budget int) [][]string {
+	var chunks [][]string
+	var current []string
+	tokens := 0
+	for _, line := range lines {
+		lineTokens := models.EstimateTokens(line)
+		if len(current) >= 2 && tokens+lineTokens > budget {
+			chunks = append(chunks, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, line)
+		tokens += lineTokens
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// topLabels returns up to n labels, most frequent first
+func topLabels(counts map[string]int, n int) []string {
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	slices.SortFunc(labels, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if len(labels) > n {
+		labels = labels[:n]
+	}
+	return labels
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // Europe/Amsterdam on systems without a zone database
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"unblink/server/internal/timeutil"
+)
+
+// fakeSummarizerDatabase holds the start times of VLM events by rollup level
+// ("" = batch manager events)
+type fakeSummarizerDatabase struct {
+	SummarizerDatabase
+	starts map[string][]time.Time
+}
+
+func (f *fakeSummarizerDatabase) ListVLMEventStarts(serviceID string, from, to time.Time, rollup string) ([]time.Time, error) {
+	var result []time.Time
+	for _, t := range f.starts[rollup] {
+		if !t.Before(from) && !t.After(to) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// withLocal runs a test with time.Local set to a zone
+func withLocal(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = local })
+	return loc
+}
+
+func rollupLevelOf(t *testing.T, granularity timeutil.GranularityLevel) rollupLevel {
+	for _, level := range rollupLevels {
+		if level.granularity == granularity {
+			return level
+		}
+	}
+	t.Fatalf("no rollup level %s", granularity)
+	return rollupLevel{}
+}
+
+func TestPendingPeriods(t *testing.T) {
+	loc := withLocal(t, "Europe/Amsterdam")
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, 10, 14, hour, min, 0, 0, loc)
+	}
+	hour := rollupLevelOf(t, timeutil.GranularityHour)
+	now := at(13, 3)
+
+	db := &fakeSummarizerDatabase{starts: map[string][]time.Time{
+		"": {at(10, 5), at(10, 40), at(11, 10), at(12, 50)},
+	}}
+	s := &EventSummarizer{db: db}
+
+	// 12:00-13:00 is still within the grace period
+	periods, err := s.pendingPeriods("cam", hour, now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{at(10, 0), at(11, 0)}, periods)
+
+	// Summarized periods are not pending
+	db.starts["hour"] = []time.Time{at(10, 0)}
+	periods, err = s.pendingPeriods("cam", hour, now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{at(11, 0)}, periods)
+
+	// Periods starting before the horizon may have lost children
+	db.starts["hour"] = nil
+	periods, err = s.pendingPeriods("cam", hour, at(10, 30), now)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{at(11, 0)}, periods)
+
+	// Nothing to summarize
+	periods, err = s.pendingPeriods("cam", rollupLevelOf(t, timeutil.GranularityDay), now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+	assert.Empty(t, periods)
+}
+
+func TestPendingPeriodsFallBack(t *testing.T) {
+	loc := withLocal(t, "Europe/Amsterdam")
+	hour := rollupLevelOf(t, timeutil.GranularityHour)
+
+	// On 25 October 2026 clocks go back from 03:00 CEST to 02:00 CET, so
+	// 02:00-03:00 local time happens twice
+	utc := func(hour, min int) time.Time {
+		return time.Date(2026, 10, 25, hour, min, 0, 0, time.UTC)
+	}
+	db := &fakeSummarizerDatabase{starts: map[string][]time.Time{
+		"": {
+			utc(0, 30), // 02:30 CEST
+			utc(1, 30), // 02:30 CET
+			utc(2, 30), // 03:30 CET
+		},
+	}}
+	s := &EventSummarizer{db: db}
+
+	now := utc(6, 0)
+	periods, err := s.pendingPeriods("cam", hour, now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+
+	// Each 02:xx hour is a period of its own
+	require.Len(t, periods, 3)
+	for i, want := range []time.Time{utc(0, 0), utc(1, 0), utc(2, 0)} {
+		assert.True(t, periods[i].Equal(want), "period %d is %v", i, periods[i])
+		assert.Equal(t, time.Hour, hour.next(periods[i]).Sub(periods[i]))
+	}
+	assert.Equal(t, []int{2, 2, 3}, []int{periods[0].In(loc).Hour(), periods[1].In(loc).Hour(), periods[2].In(loc).Hour()})
+
+	// Summarizing the first 02:00 leaves the second pending
+	db.starts["hour"] = []time.Time{periods[0]}
+	periods, err = s.pendingPeriods("cam", hour, now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.True(t, periods[0].Equal(utc(1, 0)))
+
+	// The day lasts 25 hours
+	day := rollupLevelOf(t, timeutil.GranularityDay)
+	start := day.start(utc(12, 0))
+	assert.Equal(t, 25*time.Hour, day.next(start).Sub(start))
+}
+
+func TestPendingPeriodsSpringForward(t *testing.T) {
+	loc := withLocal(t, "Europe/Amsterdam")
+	hour := rollupLevelOf(t, timeutil.GranularityHour)
+
+	// On 29 March 2026 clocks go forward from 02:00 CET to 03:00 CEST, so
+	// there is no 02:00-03:00 local time
+	utc := func(hour, min int) time.Time {
+		return time.Date(2026, 3, 29, hour, min, 0, 0, time.UTC)
+	}
+	db := &fakeSummarizerDatabase{starts: map[string][]time.Time{
+		"": {
+			utc(0, 30), // 01:30 CET
+			utc(1, 30), // 03:30 CEST
+		},
+	}}
+	s := &EventSummarizer{db: db}
+
+	now := utc(6, 0)
+	periods, err := s.pendingPeriods("cam", hour, now.Add(-summarizerLookback), now)
+	require.NoError(t, err)
+
+	require.Len(t, periods, 2)
+	assert.True(t, periods[0].Equal(utc(0, 0)), "first period %v", periods[0])
+	assert.True(t, periods[1].Equal(utc(1, 0)), "second period %v", periods[1])
+	assert.True(t, hour.next(periods[0]).Equal(periods[1]), "an hour apart")
+	assert.Equal(t, 3, periods[1].In(loc).Hour())
+
+	// The day lasts 23 hours
+	day := rollupLevelOf(t, timeutil.GranularityDay)
+	start := day.start(utc(12, 0))
+	assert.Equal(t, 23*time.Hour, day.next(start).Sub(start))
+}
+
+func TestWeekStartsOnMonday(t *testing.T) {
+	loc := withLocal(t, "Europe/Amsterdam")
+	week := rollupLevelOf(t, timeutil.GranularityWeek)
+
+	// Sunday 25 October 2026, the day clocks go back
+	start := week.start(time.Date(2026, 10, 25, 23, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, loc), start)
+	assert.Equal(t, time.Date(2026, 10, 26, 0, 0, 0, 0, loc), week.next(start))
+}
+
+func TestChunkLines(t *testing.T) {
+	line := strings.Repeat("x", 30) // 10 estimated tokens
+
+	assert.Empty(t, chunkLines(nil, 100))
+	assert.Equal(t, [][]string{{line, line, line}}, chunkLines([]string{line, line, line}, 30))
+
+	chunks := chunkLines([]string{line, line, line, line, line}, 20)
+	assert.Equal(t, [][]string{{line, line}, {line, line}, {line}}, chunks)
+
+	// Every chunk takes at least two lines, even over budget
+	long := strings.Repeat("y", 300)
+	chunks = chunkLines([]string{long, long, long}, 20)
+	assert.Equal(t, [][]string{{long, long}, {long}}, chunks)
+}
+
+func TestChunkLinesConverges(t *testing.T) {
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = strings.Repeat("z", 120)
+	}
+	for rounds := 0; len(lines) > 1; rounds++ {
+		require.Less(t, rounds, 10)
+		chunks := chunkLines(lines, 10)
+		lines = make([]string, len(chunks)) // One partial summary per chunk
+		for i := range lines {
+			lines[i] = strings.Repeat("z", 120)
+		}
+	}
+}
+
+func TestTopLabels(t *testing.T) {
+	counts := map[string]int{"car": 3, "person": 5, "dog": 1, "bicycle": 3}
+
+	assert.Equal(t, []string{"person", "bicycle", "car", "dog"}, topLabels(counts, 10))
+	// Ties are broken alphabetically
+	assert.Equal(t, []string{"person", "bicycle"}, topLabels(counts, 2))
+	assert.Empty(t, topLabels(nil, 5))
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	"github.com/openai/openai-go/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/webrtc"
+)
+
+const (
+	defaultReanalysisFrames = 48
+	maxReanalysisFrames     = 120
+	maxReanalysisRange      = 24 * time.Hour
+	reanalysisBatchSize     = 8 // Frames per VLM request
+)
+
+// reanalysisInstruction is sent with every batch; findings of earlier batches
+// are carried over as text, like the rolling context of the batch manager
+const reanalysisInstruction = `Question: %s
+
+The images are stored frames of the security camera %q, in time order, each with its capture time burned in:
+%s
+Findings from earlier frames of the same range:
+%s
+
+List the images that bear on the question and what each shows about it (leave out unrelated images). Then answer the question from these images together with the earlier findings. If the frames do not show enough to answer, say so.`
+
+// reanalysisResponse is the structured output of one batch
+type reanalysisResponse struct {
+	Observations []reanalysisObservation `json:"observations" jsonschema_description:"Images that bear on the question, with what each shows about it"`
+	Answer       string                  `json:"answer" jsonschema_description:"Answer to the question given these images and the earlier findings"`
+}
+
+type reanalysisObservation struct {
+	Image       int    `json:"image" jsonschema_description:"Number of the image (1 = first image)"`
+	Observation string `json:"observation" jsonschema_description:"What the image shows that bears on the question"`
+}
+
+// FrameAnalyzerDatabase defines the database operations of the frame analyzer
+type FrameAnalyzerDatabase interface {
+	SampleFramesInRange(serviceID string, from, to time.Time, n int) ([]*database.StorageEntry, int64, error)
+	GetService(id string) (*servicev1.Service, error)
+}
+
+// FrameAnalyzer answers new questions about stored frames
+type FrameAnalyzer struct {
+	db      FrameAnalyzerDatabase
+	backend webrtc.StorageBackend
+	client  *webrtc.FrameClient
+	schema  openai.ResponseFormatJSONSchemaJSONSchemaParam
+}
+
+// NewFrameAnalyzer creates a frame analyzer reading frames from a storage backend
+func NewFrameAnalyzer(db FrameAnalyzerDatabase, backend webrtc.StorageBackend, client *webrtc.FrameClient) *FrameAnalyzer {
+	reflector := jsonschema.Reflector{
+		AllowAdditionalProperties: false,
+		DoNotReference:            true,
+	}
+
+	return &FrameAnalyzer{
+		db:      db,
+		backend: backend,
+		client:  client,
+		schema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:        "frame_reanalysis",
+			Description: openai.String("Answer to a question about stored camera frames, citing the images it is based on"),
+			Schema:      reflector.Reflect(reanalysisResponse{}),
+			Strict:      openai.Bool(true),
+		},
+	}
+}
+
+// Analyze asks a question about the stored frames of a service in [from, to).
+// Up to maxFrames frames (0 = default) are sampled evenly from the range.
+func (a *FrameAnalyzer) Analyze(ctx context.Context, serviceID string, from, to time.Time, question string, maxFrames int) (*servicev1.ReanalyzeFramesResponse, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, fmt.Errorf("question is required")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if to.Sub(from) > maxReanalysisRange {
+		return nil, fmt.Errorf("time range must be at most %v", maxReanalysisRange)
+	}
+	if maxFrames <= 0 {
+		maxFrames = defaultReanalysisFrames
+	}
+	maxFrames = min(maxFrames, maxReanalysisFrames)
+
+	svc, err := a.db.GetService(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("service not found: %w", err)
+	}
+
+	// Sampled in the database, so long ranges are covered from start to end
+	sampled, total, err := a.db.SampleFramesInRange(serviceID, from, to, maxFrames)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &servicev1.ReanalyzeFramesResponse{FramesInRange: int32(total)}
+	if len(sampled) == 0 {
+		result.Answer = "There are no stored frames in this time range."
+		return result, nil
+	}
+
+	findings := "(none yet)"
+	for start := 0; start < len(sampled); start += reanalysisBatchSize {
+		batch := sampled[start:min(start+reanalysisBatchSize, len(sampled))]
+
+		frames, used := a.loadFrames(ctx, batch)
+		if len(frames) == 0 {
+			continue
+		}
+
+		var times strings.Builder
+		for i, entry := range used {
+			fmt.Fprintf(&times, "%d: %s\n", i+1, entry.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		}
+		instruction := fmt.Sprintf(reanalysisInstruction, question, svc.Name, times.String(), findings)
+
+		response, err := a.client.SendFrameBatchWithSchema(ctx, frames, instruction, "", a.schema)
+		if err != nil {
+			return nil, fmt.Errorf("frame analysis failed: %w", err)
+		}
+		if len(response.Choices) == 0 {
+			return nil, fmt.Errorf("frame analysis returned no answer")
+		}
+
+		var batchResp reanalysisResponse
+		if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &batchResp); err != nil {
+			return nil, fmt.Errorf("failed to parse frame analysis: %w", err)
+		}
+
+		for _, obs := range batchResp.Observations {
+			if obs.Image < 1 || obs.Image > len(used) {
+				continue
+			}
+			entry := used[obs.Image-1]
+			result.Citations = append(result.Citations, &servicev1.FrameCitation{
+				FrameId:     entry.ID,
+				Timestamp:   timestamppb.New(entry.Timestamp),
+				Observation: obs.Observation,
+			})
+		}
+		result.FramesAnalyzed += int32(len(frames))
+		result.Answer = batchResp.Answer
+		if batchResp.Answer != "" {
+			findings = batchResp.Answer
+		}
+	}
+
+	if result.FramesAnalyzed == 0 {
+		return nil, fmt.Errorf("none of the %d frames could be read", len(sampled))
+	}
+
+	slices.SortStableFunc(result.Citations, func(x, y *servicev1.FrameCitation) int {
+		return x.Timestamp.AsTime().Compare(y.Timestamp.AsTime())
+	})
+
+	log.Printf("[FrameAnalyzer] Analyzed %d/%d frames of service %s (%d citations)", result.FramesAnalyzed, total, serviceID, len(result.Citations))
+	return result, nil
+}
+
+// loadFrames reads the bytes of stored frames, skipping those that cannot be read
+func (a *FrameAnalyzer) loadFrames(ctx context.Context, entries []*database.StorageEntry) ([]*webrtc.Frame, []*database.StorageEntry) {
+	var frames []*webrtc.Frame
+	var used []*database.StorageEntry
+	for _, entry := range entries {
+		data, err := a.backend.Get(ctx, entry.StoragePath)
+		if err != nil {
+			log.Printf("[FrameAnalyzer] Failed to read frame %s: %v", entry.ID, err)
+			continue
+		}
+		frames = append(frames, &webrtc.Frame{Data: data, Timestamp: entry.Timestamp, ServiceID: entry.ServiceID})
+		used = append(used, entry)
+	}
+	return frames, used
+}
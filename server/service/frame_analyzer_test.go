@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+// fakeFrameDatabase records the sampling request and has no stored frames
+type fakeFrameDatabase struct {
+	from, to time.Time
+	n        int
+}
+
+func (f *fakeFrameDatabase) SampleFramesInRange(serviceID string, from, to time.Time, n int) ([]*database.StorageEntry, int64, error) {
+	f.from, f.to, f.n = from, to, n
+	return nil, 0, nil
+}
+
+func (f *fakeFrameDatabase) GetService(id string) (*servicev1.Service, error) {
+	return &servicev1.Service{Id: id, Name: "Garage"}, nil
+}
+
+func TestFrameAnalyzerSamplesWholeRange(t *testing.T) {
+	db := &fakeFrameDatabase{}
+	a := NewFrameAnalyzer(db, nil, nil)
+
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		maxFrames int
+		want      int
+	}{
+		{0, defaultReanalysisFrames},
+		{10, 10},
+		{1000, maxReanalysisFrames},
+	}
+	for _, tt := range tests {
+		result, err := a.Analyze(context.Background(), "cam", from, to, "Was the door open?", tt.maxFrames)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, db.n, "maxFrames %d", tt.maxFrames)
+		assert.True(t, db.from.Equal(from))
+		assert.True(t, db.to.Equal(to))
+		assert.Zero(t, result.FramesInRange)
+		assert.Equal(t, "There are no stored frames in this time range.", result.Answer)
+	}
+}
+
+func TestFrameAnalyzerValidation(t *testing.T) {
+	a := NewFrameAnalyzer(&fakeFrameDatabase{}, nil, nil)
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	_, err := a.Analyze(context.Background(), "cam", from, from.Add(time.Hour), "  ", 0)
+	assert.Error(t, err)
+	_, err = a.Analyze(context.Background(), "cam", from, from, "Was the door open?", 0)
+	assert.Error(t, err)
+	_, err = a.Analyze(context.Background(), "cam", from, from.Add(25*time.Hour), "Was the door open?", 0)
+	assert.Error(t, err)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // ServiceHandler encapsulates all components needed to handle an active service
-// It manages the bridge connection, media source, and frame extraction for one service
+// It manages the bridge connection, media source, frame extraction and recording for one service
 type ServiceHandler struct {
 	serviceID string
 	url       string
@@ -23,8 +23,9 @@ type ServiceHandler struct {
 	bridgeConn   *server.BridgeConn
 	mediaSource  webrtc.MediaSource
 	extractor    *webrtc.FrameExtractor
-	producer     core.Producer  // Track producer for cleanup
-	producerDone chan struct{}  // Signal when producer goroutine exits
+	recorder     *webrtc.VideoRecorder
+	producer     core.Producer // Track producer for cleanup
+	producerDone chan struct{} // Signal when producer goroutine exits
 
 	// Shared infrastructure (injected)
 	storage      *webrtc.Storage
@@ -32,7 +33,13 @@ type ServiceHandler struct {
 	srv          *server.Server
 
 	// Configuration
-	frameInterval time.Duration
+	frameInterval   time.Duration
+	enableIndexing  bool
+	batchSettings   webrtc.BatchSettings
+	enableRecording bool
+	segmentsDir     string
+	segmentDuration time.Duration
+	onSegment       func(*webrtc.Segment)
 
 	// Context for cancellation
 	ctx    context.Context
@@ -48,21 +55,39 @@ type ServiceHandlerConfig struct {
 	Storage       *webrtc.Storage
 	BatchManager  *webrtc.BatchManager
 	Server        *server.Server
+
+	// Feature toggles
+	EnableIndexing  bool
+	EnableRecording bool
+
+	// Per-service overrides for the batch manager (used when EnableIndexing is set)
+	BatchSettings webrtc.BatchSettings
+
+	// Recording settings (used when EnableRecording is set)
+	SegmentsDir     string
+	SegmentDuration time.Duration
+	OnSegment       func(*webrtc.Segment) // Called for every finished segment
 }
 
 // NewServiceHandler creates a new service handler
 func NewServiceHandler(cfg ServiceHandlerConfig) *ServiceHandler {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &ServiceHandler{
-		serviceID:     cfg.ServiceID,
-		url:           cfg.URL,
-		nodeID:        cfg.NodeID,
-		frameInterval: cfg.FrameInterval,
-		storage:       cfg.Storage,
-		batchManager:  cfg.BatchManager,
-		srv:           cfg.Server,
-		ctx:           ctx,
-		cancel:        cancel,
+		serviceID:       cfg.ServiceID,
+		url:             cfg.URL,
+		nodeID:          cfg.NodeID,
+		frameInterval:   cfg.FrameInterval,
+		enableIndexing:  cfg.EnableIndexing,
+		batchSettings:   cfg.BatchSettings,
+		enableRecording: cfg.EnableRecording,
+		segmentsDir:     cfg.SegmentsDir,
+		segmentDuration: cfg.SegmentDuration,
+		onSegment:       cfg.OnSegment,
+		storage:         cfg.Storage,
+		batchManager:    cfg.BatchManager,
+		srv:             cfg.Server,
+		ctx:             ctx,
+		cancel:          cancel,
 	}
 }
 
@@ -86,7 +111,8 @@ func (h *ServiceHandler) Start() error {
 	h.bridgeConn = server.NewBridgeConn(nodeConn, bridgeID, dataChan)
 
 	// Create media source
-	h.mediaSource, err = webrtc.NewMediaSource(h.url, h.serviceID, h.bridgeConn)
+	listenUDP := webrtc.NewBridgePacketListener(h.ctx, nodeConn, h.serviceID, h.url)
+	h.mediaSource, err = webrtc.NewMediaSource(h.url, h.serviceID, h.bridgeConn, listenUDP)
 	if err != nil {
 		h.bridgeConn.Close()
 		ctx := context.Background()
@@ -108,6 +134,37 @@ func (h *ServiceHandler) Start() error {
 	}()
 
 	// Create and start extractor
+	if h.enableIndexing {
+		if err := h.startExtractor(); err != nil {
+			h.closeSource(nodeConn, bridgeID)
+			return fmt.Errorf("failed to start extractor: %w", err)
+		}
+	}
+
+	// Create and start recorder
+	if h.enableRecording {
+		h.recorder = webrtc.NewVideoRecorder(h.serviceID, h.segmentsDir, h.segmentDuration, h.onSegment)
+		if err := h.recorder.Start(h.mediaSource); err != nil {
+			h.recorder = nil
+			if h.extractor != nil {
+				h.extractor.Close()
+				h.extractor = nil
+			}
+			h.closeSource(nodeConn, bridgeID)
+			return fmt.Errorf("failed to start recorder: %w", err)
+		}
+	}
+
+	log.Printf("[ServiceHandler] Started handler for service %s", h.serviceID)
+	return nil
+}
+
+// startExtractor creates and starts the frame extractor
+func (h *ServiceHandler) startExtractor() error {
+	if h.batchManager != nil {
+		h.batchManager.SetServiceSettings(h.serviceID, h.batchSettings)
+	}
+
 	h.extractor = webrtc.NewFrameExtractor(h.serviceID, h.frameInterval, func(frame *webrtc.Frame) {
 		// Preprocess frame: resize to max 800px edge and burn in timestamp
 		preprocessedData, err := webrtc.PreprocessFrame(frame.Data, frame.Timestamp)
@@ -134,17 +191,58 @@ func (h *ServiceHandler) Start() error {
 	})
 
 	if err := h.extractor.Start(h.mediaSource); err != nil {
-		h.mediaSource.Close()
-		h.bridgeConn.Close()
-		ctx := context.Background()
-		nodeConn.CloseBridge(ctx, bridgeID)
-		return fmt.Errorf("failed to start extractor: %w", err)
+		h.extractor.Close()
+		h.extractor = nil
+		return err
 	}
+	return nil
+}
 
-	log.Printf("[ServiceHandler] Started handler for service %s", h.serviceID)
+// UpdateAnalysis applies new analysis settings without touching the bridge:
+// batch settings take effect with the next frame, and the extractor is
+// started, stopped or restarted for a new interval as needed
+func (h *ServiceHandler) UpdateAnalysis(enableIndexing bool, frameInterval time.Duration, batchSettings webrtc.BatchSettings) error {
+	intervalChanged := frameInterval != h.frameInterval
+	h.enableIndexing = enableIndexing
+	h.frameInterval = frameInterval
+	h.batchSettings = batchSettings
+
+	if h.extractor != nil && (!enableIndexing || intervalChanged) {
+		h.extractor.Close()
+		h.extractor = nil
+	}
+
+	if !enableIndexing {
+		log.Printf("[ServiceHandler] Indexing off for service %s", h.serviceID)
+		return nil
+	}
+	if h.extractor != nil {
+		if h.batchManager != nil {
+			h.batchManager.SetServiceSettings(h.serviceID, batchSettings)
+		}
+		return nil
+	}
+	if err := h.startExtractor(); err != nil {
+		return fmt.Errorf("failed to start extractor: %w", err)
+	}
+	log.Printf("[ServiceHandler] Indexing service %s every %v", h.serviceID, frameInterval)
 	return nil
 }
 
+// closeSource tears down the media source and bridge after a failed start
+func (h *ServiceHandler) closeSource(nodeConn *server.NodeConn, bridgeID string) {
+	if h.producer != nil {
+		h.producer.Stop()
+		<-h.producerDone
+		h.producer = nil
+	}
+	h.mediaSource.Close()
+	h.mediaSource = nil
+	h.bridgeConn.Close()
+	h.bridgeConn = nil
+	nodeConn.CloseBridge(context.Background(), bridgeID)
+}
+
 // Stop stops all service components and cleans up resources
 func (h *ServiceHandler) Stop() {
 	log.Printf("[ServiceHandler] Stopping handler for service %s", h.serviceID)
@@ -155,6 +253,12 @@ func (h *ServiceHandler) Stop() {
 		h.extractor = nil
 	}
 
+	// Stop recorder (finalizes the segment in progress)
+	if h.recorder != nil {
+		h.recorder.Close()
+		h.recorder = nil
+	}
+
 	// Stop producer and wait for goroutine to exit (BEFORE closing media source)
 	if h.producer != nil {
 		log.Printf("[ServiceHandler] Stopping producer for service %s", h.serviceID)
@@ -194,7 +298,35 @@ func (h *ServiceHandler) GetBridgeConn() *server.BridgeConn {
 	return h.bridgeConn
 }
 
+// IsIndexing returns true if frames are being extracted for indexing
+func (h *ServiceHandler) IsIndexing() bool {
+	return h.extractor != nil
+}
+
+// IsRecording returns true if segments are being recorded
+func (h *ServiceHandler) IsRecording() bool {
+	return h.recorder != nil
+}
+
+// ExtractorStats returns the last extracted frame time and the extraction rate
+// (zero values when indexing is off)
+func (h *ServiceHandler) ExtractorStats() (time.Time, float64) {
+	if h.extractor == nil {
+		return time.Time{}, 0
+	}
+	return h.extractor.Stats()
+}
+
+// LatestFrame returns the last frame extracted for indexing (nil when indexing
+// is off or no frame was extracted yet)
+func (h *ServiceHandler) LatestFrame() *webrtc.Frame {
+	if h.extractor == nil {
+		return nil
+	}
+	return h.extractor.LatestFrame()
+}
+
 // IsRunning returns true if the handler is currently running
 func (h *ServiceHandler) IsRunning() bool {
-	return h.bridgeConn != nil && h.extractor != nil
+	return h.bridgeConn != nil && (h.extractor != nil || h.recorder != nil)
 }
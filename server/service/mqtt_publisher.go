@@ -0,0 +1,407 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/timeutil"
+	"unblink/server/webrtc"
+)
+
+const (
+	// mqttResyncInterval is how often discovery and availability are compared
+	// against the registry, picking up added, renamed and removed services
+	mqttResyncInterval = 30 * time.Second
+
+	// mqttPublishTimeout bounds the wait for a broker acknowledgement
+	mqttPublishTimeout = 5 * time.Second
+)
+
+// Labels that switch a camera's presence sensors on (matched like alert rule labels)
+var (
+	mqttPersonLabels  = []string{"person", "people", "man", "woman", "child", "pedestrian"}
+	mqttVehicleLabels = []string{"vehicle", "car", "truck", "van", "bus", "motorcycle", "motorbike", "suv"}
+)
+
+// MQTTConfig holds the broker and topic settings of the MQTT publisher
+type MQTTConfig struct {
+	BrokerURL       string
+	Username        string
+	Password        string
+	ClientID        string
+	TopicPrefix     string // Root of the state and event topics
+	DiscoveryPrefix string // Home Assistant discovery prefix
+}
+
+// mqttMessage is a publish waiting in the queue
+type mqttMessage struct {
+	topic    string
+	payload  []byte
+	retained bool
+}
+
+// MQTTPublisher publishes events to MQTT and exposes every camera to Home
+// Assistant through MQTT discovery:
+//
+//	{prefix}/status                          server availability ("online"/"offline", last will)
+//	{prefix}/{node}/{service}/event          every event of the service
+//	{prefix}/{node}/{service}/alert          alert events only
+//	{prefix}/{node}/{service}/availability   "online" while the service is online
+//	{prefix}/{node}/{service}/person         "ON"/"OFF" from the last VLM result
+//	{prefix}/{node}/{service}/vehicle        "ON"/"OFF" from the last VLM result
+//	{prefix}/{node}/{service}/description    last VLM description (JSON)
+type MQTTPublisher struct {
+	cfg      MQTTConfig
+	client   mqtt.Client
+	registry *ServiceRegistry
+
+	queue  chan mqttMessage
+	resync chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+
+	// Owned by the worker
+	discovered map[string]ServiceInfo // serviceID -> service whose discovery config is published
+	retained   map[string][]byte      // topic -> last retained payload, replayed after a reconnect
+}
+
+// NewMQTTPublisher creates a publisher for the services of a registry; call
+// Start to connect
+func NewMQTTPublisher(cfg MQTTConfig, registry *ServiceRegistry) *MQTTPublisher {
+	p := &MQTTPublisher{
+		cfg:        cfg,
+		registry:   registry,
+		queue:      make(chan mqttMessage, 1000),
+		resync:     make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		discovered: make(map[string]ServiceInfo),
+		retained:   make(map[string][]byte),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetWill(p.statusTopic(), "offline", 1, true).
+		SetOnConnectHandler(func(mqtt.Client) {
+			log.Printf("[MQTTPublisher] Connected to %s", cfg.BrokerURL)
+			p.requestResync()
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("[MQTTPublisher] Connection lost: %v", err)
+		})
+	p.client = mqtt.NewClient(opts)
+
+	return p
+}
+
+// Start connects to the broker in the background and starts publishing
+func (p *MQTTPublisher) Start() {
+	// With connect retry on, the token only completes once connected
+	p.client.Connect()
+	go p.worker()
+	log.Printf("[MQTTPublisher] Started (broker=%s, prefix=%s, discovery=%s)", p.cfg.BrokerURL, p.cfg.TopicPrefix, p.cfg.DiscoveryPrefix)
+}
+
+// Stop marks the server offline and disconnects
+func (p *MQTTPublisher) Stop() {
+	close(p.stop)
+	<-p.done
+
+	if p.client.IsConnectionOpen() {
+		p.client.Publish(p.statusTopic(), 1, true, "offline").WaitTimeout(mqttPublishTimeout)
+	}
+	p.client.Disconnect(250)
+}
+
+// HandleEvent publishes a broadcast event (an EventListener)
+func (p *MQTTPublisher) HandleEvent(event *servicev1.Event, nodeID string) {
+	fields := event.GetPayload().AsMap()
+	eventType, _ := fields["type"].(string)
+
+	// Summary rollups repeat events that were published already
+	if rollup, _ := fields["rollup"].(bool); rollup {
+		return
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"id":         event.Id,
+		"type":       eventType,
+		"node_id":    nodeID,
+		"service_id": event.ServiceId,
+		"created_at": timeutil.FormatToISO(event.GetCreatedAt().AsTime()),
+		"payload":    fields,
+	})
+	if err != nil {
+		log.Printf("[MQTTPublisher] Failed to marshal event %s: %v", event.Id, err)
+		return
+	}
+
+	base := p.serviceTopic(nodeID, event.ServiceId)
+	p.enqueue(mqttMessage{topic: base + "/event", payload: body})
+
+	switch eventType {
+	case alertEventType:
+		p.enqueue(mqttMessage{topic: base + "/alert", payload: body})
+	case statusEventType:
+		to, _ := fields["to"].(string)
+		p.enqueue(mqttMessage{topic: base + "/availability", payload: []byte(mqttAvailability(to)), retained: true})
+	}
+}
+
+// HandleResult updates a camera's presence and description sensors from a
+// VLM result (a webrtc.ResultListener)
+func (p *MQTTPublisher) HandleResult(result *webrtc.BatchResult) {
+	person, vehicle := false, false
+	labels := make([]string, 0, len(result.Response.Objects))
+	for _, obj := range result.Response.Objects {
+		labels = append(labels, obj.Label)
+		person = person || matchesAnyLabel(obj.Label, mqttPersonLabels)
+		vehicle = vehicle || matchesAnyLabel(obj.Label, mqttVehicleLabels)
+	}
+
+	description, err := json.Marshal(map[string]any{
+		"description": result.Response.Description,
+		"objects":     labels,
+		"event_id":    result.EventID,
+		"from_iso":    timeutil.FormatToISO(result.From),
+		"to_iso":      timeutil.FormatToISO(result.To),
+	})
+	if err != nil {
+		log.Printf("[MQTTPublisher] Failed to marshal description: %v", err)
+		return
+	}
+
+	base := p.serviceTopic(result.NodeID, result.ServiceID)
+	p.enqueue(mqttMessage{topic: base + "/person", payload: []byte(mqttOnOff(person)), retained: true})
+	p.enqueue(mqttMessage{topic: base + "/vehicle", payload: []byte(mqttOnOff(vehicle)), retained: true})
+	p.enqueue(mqttMessage{topic: base + "/description", payload: description, retained: true})
+}
+
+func (p *MQTTPublisher) enqueue(msg mqttMessage) {
+	select {
+	case p.queue <- msg:
+	default:
+		log.Printf("[MQTTPublisher] Queue full, dropping message for %s", msg.topic)
+	}
+}
+
+func (p *MQTTPublisher) requestResync() {
+	select {
+	case p.resync <- struct{}{}:
+	default: // Already requested
+	}
+}
+
+func (p *MQTTPublisher) worker() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(mqttResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+
+		case msg := <-p.queue:
+			if msg.retained {
+				p.retained[msg.topic] = msg.payload
+			}
+			p.publish(msg)
+
+		case <-p.resync:
+			// Everything is republished after a (re)connect
+			p.publish(mqttMessage{topic: p.statusTopic(), payload: []byte("online"), retained: true})
+			for topic, payload := range p.retained {
+				p.publish(mqttMessage{topic: topic, payload: payload, retained: true})
+			}
+			p.syncServices(true)
+
+		case <-ticker.C:
+			p.syncServices(false)
+		}
+	}
+}
+
+// publish sends a message if connected; retained state is replayed after a
+// reconnect, so nothing is queued while offline
+func (p *MQTTPublisher) publish(msg mqttMessage) {
+	if !p.client.IsConnectionOpen() {
+		return
+	}
+
+	token := p.client.Publish(msg.topic, 1, msg.retained, msg.payload)
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		log.Printf("[MQTTPublisher] Publish to %s timed out", msg.topic)
+		return
+	}
+	if err := token.Error(); err != nil {
+		log.Printf("[MQTTPublisher] Failed to publish to %s: %v", msg.topic, err)
+	}
+}
+
+// syncServices publishes discovery configs for new or renamed services (all
+// services with force), removes those of deleted services and refreshes
+// availability
+func (p *MQTTPublisher) syncServices(force bool) {
+	if !p.client.IsConnectionOpen() {
+		return
+	}
+
+	current := make(map[string]bool)
+	for _, svc := range p.registry.ListServices() {
+		current[svc.ID] = true
+
+		if prev, ok := p.discovered[svc.ID]; force || !ok || prev.Name != svc.Name || prev.NodeID != svc.NodeID {
+			for topic, config := range p.discoveryConfigs(svc) {
+				payload, err := json.Marshal(config)
+				if err != nil {
+					log.Printf("[MQTTPublisher] Failed to marshal discovery config: %v", err)
+					continue
+				}
+				p.publish(mqttMessage{topic: topic, payload: payload, retained: true})
+			}
+			p.discovered[svc.ID] = svc
+		}
+
+		topic := p.serviceTopic(svc.NodeID, svc.ID) + "/availability"
+		payload := []byte(mqttAvailability(svc.Status))
+		p.retained[topic] = payload
+		p.publish(mqttMessage{topic: topic, payload: payload, retained: true})
+	}
+
+	for id, svc := range p.discovered {
+		if current[id] {
+			continue
+		}
+		// An empty retained config removes the entity from Home Assistant
+		for topic := range p.discoveryConfigs(svc) {
+			p.publish(mqttMessage{topic: topic, payload: []byte{}, retained: true})
+		}
+		base := p.serviceTopic(svc.NodeID, svc.ID)
+		for topic := range p.retained {
+			if strings.HasPrefix(topic, base+"/") {
+				delete(p.retained, topic)
+			}
+		}
+		delete(p.discovered, id)
+		log.Printf("[MQTTPublisher] Removed Home Assistant entities of service %s", id)
+	}
+}
+
+// discoveryConfigs returns the Home Assistant discovery configs of a camera by topic
+func (p *MQTTPublisher) discoveryConfigs(svc ServiceInfo) map[string]map[string]any {
+	base := p.serviceTopic(svc.NodeID, svc.ID)
+	objectID := "unblink_" + mqttTopicSegment(svc.ID)
+
+	name := svc.Name
+	if name == "" {
+		name = svc.ID
+	}
+	device := map[string]any{
+		"identifiers":  []string{objectID},
+		"name":         name,
+		"manufacturer": "Unblink",
+		"model":        "Camera",
+	}
+	serverAvailability := map[string]any{"topic": p.statusTopic()}
+	availability := []map[string]any{serverAvailability, {"topic": base + "/availability"}}
+
+	configTopic := func(component, entity string) string {
+		return p.cfg.DiscoveryPrefix + "/" + component + "/" + objectID + "/" + entity + "/config"
+	}
+
+	return map[string]map[string]any{
+		configTopic("binary_sensor", "person"): {
+			"name":              "Person",
+			"unique_id":         objectID + "_person",
+			"state_topic":       base + "/person",
+			"device_class":      "occupancy",
+			"icon":              "mdi:account",
+			"availability":      availability,
+			"availability_mode": "all",
+			"device":            device,
+		},
+		configTopic("binary_sensor", "vehicle"): {
+			"name":              "Vehicle",
+			"unique_id":         objectID + "_vehicle",
+			"state_topic":       base + "/vehicle",
+			"device_class":      "occupancy",
+			"icon":              "mdi:car",
+			"availability":      availability,
+			"availability_mode": "all",
+			"device":            device,
+		},
+		configTopic("sensor", "description"): {
+			"name":                  "Last description",
+			"unique_id":             objectID + "_description",
+			"state_topic":           base + "/description",
+			"value_template":        "{{ value_json.description[:255] }}",
+			"json_attributes_topic": base + "/description",
+			"icon":                  "mdi:text-box-outline",
+			"availability":          availability,
+			"availability_mode":     "all",
+			"device":                device,
+		},
+		// Reports the camera itself, so it stays available while the camera is offline
+		configTopic("binary_sensor", "online"): {
+			"name":            "Online",
+			"unique_id":       objectID + "_online",
+			"state_topic":     base + "/availability",
+			"payload_on":      "online",
+			"payload_off":     "offline",
+			"device_class":    "connectivity",
+			"entity_category": "diagnostic",
+			"availability":    []map[string]any{serverAvailability},
+			"device":          device,
+		},
+	}
+}
+
+func (p *MQTTPublisher) statusTopic() string {
+	return p.cfg.TopicPrefix + "/status"
+}
+
+func (p *MQTTPublisher) serviceTopic(nodeID, serviceID string) string {
+	return p.cfg.TopicPrefix + "/" + mqttTopicSegment(nodeID) + "/" + mqttTopicSegment(serviceID)
+}
+
+// mqttTopicSegment replaces characters with a meaning in MQTT topics
+func mqttTopicSegment(s string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(s)
+}
+
+// mqttAvailability maps a service status to a Home Assistant availability payload
+func mqttAvailability(status string) string {
+	if status == ServiceStatusOnline {
+		return "online"
+	}
+	return "offline"
+}
+
+func mqttOnOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// matchesAnyLabel reports whether a VLM label matches one of the wanted labels
+func matchesAnyLabel(label string, wanted []string) bool {
+	for _, want := range wanted {
+		if labelMatches(label, want) {
+			return true
+		}
+	}
+	return false
+}
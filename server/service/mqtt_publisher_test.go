@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+// brokerMessage is a publish received by the test broker
+type brokerMessage struct {
+	topic    string
+	payload  string
+	retained bool
+}
+
+// testBroker is a minimal in-process MQTT broker: it accepts any client and
+// records what is published, without routing to subscribers
+type testBroker struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	messages []brokerMessage
+	will     brokerMessage
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	b := &testBroker{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.handle(conn)
+		}
+	}()
+	return b
+}
+
+func (b *testBroker) url() string {
+	return "tcp://" + b.ln.Addr().String()
+}
+
+func (b *testBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			b.mu.Lock()
+			if p.WillFlag {
+				b.will = brokerMessage{topic: p.WillTopic, payload: string(p.WillMessage), retained: p.WillRetain}
+			}
+			b.mu.Unlock()
+
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			ack.ReturnCode = packets.Accepted
+			ack.Write(conn)
+
+		case *packets.PublishPacket:
+			b.mu.Lock()
+			b.messages = append(b.messages, brokerMessage{topic: p.TopicName, payload: string(p.Payload), retained: p.Retain})
+			b.mu.Unlock()
+
+			if p.Qos == 1 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				ack.Write(conn)
+			}
+
+		case *packets.PingreqPacket:
+			packets.NewControlPacket(packets.Pingresp).Write(conn)
+
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+// published returns the messages published to a topic so far
+func (b *testBroker) published(topic string) []brokerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result []brokerMessage
+	for _, msg := range b.messages {
+		if msg.topic == topic {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// waitFor waits until the last message published to a topic has the payload
+// and returns it
+func (b *testBroker) waitFor(t *testing.T, topic, payload string) brokerMessage {
+	t.Helper()
+	var last brokerMessage
+	require.Eventually(t, func() bool {
+		msgs := b.published(topic)
+		if len(msgs) == 0 {
+			return false
+		}
+		last = msgs[len(msgs)-1]
+		return payload == "" || last.payload == payload
+	}, 5*time.Second, 10*time.Millisecond, "no %q on %s", payload, topic)
+	return last
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	broker := newTestBroker(t)
+	registry := &ServiceRegistry{services: map[string]*ServiceState{
+		"cam/1": {ID: "cam/1", Name: "Porch", NodeID: "node1", Status: ServiceStatusOnline},
+	}}
+
+	p := NewMQTTPublisher(MQTTConfig{
+		BrokerURL:       broker.url(),
+		ClientID:        "test",
+		TopicPrefix:     "unblink",
+		DiscoveryPrefix: "homeassistant",
+	}, registry)
+	p.Start()
+
+	// Connected: the server is online and the camera is discovered
+	status := broker.waitFor(t, "unblink/status", "online")
+	assert.True(t, status.retained)
+
+	base := "unblink/node1/cam_1"
+	availability := broker.waitFor(t, base+"/availability", "online")
+	assert.True(t, availability.retained)
+
+	for topic, stateTopic := range map[string]string{
+		"homeassistant/binary_sensor/unblink_cam_1/person/config":  base + "/person",
+		"homeassistant/binary_sensor/unblink_cam_1/vehicle/config": base + "/vehicle",
+		"homeassistant/sensor/unblink_cam_1/description/config":    base + "/description",
+		"homeassistant/binary_sensor/unblink_cam_1/online/config":  base + "/availability",
+	} {
+		msg := broker.waitFor(t, topic, "")
+		assert.True(t, msg.retained, topic)
+
+		var config struct {
+			UniqueID   string `json:"unique_id"`
+			StateTopic string `json:"state_topic"`
+			Device     struct {
+				Name string `json:"name"`
+			} `json:"device"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(msg.payload), &config), topic)
+		assert.Equal(t, stateTopic, config.StateTopic, topic)
+		assert.Equal(t, "Porch", config.Device.Name, topic)
+		assert.Contains(t, config.UniqueID, "unblink_cam_1_", topic)
+	}
+
+	// An alert reaches the event and alert topics
+	alertPayload, err := structpb.NewStruct(map[string]any{"type": alertEventType, "rule_id": "r1"})
+	require.NoError(t, err)
+	p.HandleEvent(&servicev1.Event{Id: "e1", ServiceId: "cam/1", Payload: alertPayload, CreatedAt: timestamppb.Now()}, "node1")
+
+	alert := broker.waitFor(t, base+"/alert", "")
+	assert.False(t, alert.retained)
+	var body struct {
+		ID        string         `json:"id"`
+		Type      string         `json:"type"`
+		NodeID    string         `json:"node_id"`
+		ServiceID string         `json:"service_id"`
+		Payload   map[string]any `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(alert.payload), &body))
+	assert.Equal(t, "e1", body.ID)
+	assert.Equal(t, alertEventType, body.Type)
+	assert.Equal(t, "node1", body.NodeID)
+	assert.Equal(t, "cam/1", body.ServiceID)
+	assert.Equal(t, "r1", body.Payload["rule_id"])
+	assert.Equal(t, alert.payload, broker.waitFor(t, base+"/event", "").payload)
+
+	// Summary rollups are not published; their events were
+	rollupPayload, err := structpb.NewStruct(map[string]any{"type": "vlm-indexing", "granularity": "hour", "rollup": true})
+	require.NoError(t, err)
+	p.HandleEvent(&servicev1.Event{Id: "r1", ServiceId: "cam/1", Payload: rollupPayload, CreatedAt: timestamppb.Now()}, "node1")
+
+	// A status transition updates availability, but is no alert
+	statusPayload, err := structpb.NewStruct(map[string]any{"type": statusEventType, "from": ServiceStatusOnline, "to": ServiceStatusFailed})
+	require.NoError(t, err)
+	p.HandleEvent(&servicev1.Event{Id: "e2", ServiceId: "cam/1", Payload: statusPayload, CreatedAt: timestamppb.Now()}, "node1")
+
+	availability = broker.waitFor(t, base+"/availability", "offline")
+	assert.True(t, availability.retained)
+	assert.Len(t, broker.published(base+"/event"), 2) // The alert and the status event
+	assert.Len(t, broker.published(base+"/alert"), 1)
+
+	// Stopping marks the server offline; the will does the same if the connection drops
+	p.Stop()
+	broker.waitFor(t, "unblink/status", "offline")
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	assert.Equal(t, brokerMessage{topic: "unblink/status", payload: "offline", retained: true}, broker.will)
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"path"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -10,6 +14,7 @@ import (
 	"unblink/server"
 	servicev1 "unblink/server/gen/service/v1"
 	"unblink/server/webrtc"
+	"unblink/shared"
 )
 
 // ServiceState tracks the state of a service
@@ -21,18 +26,43 @@ type ServiceState struct {
 	Online  bool
 	Handler *ServiceHandler // Handles all service operations
 
+	// Recording retention override (0 = registry default)
+	RecordingRetention time.Duration
+
+	// Analysis overrides (unset fields = registry defaults)
+	Analysis *servicev1.AnalysisSettings
+
+	// Why the handler last failed to start (cleared once it starts)
+	LastError     string
+	LastErrorCode string // shared.BridgeError* code when the node refused the bridge
+
 	// Reconnection state
 	RetryCount      int
 	LastRetryTime   time.Time
 	SuccessfulSince time.Time // Last time data flowed successfully
+
+	// Connection state reported to users (ServiceStatus*)
+	Status      string
+	StatusSince time.Time
 }
 
+// segmentRetentionInterval is how often expired segments of all services are deleted
+const segmentRetentionInterval = 10 * time.Minute
+
 // reconnectRequest represents a pending reconnection attempt
 type reconnectRequest struct {
 	serviceID string
 	backoff   time.Duration
 }
 
+// RecordingConfig holds settings for continuous segment recording
+type RecordingConfig struct {
+	Enabled          bool
+	SegmentsDir      string        // Base directory for segment files
+	SegmentDuration  time.Duration // Length of each segment
+	DefaultRetention time.Duration // Used when a service has no retention set
+}
+
 // ServiceRegistry manages all services and their handlers
 type ServiceRegistry struct {
 	db             *database.Client
@@ -41,6 +71,7 @@ type ServiceRegistry struct {
 	frameInterval  time.Duration
 	srv            *server.Server
 	enableIndexing bool // Enable frame indexing/extraction
+	recording      RecordingConfig
 
 	mu          sync.RWMutex
 	services    map[string]*ServiceState   // serviceID -> ServiceState
@@ -55,14 +86,19 @@ type ServiceRegistry struct {
 
 	// Reconnection queue
 	reconnectQueue chan reconnectRequest // Async reconnection requests
+
+	// Status reporting
+	watchers     map[string]map[chan struct{}]bool // serviceID -> status watchers
+	statusEvents chan statusEvent                  // Transitions waiting to be recorded
+	broadcaster  *EventBroadcaster
 }
 
 // NewServiceRegistry creates a new service registry
-func NewServiceRegistry(db *database.Client, frameInterval time.Duration, storage *webrtc.Storage, srv *server.Server, batchMgr *webrtc.BatchManager, idleTimeout time.Duration, maxRetries int, enableIndexing bool) *ServiceRegistry {
-	// Wire up callback to save frame metadata to database when frames are saved to disk
-	storage.SetOnSaved(func(serviceID, frameID, framePath string, timestamp time.Time, fileSize int64) {
+func NewServiceRegistry(db *database.Client, frameInterval time.Duration, storage *webrtc.Storage, srv *server.Server, batchMgr *webrtc.BatchManager, idleTimeout time.Duration, maxRetries int, enableIndexing bool, recording RecordingConfig) *ServiceRegistry {
+	// Wire up callback to save frame metadata to database when frames are saved to the backend
+	storage.SetOnSaved(func(serviceID, frameID, key string, timestamp time.Time, fileSize int64) {
 		metadata := &database.FrameMetadata{}
-		if err := db.SaveStorageItem(serviceID, framePath, timestamp, fileSize, database.StorageTypeFrame, "image/jpeg", metadata); err != nil {
+		if _, err := db.SaveStorageItem(serviceID, key, timestamp, fileSize, database.StorageTypeFrame, "image/jpeg", metadata); err != nil {
 			log.Printf("[ServiceRegistry] Failed to save frame metadata: %v", err)
 		}
 	})
@@ -74,6 +110,7 @@ func NewServiceRegistry(db *database.Client, frameInterval time.Duration, storag
 		frameInterval:  frameInterval,
 		srv:            srv,
 		enableIndexing: enableIndexing,
+		recording:      recording,
 		services:       make(map[string]*ServiceState),
 		nodes:          make(map[string]map[string]bool),
 		onlineNodes:    make(map[string]bool),
@@ -82,6 +119,8 @@ func NewServiceRegistry(db *database.Client, frameInterval time.Duration, storag
 		monitorStop:    make(chan struct{}),
 		monitorStopped: make(chan struct{}),
 		reconnectQueue: make(chan reconnectRequest, 100), // Buffered channel
+		watchers:       make(map[string]map[chan struct{}]bool),
+		statusEvents:   make(chan statusEvent, 100),
 	}
 
 	// Start idle monitoring goroutine
@@ -90,6 +129,9 @@ func NewServiceRegistry(db *database.Client, frameInterval time.Duration, storag
 	// Start reconnection worker
 	go r.reconnectionWorker()
 
+	// Start status event recorder
+	go r.statusEventWorker()
+
 	return r
 }
 
@@ -109,14 +151,19 @@ func (r *ServiceRegistry) AddService(service *servicev1.Service) error {
 	nodeOnline := r.onlineNodes[service.NodeId]
 
 	state := &ServiceState{
-		ID:     service.Id,
-		Name:   service.Name,
-		URL:    service.Url,
-		NodeID: service.NodeId,
-		Online: nodeOnline,
+		ID:                 service.Id,
+		Name:               service.Name,
+		URL:                service.Url,
+		NodeID:             service.NodeId,
+		Online:             nodeOnline,
+		RecordingRetention: time.Duration(service.RecordingRetentionHours) * time.Hour,
+		Analysis:           service.Analysis,
 	}
 
 	r.services[service.Id] = state
+	if !nodeOnline {
+		r.setStatusLocked(state, ServiceStatusOffline)
+	}
 
 	// Initialize node's service set
 	if r.nodes[service.NodeId] == nil {
@@ -170,12 +217,24 @@ func (r *ServiceRegistry) UpdateService(service *servicev1.Service) {
 		return
 	}
 
+	// Only the name or analysis settings changed: keep the bridge and apply them live
+	retention := time.Duration(service.RecordingRetentionHours) * time.Hour
+	if state.Handler != nil && state.URL == service.Url && state.NodeID == service.NodeId && state.RecordingRetention == retention {
+		state.Name = service.Name
+		state.Analysis = service.Analysis
+		r.applyAnalysisLocked(state)
+		log.Printf("[ServiceRegistry] Updated service %s without reconnecting", service.Id)
+		return
+	}
+
 	// Stop old handler
 	r.stopHandlerLocked(state)
 
 	// Update state
 	state.Name = service.Name
 	state.URL = service.Url
+	state.RecordingRetention = retention
+	state.Analysis = service.Analysis
 
 	// Handle node change
 	if state.NodeID != service.NodeId {
@@ -199,6 +258,8 @@ func (r *ServiceRegistry) UpdateService(service *servicev1.Service) {
 	state.Online = r.onlineNodes[state.NodeID]
 	if state.Online {
 		r.startHandlerLocked(state)
+	} else {
+		r.setStatusLocked(state, ServiceStatusOffline)
 	}
 
 	log.Printf("[ServiceRegistry] Updated service %s", service.Id)
@@ -243,46 +304,195 @@ func (r *ServiceRegistry) SetNodeOffline(nodeID string) {
 
 	for serviceID := range serviceIDs {
 		state := r.services[serviceID]
-		if state != nil && state.Online {
+		if state == nil {
+			continue
+		}
+		if state.Online {
 			state.Online = false
 			r.stopHandlerLocked(state)
 		}
+		r.setStatusLocked(state, ServiceStatusOffline)
 	}
 }
 
-// NOTE: Currently, the only logic in a handler is indexing.
-// That's why we can skip starting handlers if indexing is disabled.
-// If there are other functionalities in the future, we might need to revisit this.
+// NOTE: A handler only does indexing and recording.
+// That's why we can skip starting handlers if both are disabled.
 // startHandlerLocked starts the service handler for a service (caller must hold lock)
 func (r *ServiceRegistry) startHandlerLocked(state *ServiceState) {
-	// Skip handler start if indexing is disabled
-	if !r.enableIndexing {
-		log.Printf("[ServiceRegistry] Indexing disabled, skipping handler start for service %s", state.ID)
+	// Skip handler start if there is nothing to do
+	enableIndexing, frameInterval, batchSettings := r.resolveAnalysis(state.Analysis)
+	if !enableIndexing && !r.recording.Enabled {
+		log.Printf("[ServiceRegistry] Indexing and recording disabled, skipping handler start for service %s", state.ID)
+		r.setStatusLocked(state, ServiceStatusDisabled)
 		return
 	}
 
+	// Resolve retention now: the segment callback runs on the recorder goroutine
+	// and must not take the registry lock (Stop waits for it while holding the lock)
+	retention := state.RecordingRetention
+	if retention <= 0 {
+		retention = r.recording.DefaultRetention
+	}
+	serviceID := state.ID
+
 	// Create handler with configuration
 	handler := NewServiceHandler(ServiceHandlerConfig{
-		ServiceID:     state.ID,
-		URL:           state.URL,
-		NodeID:        state.NodeID,
-		FrameInterval: r.frameInterval,
-		Storage:       r.storage,
-		BatchManager:  r.batchManager,
-		Server:        r.srv,
+		ServiceID:       state.ID,
+		URL:             state.URL,
+		NodeID:          state.NodeID,
+		FrameInterval:   frameInterval,
+		Storage:         r.storage,
+		BatchManager:    r.batchManager,
+		Server:          r.srv,
+		EnableIndexing:  enableIndexing,
+		EnableRecording: r.recording.Enabled,
+		BatchSettings:   batchSettings,
+		SegmentsDir:     r.recording.SegmentsDir,
+		SegmentDuration: r.recording.SegmentDuration,
+		OnSegment: func(segment *webrtc.Segment) {
+			r.saveSegment(segment)
+			r.pruneSegments(serviceID, retention)
+		},
 	})
 
 	// Start the handler
 	if err := handler.Start(); err != nil {
-		log.Printf("[ServiceRegistry] Failed to start handler for %s: %v", state.ID, err)
+		state.LastError = err.Error()
+		state.LastErrorCode = ""
+		var bridgeErr *shared.BridgeError
+		if errors.As(err, &bridgeErr) {
+			state.LastErrorCode = bridgeErr.Code
+		}
+
+		// Retrying cannot help until the node's policy or the service URL
+		// changes, and both restart the handler
+		if state.LastErrorCode == shared.BridgeErrorTargetDenied {
+			log.Printf("[ServiceRegistry] Node %s refused the target of service %s (check the node's bridge_policy): %v", state.NodeID, state.ID, err)
+			r.setStatusLocked(state, ServiceStatusDenied)
+			return
+		}
+
+		if state.RetryCount < r.maxRetries {
+			log.Printf("[ServiceRegistry] Failed to start handler for %s (attempt %d/%d): %v", state.ID, state.RetryCount+1, r.maxRetries, err)
+			r.reconnectServiceLocked(state)
+			return
+		}
+		log.Printf("[ServiceRegistry] Failed to start handler for %s, giving up after %d retries: %v", state.ID, state.RetryCount, err)
+		r.setStatusLocked(state, ServiceStatusFailed)
 		return
 	}
 
 	state.Handler = handler
+	state.LastError = ""
+	state.LastErrorCode = ""
 	state.SuccessfulSince = time.Now() // Mark as successful
+	r.setStatusLocked(state, ServiceStatusOnline)
 	log.Printf("[ServiceRegistry] Started handler for service %s", state.ID)
 }
 
+// resolveAnalysis fills in a service's analysis settings from the registry defaults
+// (nil settings = all defaults)
+func (r *ServiceRegistry) resolveAnalysis(settings *servicev1.AnalysisSettings) (bool, time.Duration, webrtc.BatchSettings) {
+	enableIndexing := r.enableIndexing
+	if settings != nil && settings.IndexingEnabled != nil {
+		enableIndexing = settings.GetIndexingEnabled()
+	}
+
+	frameInterval := r.frameInterval
+	if seconds := settings.GetFrameIntervalSeconds(); seconds > 0 {
+		frameInterval = time.Duration(seconds * float64(time.Second))
+	}
+
+	return enableIndexing, frameInterval, webrtc.BatchSettings{
+		BatchSize:   int(settings.GetBatchSize()),
+		Model:       settings.GetModel(),
+		Instruction: settings.GetInstruction(),
+		Language:    settings.GetLanguage(),
+	}
+}
+
+// applyAnalysisLocked applies changed analysis settings to a running handler
+// (caller must hold lock). The handler is stopped if it has nothing left to do,
+// and restarted from scratch if the extractor cannot be switched in place.
+func (r *ServiceRegistry) applyAnalysisLocked(state *ServiceState) {
+	enableIndexing, frameInterval, batchSettings := r.resolveAnalysis(state.Analysis)
+
+	if !enableIndexing && !r.recording.Enabled {
+		log.Printf("[ServiceRegistry] Indexing disabled for service %s and recording is off, stopping handler", state.ID)
+		r.stopHandlerLocked(state)
+		state.Online = r.onlineNodes[state.NodeID]
+		r.setStatusLocked(state, ServiceStatusDisabled)
+		return
+	}
+
+	if err := state.Handler.UpdateAnalysis(enableIndexing, frameInterval, batchSettings); err != nil {
+		log.Printf("[ServiceRegistry] Failed to apply analysis settings to service %s, restarting handler: %v", state.ID, err)
+		r.stopHandlerLocked(state)
+		state.Online = r.onlineNodes[state.NodeID]
+		if state.Online {
+			r.startHandlerLocked(state)
+		}
+	}
+}
+
+// saveSegment moves a finished recording segment to the storage backend and registers it
+func (r *ServiceRegistry) saveSegment(segment *webrtc.Segment) {
+	key := path.Join("segments", segment.ServiceID, filepath.Base(segment.Path))
+	if err := r.storage.Backend().PutFile(context.Background(), key, segment.Path, "video/mp4"); err != nil {
+		log.Printf("[ServiceRegistry] Failed to store segment %s: %v", segment.Path, err)
+		return
+	}
+
+	metadata := &database.SegmentMetadata{DurationMs: segment.Duration.Milliseconds()}
+	if _, err := r.db.SaveStorageItem(segment.ServiceID, key, segment.StartTime, segment.Size, database.StorageTypeSegment, "video/mp4", metadata); err != nil {
+		log.Printf("[ServiceRegistry] Failed to save segment metadata: %v", err)
+	}
+}
+
+// pruneSegments deletes recording segments older than the retention window
+func (r *ServiceRegistry) pruneSegments(serviceID string, retention time.Duration) {
+	expired, err := r.db.ListExpiredStorageItems(serviceID, database.StorageTypeSegment, time.Now().Add(-retention))
+	if err != nil {
+		log.Printf("[ServiceRegistry] Failed to list expired segments for service %s: %v", serviceID, err)
+		return
+	}
+
+	for _, entry := range expired {
+		if err := r.storage.Backend().Delete(context.Background(), entry.StoragePath); err != nil {
+			log.Printf("[ServiceRegistry] Failed to remove segment %s: %v", entry.StoragePath, err)
+			continue
+		}
+		if err := r.db.DeleteStorageItem(entry.ID); err != nil {
+			log.Printf("[ServiceRegistry] Failed to delete segment %s: %v", entry.ID, err)
+		}
+	}
+
+	if len(expired) > 0 {
+		log.Printf("[ServiceRegistry] Pruned %d segments for service %s (retention=%v)", len(expired), serviceID, retention)
+	}
+}
+
+// pruneAllSegments applies the retention window of every service. The
+// registry lock is only held to read the services.
+func (r *ServiceRegistry) pruneAllSegments() {
+	retentions := make(map[string]time.Duration)
+	r.mu.RLock()
+	for _, state := range r.services {
+		retention := state.RecordingRetention
+		if retention <= 0 {
+			retention = r.recording.DefaultRetention
+		}
+		retentions[state.ID] = retention
+	}
+	r.mu.RUnlock()
+
+	for serviceID, retention := range retentions {
+		if retention > 0 {
+			r.pruneSegments(serviceID, retention)
+		}
+	}
+}
+
 // stopHandlerLocked stops the service handler for a service (caller must hold lock)
 func (r *ServiceRegistry) stopHandlerLocked(state *ServiceState) {
 	if state.Handler != nil {
@@ -329,14 +539,19 @@ func (r *ServiceRegistry) addServiceLocked(service *servicev1.Service) error {
 	nodeOnline := r.onlineNodes[service.NodeId]
 
 	state := &ServiceState{
-		ID:     service.Id,
-		Name:   service.Name,
-		URL:    service.Url,
-		NodeID: service.NodeId,
-		Online: nodeOnline,
+		ID:                 service.Id,
+		Name:               service.Name,
+		URL:                service.Url,
+		NodeID:             service.NodeId,
+		Online:             nodeOnline,
+		RecordingRetention: time.Duration(service.RecordingRetentionHours) * time.Hour,
+		Analysis:           service.Analysis,
 	}
 
 	r.services[service.Id] = state
+	if !nodeOnline {
+		r.setStatusLocked(state, ServiceStatusOffline)
+	}
 
 	// Initialize node's service set
 	if r.nodes[service.NodeId] == nil {
@@ -376,6 +591,11 @@ func (r *ServiceRegistry) monitorIdleConnections() {
 	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
 	defer ticker.Stop()
 
+	// Segments also expire for services that are idle or offline, which never
+	// finish a segment to trigger pruning
+	retentionTicker := time.NewTicker(segmentRetentionInterval)
+	defer retentionTicker.Stop()
+
 	log.Printf("[ServiceRegistry] Started idle connection monitor (timeout=%v, maxRetries=%d)", r.idleTimeout, r.maxRetries)
 
 	for {
@@ -386,6 +606,9 @@ func (r *ServiceRegistry) monitorIdleConnections() {
 
 		case <-ticker.C:
 			r.checkIdleConnections()
+
+		case <-retentionTicker.C:
+			r.pruneAllSegments()
 		}
 	}
 }
@@ -415,6 +638,9 @@ func (r *ServiceRegistry) checkIdleConnections() {
 			if state.RetryCount >= r.maxRetries {
 				log.Printf("[ServiceRegistry] Service %s exceeded max retries (%d/%d), giving up", state.ID, state.RetryCount, r.maxRetries)
 				r.stopHandlerLocked(state)
+				state.LastError = fmt.Sprintf("no data for %v after %d reconnects", idleDuration.Round(time.Second), state.RetryCount)
+				state.LastErrorCode = ""
+				r.setStatusLocked(state, ServiceStatusFailed)
 				continue
 			}
 
@@ -444,6 +670,7 @@ func (r *ServiceRegistry) reconnectServiceLocked(state *ServiceState) {
 	// Increment retry count
 	state.RetryCount++
 	state.LastRetryTime = time.Now()
+	r.setStatusLocked(state, ServiceStatusReconnecting)
 
 	// Calculate backoff
 	backoff := time.Duration(state.RetryCount) * 2 * time.Second
@@ -490,6 +717,7 @@ func (r *ServiceRegistry) reconnectionWorker() {
 
 			// Attempt reconnection
 			log.Printf("[ServiceRegistry] Reconnecting service %s to node %s", req.serviceID, state.NodeID)
+			state.Online = true
 			r.startHandlerLocked(state)
 
 			if state.Handler != nil && state.Handler.IsRunning() {
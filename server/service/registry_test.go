@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+// newTestRegistry creates a registry with indexing and recording off, so
+// services on online nodes are resolved without starting handlers
+func newTestRegistry(onlineNodes ...string) *ServiceRegistry {
+	r := &ServiceRegistry{
+		frameInterval: 5 * time.Second,
+		services:      make(map[string]*ServiceState),
+		nodes:         make(map[string]map[string]bool),
+		onlineNodes:   make(map[string]bool),
+		watchers:      make(map[string]map[chan struct{}]bool),
+	}
+	for _, nodeID := range onlineNodes {
+		r.onlineNodes[nodeID] = true
+	}
+	return r
+}
+
+func TestAddServiceWithoutAnalysisOnOnlineNode(t *testing.T) {
+	r := newTestRegistry("node1")
+
+	// Services created over the API have no analysis settings
+	require.NoError(t, r.AddService(&servicev1.Service{Id: "cam1", Name: "Porch", Url: "rtsp://camera/stream", NodeId: "node1"}))
+
+	state := r.services["cam1"]
+	require.NotNil(t, state)
+	assert.True(t, state.Online)
+	assert.Equal(t, ServiceStatusDisabled, state.Status)
+}
+
+func TestResolveAnalysis(t *testing.T) {
+	r := newTestRegistry()
+	r.enableIndexing = true
+
+	enableIndexing, frameInterval, batch := r.resolveAnalysis(nil)
+	assert.True(t, enableIndexing)
+	assert.Equal(t, 5*time.Second, frameInterval)
+	assert.Zero(t, batch)
+
+	enableIndexing, frameInterval, batch = r.resolveAnalysis(&servicev1.AnalysisSettings{
+		IndexingEnabled:      proto.Bool(false),
+		FrameIntervalSeconds: 0.5,
+		BatchSize:            4,
+		Language:             "nl",
+	})
+	assert.False(t, enableIndexing)
+	assert.Equal(t, 500*time.Millisecond, frameInterval)
+	assert.Equal(t, 4, batch.BatchSize)
+	assert.Equal(t, "nl", batch.Language)
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"unblink/server"
 	servicev1 "unblink/server/gen/service/v1"
 	"unblink/server/gen/service/v1/servicev1connect"
 	"unblink/server/internal/ctxutil"
+	"unblink/server/webrtc"
+	"unblink/shared"
 )
 
 // generateID creates a unique ID using crypto/rand
@@ -31,22 +38,33 @@ type Database interface {
 	GetService(id string) (*servicev1.Service, error)
 	ListServicesByNodeId(nodeID string) ([]*servicev1.Service, error)
 	UpdateService(id, name, url string) error
+	SetServiceRecordingRetention(id string, hours int32) error
+	SetServiceAnalysisSettings(id string, settings *servicev1.AnalysisSettings) error
 	DeleteService(id string) error
 	CheckNodeAccess(nodeID, userID string) (bool, error)
 	IsGuest(userID string) (bool, error)
 	AssociateUserNode(userID, nodeID string) error
 	ListUserNodes(userID string) ([]string, error)
+	ListUserNodeInventory(userID string) ([]*servicev1.Node, error)
+	GetNode(id string) (*servicev1.Node, error)
+	SetNodeDisplayName(id, displayName string) error
+	RevokeNodeTokens(nodeID string) (int64, error)
 }
 
+// maxNodeDisplayNameLength limits user-chosen node names
+const maxNodeDisplayNameLength = 100
+
 type Service struct {
 	db       Database
 	registry *ServiceRegistry
+	nodes    *server.Server // Live node connections (may be nil)
 }
 
-func NewService(db Database, registry *ServiceRegistry) *Service {
+func NewService(db Database, registry *ServiceRegistry, nodes *server.Server) *Service {
 	return &Service{
 		db:       db,
 		registry: registry,
+		nodes:    nodes,
 	}
 }
 
@@ -142,6 +160,16 @@ func (s *Service) UpdateService(ctx context.Context, req *connect.Request[servic
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("url is required"))
 	}
 
+	if req.Msg.GetRecordingRetentionHours() < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("recording_retention_hours must not be negative"))
+	}
+
+	if req.Msg.Analysis != nil {
+		if err := validateAnalysisSettings(req.Msg.Analysis); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+
 	// Get the existing service to get node_id
 	existingService, err := s.db.GetService(req.Msg.Id)
 	if err != nil {
@@ -159,13 +187,33 @@ func (s *Service) UpdateService(ctx context.Context, req *connect.Request[servic
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update service: %w", err))
 	}
 
+	// Update recording retention if provided
+	retentionHours := existingService.RecordingRetentionHours
+	if req.Msg.RecordingRetentionHours != nil {
+		retentionHours = req.Msg.GetRecordingRetentionHours()
+		if err := s.db.SetServiceRecordingRetention(req.Msg.Id, retentionHours); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update service: %w", err))
+		}
+	}
+
+	// Replace analysis settings if provided
+	analysis := existingService.Analysis
+	if req.Msg.Analysis != nil {
+		analysis = req.Msg.Analysis
+		if err := s.db.SetServiceAnalysisSettings(req.Msg.Id, analysis); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update service: %w", err))
+		}
+	}
+
 	// Notify registry
 	if s.registry != nil {
 		s.registry.UpdateService(&servicev1.Service{
-			Id:     req.Msg.Id,
-			Name:   name,
-			Url:    url,
-			NodeId: existingService.NodeId,
+			Id:                      req.Msg.Id,
+			Name:                    name,
+			Url:                     url,
+			NodeId:                  existingService.NodeId,
+			RecordingRetentionHours: retentionHours,
+			Analysis:                analysis,
 		})
 	}
 
@@ -173,16 +221,75 @@ func (s *Service) UpdateService(ctx context.Context, req *connect.Request[servic
 
 	return connect.NewResponse(&servicev1.UpdateServiceResponse{
 		Service: &servicev1.Service{
-			Id:        req.Msg.Id,
-			Name:      name,
-			Url:       url,
-			NodeId:    existingService.NodeId,
-			CreatedAt: existingService.CreatedAt,
-			UpdatedAt: timestamppb.New(time.Now()),
+			Id:                      req.Msg.Id,
+			Name:                    name,
+			Url:                     url,
+			NodeId:                  existingService.NodeId,
+			CreatedAt:               existingService.CreatedAt,
+			UpdatedAt:               timestamppb.New(time.Now()),
+			RecordingRetentionHours: retentionHours,
+			Analysis:                analysis,
 		},
 	}), nil
 }
 
+// Bounds for per-service analysis settings
+const (
+	minFrameIntervalSeconds = 0.5
+	maxFrameIntervalSeconds = 3600
+	maxBatchSize            = 32
+	maxInstructionLength    = 4000
+	maxLanguageLength       = 50
+	maxZones                = 20
+)
+
+// zoneNamePattern keeps zone names usable as one word in alert predicates ("in zone driveway")
+var zoneNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// validateAnalysisSettings checks analysis settings from a request, trimming
+// surrounding whitespace from the text fields
+func validateAnalysisSettings(settings *servicev1.AnalysisSettings) error {
+	settings.Model = strings.TrimSpace(settings.Model)
+	settings.Instruction = strings.TrimSpace(settings.Instruction)
+	settings.Language = strings.TrimSpace(settings.Language)
+
+	if seconds := settings.FrameIntervalSeconds; seconds != 0 && (seconds < minFrameIntervalSeconds || seconds > maxFrameIntervalSeconds) {
+		return fmt.Errorf("analysis.frame_interval_seconds must be between %v and %v (or 0 for the server default)", minFrameIntervalSeconds, maxFrameIntervalSeconds)
+	}
+	if settings.BatchSize < 0 || settings.BatchSize > maxBatchSize {
+		return fmt.Errorf("analysis.batch_size must be between 1 and %d (or 0 for the server default)", maxBatchSize)
+	}
+	if len(settings.Instruction) > maxInstructionLength {
+		return fmt.Errorf("analysis.instruction must be at most %d characters", maxInstructionLength)
+	}
+	if len(settings.Language) > maxLanguageLength {
+		return fmt.Errorf("analysis.language must be at most %d characters", maxLanguageLength)
+	}
+
+	if len(settings.Zones) > maxZones {
+		return fmt.Errorf("analysis.zones must have at most %d entries", maxZones)
+	}
+	names := make(map[string]bool)
+	for _, zone := range settings.Zones {
+		if zone == nil {
+			return fmt.Errorf("analysis.zones: empty zone")
+		}
+		zone.Name = strings.TrimSpace(zone.Name)
+		if !zoneNamePattern.MatchString(zone.Name) {
+			return fmt.Errorf("analysis.zones: name %q must be 1-32 letters, digits, '-' or '_'", zone.Name)
+		}
+		if names[strings.ToLower(zone.Name)] {
+			return fmt.Errorf("analysis.zones: duplicate zone %q", zone.Name)
+		}
+		names[strings.ToLower(zone.Name)] = true
+
+		if zone.X1 < 0 || zone.Y1 < 0 || zone.X2 > 1000 || zone.Y2 > 1000 || zone.X1 >= zone.X2 || zone.Y1 >= zone.Y2 {
+			return fmt.Errorf("analysis.zones: zone %q must satisfy 0 <= x1 < x2 <= 1000 and 0 <= y1 < y2 <= 1000", zone.Name)
+		}
+	}
+	return nil
+}
+
 // DeleteService deletes a service by ID
 func (s *Service) DeleteService(ctx context.Context, req *connect.Request[servicev1.DeleteServiceRequest]) (*connect.Response[servicev1.DeleteServiceResponse], error) {
 	if req.Msg.ServiceId == "" {
@@ -217,6 +324,234 @@ func (s *Service) DeleteService(ctx context.Context, req *connect.Request[servic
 	}), nil
 }
 
+// DiscoverDevices asks a node to look for cameras on its LAN. With ONVIF
+// credentials, devices come back with stream URLs ready for CreateService.
+func (s *Service) DiscoverDevices(ctx context.Context, req *connect.Request[servicev1.DiscoverDevicesRequest]) (*connect.Response[servicev1.DiscoverDevicesResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	if s.nodes == nil {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("node %s is offline", req.Msg.NodeId))
+	}
+	conn, ok := s.nodes.GetNodeConnection(req.Msg.NodeId)
+	if !ok || !conn.IsReady() {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("node %s is offline", req.Msg.NodeId))
+	}
+	if !conn.HasCapability(shared.CapabilityDiscovery) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("node %s does not support discovery, update the node", req.Msg.NodeId))
+	}
+
+	timeout := shared.DefaultDiscoveryTimeout
+	if req.Msg.TimeoutSeconds > 0 {
+		timeout = time.Duration(min(req.Msg.TimeoutSeconds, 10)) * time.Second
+	}
+
+	devices, err := conn.Discover(ctx, &shared.DiscoverRequest{
+		TimeoutMs: int(timeout.Milliseconds()),
+		Username:  req.Msg.Username,
+		Password:  req.Msg.Password,
+	})
+	if err != nil {
+		var bridgeErr *shared.BridgeError
+		if errors.As(err, &bridgeErr) && bridgeErr.Code == shared.BridgeErrorTargetDenied {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("discovery refused by node %s: %w", req.Msg.NodeId, err))
+		}
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("discovery failed: %w", err))
+	}
+
+	log.Printf("[Service] Discovered %d devices on node %s", len(devices), req.Msg.NodeId)
+
+	resp := &servicev1.DiscoverDevicesResponse{}
+	for _, device := range devices {
+		pbDevice := &servicev1.DiscoveredDevice{
+			Address:      device.Address,
+			Source:       device.Source,
+			Name:         device.Name,
+			Manufacturer: device.Manufacturer,
+			Model:        device.Model,
+			OnvifUrl:     device.ONVIFURL,
+			Error:        device.Error,
+		}
+		for _, stream := range device.Streams {
+			pbDevice.Streams = append(pbDevice.Streams, &servicev1.DiscoveredStream{
+				Profile:  stream.Profile,
+				Url:      stream.URL,
+				Encoding: stream.Encoding,
+				Width:    int32(stream.Width),
+				Height:   int32(stream.Height),
+			})
+		}
+		resp.Devices = append(resp.Devices, pbDevice)
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// TestServiceURL checks a URL through its node before it is saved as a service:
+// whether the camera is reachable and accepts the credentials, what it streams,
+// and one preview frame. Nothing is persisted.
+func (s *Service) TestServiceURL(ctx context.Context, req *connect.Request[servicev1.TestServiceURLRequest]) (*connect.Response[servicev1.TestServiceURLResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+	if req.Msg.Url == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("url is required"))
+	}
+
+	parsed, err := url.Parse(req.Msg.Url)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid url: %w", err))
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "rtsp", "rtsps", "http", "https":
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unsupported URL scheme: %s", parsed.Scheme))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	if s.nodes == nil {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("node %s is offline", req.Msg.NodeId))
+	}
+	nodeConn, ok := s.nodes.GetNodeConnection(req.Msg.NodeId)
+	if !ok || !nodeConn.IsReady() {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("node %s is offline", req.Msg.NodeId))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webrtc.ProbeTimeout)
+	defer cancel()
+
+	resp := &servicev1.TestServiceURLResponse{}
+
+	bridgeID, dataChan, err := nodeConn.OpenBridge(ctx, "test-"+generateID(), req.Msg.Url)
+	if err != nil {
+		resp.Error = err.Error()
+		var bridgeErr *shared.BridgeError
+		if errors.As(err, &bridgeErr) {
+			resp.ErrorCode = bridgeErr.Code
+		}
+		log.Printf("[Service] Tested URL on node %s: bridge failed: %v", req.Msg.NodeId, err)
+		return connect.NewResponse(resp), nil
+	}
+	resp.Reachable = true
+
+	bridgeConn := server.NewBridgeConn(nodeConn, bridgeID, dataChan)
+	defer func() {
+		bridgeConn.Close()
+		nodeConn.CloseBridge(context.Background(), bridgeID)
+	}()
+
+	result, err := webrtc.ProbeSource(ctx, req.Msg.Url, bridgeConn)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Success = true
+	}
+	if result != nil {
+		resp.Responded = result.Responded
+		resp.AuthFailed = result.AuthFailed
+		resp.SourceType = string(result.SourceType)
+		resp.Width = int32(result.Width)
+		resp.Height = int32(result.Height)
+		resp.PreviewJpeg = result.Preview
+		resp.PreviewError = result.PreviewError
+		for _, codec := range result.Codecs {
+			resp.Codecs = append(resp.Codecs, &servicev1.StreamCodec{
+				Kind:      codec.Kind,
+				Name:      codec.Name,
+				ClockRate: codec.ClockRate,
+				Channels:  uint32(codec.Channels),
+			})
+		}
+	}
+
+	log.Printf("[Service] Tested URL on node %s: success=%v, auth_failed=%v, codecs=%d, %dx%d, preview=%d bytes",
+		req.Msg.NodeId, resp.Success, resp.AuthFailed, len(resp.Codecs), resp.Width, resp.Height, len(resp.PreviewJpeg))
+
+	return connect.NewResponse(resp), nil
+}
+
+// statusUpdateInterval is how often WatchServiceStatus refreshes a status
+// that did not change state, so frame times and rates stay current
+const statusUpdateInterval = 5 * time.Second
+
+// GetServiceStatus returns the live health of a service
+func (s *Service) GetServiceStatus(ctx context.Context, req *connect.Request[servicev1.GetServiceStatusRequest]) (*connect.Response[servicev1.GetServiceStatusResponse], error) {
+	if err := s.checkServiceAccess(ctx, req.Msg.ServiceId); err != nil {
+		return nil, err
+	}
+
+	status, ok := s.registry.GetStatus(req.Msg.ServiceId)
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("service %s is not running on this server", req.Msg.ServiceId))
+	}
+
+	return connect.NewResponse(&servicev1.GetServiceStatusResponse{
+		Status: status,
+	}), nil
+}
+
+// WatchServiceStatus streams the live health of a service: right away, on
+// every state change and every statusUpdateInterval
+func (s *Service) WatchServiceStatus(ctx context.Context, req *connect.Request[servicev1.WatchServiceStatusRequest], stream *connect.ServerStream[servicev1.WatchServiceStatusResponse]) error {
+	serviceID := req.Msg.ServiceId
+	if err := s.checkServiceAccess(ctx, serviceID); err != nil {
+		return err
+	}
+
+	changed, cancel := s.registry.WatchStatus(serviceID)
+	defer cancel()
+
+	ticker := time.NewTicker(statusUpdateInterval)
+	defer ticker.Stop()
+
+	log.Printf("[Service] Watching status of service %s", serviceID)
+
+	for {
+		status, ok := s.registry.GetStatus(serviceID)
+		if !ok {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("service %s was removed", serviceID))
+		}
+		if err := stream.Send(&servicev1.WatchServiceStatusResponse{Status: status}); err != nil {
+			log.Printf("[Service] Status stream send error: service=%s, err=%v", serviceID, err)
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkServiceAccess verifies that the caller can see a service's node
+func (s *Service) checkServiceAccess(ctx context.Context, serviceID string) error {
+	if serviceID == "" {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("service_id is required"))
+	}
+	if s.registry == nil {
+		return connect.NewError(connect.CodeUnavailable, fmt.Errorf("service registry not available"))
+	}
+
+	service, err := s.db.GetService(serviceID)
+	if err != nil {
+		return connect.NewError(connect.CodeNotFound, fmt.Errorf("service not found: %w", err))
+	}
+
+	return ctxutil.CheckNodeAccessWithContext(ctx, s.db, service.NodeId)
+}
+
 // AssociateUserNode associates a node with the authenticated user
 func (s *Service) AssociateUserNode(ctx context.Context, req *connect.Request[servicev1.AssociateUserNodeRequest]) (*connect.Response[servicev1.AssociateUserNodeResponse], error) {
 	if req.Msg.NodeId == "" {
@@ -259,15 +594,125 @@ func (s *Service) ListUserNodes(ctx context.Context, req *connect.Request[servic
 		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("not authenticated"))
 	}
 
-	nodeIDs, err := s.db.ListUserNodes(userID)
+	nodes, err := s.db.ListUserNodeInventory(userID)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list user nodes: %w", err))
 	}
 
+	nodeIDs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		s.addLiveNodeState(node)
+		nodeIDs = append(nodeIDs, node.Id)
+	}
+
 	return connect.NewResponse(&servicev1.ListUserNodesResponse{
 		NodeIds: nodeIDs,
+		Nodes:   nodes,
 	}), nil
 }
 
+// UpdateNode sets the display name of a node
+func (s *Service) UpdateNode(ctx context.Context, req *connect.Request[servicev1.UpdateNodeRequest]) (*connect.Response[servicev1.UpdateNodeResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	displayName := strings.TrimSpace(req.Msg.DisplayName)
+	if len(displayName) > maxNodeDisplayNameLength {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("display_name must be at most %d characters", maxNodeDisplayNameLength))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetNodeDisplayName(req.Msg.NodeId, displayName); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update node: %w", err))
+	}
+
+	node, err := s.db.GetNode(req.Msg.NodeId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get node: %w", err))
+	}
+	if node == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("node not found"))
+	}
+	s.addLiveNodeState(node)
+
+	log.Printf("[Service] Updated node: id=%s, display_name=%q", req.Msg.NodeId, displayName)
+
+	return connect.NewResponse(&servicev1.UpdateNodeResponse{
+		Node: node,
+	}), nil
+}
+
+// RevokeNodeToken revokes every token of a node and disconnects it. The node
+// cannot get a new token afterwards, so a lost or stolen node stays locked out.
+func (s *Service) RevokeNodeToken(ctx context.Context, req *connect.Request[servicev1.RevokeNodeTokenRequest]) (*connect.Response[servicev1.RevokeNodeTokenResponse], error) {
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("not authenticated"))
+	}
+
+	// Only allow non-guest users to revoke nodes
+	isGuest, err := s.db.IsGuest(userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to check guest status: %w", err))
+	}
+	if isGuest {
+		return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("guest users cannot revoke nodes"))
+	}
+
+	// Verify node access
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	count, err := s.db.RevokeNodeTokens(req.Msg.NodeId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to revoke node tokens: %w", err))
+	}
+
+	// Revoked tokens are refused on the next connect; close the live one now
+	disconnected := false
+	if s.nodes != nil {
+		disconnected = s.nodes.DisconnectNode(req.Msg.NodeId)
+	}
+
+	log.Printf("[Service] Revoked node: id=%s, tokens=%d, disconnected=%v, by user_id=%s", req.Msg.NodeId, count, disconnected, userID)
+
+	return connect.NewResponse(&servicev1.RevokeNodeTokenResponse{
+		RevokedTokens: int32(count),
+		Disconnected:  disconnected,
+	}), nil
+}
+
+// addLiveNodeState fills in the node fields only the relay knows right now
+func (s *Service) addLiveNodeState(node *servicev1.Node) {
+	if s.nodes == nil {
+		return
+	}
+
+	conn, ok := s.nodes.GetNodeConnection(node.Id)
+	if !ok || !conn.IsReady() {
+		return
+	}
+
+	node.Online = true
+	node.LastSeen = timestamppb.Now()
+	node.ActiveBridgeCount = int32(conn.BridgeCount())
+	if node.Hostname == "" {
+		node.Hostname = conn.Hostname()
+	}
+	if node.NodeVersion == "" {
+		node.NodeVersion = conn.NodeVersion()
+	}
+}
+
 // Ensure Service implements interface
 var _ servicev1connect.ServiceServiceHandler = (*Service)(nil)
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+// Service connection states (ServiceState.Status)
+const (
+	ServiceStatusOffline      = "offline"      // The service's node is not connected
+	ServiceStatusOnline       = "online"       // The handler is running
+	ServiceStatusReconnecting = "reconnecting" // The bridge went idle; a reconnect is queued
+	ServiceStatusFailed       = "failed"       // The handler could not start, or retries ran out
+	ServiceStatusDisabled     = "disabled"     // Indexing and recording are off, so no handler runs
+	ServiceStatusDenied       = "denied"       // The node's bridge policy refused the target; not retried
+)
+
+// statusEventType is the payload type of events recording status transitions
+const statusEventType = "service-status"
+
+// statusEvent is a status transition waiting to be recorded
+type statusEvent struct {
+	serviceID string
+	nodeID    string
+	from      string
+	to        string
+	err       string
+	errCode   string
+	retries   int
+	at        time.Time
+}
+
+// SetEventBroadcaster makes status transitions reach event stream subscribers
+func (r *ServiceRegistry) SetEventBroadcaster(broadcaster *EventBroadcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broadcaster = broadcaster
+}
+
+// setStatusLocked moves a service to a new status, wakes its watchers and
+// queues an event for the transition (caller must hold lock). The first
+// status a service gets is not a transition and is not recorded.
+func (r *ServiceRegistry) setStatusLocked(state *ServiceState, status string) {
+	if state.Status == status {
+		return
+	}
+
+	from := state.Status
+	state.Status = status
+	state.StatusSince = time.Now()
+
+	for watcher := range r.watchers[state.ID] {
+		select {
+		case watcher <- struct{}{}:
+		default: // Already signalled
+		}
+	}
+
+	if from == "" {
+		return
+	}
+
+	log.Printf("[ServiceRegistry] Service %s: %s -> %s", state.ID, from, status)
+
+	select {
+	case r.statusEvents <- statusEvent{
+		serviceID: state.ID,
+		nodeID:    state.NodeID,
+		from:      from,
+		to:        status,
+		err:       state.LastError,
+		errCode:   state.LastErrorCode,
+		retries:   state.RetryCount,
+		at:        state.StatusSince,
+	}:
+	default:
+		log.Printf("[ServiceRegistry] Status event queue full, dropping %s -> %s for %s", from, status, state.ID)
+	}
+}
+
+// statusEventWorker records status transitions as events outside the registry lock
+func (r *ServiceRegistry) statusEventWorker() {
+	for {
+		select {
+		case <-r.monitorStop:
+			return
+
+		case ev := <-r.statusEvents:
+			fields := map[string]any{
+				"type":        statusEventType,
+				"from":        ev.from,
+				"to":          ev.to,
+				"retry_count": ev.retries,
+			}
+			if (ev.to == ServiceStatusFailed || ev.to == ServiceStatusDenied) && ev.err != "" {
+				fields["error"] = ev.err
+				if ev.errCode != "" {
+					fields["error_code"] = ev.errCode
+				}
+			}
+
+			payload, err := structpb.NewStruct(fields)
+			if err != nil {
+				log.Printf("[ServiceRegistry] Failed to create status event payload: %v", err)
+				continue
+			}
+
+			eventID := uuid.New().String()
+			if err := r.db.CreateEvent(eventID, ev.serviceID, payload); err != nil {
+				log.Printf("[ServiceRegistry] Failed to record status event for %s: %v", ev.serviceID, err)
+				continue
+			}
+
+			r.mu.RLock()
+			broadcaster := r.broadcaster
+			r.mu.RUnlock()
+			if broadcaster != nil {
+				broadcaster.Broadcast(&servicev1.Event{
+					Id:        eventID,
+					ServiceId: ev.serviceID,
+					Payload:   payload,
+					CreatedAt: timestamppb.New(ev.at),
+				}, ev.nodeID)
+			}
+		}
+	}
+}
+
+// WatchStatus returns a channel that is signalled whenever a service's status
+// changes, and a function to stop watching
+func (r *ServiceRegistry) WatchStatus(serviceID string) (<-chan struct{}, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watcher := make(chan struct{}, 1)
+	if r.watchers[serviceID] == nil {
+		r.watchers[serviceID] = make(map[chan struct{}]bool)
+	}
+	r.watchers[serviceID][watcher] = true
+
+	return watcher, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.watchers[serviceID], watcher)
+		if len(r.watchers[serviceID]) == 0 {
+			delete(r.watchers, serviceID)
+		}
+	}
+}
+
+// GetStatus returns a snapshot of a service's health, or false if the
+// service is not registered
+func (r *ServiceRegistry) GetStatus(serviceID string) (*servicev1.ServiceStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state, exists := r.services[serviceID]
+	if !exists {
+		return nil, false
+	}
+
+	status := &servicev1.ServiceStatus{
+		ServiceId:     state.ID,
+		State:         state.Status,
+		StateSince:    optionalTimestamp(state.StatusSince),
+		RetryCount:    int32(state.RetryCount),
+		MaxRetries:    int32(r.maxRetries),
+		LastRetryAt:   optionalTimestamp(state.LastRetryTime),
+		LastError:     state.LastError,
+		LastErrorCode: state.LastErrorCode,
+	}
+
+	if handler := state.Handler; handler != nil {
+		status.Indexing = handler.IsIndexing()
+		status.Recording = handler.IsRecording()
+
+		lastFrame, fps := handler.ExtractorStats()
+		status.LastFrameAt = optionalTimestamp(lastFrame)
+		status.ExtractorFps = fps
+
+		if bridgeConn := handler.GetBridgeConn(); bridgeConn != nil {
+			status.LastDataAt = optionalTimestamp(bridgeConn.LastDataTime())
+			if stats, ok := bridgeConn.FlowStats(); ok {
+				status.BridgeBufferedBytes = stats.BufferedBytes
+				status.BridgeInFlightBytes = stats.InFlightBytes
+				status.BridgeSendCredit = stats.SendCredit
+			}
+		}
+	}
+
+	return status, true
+}
+
+// ServiceInfo identifies a registered service and its current status
+type ServiceInfo struct {
+	ID     string
+	Name   string
+	NodeID string
+	Status string
+}
+
+// ListServices returns a snapshot of all registered services
+func (r *ServiceRegistry) ListServices() []ServiceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make([]ServiceInfo, 0, len(r.services))
+	for _, state := range r.services {
+		services = append(services, ServiceInfo{
+			ID:     state.ID,
+			Name:   state.Name,
+			NodeID: state.NodeID,
+			Status: state.Status,
+		})
+	}
+	return services
+}
+
+// optionalTimestamp maps the zero time to an unset timestamp
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	servicev1 "unblink/server/gen/service/v1"
+)
+
+func TestValidateAnalysisSettings(t *testing.T) {
+	zone := func(name string, x1, y1, x2, y2 int32) *servicev1.Zone {
+		return &servicev1.Zone{Name: name, X1: x1, Y1: y1, X2: x2, Y2: y2}
+	}
+
+	tests := []struct {
+		name     string
+		settings *servicev1.AnalysisSettings
+		wantErr  string
+	}{
+		{"empty", &servicev1.AnalysisSettings{}, ""},
+		{"frame interval too short", &servicev1.AnalysisSettings{FrameIntervalSeconds: 0.1}, "frame_interval_seconds"},
+		{"batch size too large", &servicev1.AnalysisSettings{BatchSize: maxBatchSize + 1}, "batch_size"},
+		{"zones", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{
+			zone("driveway", 0, 500, 400, 1000),
+			zone("front_door-2", 600, 0, 1000, 400),
+		}}, ""},
+		{"nil zone", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{nil}}, "empty zone"},
+		{"unnamed zone", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone(" ", 0, 0, 10, 10)}}, "name"},
+		{"two words", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("front door", 0, 0, 10, 10)}}, "name"},
+		{"newline in name", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("front\ndoor", 0, 0, 10, 10)}}, "name"},
+		{"predicate syntax in name", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("zone=yard", 0, 0, 10, 10)}}, "name"},
+		{"duplicate", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{
+			zone("yard", 0, 0, 10, 10),
+			zone("Yard", 20, 20, 30, 30),
+		}}, "duplicate"},
+		{"empty rectangle", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("yard", 10, 0, 10, 10)}}, "x1 < x2"},
+		{"outside the image", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("yard", 0, 0, 1001, 10)}}, "x1 < x2"},
+		{"negative", &servicev1.AnalysisSettings{Zones: []*servicev1.Zone{zone("yard", -1, 0, 10, 10)}}, "x1 < x2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAnalysisSettings(tt.settings)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAnalysisSettingsTrims(t *testing.T) {
+	settings := &servicev1.AnalysisSettings{
+		Model:    " vlm ",
+		Language: " German\n",
+		Zones:    []*servicev1.Zone{{Name: " yard ", X2: 10, Y2: 10}},
+	}
+	require.NoError(t, validateAnalysisSettings(settings))
+	assert.Equal(t, "vlm", settings.Model)
+	assert.Equal(t, "German", settings.Language)
+	assert.Equal(t, "yard", settings.Zones[0].Name)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"unblink/server"
+	"unblink/server/webrtc"
+)
+
+const (
+	// snapshotMaxAge is how old the last indexed frame may be to count as live
+	snapshotMaxAge = time.Minute
+
+	// snapshotTimeout bounds opening a bridge and waiting for its first frame
+	snapshotTimeout = 20 * time.Second
+)
+
+// CaptureFrame returns a current frame of a service: the last frame of its
+// running extractor if recent enough, otherwise the first frame of a bridge
+// opened just for this
+func (r *ServiceRegistry) CaptureFrame(ctx context.Context, serviceID string) (*webrtc.Frame, error) {
+	r.mu.RLock()
+	state, exists := r.services[serviceID]
+	if !exists {
+		r.mu.RUnlock()
+		return nil, fmt.Errorf("service %s not found", serviceID)
+	}
+	if !r.onlineNodes[state.NodeID] {
+		r.mu.RUnlock()
+		return nil, fmt.Errorf("node %s is offline", state.NodeID)
+	}
+
+	var latest *webrtc.Frame
+	if state.Handler != nil {
+		latest = state.Handler.LatestFrame()
+	}
+	nodeID, url, srv := state.NodeID, state.URL, r.srv
+	r.mu.RUnlock()
+
+	if latest != nil && time.Since(latest.Timestamp) <= snapshotMaxAge {
+		return latest, nil
+	}
+
+	return captureFrameFromBridge(ctx, srv, nodeID, serviceID, url)
+}
+
+// captureFrameFromBridge opens a bridge to a service, extracts one frame and
+// closes the bridge again
+func captureFrameFromBridge(ctx context.Context, srv *server.Server, nodeID, serviceID, url string) (*webrtc.Frame, error) {
+	nodeConn, exists := srv.GetNodeConnection(nodeID)
+	if !exists {
+		return nil, fmt.Errorf("node %s not connected", nodeID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	bridgeID, dataChan, err := nodeConn.OpenBridge(ctx, serviceID, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bridge: %w", err)
+	}
+	bridgeConn := server.NewBridgeConn(nodeConn, bridgeID, dataChan)
+	defer func() {
+		bridgeConn.Close()
+		nodeConn.CloseBridge(context.Background(), bridgeID)
+	}()
+
+	listenUDP := webrtc.NewBridgePacketListener(ctx, nodeConn, serviceID, url)
+	mediaSource, err := webrtc.NewMediaSource(url, serviceID, bridgeConn, listenUDP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media source: %w", err)
+	}
+	defer mediaSource.Close()
+
+	// Pump RTP data from the bridge; stopped before the media source is closed
+	producer := mediaSource.GetProducer()
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		producer.Start()
+	}()
+	defer func() {
+		producer.Stop()
+		<-producerDone
+	}()
+
+	frames := make(chan *webrtc.Frame, 1)
+	extractor := webrtc.NewFrameExtractor(serviceID, time.Second, func(frame *webrtc.Frame) {
+		select {
+		case frames <- frame:
+		default:
+		}
+	})
+	if err := extractor.Start(mediaSource); err != nil {
+		extractor.Close()
+		return nil, fmt.Errorf("failed to start extractor: %w", err)
+	}
+	defer extractor.Close()
+
+	select {
+	case frame := <-frames:
+		log.Printf("[ServiceRegistry] Captured snapshot of service %s over bridge %s", serviceID, bridgeID)
+		return frame, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no frame from service %s: %w", serviceID, ctx.Err())
+	}
+}
@@ -2,34 +2,72 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"unblink/database"
 	servicev1 "unblink/server/gen/service/v1"
 	"unblink/server/gen/service/v1/servicev1connect"
 	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+	"unblink/server/internal/timeutil"
+	"unblink/server/webrtc"
 )
 
+// deleteOldBatchSize is the number of storage items DeleteOldStorageItems lists at a time
+const deleteOldBatchSize = 500
+
 // StorageConfig holds configuration for storage
 type StorageConfig struct {
-	StorageBaseDir string // Base directory where storage items are stored
+	Backend        webrtc.StorageBackend // Where storage item bytes live
+	StorageBaseDir string                // Base directory where storage items are stored
+	ClipsDir       string                // Local working directory for exported clips
+	ClipPreRoll    time.Duration         // Default time added before a clip's start
+	ClipPostRoll   time.Duration         // Default time added after a clip's end
+	URLSigningKey  []byte                // HMAC key for signed /storage/ URLs
+	FrameAnalyzer  *FrameAnalyzer        // Answers ReanalyzeFrames (nil = unavailable)
+}
+
+// RequestAuthenticator authenticates plain HTTP requests (implemented by server.AuthInterceptor)
+type RequestAuthenticator interface {
+	AuthenticateRequest(r *http.Request) (context.Context, error)
+}
+
+// StorageDatabase defines the database operations of the storage service
+type StorageDatabase interface {
+	GetService(id string) (*servicev1.Service, error)
+	CheckNodeAccess(nodeID, userID string) (bool, error)
+	GetEvent(id string) (*servicev1.Event, error)
+	ListStorageItemsForService(serviceID string, storageType string, from, to time.Time, limit, offset int64) ([]*database.StorageEntry, int64, error)
+	GetStorageItemInfo(itemID string) (*database.StorageEntry, error)
+	ListOldStorageItems(serviceID string, storageType string, cutoff time.Time, limit int) ([]*database.StorageEntry, error)
+	DeleteStorageItem(itemID string) error
+	ListSegmentsInRange(serviceID string, from, to time.Time) ([]*database.StorageEntry, error)
+	SaveStorageItem(serviceID, storagePath string, timestamp time.Time, fileSize int64, storageType database.StorageType, contentType string, metadata any) (string, error)
 }
 
 // StorageService handles storage operations
 type StorageService struct {
-	db     *database.Client
+	db     StorageDatabase
 	config *StorageConfig
+	auth   RequestAuthenticator
 }
 
-func NewStorageService(db *database.Client, config *StorageConfig) *StorageService {
+func NewStorageService(db StorageDatabase, config *StorageConfig, auth RequestAuthenticator) *StorageService {
 	return &StorageService{
 		db:     db,
 		config: config,
+		auth:   auth,
 	}
 }
 
@@ -42,7 +80,7 @@ func (s *StorageService) verifyServiceAccess(ctx context.Context, serviceID stri
 
 	// Get service to find its node
 	service, err := s.db.GetService(serviceID)
-	if err != nil {
+	if err != nil || service == nil {
 		return connect.NewError(connect.CodeNotFound, fmt.Errorf("service not found"))
 	}
 
@@ -59,6 +97,31 @@ func (s *StorageService) verifyServiceAccess(ctx context.Context, serviceID stri
 	return nil
 }
 
+// verifyEventAccess loads an event the user can access.
+// Missing events and events of other users' nodes get the same error, so
+// event IDs can't be probed.
+func (s *StorageService) verifyEventAccess(ctx context.Context, eventID string) (*servicev1.Event, error) {
+	if _, ok := ctxutil.GetUserIDFromContext(ctx); !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("not authenticated"))
+	}
+
+	event, err := s.db.GetEvent(eventID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get event: %w", err))
+	}
+	if event == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("event not found"))
+	}
+
+	if err := s.verifyServiceAccess(ctx, event.ServiceId); err != nil {
+		if code := connect.CodeOf(err); code == connect.CodePermissionDenied || code == connect.CodeNotFound {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("event not found"))
+		}
+		return nil, err
+	}
+	return event, nil
+}
+
 // ListStorageItems lists storage items for a service
 func (s *StorageService) ListStorageItems(ctx context.Context, req *connect.Request[servicev1.ListStorageItemsRequest]) (*connect.Response[servicev1.ListStorageItemsResponse], error) {
 	if req.Msg.ServiceId == "" {
@@ -81,7 +144,15 @@ func (s *StorageService) ListStorageItems(ctx context.Context, req *connect.Requ
 	// Use type from request (empty string = all types)
 	storageType := req.Msg.Type
 
-	entries, total, err := s.db.ListStorageItemsForService(req.Msg.ServiceId, storageType, limit, req.Msg.Offset)
+	var from, to time.Time
+	if req.Msg.From != nil {
+		from = req.Msg.From.AsTime()
+	}
+	if req.Msg.To != nil {
+		to = req.Msg.To.AsTime()
+	}
+
+	entries, total, err := s.db.ListStorageItemsForService(req.Msg.ServiceId, storageType, from, to, limit, req.Msg.Offset)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list storage items: %w", err))
 	}
@@ -122,9 +193,13 @@ func (s *StorageService) GetStorageItem(ctx context.Context, req *connect.Reques
 		return nil, err
 	}
 
-	// Verify file exists on disk
-	if _, err := os.Stat(entry.StoragePath); os.IsNotExist(err) {
-		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("storage item file not found on disk"))
+	// Verify the object exists in the backend
+	exists, err := s.config.Backend.Exists(ctx, entry.StoragePath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to check storage item: %w", err))
+	}
+	if !exists {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("storage item not found in storage backend"))
 	}
 
 	item := &servicev1.StorageItem{
@@ -142,6 +217,39 @@ func (s *StorageService) GetStorageItem(ctx context.Context, req *connect.Reques
 	}), nil
 }
 
+// GetStorageItemURL returns a short-lived signed URL for fetching a storage item over HTTP
+func (s *StorageService) GetStorageItemURL(ctx context.Context, req *connect.Request[servicev1.GetStorageItemURLRequest]) (*connect.Response[servicev1.GetStorageItemURLResponse], error) {
+	if req.Msg.ItemId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("item_id is required"))
+	}
+
+	entry, err := s.db.GetStorageItemInfo(req.Msg.ItemId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("storage item not found: %w", err))
+	}
+
+	// Verify service access
+	if err := s.verifyServiceAccess(ctx, entry.ServiceID); err != nil {
+		return nil, err
+	}
+
+	ttl := req.Msg.TtlSeconds
+	if ttl <= 0 {
+		ttl = 300 // Default 5 minutes
+	}
+	if ttl > 3600 {
+		ttl = 3600 // Max 1 hour
+	}
+
+	expiresAt := time.Now().Add(time.Duration(ttl) * time.Second)
+	signedURL := storageurl.Sign(s.config.URLSigningKey, entry.ID, expiresAt)
+
+	return connect.NewResponse(&servicev1.GetStorageItemURLResponse{
+		Url:       signedURL,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}), nil
+}
+
 // DeleteOldStorageItems deletes storage items older than the specified duration
 func (s *StorageService) DeleteOldStorageItems(ctx context.Context, req *connect.Request[servicev1.DeleteOldStorageItemsRequest]) (*connect.Response[servicev1.DeleteOldStorageItemsResponse], error) {
 	if req.Msg.ServiceId == "" {
@@ -160,9 +268,29 @@ func (s *StorageService) DeleteOldStorageItems(ctx context.Context, req *connect
 	// Use type from request (empty string = all types)
 	storageType := req.Msg.Type
 
-	deletedCount, err := s.db.DeleteOldStorageItems(req.Msg.ServiceId, storageType, req.Msg.OlderThanSeconds)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete old storage items: %w", err))
+	cutoff := time.Now().Add(-time.Duration(req.Msg.OlderThanSeconds) * time.Second)
+
+	// Remove the stored object before its row, so a failed delete can be retried
+	var deletedCount int64
+	for {
+		entries, err := s.db.ListOldStorageItems(req.Msg.ServiceId, storageType, cutoff, deleteOldBatchSize)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list old storage items: %w", err))
+		}
+
+		for _, entry := range entries {
+			if err := s.config.Backend.Delete(ctx, entry.StoragePath); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete %s after %d items: %w", entry.StoragePath, deletedCount, err))
+			}
+			if err := s.db.DeleteStorageItem(entry.ID); err != nil {
+				return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete storage item %s after %d items: %w", entry.ID, deletedCount, err))
+			}
+			deletedCount++
+		}
+
+		if len(entries) < deleteOldBatchSize {
+			break
+		}
 	}
 
 	log.Printf("[Storage] Deleted %d storage items for service %s (older than %d seconds)", deletedCount, req.Msg.ServiceId, req.Msg.OlderThanSeconds)
@@ -172,30 +300,238 @@ func (s *StorageService) DeleteOldStorageItems(ctx context.Context, req *connect
 	}), nil
 }
 
+// ExportClip stitches recorded segments into a single MP4 clip and stores it as a "clip" item
+func (s *StorageService) ExportClip(ctx context.Context, req *connect.Request[servicev1.ExportClipRequest]) (*connect.Response[servicev1.ExportClipResponse], error) {
+	var serviceID string
+	var from, to time.Time
+
+	if req.Msg.EventId != "" {
+		event, err := s.verifyEventAccess(ctx, req.Msg.EventId)
+		if err != nil {
+			return nil, err
+		}
+		serviceID = event.ServiceId
+		from, to = eventTimeRange(event)
+	} else {
+		if req.Msg.ServiceId == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("event_id or service_id is required"))
+		}
+		if req.Msg.From == nil || req.Msg.To == nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from and to are required with service_id"))
+		}
+		serviceID = req.Msg.ServiceId
+		from = req.Msg.From.AsTime()
+		to = req.Msg.To.AsTime()
+		if to.Before(from) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("to must not be before from"))
+		}
+
+		// Verify service access
+		if err := s.verifyServiceAccess(ctx, serviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply pre/post-roll
+	preRoll := s.config.ClipPreRoll
+	if req.Msg.PreRollSeconds != nil {
+		preRoll = time.Duration(req.Msg.GetPreRollSeconds()) * time.Second
+	}
+	postRoll := s.config.ClipPostRoll
+	if req.Msg.PostRollSeconds != nil {
+		postRoll = time.Duration(req.Msg.GetPostRollSeconds()) * time.Second
+	}
+	if preRoll < 0 || postRoll < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("pre_roll_seconds and post_roll_seconds must not be negative"))
+	}
+	from = from.Add(-preRoll)
+	to = to.Add(postRoll)
+	if !to.After(from) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("clip must be longer than zero seconds"))
+	}
+
+	entries, err := s.db.ListSegmentsInRange(serviceID, from, to)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list segments: %w", err))
+	}
+	if len(entries) == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no recorded segments between %s and %s", timeutil.FormatToISO(from), timeutil.FormatToISO(to)))
+	}
+
+	segments := make([]webrtc.ClipSegment, 0, len(entries))
+	for _, entry := range entries {
+		location, err := s.config.Backend.Locate(ctx, entry.StoragePath, time.Hour)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to locate segment: %w", err))
+		}
+		segments = append(segments, webrtc.ClipSegment{
+			Source:    location.String(),
+			StartTime: entry.Timestamp,
+			Duration:  segmentDuration(entry),
+		})
+	}
+
+	// Clamp the clip start to the recorded range so the stored timestamp is accurate
+	if first := entries[0].Timestamp; from.Before(first) {
+		from = first
+	}
+
+	clipID := uuid.New().String()
+	clipPath := filepath.Join(s.config.ClipsDir, serviceID, clipID+".mp4")
+	if err := webrtc.ExportClip(ctx, segments, from, to, clipPath); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to export clip: %w", err))
+	}
+
+	info, err := os.Stat(clipPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to stat clip: %w", err))
+	}
+
+	key := path.Join("clips", serviceID, clipID+".mp4")
+	if err := s.config.Backend.PutFile(ctx, key, clipPath, "video/mp4"); err != nil {
+		os.Remove(clipPath)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to store clip: %w", err))
+	}
+
+	// Recording gaps are left out of the clip
+	_, duration := webrtc.ClipWindow(segments, from, to)
+	metadata := &database.ClipMetadata{
+		EventID:    req.Msg.EventId,
+		From:       timeutil.FormatToISO(from),
+		To:         timeutil.FormatToISO(to),
+		DurationMs: duration.Milliseconds(),
+	}
+	itemID, err := s.db.SaveStorageItem(serviceID, key, from, info.Size(), database.StorageTypeClip, "video/mp4", metadata)
+	if err != nil {
+		s.config.Backend.Delete(ctx, key)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to save clip: %w", err))
+	}
+
+	log.Printf("[Storage] Exported clip %s for service %s (%d segments, %s - %s)", itemID, serviceID, len(segments), metadata.From, metadata.To)
+
+	return connect.NewResponse(&servicev1.ExportClipResponse{
+		Item: &servicev1.StorageItem{
+			Id:        itemID,
+			ServiceId: serviceID,
+			Type:      string(database.StorageTypeClip),
+			Size:      info.Size(),
+			Timestamp: timestamppb.New(from),
+		},
+	}), nil
+}
+
+// ReanalyzeFrames asks a new question about the stored frames of a service in a time range
+func (s *StorageService) ReanalyzeFrames(ctx context.Context, req *connect.Request[servicev1.ReanalyzeFramesRequest]) (*connect.Response[servicev1.ReanalyzeFramesResponse], error) {
+	if req.Msg.ServiceId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("service_id is required"))
+	}
+	if req.Msg.From == nil || req.Msg.To == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from and to are required"))
+	}
+	if strings.TrimSpace(req.Msg.Question) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("question is required"))
+	}
+	if s.config.FrameAnalyzer == nil {
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("frame analysis is not available"))
+	}
+
+	// Verify service access
+	if err := s.verifyServiceAccess(ctx, req.Msg.ServiceId); err != nil {
+		return nil, err
+	}
+
+	from, to := req.Msg.From.AsTime(), req.Msg.To.AsTime()
+	if !from.Before(to) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from must be before to"))
+	}
+	if to.Sub(from) > maxReanalysisRange {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("time range must be at most %v", maxReanalysisRange))
+	}
+
+	result, err := s.config.FrameAnalyzer.Analyze(ctx, req.Msg.ServiceId, from, to, req.Msg.Question, int(req.Msg.MaxFrames))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to analyze frames: %w", err))
+	}
+
+	return connect.NewResponse(result), nil
+}
+
+// segmentDuration reads the recorded length of a segment (0 = unknown)
+func segmentDuration(entry *database.StorageEntry) time.Duration {
+	if entry.Metadata == "" {
+		return 0
+	}
+	var metadata database.SegmentMetadata
+	if err := json.Unmarshal([]byte(entry.Metadata), &metadata); err != nil {
+		return 0
+	}
+	return time.Duration(metadata.DurationMs) * time.Millisecond
+}
+
+// eventTimeRange returns the time window an event covers.
+// VLM events carry from_iso/to_iso; other events fall back to their creation time.
+func eventTimeRange(event *servicev1.Event) (time.Time, time.Time) {
+	createdAt := event.CreatedAt.AsTime()
+	from, to := createdAt, createdAt
+
+	if event.Payload != nil {
+		fields := event.Payload.GetFields()
+		if v, ok := fields["from_iso"]; ok {
+			if t, err := time.Parse(time.RFC3339, v.GetStringValue()); err == nil {
+				from = t
+			}
+		}
+		if v, ok := fields["to_iso"]; ok {
+			if t, err := time.Parse(time.RFC3339, v.GetStringValue()); err == nil {
+				to = t
+			}
+		}
+	}
+
+	if to.Before(from) {
+		to = from
+	}
+	return from, to
+}
+
 // Ensure StorageService implements interface
 var _ servicev1connect.StorageServiceHandler = (*StorageService)(nil)
 
-// RegisterHTTPHandlers registers HTTP handlers for serving JPEG files
+// RegisterHTTPHandlers registers HTTP handlers for serving stored files
 func (s *StorageService) RegisterHTTPHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/storage/", s.serveStorage)
 	log.Printf("[Storage] Registered HTTP handler for /storage/")
 }
 
-// serveStorage serves JPEG files via HTTP
+// serveStorage serves stored files (frames, segments, clips) via HTTP
 // URL format: /storage/{itemID}
-// NOTE: This HTTP endpoint does NOT have authentication (assuming auth is handled upstream, e.g., reverse proxy)
+// Requires either a user JWT in the Authorization header (plus node access),
+// or a signed URL from GetStorageItemURL (?expires=...&sig=...).
+// Missing/invalid/expired credentials get 401, valid users without access get 403.
 func (s *StorageService) serveStorage(w http.ResponseWriter, r *http.Request) {
 	// Extract itemID from path: /storage/{itemID}
-	path := r.URL.Path
-	if len(path) < 10 || path[:10] != "/storage/" {
+	itemID, ok := strings.CutPrefix(r.URL.Path, "/storage/")
+	if !ok || itemID == "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	itemID := path[10:] // Remove "/storage/"
-	if itemID == "" {
-		http.NotFound(w, r)
-		return
+	// Authenticate before touching the database so item IDs can't be probed
+	query := r.URL.Query()
+	signed := query.Has("sig")
+	var ctx context.Context
+	if signed {
+		if err := storageurl.Verify(s.config.URLSigningKey, itemID, query); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	} else {
+		var err error
+		ctx, err = s.auth.AuthenticateRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), httpStatusFromError(err))
+			return
+		}
 	}
 
 	// Look up item in database to get file path
@@ -205,14 +541,50 @@ func (s *StorageService) serveStorage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Signed URLs were checked for access when minted
+	if !signed {
+		if err := s.verifyServiceAccess(ctx, entry.ServiceID); err != nil {
+			http.Error(w, err.Error(), httpStatusFromError(err))
+			return
+		}
+	}
+
+	location, err := s.config.Backend.Locate(r.Context(), entry.StoragePath, 15*time.Minute)
+	if err != nil {
+		log.Printf("[Storage] Failed to locate storage item %s: %v", entry.ID, err)
+		http.Error(w, "failed to locate storage item", http.StatusInternalServerError)
+		return
+	}
+
+	// Remote backends: redirect to a presigned URL so bytes go straight from the bucket
+	if location.URL != "" {
+		w.Header().Set("Cache-Control", "no-store") // Presigned URLs expire
+		http.Redirect(w, r, location.URL, http.StatusFound)
+		return
+	}
+
 	// Check if file exists on disk
-	if _, err := os.Stat(entry.StoragePath); os.IsNotExist(err) {
+	if _, err := os.Stat(location.Path); os.IsNotExist(err) {
 		http.NotFound(w, r)
 		return
 	}
 
 	// Serve file
 	w.Header().Set("Content-Type", entry.ContentType)
-	w.Header().Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
-	http.ServeFile(w, r, entry.StoragePath)
+	w.Header().Set("Cache-Control", "private, max-age=3600") // Cache for 1 hour, never in shared caches
+	http.ServeFile(w, r, location.Path)
+}
+
+// httpStatusFromError maps a connect error code to an HTTP status
+func httpStatusFromError(err error) int {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
 }
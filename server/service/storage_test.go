@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/storageurl"
+	"unblink/server/internal/timeutil"
+	"unblink/server/webrtc"
+)
+
+// fakeStorageDatabase holds services, events and storage items in memory.
+// Nodes listed in owners are private to that user; other nodes are public.
+type fakeStorageDatabase struct {
+	StorageDatabase
+	services map[string]*servicev1.Service
+	owners   map[string]string
+	events   map[string]*servicev1.Event
+	items    map[string]*database.StorageEntry
+
+	eventLookups   int
+	segmentLookups int
+}
+
+func newFakeStorageDatabase() *fakeStorageDatabase {
+	return &fakeStorageDatabase{
+		services: map[string]*servicev1.Service{
+			"cam1": {Id: "cam1", NodeId: "node1"},
+			"cam2": {Id: "cam2", NodeId: "node2"},
+		},
+		owners: map[string]string{"node1": "alice", "node2": "bob"},
+		events: map[string]*servicev1.Event{},
+		items:  map[string]*database.StorageEntry{},
+	}
+}
+
+func (f *fakeStorageDatabase) GetService(id string) (*servicev1.Service, error) {
+	return f.services[id], nil
+}
+
+func (f *fakeStorageDatabase) CheckNodeAccess(nodeID, userID string) (bool, error) {
+	owner, ok := f.owners[nodeID]
+	return !ok || owner == userID, nil
+}
+
+func (f *fakeStorageDatabase) GetEvent(id string) (*servicev1.Event, error) {
+	f.eventLookups++
+	return f.events[id], nil
+}
+
+func (f *fakeStorageDatabase) GetStorageItemInfo(itemID string) (*database.StorageEntry, error) {
+	entry, ok := f.items[itemID]
+	if !ok {
+		return nil, fmt.Errorf("storage item not found")
+	}
+	return entry, nil
+}
+
+func (f *fakeStorageDatabase) ListSegmentsInRange(serviceID string, from, to time.Time) ([]*database.StorageEntry, error) {
+	f.segmentLookups++
+	var result []*database.StorageEntry
+	for _, entry := range f.items {
+		if entry.ServiceID == serviceID && entry.Type == database.StorageTypeSegment &&
+			entry.Timestamp.Before(to) && entry.Timestamp.Add(segmentDuration(entry)).After(from) {
+			result = append(result, entry)
+		}
+	}
+	// Ordered by start time like the database
+	for i := 1; i < len(result); i++ {
+		for j := i; j > 0 && result[j].Timestamp.Before(result[j-1].Timestamp); j-- {
+			result[j], result[j-1] = result[j-1], result[j]
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStorageDatabase) SaveStorageItem(serviceID, storagePath string, timestamp time.Time, fileSize int64, storageType database.StorageType, contentType string, metadata any) (string, error) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	id := fmt.Sprintf("item%d", len(f.items)+1)
+	f.items[id] = &database.StorageEntry{
+		ID:          id,
+		ServiceID:   serviceID,
+		Type:        storageType,
+		StoragePath: storagePath,
+		Timestamp:   timestamp,
+		FileSize:    fileSize,
+		ContentType: contentType,
+		Metadata:    string(metadataJSON),
+	}
+	return id, nil
+}
+
+// fakeAuthenticator accepts "Bearer <user ID>"
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) AuthenticateRequest(r *http.Request) (context.Context, error) {
+	userID, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || userID == "" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("missing token"))
+	}
+	return ctxutil.SetUserIDInContext(r.Context(), userID), nil
+}
+
+// remoteBackend locates every object at a presigned URL
+type remoteBackend struct {
+	webrtc.StorageBackend
+}
+
+func (remoteBackend) Locate(ctx context.Context, key string, ttl time.Duration) (webrtc.Location, error) {
+	return webrtc.Location{URL: "https://bucket.example.com/" + key + "?X-Amz-Signature=abc"}, nil
+}
+
+func newTestStorageService(t *testing.T, db *fakeStorageDatabase) *StorageService {
+	dir := t.TempDir()
+	return NewStorageService(db, &StorageConfig{
+		Backend:       webrtc.NewLocalBackend(filepath.Join(dir, "storage")),
+		ClipsDir:      filepath.Join(dir, "clips"),
+		ClipPreRoll:   5 * time.Second,
+		ClipPostRoll:  5 * time.Second,
+		URLSigningKey: []byte("test-key"),
+	}, fakeAuthenticator{})
+}
+
+func asUser(userID string) context.Context {
+	return ctxutil.SetUserIDInContext(context.Background(), userID)
+}
+
+func TestExportClipHidesOtherUsersEvents(t *testing.T) {
+	db := newFakeStorageDatabase()
+	db.events["e2"] = &servicev1.Event{Id: "e2", ServiceId: "cam2", CreatedAt: timestamppb.Now()}
+	s := newTestStorageService(t, db)
+
+	// Unauthenticated callers don't reach the database
+	_, err := s.ExportClip(context.Background(), connect.NewRequest(&servicev1.ExportClipRequest{EventId: "e2"}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	assert.Zero(t, db.eventLookups)
+
+	// Another user's event looks the same as a missing one
+	_, notFound := s.ExportClip(asUser("alice"), connect.NewRequest(&servicev1.ExportClipRequest{EventId: "missing"}))
+	_, denied := s.ExportClip(asUser("alice"), connect.NewRequest(&servicev1.ExportClipRequest{EventId: "e2"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(notFound))
+	assert.Equal(t, notFound.Error(), denied.Error())
+	assert.Zero(t, db.segmentLookups)
+
+	// The owner gets past the access check
+	_, err = s.ExportClip(asUser("bob"), connect.NewRequest(&servicev1.ExportClipRequest{EventId: "e2"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	assert.Contains(t, err.Error(), "no recorded segments")
+	assert.Equal(t, 1, db.segmentLookups)
+}
+
+func TestExportClipValidation(t *testing.T) {
+	db := newFakeStorageDatabase()
+	s := newTestStorageService(t, db)
+	from := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	negative := int32(-1)
+
+	tests := []struct {
+		name string
+		req  *servicev1.ExportClipRequest
+		code connect.Code
+	}{
+		{"no event or service", &servicev1.ExportClipRequest{}, connect.CodeInvalidArgument},
+		{"no range", &servicev1.ExportClipRequest{ServiceId: "cam1"}, connect.CodeInvalidArgument},
+		{"reversed range", &servicev1.ExportClipRequest{ServiceId: "cam1", From: timestamppb.New(from), To: timestamppb.New(from.Add(-time.Second))}, connect.CodeInvalidArgument},
+		{"negative pre-roll", &servicev1.ExportClipRequest{ServiceId: "cam1", From: timestamppb.New(from), To: timestamppb.New(from.Add(time.Minute)), PreRollSeconds: &negative}, connect.CodeInvalidArgument},
+		{"other user's service", &servicev1.ExportClipRequest{ServiceId: "cam2", From: timestamppb.New(from), To: timestamppb.New(from.Add(time.Minute))}, connect.CodePermissionDenied},
+		{"no segments", &servicev1.ExportClipRequest{ServiceId: "cam1", From: timestamppb.New(from), To: timestamppb.New(from.Add(time.Minute))}, connect.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ExportClip(asUser("alice"), connect.NewRequest(tt.req))
+			assert.Equal(t, tt.code, connect.CodeOf(err), "%v", err)
+		})
+	}
+}
+
+// writeTestSegment records a short test pattern segment with FFmpeg
+func writeTestSegment(t *testing.T, path string, duration time.Duration) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	out, err := exec.Command("ffmpeg", "-loglevel", "error", "-y",
+		"-f", "lavfi", "-i", fmt.Sprintf("testsrc=size=64x64:rate=10:duration=%.0f", duration.Seconds()),
+		"-c:v", "libx264", "-g", "10", path,
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestExportClipAcrossGap(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+
+	db := newFakeStorageDatabase()
+	s := newTestStorageService(t, db)
+	storageDir := filepath.Join(filepath.Dir(s.config.ClipsDir), "storage")
+
+	// Two 4s segments with a minute between them
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{start, start.Add(time.Minute)} {
+		key := fmt.Sprintf("segments/cam1/%d.mp4", i)
+		writeTestSegment(t, filepath.Join(storageDir, key), 4*time.Second)
+		db.items[fmt.Sprintf("seg%d", i)] = &database.StorageEntry{
+			ID:          fmt.Sprintf("seg%d", i),
+			ServiceID:   "cam1",
+			Type:        database.StorageTypeSegment,
+			StoragePath: key,
+			Timestamp:   at,
+			Metadata:    `{"duration_ms": 4000}`,
+		}
+	}
+
+	// 2s before the gap and 2s after it
+	noRoll := int32(0)
+	resp, err := s.ExportClip(asUser("alice"), connect.NewRequest(&servicev1.ExportClipRequest{
+		ServiceId:       "cam1",
+		From:            timestamppb.New(start.Add(2 * time.Second)),
+		To:              timestamppb.New(start.Add(time.Minute + 2*time.Second)),
+		PreRollSeconds:  &noRoll,
+		PostRollSeconds: &noRoll,
+	}))
+	require.NoError(t, err)
+
+	clip := db.items[resp.Msg.Item.Id]
+	require.NotNil(t, clip)
+	assert.Equal(t, database.StorageTypeClip, clip.Type)
+
+	var metadata database.ClipMetadata
+	require.NoError(t, json.Unmarshal([]byte(clip.Metadata), &metadata))
+	assert.Equal(t, int64(4000), metadata.DurationMs)
+	assert.Equal(t, timeutil.FormatToISO(start.Add(2*time.Second)), metadata.From)
+
+	exists, err := s.config.Backend.Exists(context.Background(), clip.StoragePath)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestSegmentDuration(t *testing.T) {
+	assert.Equal(t, 4500*time.Millisecond, segmentDuration(&database.StorageEntry{Metadata: `{"duration_ms": 4500}`}))
+	assert.Zero(t, segmentDuration(&database.StorageEntry{}))
+	assert.Zero(t, segmentDuration(&database.StorageEntry{Metadata: `not json`}))
+}
+
+func TestEventTimeRange(t *testing.T) {
+	createdAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	payload, err := structpb.NewStruct(map[string]any{
+		"from_iso": "2026-10-16T11:58:00Z",
+		"to_iso":   "2026-10-16T11:59:00Z",
+	})
+	require.NoError(t, err)
+
+	from, to := eventTimeRange(&servicev1.Event{CreatedAt: timestamppb.New(createdAt), Payload: payload})
+	assert.Equal(t, createdAt.Add(-2*time.Minute), from)
+	assert.Equal(t, createdAt.Add(-time.Minute), to)
+
+	// Events without a range cover their creation time
+	from, to = eventTimeRange(&servicev1.Event{CreatedAt: timestamppb.New(createdAt)})
+	assert.Equal(t, createdAt, from)
+	assert.Equal(t, createdAt, to)
+}
+
+func TestServeStorage(t *testing.T) {
+	db := newFakeStorageDatabase()
+	s := newTestStorageService(t, db)
+	require.NoError(t, s.config.Backend.Put(context.Background(), "frames/cam2/f1.jpg", []byte("jpeg bytes"), "image/jpeg"))
+	db.items["f1"] = &database.StorageEntry{ID: "f1", ServiceID: "cam2", Type: database.StorageTypeFrame, StoragePath: "frames/cam2/f1.jpg", ContentType: "image/jpeg"}
+
+	mux := http.NewServeMux()
+	s.RegisterHTTPHandlers(mux)
+
+	get := func(target, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if userID != "" {
+			req.Header.Set("Authorization", "Bearer "+userID)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	signed := storageurl.Sign(s.config.URLSigningKey, "f1", time.Now().Add(time.Minute))
+	tests := []struct {
+		name   string
+		target string
+		userID string
+		status int
+	}{
+		{"no credentials", "/storage/f1", "", http.StatusUnauthorized},
+		{"no credentials for a missing item", "/storage/missing", "", http.StatusUnauthorized},
+		{"no access", "/storage/f1", "alice", http.StatusForbidden},
+		{"missing item", "/storage/missing", "bob", http.StatusNotFound},
+		{"owner", "/storage/f1", "bob", http.StatusOK},
+		{"signed URL", signed, "", http.StatusOK},
+		{"tampered signed URL", strings.Replace(signed, "sig=", "sig=0", 1), "", http.StatusUnauthorized},
+		{"signed URL for another item", strings.Replace(signed, "/storage/f1", "/storage/f2", 1), "", http.StatusUnauthorized},
+		{"no item ID", "/storage/", "bob", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.target, tt.userID)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusOK {
+				assert.Equal(t, "jpeg bytes", rec.Body.String())
+				assert.Equal(t, "image/jpeg", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "private, max-age=3600", rec.Header().Get("Cache-Control"))
+			}
+		})
+	}
+
+	// Objects missing from disk are not found
+	require.NoError(t, s.config.Backend.Delete(context.Background(), "frames/cam2/f1.jpg"))
+	assert.Equal(t, http.StatusNotFound, get("/storage/f1", "bob").Code)
+}
+
+func TestServeStorageRedirectsToRemoteBackend(t *testing.T) {
+	db := newFakeStorageDatabase()
+	db.items["f1"] = &database.StorageEntry{ID: "f1", ServiceID: "cam1", Type: database.StorageTypeFrame, StoragePath: "frames/cam1/f1.jpg", ContentType: "image/jpeg"}
+	s := NewStorageService(db, &StorageConfig{Backend: remoteBackend{}, URLSigningKey: []byte("test-key")}, fakeAuthenticator{})
+
+	req := httptest.NewRequest(http.MethodGet, "/storage/f1", nil)
+	req.Header.Set("Authorization", "Bearer alice")
+	rec := httptest.NewRecorder()
+	s.serveStorage(rec, req)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://bucket.example.com/frames/cam1/f1.jpg?X-Amz-Signature=abc", rec.Header().Get("Location"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+}
+
+func TestGetStorageItemURL(t *testing.T) {
+	db := newFakeStorageDatabase()
+	s := newTestStorageService(t, db)
+	require.NoError(t, s.config.Backend.Put(context.Background(), "frames/cam1/f1.jpg", []byte("jpeg bytes"), "image/jpeg"))
+	db.items["f1"] = &database.StorageEntry{ID: "f1", ServiceID: "cam1", Type: database.StorageTypeFrame, StoragePath: "frames/cam1/f1.jpg", ContentType: "image/jpeg"}
+
+	// Only users with access get a URL
+	_, err := s.GetStorageItemURL(asUser("bob"), connect.NewRequest(&servicev1.GetStorageItemURLRequest{ItemId: "f1"}))
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	resp, err := s.GetStorageItemURL(asUser("alice"), connect.NewRequest(&servicev1.GetStorageItemURLRequest{ItemId: "f1", TtlSeconds: 60}))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), resp.Msg.ExpiresAt.AsTime(), 5*time.Second)
+
+	// The URL works without credentials
+	rec := httptest.NewRecorder()
+	s.serveStorage(rec, httptest.NewRequest(http.MethodGet, resp.Msg.Url, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg bytes", string(body))
+}
+
+func TestHTTPStatusFromError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{connect.NewError(connect.CodeUnauthenticated, errors.New("missing token")), http.StatusUnauthorized},
+		{connect.NewError(connect.CodePermissionDenied, errors.New("no access")), http.StatusForbidden},
+		{connect.NewError(connect.CodeNotFound, errors.New("service not found")), http.StatusNotFound},
+		{connect.NewError(connect.CodeInternal, errors.New("database down")), http.StatusInternalServerError},
+		{fmt.Errorf("wrapped: %w", connect.NewError(connect.CodePermissionDenied, errors.New("no access"))), http.StatusForbidden},
+		{errors.New("plain error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.status, httpStatusFromError(tt.err), "%v", tt.err)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/gen/service/v1/servicev1connect"
+	"unblink/server/internal/ctxutil"
+	"unblink/server/internal/netguard"
+)
+
+// Limits for user-created webhooks
+const (
+	maxWebhookURLLength       = 2000
+	maxWebhookEventTypes      = 20
+	maxWebhookEventTypeLength = 50
+)
+
+// WebhookDatabase defines the database operations for webhooks
+type WebhookDatabase interface {
+	GetService(id string) (*servicev1.Service, error)
+	CheckNodeAccess(nodeID, userID string) (bool, error)
+	CreateWebhook(userID, secret string, webhook *servicev1.Webhook) error
+	UpdateWebhook(webhook *servicev1.Webhook) error
+	GetWebhook(id, userID string) (*servicev1.Webhook, error)
+	DeleteWebhook(id string) error
+	ListWebhooksByUser(userID, nodeID string) ([]*servicev1.Webhook, error)
+	ListWebhookDeliveries(webhookID, status string, pageSize, pageOffset int32) ([]*servicev1.WebhookDelivery, int32, error)
+	GetWebhookDelivery(id string) (*servicev1.WebhookDelivery, error)
+	RequeueWebhookDelivery(id string) error
+}
+
+type WebhookService struct {
+	db WebhookDatabase
+}
+
+func NewWebhookService(db WebhookDatabase) *WebhookService {
+	return &WebhookService{db: db}
+}
+
+// CreateWebhook subscribes the current user to the events of a node or service
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *connect.Request[servicev1.CreateWebhookRequest]) (*connect.Response[servicev1.CreateWebhookResponse], error) {
+	userID, err := ctxutil.GetRequiredUserIDFromContext(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	if req.Msg.NodeId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("node_id is required"))
+	}
+
+	// Verify node access first
+	if err := ctxutil.CheckNodeAccessWithContext(ctx, s.db, req.Msg.NodeId); err != nil {
+		return nil, err
+	}
+
+	if req.Msg.ServiceId != "" {
+		svc, err := s.db.GetService(req.Msg.ServiceId)
+		if err != nil || svc == nil || svc.NodeId != req.Msg.NodeId {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("service %s not found on node %s", req.Msg.ServiceId, req.Msg.NodeId))
+		}
+	}
+
+	now := time.Now()
+	webhook := &servicev1.Webhook{
+		Id:         generateID(),
+		NodeId:     req.Msg.NodeId,
+		ServiceId:  req.Msg.ServiceId,
+		Url:        strings.TrimSpace(req.Msg.Url),
+		EventTypes: normalizeEventTypes(req.Msg.EventTypes),
+		Enabled:    true,
+		CreatedAt:  timestamppb.New(now),
+		UpdatedAt:  timestamppb.New(now),
+	}
+	if err := validateWebhook(ctx, webhook); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	secret := generateWebhookSecret()
+	if err := s.db.CreateWebhook(userID, secret, webhook); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to create webhook: %w", err))
+	}
+
+	log.Printf("[WebhookService] Created webhook: id=%s, user=%s, node_id=%s, service_id=%s", webhook.Id, userID, webhook.NodeId, webhook.ServiceId)
+
+	// The secret is only ever returned here
+	webhook.Secret = secret
+	return connect.NewResponse(&servicev1.CreateWebhookResponse{
+		Webhook: webhook,
+	}), nil
+}
+
+// ListWebhooks lists the current user's webhooks
+func (s *WebhookService) ListWebhooks(ctx context.Context, req *connect.Request[servicev1.ListWebhooksRequest]) (*connect.Response[servicev1.ListWebhooksResponse], error) {
+	userID, err := ctxutil.GetRequiredUserIDFromContext(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	webhooks, err := s.db.ListWebhooksByUser(userID, req.Msg.NodeId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list webhooks: %w", err))
+	}
+
+	return connect.NewResponse(&servicev1.ListWebhooksResponse{
+		Webhooks: webhooks,
+	}), nil
+}
+
+// UpdateWebhook changes the URL, event types or enabled state of a webhook
+func (s *WebhookService) UpdateWebhook(ctx context.Context, req *connect.Request[servicev1.UpdateWebhookRequest]) (*connect.Response[servicev1.UpdateWebhookResponse], error) {
+	webhook, err := s.getWebhook(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Url = strings.TrimSpace(req.Msg.Url)
+	webhook.EventTypes = normalizeEventTypes(req.Msg.EventTypes)
+	webhook.Enabled = req.Msg.Enabled
+	if err := validateWebhook(ctx, webhook); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if err := s.db.UpdateWebhook(webhook); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to update webhook: %w", err))
+	}
+	webhook.UpdatedAt = timestamppb.New(time.Now())
+
+	log.Printf("[WebhookService] Updated webhook: id=%s, enabled=%v", webhook.Id, webhook.Enabled)
+
+	return connect.NewResponse(&servicev1.UpdateWebhookResponse{
+		Webhook: webhook,
+	}), nil
+}
+
+// DeleteWebhook deletes a webhook together with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, req *connect.Request[servicev1.DeleteWebhookRequest]) (*connect.Response[servicev1.DeleteWebhookResponse], error) {
+	webhook, err := s.getWebhook(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.DeleteWebhook(webhook.Id); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to delete webhook: %w", err))
+	}
+
+	log.Printf("[WebhookService] Deleted webhook: id=%s", webhook.Id)
+
+	return connect.NewResponse(&servicev1.DeleteWebhookResponse{
+		Success: true,
+	}), nil
+}
+
+// ListWebhookDeliveries lists the delivery log of a webhook, newest first
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, req *connect.Request[servicev1.ListWebhookDeliveriesRequest]) (*connect.Response[servicev1.ListWebhookDeliveriesResponse], error) {
+	webhook, err := s.getWebhook(ctx, req.Msg.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Msg.Status {
+	case "", database.WebhookDeliveryPending, database.WebhookDeliveryDelivered, database.WebhookDeliveryDead:
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid status %q", req.Msg.Status))
+	}
+
+	deliveries, totalCount, err := s.db.ListWebhookDeliveries(webhook.Id, req.Msg.Status, req.Msg.PageSize, req.Msg.PageOffset)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to list webhook deliveries: %w", err))
+	}
+
+	return connect.NewResponse(&servicev1.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		TotalCount: totalCount,
+	}), nil
+}
+
+// RetryWebhookDelivery queues a finished delivery again, e.g. from the dead-letter state
+func (s *WebhookService) RetryWebhookDelivery(ctx context.Context, req *connect.Request[servicev1.RetryWebhookDeliveryRequest]) (*connect.Response[servicev1.RetryWebhookDeliveryResponse], error) {
+	if req.Msg.Id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id is required"))
+	}
+
+	delivery, err := s.db.GetWebhookDelivery(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get webhook delivery: %w", err))
+	}
+	if delivery == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("webhook delivery not found"))
+	}
+
+	// Only the owner of the webhook may retry its deliveries
+	if _, err := s.getWebhook(ctx, delivery.WebhookId); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == database.WebhookDeliveryPending {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("delivery is already pending"))
+	}
+
+	if err := s.db.RequeueWebhookDelivery(delivery.Id); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to requeue webhook delivery: %w", err))
+	}
+
+	log.Printf("[WebhookService] Requeued webhook delivery: id=%s, webhook=%s", delivery.Id, delivery.WebhookId)
+
+	delivery.Status = database.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = timestamppb.New(time.Now())
+	return connect.NewResponse(&servicev1.RetryWebhookDeliveryResponse{
+		Delivery: delivery,
+	}), nil
+}
+
+// getWebhook loads a webhook of the current user
+func (s *WebhookService) getWebhook(ctx context.Context, id string) (*servicev1.Webhook, error) {
+	userID, err := ctxutil.GetRequiredUserIDFromContext(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	if id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("id is required"))
+	}
+
+	webhook, err := s.db.GetWebhook(id, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get webhook: %w", err))
+	}
+	if webhook == nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("webhook not found"))
+	}
+	return webhook, nil
+}
+
+// validateWebhook checks the user-supplied fields of a webhook
+func validateWebhook(ctx context.Context, webhook *servicev1.Webhook) error {
+	if webhook.Url == "" {
+		return fmt.Errorf("url is required")
+	}
+	if len(webhook.Url) > maxWebhookURLLength {
+		return fmt.Errorf("url must be at most %d characters", maxWebhookURLLength)
+	}
+	u, err := url.Parse(webhook.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http:// or https:// URL")
+	}
+	// Deliveries are checked again when sent, as the name may resolve elsewhere by then
+	if err := netguard.CheckURL(ctx, webhook.Url); err != nil {
+		return fmt.Errorf("url must point to a public address: %w", err)
+	}
+
+	if len(webhook.EventTypes) > maxWebhookEventTypes {
+		return fmt.Errorf("at most %d event types are allowed", maxWebhookEventTypes)
+	}
+	for _, eventType := range webhook.EventTypes {
+		if len(eventType) > maxWebhookEventTypeLength {
+			return fmt.Errorf("event type %q is too long", eventType)
+		}
+	}
+	return nil
+}
+
+// normalizeEventTypes trims event types and drops empty and repeated ones
+func normalizeEventTypes(eventTypes []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		result = append(result, eventType)
+	}
+	return result
+}
+
+// generateWebhookSecret creates a random signing secret
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// Ensure WebhookService implements interface
+var _ servicev1connect.WebhookServiceHandler = (*WebhookService)(nil)
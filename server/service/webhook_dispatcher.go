@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"unblink/database"
+	"unblink/server/internal/netguard"
+	"unblink/server/internal/timeutil"
+)
+
+const (
+	// webhookPollInterval is how often the outbox is checked for due deliveries
+	webhookPollInterval = 2 * time.Second
+
+	// webhookBatchSize is the number of deliveries claimed (and sent in parallel) at once
+	webhookBatchSize = 20
+
+	// webhookTimeout bounds one delivery attempt
+	webhookTimeout = 10 * time.Second
+
+	// webhookLease keeps a claimed delivery from being claimed again while it is sent
+	webhookLease = time.Minute
+
+	// webhookMaxAttempts is the number of attempts before a delivery is dead
+	webhookMaxAttempts = 10
+
+	// First retry delay, doubled for every further attempt up to the maximum
+	webhookRetryBase = 10 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+
+	// Delivered entries are kept in the delivery log this long
+	webhookLogRetention = 7 * 24 * time.Hour
+	webhookPruneEvery   = time.Hour
+)
+
+// WebhookDispatcherDatabase defines the outbox operations of the dispatcher
+type WebhookDispatcherDatabase interface {
+	ClaimWebhookDeliveries(limit int, lease time.Duration) ([]*database.WebhookJob, error)
+	MarkWebhookDelivered(id string, statusCode int) error
+	MarkWebhookFailed(id string, statusCode int, errMsg string, retryIn time.Duration, dead bool) error
+	PruneWebhookDeliveries(maxAge time.Duration) (int64, error)
+}
+
+// WebhookDispatcher sends the deliveries queued in the webhook outbox
+type WebhookDispatcher struct {
+	db     WebhookDispatcherDatabase
+	client *http.Client
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewWebhookDispatcher creates a dispatcher; call Start to begin sending
+func NewWebhookDispatcher(db WebhookDispatcherDatabase) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db: db,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			// Only public addresses; checked on every connection, after DNS
+			Transport: netguard.NewTransport(),
+			// Redirects are not followed; the endpoint must answer itself
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Start runs the dispatcher loop in the background
+func (d *WebhookDispatcher) Start() {
+	go d.run()
+}
+
+// Stop ends the dispatcher loop after the current batch
+func (d *WebhookDispatcher) Stop() {
+	close(d.stop)
+	<-d.stopped
+}
+
+func (d *WebhookDispatcher) run() {
+	defer close(d.stopped)
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	log.Printf("[WebhookDispatcher] Started (poll=%v, maxAttempts=%d)", webhookPollInterval, webhookMaxAttempts)
+
+	lastPrune := time.Time{}
+	for {
+		select {
+		case <-d.stop:
+			log.Printf("[WebhookDispatcher] Stopping")
+			return
+
+		case <-ticker.C:
+			// Keep going while full batches come back, so a backlog drains quickly
+			for d.dispatchBatch() == webhookBatchSize {
+				select {
+				case <-d.stop:
+					log.Printf("[WebhookDispatcher] Stopping")
+					return
+				default:
+				}
+			}
+
+			if time.Since(lastPrune) > webhookPruneEvery {
+				lastPrune = time.Now()
+				if n, err := d.db.PruneWebhookDeliveries(webhookLogRetention); err != nil {
+					log.Printf("[WebhookDispatcher] Failed to prune delivery log: %v", err)
+				} else if n > 0 {
+					log.Printf("[WebhookDispatcher] Pruned %d delivered entries", n)
+				}
+			}
+		}
+	}
+}
+
+// dispatchBatch claims due deliveries, sends them in parallel and records the
+// outcomes. It returns the number of deliveries claimed.
+func (d *WebhookDispatcher) dispatchBatch() int {
+	jobs, err := d.db.ClaimWebhookDeliveries(webhookBatchSize, webhookLease)
+	if err != nil {
+		log.Printf("[WebhookDispatcher] Failed to claim deliveries: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job *database.WebhookJob) {
+			defer wg.Done()
+			d.deliver(job)
+		}(job)
+	}
+	wg.Wait()
+
+	return len(jobs)
+}
+
+// deliver makes one attempt and records it
+func (d *WebhookDispatcher) deliver(job *database.WebhookJob) {
+	statusCode, err := d.send(job, time.Now())
+	if err == nil {
+		if err := d.db.MarkWebhookDelivered(job.DeliveryID, statusCode); err != nil {
+			log.Printf("[WebhookDispatcher] Failed to record delivery %s: %v", job.DeliveryID, err)
+		}
+		return
+	}
+
+	attempts := int(job.Attempts) + 1
+	dead := attempts >= webhookMaxAttempts
+	retryIn := webhookRetryDelay(attempts)
+	if dead {
+		log.Printf("[WebhookDispatcher] Delivery %s to %s failed %d times, giving up: %v", job.DeliveryID, job.URL, attempts, err)
+	} else {
+		log.Printf("[WebhookDispatcher] Delivery %s to %s failed (attempt %d, retry in %v): %v", job.DeliveryID, job.URL, attempts, retryIn, err)
+	}
+
+	if err := d.db.MarkWebhookFailed(job.DeliveryID, statusCode, err.Error(), retryIn, dead); err != nil {
+		log.Printf("[WebhookDispatcher] Failed to record delivery %s: %v", job.DeliveryID, err)
+	}
+}
+
+// webhookBody is the JSON posted for an event
+type webhookBody struct {
+	DeliveryID string         `json:"delivery_id"`
+	EventID    string         `json:"event_id"`
+	EventType  string         `json:"event_type"`
+	NodeID     string         `json:"node_id"`
+	ServiceID  string         `json:"service_id"`
+	CreatedAt  string         `json:"created_at"`
+	Payload    map[string]any `json:"payload"`
+}
+
+// send posts the event of a job and returns the HTTP status (0 if there was no
+// response). Any non-2xx status is an error.
+func (d *WebhookDispatcher) send(job *database.WebhookJob, now time.Time) (int, error) {
+	payload := job.Event.GetPayload().AsMap()
+	eventType, _ := payload["type"].(string)
+
+	body, err := json.Marshal(webhookBody{
+		DeliveryID: job.DeliveryID,
+		EventID:    job.Event.Id,
+		EventType:  eventType,
+		NodeID:     job.NodeID,
+		ServiceID:  job.Event.ServiceId,
+		CreatedAt:  timeutil.FormatToISO(job.Event.GetCreatedAt().AsTime()),
+		Payload:    payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal body: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Unblink-Webhook/1")
+	req.Header.Set("X-Unblink-Delivery", job.DeliveryID)
+	req.Header.Set("X-Unblink-Event", eventType)
+	req.Header.Set("X-Unblink-Timestamp", timestamp)
+	req.Header.Set("X-Unblink-Signature", "sha256="+webhookSignature(job.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// The body is not kept: the delivery log would show the user whatever the
+	// URL answers
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookSignature computes the hex HMAC-SHA256 of "timestamp.body". Including
+// the timestamp lets receivers reject replayed requests.
+func webhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay is the wait after the given number of failed attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMax)
+}
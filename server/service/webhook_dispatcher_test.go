@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"unblink/database"
+	servicev1 "unblink/server/gen/service/v1"
+	"unblink/server/internal/netguard"
+)
+
+func testWebhookJob(t *testing.T, url string) *database.WebhookJob {
+	payload, err := structpb.NewStruct(map[string]any{"type": "alert", "rule_id": "r1"})
+	require.NoError(t, err)
+	return &database.WebhookJob{
+		DeliveryID: "d1",
+		URL:        url,
+		Secret:     "secret",
+		NodeID:     "n1",
+		Event: &servicev1.Event{
+			Id:        "e1",
+			ServiceId: "s1",
+			Payload:   payload,
+			CreatedAt: timestamppb.Now(),
+		},
+	}
+}
+
+func TestWebhookSendRefusesLoopback(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	d := NewWebhookDispatcher(nil)
+	statusCode, err := d.send(testWebhookJob(t, server.URL), time.Now())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	assert.Zero(t, statusCode)
+	assert.False(t, called)
+}
+
+func TestWebhookSend(t *testing.T) {
+	now := time.Now()
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get("X-Unblink-Timestamp")
+		assert.Equal(t, "alert", r.Header.Get("X-Unblink-Event"))
+		assert.Equal(t, "sha256="+webhookSignature("secret", timestamp, body), r.Header.Get("X-Unblink-Signature"))
+
+		w.WriteHeader(status)
+		w.Write([]byte("internal details the user should not see"))
+	}))
+	defer server.Close()
+
+	d := NewWebhookDispatcher(nil)
+	d.client.Transport = netguard.NewTransport(server.URL) // The test server is on loopback
+
+	statusCode, err := d.send(testWebhookJob(t, server.URL), now)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Failures record the status only, not the body
+	status = http.StatusInternalServerError
+	statusCode, err = d.send(testWebhookJob(t, server.URL), now)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Equal(t, "HTTP 500", err.Error())
+}
+
+func TestValidateWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, validateWebhook(ctx, &servicev1.Webhook{Url: "https://8.8.8.8/hook"}))
+
+	for _, url := range []string{
+		"",
+		"ftp://example.com/hook",
+		"http:///hook",
+		"http://127.0.0.1:8080/hook",
+		"http://192.168.1.20/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://localhost/hook",
+	} {
+		assert.Error(t, validateWebhook(ctx, &servicev1.Webhook{Url: url}), url)
+	}
+}
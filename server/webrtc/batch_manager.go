@@ -3,6 +3,7 @@ package webrtc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"strings"
 	"sync"
@@ -28,6 +29,18 @@ type rollingContext struct {
 	lastBatchSentTime int64        // Unix nano timestamp of last batch sent (for ordering)
 }
 
+// detectionInstruction asks for the bounding boxes AnnotateFrame draws; it is
+// kept when a service replaces the base instruction
+const detectionInstruction = "Detect the most important objects (MAX 10) and return bounding boxes in NORMALIZED 1000 COORDINATES (0=top/left, 1000=bottom/right)."
+
+// BatchSettings are a service's overrides of how its frames are batched and analyzed
+type BatchSettings struct {
+	BatchSize   int    // Frames per request, 0 = manager default
+	Model       string // VLM model, empty = client default
+	Instruction string // Replaces the base instruction, empty = built-in
+	Language    string // Language of descriptions, empty = English
+}
+
 // BatchManager accumulates frames and sends them in batches to vLLM
 // It maintains rolling context per service, asking the model to describe only what's new
 type BatchManager struct {
@@ -36,6 +49,8 @@ type BatchManager struct {
 	frameBuffers      map[string][]*Frame        // serviceID -> buffer of frames (max size = batchSize)
 	rollingContexts   map[string]*rollingContext // serviceID -> rolling state
 	processingLocks   map[string]bool            // serviceID -> is currently processing
+	settings          map[string]BatchSettings   // serviceID -> per-service overrides
+	listeners         []ResultListener           // Called for every analyzed batch
 	baseInstruction   string                     // Base instruction
 	storage           *Storage                   // Storage for saving annotated frames
 	db                *database.Client           // Database for events
@@ -51,13 +66,63 @@ func NewBatchManager(client *FrameClient, batchSize int, storage *Storage, db *d
 		frameBuffers:     make(map[string][]*Frame),
 		rollingContexts:  make(map[string]*rollingContext),
 		processingLocks:  make(map[string]bool),
-		baseInstruction:  "Analyze these video frames for motion, action, emotion, facial expressions, and subtle details. Detect the most important objects (MAX 10) and return bounding boxes in NORMALIZED 1000 COORDINATES (0=top/left, 1000=bottom/right).",
+		settings:         make(map[string]BatchSettings),
+		baseInstruction:  "Analyze these video frames for motion, action, emotion, facial expressions, and subtle details. " + detectionInstruction,
 		storage:          storage,
 		db:               db,
 		eventBroadcaster: broadcaster,
 	}
 }
 
+// BatchResult is an analyzed batch, passed to result listeners once its event is stored
+type BatchResult struct {
+	ServiceID string
+	NodeID    string
+	EventID   string    // The vlm-indexing event
+	From      time.Time // First and last frame of the batch
+	To        time.Time
+	Response  *VLMResponse
+}
+
+// ResultListener receives every analyzed batch. It runs on the batch's
+// goroutine and should hand off slow work.
+type ResultListener func(*BatchResult)
+
+// AddResultListener registers a listener for analyzed batches
+func (m *BatchManager) AddResultListener(listener ResultListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// notifyResult passes a batch result to every listener
+func (m *BatchManager) notifyResult(result *BatchResult) {
+	m.mu.Lock()
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(result)
+	}
+}
+
+// SetServiceSettings sets the batch settings of a service; they apply from the next frame
+func (m *BatchManager) SetServiceSettings(serviceID string, settings BatchSettings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[serviceID] = settings
+	log.Printf("[BatchManager] Settings for service %s: batchSize=%d, model=%q, customInstruction=%v, language=%q",
+		serviceID, m.batchSizeLocked(serviceID), settings.Model, settings.Instruction != "", settings.Language)
+}
+
+// batchSizeLocked returns the batch size of a service (caller must hold lock)
+func (m *BatchManager) batchSizeLocked(serviceID string) int {
+	if size := m.settings[serviceID].BatchSize; size > 0 {
+		return size
+	}
+	return m.batchSize
+}
+
 // AddFrame adds a frame to the buffer
 // If processing: add to buffer, remove oldest if buffer full
 // If not processing: grab all frames in buffer and send
@@ -65,10 +130,11 @@ func (m *BatchManager) AddFrame(frame *Frame) {
 	m.mu.Lock()
 
 	serviceID := frame.ServiceID
+	batchSize := m.batchSizeLocked(serviceID)
 
 	// Initialize buffer if needed
 	if m.frameBuffers[serviceID] == nil {
-		m.frameBuffers[serviceID] = make([]*Frame, 0, m.batchSize)
+		m.frameBuffers[serviceID] = make([]*Frame, 0, batchSize)
 	}
 
 	buffer := m.frameBuffers[serviceID]
@@ -76,10 +142,11 @@ func (m *BatchManager) AddFrame(frame *Frame) {
 	// Add frame to buffer
 	buffer = append(buffer, frame)
 
-	// If buffer exceeds batch size, remove oldest frame (FIFO)
-	if len(buffer) > m.batchSize {
-		buffer = buffer[1:] // Remove first (oldest) element
-		log.Printf("[BatchManager] Buffer full for service %s, removed oldest frame", serviceID)
+	// If buffer exceeds batch size, remove oldest frames (FIFO)
+	// More than one only after the batch size was lowered
+	if over := len(buffer) - batchSize; over > 0 {
+		buffer = buffer[over:] // Remove first (oldest) elements
+		log.Printf("[BatchManager] Buffer full for service %s, removed %d oldest frame(s)", serviceID, over)
 	}
 
 	m.frameBuffers[serviceID] = buffer
@@ -87,7 +154,7 @@ func (m *BatchManager) AddFrame(frame *Frame) {
 	// Check if we should send a batch:
 	// 1. Buffer is full (== batchSize)
 	// 2. This service is not currently processing
-	shouldSend := len(buffer) == m.batchSize && !m.processingLocks[serviceID]
+	shouldSend := len(buffer) == batchSize && !m.processingLocks[serviceID]
 
 	if shouldSend {
 		// Grab all frames from buffer
@@ -118,11 +185,12 @@ func (m *BatchManager) AddFrame(frame *Frame) {
 
 		// Mark service as processing
 		m.processingLocks[serviceID] = true
+		settings := m.settings[serviceID]
 
 		m.mu.Unlock()
 
 		// Send batch asynchronously to avoid blocking
-		go m.sendBatch(serviceID, framesToSend, prevResponse, prevLastFrame, batchSentTime)
+		go m.sendBatch(serviceID, framesToSend, prevResponse, prevLastFrame, batchSentTime, settings)
 	} else {
 		if m.processingLocks[serviceID] {
 			log.Printf("[BatchManager] Buffering frame for service %s (currently processing, %d frames in buffer)", serviceID, len(buffer))
@@ -132,7 +200,7 @@ func (m *BatchManager) AddFrame(frame *Frame) {
 }
 
 // sendBatch sends a batch of frames to vLLM with rolling context
-func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResponse *VLMResponse, lastFrame *Frame, batchSentTime int64) {
+func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResponse *VLMResponse, lastFrame *Frame, batchSentTime int64, settings BatchSettings) {
 	// Release the processing lock when done
 	defer func() {
 		m.mu.Lock()
@@ -141,11 +209,15 @@ func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResp
 	}()
 	// Build instruction based on whether we have previous context
 	// Note: JSON schema is handled by response_format in FrameClient
-	instruction := m.baseInstruction
+	baseInstruction := m.baseInstruction
+	if settings.Instruction != "" {
+		baseInstruction = settings.Instruction + "\n\n" + detectionInstruction
+	}
+	instruction := baseInstruction
 
 	if previousResponse != nil && previousResponse.Description != "" {
 		instruction = strings.Join([]string{
-			m.baseInstruction,
+			baseInstruction,
 			"",
 			"PREVIOUS ANALYSIS (use for object tracking):",
 			previousResponse.Description,
@@ -162,6 +234,11 @@ func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResp
 		}, "\n")
 	}
 
+	if settings.Language != "" {
+		// Labels stay in English: alert rules and event search match on them
+		instruction += fmt.Sprintf("\n\nWrite the description in %s. Keep the object labels in English.", settings.Language)
+	}
+
 	// Prepend last frame for visual continuity
 	framesToSend := frames
 	if lastFrame != nil {
@@ -172,7 +249,7 @@ func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResp
 	}
 
 	ctx := context.Background()
-	response, err := m.client.SendFrameBatchWithInstruction(ctx, framesToSend, instruction)
+	response, err := m.client.SendFrameBatchWithStructuredOutput(ctx, framesToSend, instruction, settings.Model)
 	if err != nil {
 		log.Printf("[BatchManager] Failed to send batch for service %s: %v", serviceID, err)
 		return
@@ -204,20 +281,25 @@ func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResp
 				duration := lastFrame.Timestamp.Sub(firstFrame.Timestamp)
 				granularity := timeutil.CalculateGranularity(int64(duration.Seconds()))
 
-				payload, err := structpb.NewStruct(map[string]any{
+				fields := map[string]any{
 					"type":       "vlm-indexing",
 					"granularity": string(granularity),
 					"from_iso":   timeutil.FormatToISO(firstFrame.Timestamp),
 					"to_iso":     timeutil.FormatToISO(lastFrame.Timestamp),
 					"response":   responseMap,
-				})
+				}
+				if settings.Language != "" {
+					// Picks the text search configuration of keyword search
+					fields["language"] = settings.Language
+				}
+				payload, err := structpb.NewStruct(fields)
 				if err != nil {
 					log.Printf("[BatchManager] Failed to create event payload: %v", err)
 				} else {
 					eventID := uuid.New().String()
 					if err := m.db.CreateEvent(eventID, serviceID, payload); err != nil {
 						log.Printf("[BatchManager] Failed to create event: %v", err)
-					} else {
+					} else if svc, err := m.db.GetService(serviceID); err == nil && svc != nil {
 						// Broadcast the event to subscribers
 						if m.eventBroadcaster != nil {
 							event := &servicev1.Event{
@@ -226,11 +308,17 @@ func (m *BatchManager) sendBatch(serviceID string, frames []*Frame, previousResp
 								Payload:   payload,
 								CreatedAt: timestamppb.New(time.Now()),
 							}
-							// Get node_id from service
-							if svc, err := m.db.GetService(serviceID); err == nil && svc != nil {
-								m.eventBroadcaster.Broadcast(event, svc.NodeId)
-							}
+							m.eventBroadcaster.Broadcast(event, svc.NodeId)
 						}
+
+						m.notifyResult(&BatchResult{
+							ServiceID: serviceID,
+							NodeID:    svc.NodeId,
+							EventID:   eventID,
+							From:      firstFrame.Timestamp,
+							To:        lastFrame.Timestamp,
+							Response:  &vlmResp,
+						})
 					}
 				}
 			}
@@ -316,7 +404,7 @@ func (m *BatchManager) Flush() {
 			// Mark service as processing
 			m.processingLocks[serviceID] = true
 
-			go m.sendBatch(serviceID, framesToSend, prevResponse, prevLastFrame, batchSentTime)
+			go m.sendBatch(serviceID, framesToSend, prevResponse, prevLastFrame, batchSentTime, m.settings[serviceID])
 		}
 	}
 }
@@ -364,5 +452,6 @@ func (m *BatchManager) RemoveService(serviceID string) {
 	delete(m.frameBuffers, serviceID)
 	delete(m.rollingContexts, serviceID)
 	delete(m.processingLocks, serviceID)
+	delete(m.settings, serviceID)
 	log.Printf("[BatchManager] Removed service %s (freed buffer and context)", serviceID)
 }
@@ -0,0 +1,118 @@
+package webrtc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ClipSegment is a recorded segment used as input for a clip
+type ClipSegment struct {
+	Source    string        // Path or URL of the MP4 segment (see Location.String)
+	StartTime time.Time     // Wall-clock time of the first frame in the segment
+	Duration  time.Duration // Length of the segment (0 = unknown, assumed to reach the next segment)
+}
+
+// ClipWindow maps the wall-clock range [from, to) onto the stitched segments.
+// It returns where the clip starts in the concatenated video and how long it is.
+// Recording gaps take no time in the concatenated video, so times inside a gap
+// map to the start of the next segment.
+func ClipWindow(segments []ClipSegment, from, to time.Time) (offset, duration time.Duration) {
+	offset = mediaPosition(segments, from)
+	duration = mediaPosition(segments, to) - offset
+	return offset, duration
+}
+
+// mediaPosition returns the position of a wall-clock time in the stitched segments
+func mediaPosition(segments []ClipSegment, t time.Time) time.Duration {
+	var position time.Duration
+	for i, segment := range segments {
+		if t.Before(segment.StartTime) {
+			return position
+		}
+
+		length := segment.Duration
+		if length <= 0 {
+			if i == len(segments)-1 {
+				return position + t.Sub(segment.StartTime)
+			}
+			length = max(segments[i+1].StartTime.Sub(segment.StartTime), 0)
+		}
+		if elapsed := t.Sub(segment.StartTime); elapsed < length {
+			return position + elapsed
+		}
+		position += length
+	}
+	return position
+}
+
+// ExportClip stitches consecutive segments into a single MP4 trimmed to [from, to).
+// Segments must be ordered by start time; recording gaps between them are skipped.
+// Streams are copied, so the clip starts on the keyframe at or before from.
+func ExportClip(ctx context.Context, segments []ClipSegment, from, to time.Time, outPath string) error {
+	if len(segments) == 0 {
+		return fmt.Errorf("no segments to export")
+	}
+	if !to.After(from) {
+		return fmt.Errorf("clip end must be after start")
+	}
+	offset, duration := ClipWindow(segments, from, to)
+	if duration <= 0 {
+		return fmt.Errorf("no recorded video between clip start and end")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return fmt.Errorf("create clip directory: %w", err)
+	}
+
+	// Write concat demuxer list next to the output
+	var list strings.Builder
+	for _, segment := range segments {
+		source := segment.Source
+		if !strings.Contains(source, "://") {
+			absPath, err := filepath.Abs(source)
+			if err != nil {
+				return fmt.Errorf("resolve segment path: %w", err)
+			}
+			source = absPath
+		}
+		fmt.Fprintf(&list, "file '%s'\n", strings.ReplaceAll(source, "'", `'\''`))
+	}
+	listPath := outPath + ".txt"
+	if err := os.WriteFile(listPath, []byte(list.String()), 0644); err != nil {
+		return fmt.Errorf("write concat list: %w", err)
+	}
+	defer os.Remove(listPath)
+
+	cmd := exec.CommandContext(ctx,
+		"ffmpeg",
+		"-loglevel", "error", // Only log errors
+		"-y",           // Overwrite output
+		"-f", "concat", // Stitch segments
+		"-safe", "0", // Allow absolute paths in the list
+		"-protocol_whitelist", "file,http,https,tcp,tls,crypto", // Allow presigned URLs in the list
+		"-ss", fmt.Sprintf("%.3f", offset.Seconds()), // Seek to clip start
+		"-i", listPath,
+		"-t", fmt.Sprintf("%.3f", duration.Seconds()), // Clip length
+		"-c", "copy", // Remux, no transcoding
+		"-movflags", "+faststart", // Playable while downloading
+		outPath,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		os.Remove(outPath)
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	log.Printf("[ClipExporter] Exported clip from %d segments (offset=%v, duration=%v) to %s", len(segments), offset, duration, outPath)
+	return nil
+}
@@ -0,0 +1,60 @@
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipWindow(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	// 0-10s and 10-20s, then a gap until 60s, then 60-70s
+	segments := []ClipSegment{
+		{StartTime: at(0), Duration: 10 * time.Second},
+		{StartTime: at(10), Duration: 10 * time.Second},
+		{StartTime: at(60), Duration: 10 * time.Second},
+	}
+
+	tests := []struct {
+		name             string
+		from, to         time.Time
+		offset, duration time.Duration
+	}{
+		{"contiguous", at(5), at(15), 5 * time.Second, 10 * time.Second},
+		{"before the first segment", at(-30), at(5), 0, 5 * time.Second},
+		{"across the gap", at(15), at(65), 15 * time.Second, 10 * time.Second},
+		{"from inside the gap", at(30), at(65), 20 * time.Second, 5 * time.Second},
+		{"after the gap", at(62), at(68), 22 * time.Second, 6 * time.Second},
+		{"past the last segment", at(65), at(90), 25 * time.Second, 5 * time.Second},
+		{"only the gap", at(25), at(55), 20 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, duration := ClipWindow(segments, tt.from, tt.to)
+			assert.Equal(t, tt.offset, offset)
+			assert.Equal(t, tt.duration, duration)
+		})
+	}
+}
+
+func TestClipWindowUnknownDurations(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	// Segments without a duration reach the next segment; the last one is open
+	segments := []ClipSegment{
+		{StartTime: at(0)},
+		{StartTime: at(10)},
+	}
+
+	offset, duration := ClipWindow(segments, at(5), at(25))
+	assert.Equal(t, 5*time.Second, offset)
+	assert.Equal(t, 20*time.Second, duration)
+}
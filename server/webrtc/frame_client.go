@@ -52,14 +52,29 @@ func (c *FrameClient) SendFrameBatch(ctx context.Context, frames []*Frame) (*ope
 
 // SendFrameBatchWithInstruction sends a batch of frames to the vLLM endpoint with a custom instruction
 func (c *FrameClient) SendFrameBatchWithInstruction(ctx context.Context, frames []*Frame, instruction string) (*openai.ChatCompletion, error) {
-	return c.SendFrameBatchWithStructuredOutput(ctx, frames, instruction)
+	return c.SendFrameBatchWithStructuredOutput(ctx, frames, instruction, "")
 }
 
 // SendFrameBatchWithStructuredOutput sends frames with structured output using response_format
-func (c *FrameClient) SendFrameBatchWithStructuredOutput(ctx context.Context, frames []*Frame, instruction string) (*openai.ChatCompletion, error) {
+// An empty model uses the client's default model
+func (c *FrameClient) SendFrameBatchWithStructuredOutput(ctx context.Context, frames []*Frame, instruction, model string) (*openai.ChatCompletion, error) {
+	return c.SendFrameBatchWithSchema(ctx, frames, instruction, model, openai.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:        "vlm_response",
+		Description: openai.String("Video frame analysis with detected objects and detailed description"),
+		Schema:      GenerateVLMResponseSchema(),
+		Strict:      openai.Bool(true),
+	})
+}
+
+// SendFrameBatchWithSchema sends frames and asks for a response matching a custom JSON schema
+// An empty model uses the client's default model
+func (c *FrameClient) SendFrameBatchWithSchema(ctx context.Context, frames []*Frame, instruction, model string, schemaParam openai.ResponseFormatJSONSchemaJSONSchemaParam) (*openai.ChatCompletion, error) {
 	if len(frames) == 0 {
 		return nil, fmt.Errorf("no frames to send")
 	}
+	if model == "" {
+		model = c.Model
+	}
 
 	// Build message content with images and text
 	content := []openai.ChatCompletionContentPartUnionParam{}
@@ -80,18 +95,9 @@ func (c *FrameClient) SendFrameBatchWithStructuredOutput(ctx context.Context, fr
 		}))
 	}
 
-	// Build JSON schema for structured output
-	schema := GenerateVLMResponseSchema()
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        "vlm_response",
-		Description: openai.String("Video frame analysis with detected objects and detailed description"),
-		Schema:      schema,
-		Strict:      openai.Bool(true),
-	}
-
 	// Build request with structured output
 	params := openai.ChatCompletionNewParams{
-		Model: openai.ChatModel(c.Model),
+		Model: openai.ChatModel(model),
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.UserMessage(content),
 		},
@@ -112,8 +118,8 @@ func (c *FrameClient) SendFrameBatchWithStructuredOutput(ctx context.Context, fr
 	}
 	duration := time.Since(startTime)
 
-	log.Printf("[FrameClient] Request successful: duration=%v, frames=%d, tokens=%d",
-		duration, len(frames), response.Usage.TotalTokens)
+	log.Printf("[FrameClient] Request successful: model=%s, duration=%v, frames=%d, tokens=%d",
+		model, duration, len(frames), response.Usage.TotalTokens)
 
 	if len(response.Choices) > 0 {
 		log.Printf("[FrameClient] Response content: %s", response.Choices[0].Message.Content)
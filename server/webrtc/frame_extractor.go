@@ -33,8 +33,16 @@ type FrameExtractor struct {
 	ffmpegCmd    *exec.Cmd
 	ffmpegStdin  io.WriteCloser
 	ffmpegStdout io.ReadCloser
+
+	// Extraction times within the last fpsWindow, for Stats, and the last frame
+	statsMu    sync.Mutex
+	frameTimes []time.Time
+	latest     *Frame
 }
 
+// fpsWindow is the period Stats measures the extraction rate over
+const fpsWindow = time.Minute
+
 // NewFrameExtractor creates a new frame extractor
 func NewFrameExtractor(serviceID string, interval time.Duration, onFrame func(*Frame)) *FrameExtractor {
 	return &FrameExtractor{
@@ -233,6 +241,7 @@ func (e *FrameExtractor) readFramesFromFFmpeg() {
 					}
 
 					log.Printf("[FrameExtractor] Extracted JPEG frame (%d bytes)", len(frameData))
+					e.recordFrame(frame)
 
 					// Call callback
 					if e.onFrame != nil {
@@ -248,6 +257,51 @@ func (e *FrameExtractor) readFramesFromFFmpeg() {
 	}
 }
 
+// recordFrame notes an extracted frame and forgets those older than fpsWindow
+func (e *FrameExtractor) recordFrame(frame *Frame) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	e.latest = frame
+	t := frame.Timestamp
+	e.frameTimes = append(e.frameTimes, t)
+	cutoff := t.Add(-fpsWindow)
+	i := 0
+	for i < len(e.frameTimes) && e.frameTimes[i].Before(cutoff) {
+		i++
+	}
+	e.frameTimes = e.frameTimes[i:]
+}
+
+// Stats returns when the last frame was extracted (zero if none yet) and the
+// extraction rate over the last minute
+func (e *FrameExtractor) Stats() (time.Time, float64) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	if len(e.frameTimes) == 0 {
+		return time.Time{}, 0
+	}
+	last := e.frameTimes[len(e.frameTimes)-1]
+
+	// Count only frames still inside the window as of now
+	cutoff := time.Now().Add(-fpsWindow)
+	recent := 0
+	for _, t := range e.frameTimes {
+		if !t.Before(cutoff) {
+			recent++
+		}
+	}
+	return last, float64(recent) / fpsWindow.Seconds()
+}
+
+// LatestFrame returns the last extracted frame as it came from FFmpeg (nil if none yet)
+func (e *FrameExtractor) LatestFrame() *Frame {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	return e.latest
+}
+
 // Close stops the frame extractor
 func (e *FrameExtractor) Close() {
 	e.closeOnce.Do(func() {
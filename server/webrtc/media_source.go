@@ -1,12 +1,15 @@
 package webrtc
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/url"
 	"strings"
 
 	"github.com/AlexxIT/go2rtc/pkg/core"
+	"unblink/server"
+	"unblink/shared"
 )
 
 // MediaSource represents a media source that can provide a producer and receivers
@@ -24,9 +27,24 @@ const (
 	SourceMJPEG SourceType = "mjpeg"
 )
 
+// PacketPairListener opens an RTP/RTCP socket pair towards the service host
+type PacketPairListener func() (net.PacketConn, net.PacketConn, error)
+
+// NewBridgePacketListener returns a PacketPairListener that opens UDP bridges
+// through the node, or nil if the node does not support them
+func NewBridgePacketListener(ctx context.Context, nodeConn *server.NodeConn, serviceID, serviceURL string) PacketPairListener {
+	if !nodeConn.HasCapability(shared.CapabilityUDPBridges) {
+		return nil
+	}
+	return func() (net.PacketConn, net.PacketConn, error) {
+		return nodeConn.ListenPacketPair(ctx, serviceID, serviceURL)
+	}
+}
+
 // NewMediaSource creates the appropriate media source based on the service URL
 // It inspects the URL scheme and determines the source type (RTSP or MJPEG)
-func NewMediaSource(serviceURL, bridgeID string, bridgeConn net.Conn) (MediaSource, error) {
+// listenUDP is used by RTSP sources that ask for UDP transport and may be nil.
+func NewMediaSource(serviceURL, bridgeID string, bridgeConn net.Conn, listenUDP PacketPairListener) (MediaSource, error) {
 	sourceType, err := determineSourceType(serviceURL)
 	if err != nil {
 		return nil, fmt.Errorf("determine source type: %w", err)
@@ -34,7 +52,7 @@ func NewMediaSource(serviceURL, bridgeID string, bridgeConn net.Conn) (MediaSour
 
 	switch sourceType {
 	case SourceRTSP:
-		return NewRTSPSourceWithBridge(serviceURL, bridgeID, bridgeConn)
+		return NewRTSPSourceWithBridge(serviceURL, bridgeID, bridgeConn, listenUDP)
 	case SourceMJPEG:
 		return NewMJPEGSourceWithBridge(serviceURL, bridgeID, bridgeConn)
 	default:
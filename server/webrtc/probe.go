@@ -0,0 +1,395 @@
+package webrtc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"time"
+
+	"github.com/AlexxIT/go2rtc/pkg/core"
+	"github.com/AlexxIT/go2rtc/pkg/h264"
+	"github.com/AlexxIT/go2rtc/pkg/h265"
+
+	"unblink/server/internal/rtsp"
+)
+
+const (
+	// ProbeTimeout is how long callers should give a probe, including the preview
+	ProbeTimeout = 20 * time.Second
+
+	// maxPreviewSize bounds the JPEG read from an MJPEG stream
+	maxPreviewSize = 8 << 20
+)
+
+// ProbeCodec is a track offered by a probed source
+type ProbeCodec struct {
+	Kind      string // "video" or "audio"
+	Name      string // e.g. "H264", "PCMA", "JPEG"
+	ClockRate uint32
+	Channels  uint8
+}
+
+// ProbeResult is what a probe learned about a source, up to where it stopped
+type ProbeResult struct {
+	SourceType SourceType
+	Responded  bool // The camera answered the protocol
+	AuthFailed bool // The camera wants other (or any) credentials
+	Codecs     []ProbeCodec
+	Width      int
+	Height     int
+	Preview    []byte // One JPEG frame, nil if none could be captured
+
+	// Why there is no preview although the stream works
+	PreviewError string
+}
+
+// ProbeSource checks a service URL over conn without starting a handler:
+// RTSP OPTIONS/DESCRIBE and one frame after PLAY, or an MJPEG GET and its
+// first frame. RTSP always uses interleaved TCP. The error says why the
+// source is unusable; the result is filled in up to where the probe stopped.
+func ProbeSource(ctx context.Context, serviceURL string, conn net.Conn) (*ProbeResult, error) {
+	sourceType, err := determineSourceType(serviceURL)
+	if err != nil {
+		return nil, fmt.Errorf("determine source type: %w", err)
+	}
+
+	result := &ProbeResult{SourceType: sourceType}
+	switch sourceType {
+	case SourceRTSP:
+		err = probeRTSP(ctx, serviceURL, conn, result)
+	case SourceMJPEG:
+		err = probeMJPEG(ctx, serviceURL, conn, result)
+	default:
+		err = fmt.Errorf("unsupported source type: %s", sourceType)
+	}
+	return result, err
+}
+
+func probeRTSP(ctx context.Context, serviceURL string, conn net.Conn, result *ProbeResult) error {
+	serviceURL, _ = splitRTSPTransport(serviceURL)
+
+	client := rtsp.NewClient(serviceURL)
+	client.SetConn(conn)
+	if err := client.Dial(); err != nil {
+		return fmt.Errorf("RTSP dial: %w", err)
+	}
+	defer client.Close()
+
+	// OPTIONS is optional for some cameras, so only a 401 ends the probe here
+	if err := client.Options(); err != nil {
+		if isRTSPAuthError(err) {
+			result.Responded, result.AuthFailed = true, true
+			return fmt.Errorf("RTSP options: %w", err)
+		}
+		log.Printf("[Probe] RTSP OPTIONS failed, trying DESCRIBE: %v", err)
+	}
+
+	if err := client.Describe(); err != nil {
+		if isRTSPAuthError(err) {
+			result.Responded, result.AuthFailed = true, true
+		}
+		return fmt.Errorf("RTSP describe: %w", err)
+	}
+	result.Responded = true
+
+	var video *core.Media
+	var videoCodec *core.Codec
+	for _, media := range client.GetMedias() {
+		for _, codec := range media.Codecs {
+			result.Codecs = append(result.Codecs, ProbeCodec{
+				Kind:      media.Kind,
+				Name:      codec.Name,
+				ClockRate: codec.ClockRate,
+				Channels:  codec.Channels,
+			})
+			if media.Kind == core.KindVideo && videoCodec == nil {
+				video, videoCodec = media, codec
+			}
+		}
+	}
+	if videoCodec == nil {
+		return fmt.Errorf("no video track in RTSP stream")
+	}
+	result.Width, result.Height = resolutionFromFmtp(videoCodec)
+
+	if videoCodec.Name != core.CodecH264 {
+		result.PreviewError = fmt.Sprintf("no preview for %s video", videoCodec.Name)
+		return nil
+	}
+
+	preview, err := previewRTSP(ctx, client, video, videoCodec)
+	if err != nil {
+		result.PreviewError = err.Error()
+		return nil
+	}
+	result.Preview = preview
+	if result.Width == 0 {
+		result.Width, result.Height = jpegSize(preview)
+	}
+	return nil
+}
+
+// previewRTSP plays a described stream until its first frame decodes
+func previewRTSP(ctx context.Context, client *rtsp.Conn, media *core.Media, codec *core.Codec) ([]byte, error) {
+	receiver, err := client.GetTrack(media, codec)
+	if err != nil {
+		return nil, fmt.Errorf("RTSP setup: %w", err)
+	}
+	if err := client.Play(); err != nil {
+		return nil, fmt.Errorf("RTSP play: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = client.Start()
+	}()
+	defer func() {
+		_ = client.Stop()
+		<-done
+	}()
+
+	return capturePreview(ctx, media, receiver)
+}
+
+func isRTSPAuthError(err error) bool {
+	return errors.Is(err, rtsp.ErrNoCredentials) || errors.Is(err, rtsp.ErrWrongCredentials)
+}
+
+// resolutionFromFmtp reads the frame size from the SPS in the SDP, if present
+func resolutionFromFmtp(codec *core.Codec) (int, int) {
+	switch codec.Name {
+	case core.CodecH264:
+		if sps, _ := h264.GetParameterSet(codec.FmtpLine); len(sps) > 0 {
+			if s := h264.DecodeSPS(sps); s != nil {
+				return int(s.Width()), int(s.Height())
+			}
+		}
+	case core.CodecH265:
+		if _, sps, _ := h265.GetParameterSet(codec.FmtpLine); len(sps) > 2 {
+			if s := h265.DecodeSPS(sps); s != nil {
+				return int(s.Width()), int(s.Height())
+			}
+		}
+	}
+	return 0, 0
+}
+
+// capturePreview decodes the first H.264 frame of a track into a JPEG
+func capturePreview(ctx context.Context, media *core.Media, receiver *core.Receiver) ([]byte, error) {
+	consumer := NewH264Consumer()
+	defer consumer.Stop()
+
+	if err := consumer.AddTrack(media, receiver.Codec, receiver); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-loglevel", "error",
+		"-f", "h264", // Input format (raw H.264 Annex-B)
+		"-i", "pipe:0",
+		"-frames:v", "1", // First decodable frame only
+		"-f", "image2pipe",
+		"-c:v", "mjpeg",
+		"-q:v", "2",
+		"pipe:1",
+	)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("FFmpeg start: %w", err)
+	}
+
+	// Ends with a write error once FFmpeg has its frame and exits
+	go func() {
+		_, _ = consumer.WriteTo(stdin)
+		_ = stdin.Close()
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("no frame received: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("FFmpeg: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("FFmpeg produced no frame")
+	}
+	return stdout.Bytes(), nil
+}
+
+func probeMJPEG(ctx context.Context, serviceURL string, conn net.Conn, result *ProbeResult) error {
+	parsed, err := url.Parse(serviceURL)
+	if err != nil {
+		return fmt.Errorf("parse service URL: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "unb/1.0")
+	req.Close = true
+	if parsed.User != nil {
+		password, _ := parsed.User.Password()
+		req.SetBasicAuth(parsed.User.Username(), password)
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("send HTTP request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("read HTTP response: %w", err)
+	}
+	defer resp.Body.Close()
+	result.Responded = true
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		result.AuthFailed = true
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+
+	frame, err := readJPEG(io.LimitReader(resp.Body, maxPreviewSize))
+	if err != nil {
+		return fmt.Errorf("read frame (Content-Type %q): %w", resp.Header.Get("Content-Type"), err)
+	}
+
+	result.Codecs = []ProbeCodec{{Kind: core.KindVideo, Name: core.CodecJPEG}}
+	result.Preview = frame
+	result.Width, result.Height = jpegSize(frame)
+	return nil
+}
+
+// readJPEG returns the first complete JPEG (SOI to its EOI) in a stream, which
+// works for multipart MJPEG and single snapshots alike
+func readJPEG(r io.Reader) ([]byte, error) {
+	soi := []byte{0xFF, 0xD8}
+
+	var buf []byte
+	var scanner jpegScanner
+	chunk := make([]byte, 32*1024)
+	start := -1
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+
+		if start < 0 {
+			start = bytes.Index(buf, soi)
+		}
+		if start >= 0 {
+			length, done, scanErr := scanner.scan(buf[start:])
+			if scanErr != nil {
+				return nil, scanErr
+			}
+			if done {
+				return buf[start : start+length], nil
+			}
+		}
+
+		if err == io.EOF {
+			return nil, fmt.Errorf("stream ended before a complete JPEG")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// jpegScanner finds the end of a JPEG by walking its segments, so an EOI inside
+// a segment (like the thumbnail in an EXIF block) does not end the image early
+type jpegScanner struct {
+	pos    int  // Next byte to look at, from SOI
+	inScan bool // Inside the entropy-coded data that follows SOS
+}
+
+// scan continues through data, which starts at SOI and grows between calls.
+// It returns the length of the JPEG once its EOI is reached; false means more
+// data is needed.
+func (s *jpegScanner) scan(data []byte) (int, bool, error) {
+	if s.pos == 0 {
+		s.pos = 2 // Skip SOI
+	}
+
+	for s.pos+1 < len(data) {
+		if s.inScan {
+			// Entropy-coded data runs until a marker other than a stuffed
+			// 0xFF00 or a restart marker
+			i := bytes.IndexByte(data[s.pos:len(data)-1], 0xFF)
+			if i < 0 {
+				s.pos = len(data) - 1
+				return 0, false, nil
+			}
+			s.pos += i
+			switch next := data[s.pos+1]; {
+			case next == 0xFF: // Fill byte before a marker
+				s.pos++
+			case next == 0x00, next >= 0xD0 && next <= 0xD7:
+				s.pos += 2
+			default:
+				s.inScan = false
+			}
+			continue
+		}
+
+		if data[s.pos] != 0xFF {
+			return 0, false, fmt.Errorf("invalid JPEG: no marker at offset %d", s.pos)
+		}
+		marker := data[s.pos+1]
+		switch {
+		case marker == 0xFF: // Fill byte
+			s.pos++
+			continue
+		case marker == 0xD9: // EOI
+			return s.pos + 2, true, nil
+		case marker == 0x01, marker >= 0xD0 && marker <= 0xD7: // No payload
+			s.pos += 2
+			continue
+		}
+
+		if s.pos+3 >= len(data) {
+			return 0, false, nil
+		}
+		length := int(binary.BigEndian.Uint16(data[s.pos+2:]))
+		if length < 2 {
+			return 0, false, fmt.Errorf("invalid JPEG: segment length %d at offset %d", length, s.pos)
+		}
+		s.pos += 2 + length
+		s.inScan = marker == 0xDA // SOS
+	}
+
+	return 0, false, nil
+}
+
+// jpegSize returns the dimensions of a JPEG, or zeros if it does not decode
+func jpegSize(data []byte) (int, int) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+	return config.Width, config.Height
+}
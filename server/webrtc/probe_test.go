@@ -0,0 +1,97 @@
+package webrtc
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJPEG encodes a plain image of the given size
+func testJPEG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil))
+	return buf.Bytes()
+}
+
+// withEXIFThumbnail inserts an APP1 segment holding a thumbnail JPEG right
+// after SOI, like cameras do
+func withEXIFThumbnail(t *testing.T, frame []byte) []byte {
+	thumbnail := testJPEG(t, 8, 8)
+	payload := append([]byte("Exif\x00\x00"), thumbnail...)
+	length := len(payload) + 2
+
+	out := append([]byte{}, frame[:2]...)
+	out = append(out, 0xFF, 0xE1, byte(length>>8), byte(length))
+	out = append(out, payload...)
+	return append(out, frame[2:]...)
+}
+
+func TestReadJPEG(t *testing.T) {
+	frame := testJPEG(t, 64, 48)
+	exif := withEXIFThumbnail(t, frame)
+
+	tests := []struct {
+		name   string
+		stream []byte
+		want   []byte
+	}{
+		{"single snapshot", frame, frame},
+		{"EXIF thumbnail", exif, exif},
+		{
+			"multipart MJPEG",
+			append(append([]byte("--frame\r\nContent-Type: image/jpeg\r\n\r\n"), exif...), []byte("\r\n--frame\r\n")...),
+			exif,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readJPEG(bytes.NewReader(tt.stream))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// Segments and markers split across reads
+			got, err = readJPEG(iotest.OneByteReader(bytes.NewReader(tt.stream)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			width, height := jpegSize(got)
+			assert.Equal(t, 64, width)
+			assert.Equal(t, 48, height)
+		})
+	}
+}
+
+func TestReadJPEGFirstFrame(t *testing.T) {
+	first := testJPEG(t, 16, 16)
+	second := testJPEG(t, 32, 32)
+
+	got, err := readJPEG(bytes.NewReader(append(append([]byte{}, first...), second...)))
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+}
+
+func TestReadJPEGIncomplete(t *testing.T) {
+	frame := withEXIFThumbnail(t, testJPEG(t, 64, 48))
+
+	// Cut right after the thumbnail's EOI
+	thumbnailEnd := bytes.Index(frame, []byte{0xFF, 0xD9}) + 2
+	_, err := readJPEG(bytes.NewReader(frame[:thumbnailEnd]))
+	assert.Error(t, err)
+
+	_, err = readJPEG(bytes.NewReader([]byte("no image here")))
+	assert.Error(t, err)
+}
+
+func TestReadJPEGInvalid(t *testing.T) {
+	_, err := readJPEG(bytes.NewReader([]byte{0xFF, 0xD8, 0x12, 0x34, 0x56}))
+	assert.ErrorContains(t, err, "invalid JPEG")
+
+	_, err = readJPEG(bytes.NewReader([]byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x01}))
+	assert.ErrorContains(t, err, "segment length")
+}
@@ -2,6 +2,7 @@ package webrtc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -11,6 +12,7 @@ import (
 	"unblink/server"
 	"unblink/server/internal/ctxutil"
 	webrtcv1 "unblink/server/gen/webrtc/v1"
+	"unblink/shared"
 )
 
 // Service implements the WebRTC gRPC/Connect service
@@ -93,6 +95,10 @@ func (s *Service) CreateWebRTCSession(
 	)
 	if err != nil {
 		log.Printf("[WebRTC Service] Failed to create session: %v", err)
+		var bridgeErr *shared.BridgeError
+		if errors.As(err, &bridgeErr) && bridgeErr.Code == shared.BridgeErrorTargetDenied {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("node refused the service target: %w", err))
+		}
 		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to create session: %w", err))
 	}
 
@@ -70,7 +70,7 @@ func NewSession(
 	}
 
 	// Create media source based on service type (RTSP, MJPEG, etc.)
-	source, err := NewMediaSource(serviceURL, bridgeID, bridgeConn)
+	source, err := NewMediaSource(serviceURL, bridgeID, bridgeConn, NewBridgePacketListener(ctx, nodeConn, serviceID, serviceURL))
 	if err != nil {
 		bridgeConn.Close()
 		nodeConn.CloseBridge(ctx, bridgeID)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/AlexxIT/go2rtc/pkg/core"
@@ -16,12 +18,16 @@ type RTSPSource struct {
 	receivers []*core.Receiver
 }
 
-// NewRTSPSourceWithBridge creates a new RTSP source using a direct bridge connection
-func NewRTSPSourceWithBridge(serviceURL, bridgeID string, bridgeConn net.Conn) (*RTSPSource, error) {
+// NewRTSPSourceWithBridge creates a new RTSP source using a direct bridge connection.
+// Media is interleaved on the bridge unless the URL ends in #transport=udp and
+// listenUDP is set, in which case RTP and RTCP travel over UDP bridges.
+func NewRTSPSourceWithBridge(serviceURL, bridgeID string, bridgeConn net.Conn, listenUDP PacketPairListener) (*RTSPSource, error) {
 
 	log.Printf("[RTSP] Starting RTSP source for bridge %s", bridgeID)
 	log.Printf("[RTSP] RTSP URL: %s", serviceURL)
 
+	serviceURL, transport := splitRTSPTransport(serviceURL)
+
 	// Create RTSP client
 	client := rtsp.NewClient(serviceURL)
 
@@ -29,6 +35,16 @@ func NewRTSPSourceWithBridge(serviceURL, bridgeID string, bridgeConn net.Conn) (
 	log.Printf("[RTSP] Injecting custom bridge connection")
 	client.SetConn(bridgeConn)
 
+	if transport == "udp" {
+		if listenUDP != nil {
+			log.Printf("[RTSP] Using UDP transport over datagram bridges")
+			client.Transport = "udp"
+			client.ListenPacketPair = listenUDP
+		} else {
+			log.Printf("[RTSP] Node does not support UDP bridges, falling back to TCP")
+		}
+	}
+
 	log.Printf("[RTSP] Dialing RTSP server...")
 
 	// Connect to RTSP server
@@ -108,6 +124,21 @@ func NewRTSPSourceWithBridge(serviceURL, bridgeID string, bridgeConn net.Conn) (
 	return source, nil
 }
 
+// splitRTSPTransport removes a "#transport=udp" style fragment from an RTSP URL
+// and returns the requested transport ("" when not set)
+func splitRTSPTransport(serviceURL string) (string, string) {
+	base, fragment, found := strings.Cut(serviceURL, "#")
+	if !found {
+		return serviceURL, ""
+	}
+
+	params, err := url.ParseQuery(fragment)
+	if err != nil {
+		return base, ""
+	}
+	return base, strings.ToLower(params.Get("transport"))
+}
+
 // GetProducer implements MediaSource
 func (s *RTSPSource) GetProducer() core.Producer {
 	return s.client
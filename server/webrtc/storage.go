@@ -1,57 +1,56 @@
 package webrtc
 
 import (
+	"context"
 	"log"
-	"os"
-	"path/filepath"
+	"path"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// Storage handles saving frames to disk
+// Storage handles saving frames to the storage backend
 type Storage struct {
-	baseDir string // Base directory for frame storage (empty = no storage)
-	onSaved func(serviceID, frameID string, framePath string, timestamp time.Time, fileSize int64) // Callback after frame is saved
+	backend StorageBackend                                                                   // Where frames are written (nil = no storage)
+	onSaved func(serviceID, frameID string, key string, timestamp time.Time, fileSize int64) // Callback after frame is saved
 }
 
 // NewStorage creates a new storage handler
-func NewStorage(baseDir string) *Storage {
+func NewStorage(backend StorageBackend) *Storage {
 	return &Storage{
-		baseDir: baseDir,
+		backend: backend,
 	}
 }
 
+// Backend returns the storage backend frames are written to
+func (fs *Storage) Backend() StorageBackend {
+	return fs.backend
+}
+
 // SetOnSaved sets the callback to be called after a frame is saved
-func (fs *Storage) SetOnSaved(onSaved func(serviceID, frameID string, framePath string, timestamp time.Time, fileSize int64)) {
+func (fs *Storage) SetOnSaved(onSaved func(serviceID, frameID string, key string, timestamp time.Time, fileSize int64)) {
 	fs.onSaved = onSaved
 }
 
-// Save saves a frame to disk if baseDir is configured
+// Save saves a frame to the backend if one is configured
 func (fs *Storage) Save(serviceID string, frame *Frame) {
-	if fs.baseDir == "" {
-		log.Printf("[Storage] Skipping frame save for service %s - no baseDir configured", serviceID)
-		return
-	}
-
-	serviceFramesDir := filepath.Join(fs.baseDir, serviceID)
-	if err := os.MkdirAll(serviceFramesDir, 0755); err != nil {
-		log.Printf("[Storage] Failed to create frames directory %s: %v", serviceFramesDir, err)
+	if fs.backend == nil {
+		log.Printf("[Storage] Skipping frame save for service %s - no backend configured", serviceID)
 		return
 	}
 
 	frameID := uuid.New().String()
-	framePath := filepath.Join(serviceFramesDir, frameID+".jpg")
-	if err := os.WriteFile(framePath, frame.Data, 0644); err != nil {
+	key := path.Join("frames", serviceID, frameID+".jpg")
+	if err := fs.backend.Put(context.Background(), key, frame.Data, "image/jpeg"); err != nil {
 		log.Printf("[Storage] Failed to save frame %s: %v", frameID, err)
 	} else {
-		log.Printf("[Storage] Saved frame service=%s size=%d path=%s", serviceID, len(frame.Data), framePath)
+		log.Printf("[Storage] Saved frame service=%s size=%d key=%s", serviceID, len(frame.Data), key)
 
 		// Call callback if registered (e.g., to save metadata to database)
 		if fs.onSaved != nil {
 			// Note: We're passing frame.Timestamp which is when the frame was captured
 			// The database should use created_at as the DB insertion time
-			fs.onSaved(serviceID, frameID, framePath, frame.Timestamp, int64(len(frame.Data)))
+			fs.onSaved(serviceID, frameID, key, frame.Timestamp, int64(len(frame.Data)))
 		}
 	}
 }
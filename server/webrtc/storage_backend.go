@@ -0,0 +1,135 @@
+package webrtc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StorageBackend stores the bytes of storage items (frames, segments, clips).
+// Keys are slash-separated, e.g. "frames/{serviceID}/{frameID}.jpg", and are what
+// the storage table keeps in storage_path.
+type StorageBackend interface {
+	// Put stores data under key
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// PutFile stores a local file under key. The local file is consumed (moved or deleted).
+	PutFile(ctx context.Context, key, srcPath, contentType string) error
+	// Get reads the object under key
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the object under key (missing objects are not an error)
+	Delete(ctx context.Context, key string) error
+	// Exists reports whether an object is stored under key
+	Exists(ctx context.Context, key string) (bool, error)
+	// Locate returns where the object can be read from
+	Locate(ctx context.Context, key string, ttl time.Duration) (Location, error)
+}
+
+// Location is where a stored object can be read from.
+// Exactly one of Path (local file) or URL (presigned remote URL) is set.
+type Location struct {
+	Path string
+	URL  string
+}
+
+// String returns the path or URL, which FFmpeg accepts either way
+func (l Location) String() string {
+	if l.URL != "" {
+		return l.URL
+	}
+	return l.Path
+}
+
+// LocalBackend stores objects as files under a base directory
+type LocalBackend struct {
+	baseDir string
+}
+
+// NewLocalBackend creates a disk-backed storage backend
+func NewLocalBackend(baseDir string) *LocalBackend {
+	return &LocalBackend{
+		baseDir: baseDir,
+	}
+}
+
+// path resolves a key to a file path.
+// Rows written before keys were introduced hold full paths under baseDir; those are used as-is.
+func (b *LocalBackend) path(key string) string {
+	if filepath.IsAbs(key) || strings.HasPrefix(key, b.baseDir+string(filepath.Separator)) {
+		return key
+	}
+	return filepath.Join(b.baseDir, filepath.FromSlash(key))
+}
+
+// Put writes data to a file
+func (b *LocalBackend) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	return nil
+}
+
+// PutFile moves a local file into place (no-op if it is already there)
+func (b *LocalBackend) PutFile(ctx context.Context, key, srcPath, contentType string) error {
+	path := b.path(key)
+
+	srcAbs, err := filepath.Abs(srcPath)
+	if err != nil {
+		return fmt.Errorf("resolve source path: %w", err)
+	}
+	dstAbs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve destination path: %w", err)
+	}
+	if srcAbs == dstAbs {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.Rename(srcPath, path); err != nil {
+		return fmt.Errorf("move file: %w", err)
+	}
+	return nil
+}
+
+// Get reads a file
+func (b *LocalBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(b.path(key))
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes a file
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove file: %w", err)
+	}
+	return nil
+}
+
+// Exists checks whether a file exists
+func (b *LocalBackend) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(b.path(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("stat file: %w", err)
+	}
+	return true, nil
+}
+
+// Locate returns the file path (ttl is ignored)
+func (b *LocalBackend) Locate(ctx context.Context, key string, ttl time.Duration) (Location, error) {
+	return Location{Path: b.path(key)}, nil
+}
@@ -0,0 +1,319 @@
+package webrtc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexxIT/go2rtc/pkg/core"
+	"github.com/AlexxIT/go2rtc/pkg/mp4"
+)
+
+// Segment represents a finished MP4 recording segment on disk
+type Segment struct {
+	ID        string        // Segment file name without extension
+	ServiceID string        // Service identifier (e.g., camera name)
+	Path      string        // Absolute or relative path to the MP4 file
+	StartTime time.Time     // Wall-clock time of the first frame in the segment
+	Duration  time.Duration // Length of the segment
+	Size      int64         // File size in bytes
+}
+
+// VideoRecorder records rolling MP4 segments from a media source.
+// Tracks are remuxed into fragmented MP4 by go2rtc and cut into segments by FFmpeg
+// (video is copied, audio is re-encoded to AAC so PCMA/PCMU cameras play in browsers).
+type VideoRecorder struct {
+	serviceID       string
+	baseDir         string
+	segmentDuration time.Duration
+	onSegment       func(*Segment) // Callback when a segment is finished
+	closeChan       chan struct{}
+	closeOnce       sync.Once
+	wg              sync.WaitGroup // Track goroutine completion
+
+	consumer *mp4.Consumer
+
+	// Wall-clock time FFmpeg received the first byte of input (Unix nanoseconds,
+	// 0 = nothing written yet). FFmpeg reports segment times relative to it.
+	inputStart atomic.Int64
+
+	// FFmpeg pipeline
+	ffmpegCmd    *exec.Cmd
+	ffmpegStdin  io.WriteCloser
+	ffmpegStdout io.ReadCloser
+}
+
+// NewVideoRecorder creates a new video recorder writing into baseDir/serviceID
+func NewVideoRecorder(serviceID, baseDir string, segmentDuration time.Duration, onSegment func(*Segment)) *VideoRecorder {
+	return &VideoRecorder{
+		serviceID:       serviceID,
+		baseDir:         baseDir,
+		segmentDuration: segmentDuration,
+		onSegment:       onSegment,
+		closeChan:       make(chan struct{}),
+	}
+}
+
+// Start begins recording segments from the media source
+func (r *VideoRecorder) Start(mediaSource MediaSource) error {
+	log.Printf("[VideoRecorder] Starting recording for service %s (segment=%v)", r.serviceID, r.segmentDuration)
+
+	producer := mediaSource.GetProducer()
+	if producer == nil {
+		return fmt.Errorf("media source has no producer")
+	}
+
+	serviceDir := filepath.Join(r.baseDir, r.serviceID)
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		return fmt.Errorf("create segments directory: %w", err)
+	}
+
+	// Attach tracks before starting FFmpeg so a source without video fails fast
+	r.consumer = mp4.NewConsumer(nil)
+	if err := r.addTracks(producer); err != nil {
+		r.consumer.Stop()
+		return err
+	}
+
+	if err := r.startFFmpeg(serviceDir); err != nil {
+		r.consumer.Stop()
+		return fmt.Errorf("failed to start FFmpeg: %w", err)
+	}
+
+	r.wg.Add(2)
+	go r.writeMP4ToFFmpeg()
+	go r.readSegmentsFromFFmpeg(serviceDir)
+
+	return nil
+}
+
+// addTracks attaches the producer's video and audio tracks to the MP4 consumer.
+// Only the first codec of each media is used since that is the one the source has set up.
+func (r *VideoRecorder) addTracks(producer core.Producer) error {
+	hasVideo := false
+
+	for _, media := range producer.GetMedias() {
+		if len(media.Codecs) == 0 {
+			continue
+		}
+		codec := media.Codecs[0]
+
+		switch codec.Name {
+		case core.CodecH264, core.CodecH265:
+		case core.CodecAAC, core.CodecPCMA, core.CodecPCMU, core.CodecOpus:
+		default:
+			log.Printf("[VideoRecorder] Skipping unsupported %s codec %s for service %s", media.Kind, codec.Name, r.serviceID)
+			continue
+		}
+
+		receiver, err := producer.GetTrack(media, codec)
+		if err != nil {
+			log.Printf("[VideoRecorder] Failed to get %s track: %v", codec.Name, err)
+			continue
+		}
+
+		if err := r.consumer.AddTrack(media, codec, receiver); err != nil {
+			log.Printf("[VideoRecorder] Failed to add %s track: %v", codec.Name, err)
+			continue
+		}
+
+		if media.Kind == core.KindVideo {
+			hasVideo = true
+		}
+		log.Printf("[VideoRecorder] Recording %s/%s for service %s", media.Kind, codec.Name, r.serviceID)
+	}
+
+	if !hasVideo {
+		return fmt.Errorf("no recordable video track")
+	}
+	return nil
+}
+
+// startFFmpeg starts the FFmpeg process that cuts the fMP4 stream into segments.
+// Finished segments are reported on stdout as CSV lines: filename,start,end
+func (r *VideoRecorder) startFFmpeg(serviceDir string) error {
+	pattern := filepath.Join(serviceDir, fmt.Sprintf("%d-%%05d.mp4", time.Now().Unix()))
+
+	r.ffmpegCmd = exec.Command(
+		"ffmpeg",
+		"-loglevel", "error", // Only log errors
+		"-f", "mp4", // Input format (fragmented MP4 from go2rtc)
+		"-i", "pipe:0", // Read from stdin
+		"-map", "0:v:0", // First video track
+		"-map", "0:a:0?", // First audio track, if any
+		"-c:v", "copy", // Remux video, no transcoding
+		"-c:a", "aac", // Normalize audio for MP4 playback
+		"-f", "segment", // Split output into segments
+		"-segment_time", strconv.Itoa(int(r.segmentDuration.Seconds())),
+		"-segment_format", "mp4",
+		"-segment_format_options", "movflags=+faststart",
+		"-reset_timestamps", "1", // Each segment starts at 0
+		"-segment_list", "pipe:1", // Report finished segments on stdout
+		"-segment_list_type", "csv",
+		pattern,
+	)
+
+	var err error
+	r.ffmpegStdin, err = r.ffmpegCmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("stdin pipe: %w", err)
+	}
+
+	r.ffmpegStdout, err = r.ffmpegCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout pipe: %w", err)
+	}
+
+	// Capture stderr for debugging
+	r.ffmpegCmd.Stderr = os.Stderr
+
+	if err := r.ffmpegCmd.Start(); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+
+	log.Printf("[VideoRecorder] Started FFmpeg for service %s", r.serviceID)
+	return nil
+}
+
+// writeMP4ToFFmpeg streams fragmented MP4 into FFmpeg until the consumer is stopped
+func (r *VideoRecorder) writeMP4ToFFmpeg() {
+	defer r.wg.Done()
+	// Closing stdin lets FFmpeg finalize the last segment
+	defer r.ffmpegStdin.Close()
+	defer log.Printf("[VideoRecorder] Stopped MP4 writer for service %s", r.serviceID)
+
+	written, err := r.consumer.WriteTo(&inputStartWriter{w: r.ffmpegStdin, start: &r.inputStart})
+	if err != nil {
+		log.Printf("[VideoRecorder] MP4 writer finished: written=%d err=%v", written, err)
+	} else {
+		log.Printf("[VideoRecorder] MP4 writer finished: written=%d", written)
+	}
+}
+
+// readSegmentsFromFFmpeg reads the segment list from FFmpeg stdout and reports finished segments
+func (r *VideoRecorder) readSegmentsFromFFmpeg(serviceDir string) {
+	defer r.wg.Done()
+	defer log.Printf("[VideoRecorder] Stopped segment reader for service %s", r.serviceID)
+
+	scanner := bufio.NewScanner(r.ffmpegStdout)
+	for scanner.Scan() {
+		segment, err := r.parseSegmentLine(serviceDir, scanner.Text())
+		if err != nil {
+			log.Printf("[VideoRecorder] Ignoring segment list entry %q: %v", scanner.Text(), err)
+			continue
+		}
+
+		log.Printf("[VideoRecorder] Finished segment service=%s duration=%v size=%d path=%s",
+			r.serviceID, segment.Duration, segment.Size, segment.Path)
+
+		if r.onSegment != nil {
+			r.onSegment(segment)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		select {
+		case <-r.closeChan:
+		default:
+			log.Printf("[VideoRecorder] Error reading segment list: %v", err)
+		}
+	}
+}
+
+// inputStartWriter records when the first byte is written to FFmpeg
+type inputStartWriter struct {
+	w     io.Writer
+	start *atomic.Int64
+}
+
+func (w *inputStartWriter) Write(p []byte) (int, error) {
+	if w.start.Load() == 0 {
+		w.start.CompareAndSwap(0, time.Now().UnixNano())
+	}
+	return w.w.Write(p)
+}
+
+// parseSegmentLine converts a CSV segment list entry into a Segment.
+// FFmpeg reports times relative to the start of the input, so the wall-clock
+// start is the segment's start offset added to the time the input started.
+func (r *VideoRecorder) parseSegmentLine(serviceDir, line string) (*Segment, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("expected 3 fields, got %d", len(fields))
+	}
+
+	start, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse start: %w", err)
+	}
+	end, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse end: %w", err)
+	}
+
+	path := filepath.Join(serviceDir, filepath.Base(fields[0]))
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat segment: %w", err)
+	}
+
+	duration := time.Duration((end - start) * float64(time.Second))
+	startTime := time.Now().Add(-duration) // Input start unknown: the segment just finished
+	if inputStart := r.inputStart.Load(); inputStart != 0 {
+		startTime = time.Unix(0, inputStart).Add(time.Duration(start * float64(time.Second)))
+	}
+
+	return &Segment{
+		ID:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		ServiceID: r.serviceID,
+		Path:      path,
+		StartTime: startTime,
+		Duration:  duration,
+		Size:      info.Size(),
+	}, nil
+}
+
+// Close stops the recorder, finalizing the segment in progress
+func (r *VideoRecorder) Close() {
+	r.closeOnce.Do(func() {
+		log.Printf("[VideoRecorder] Closing video recorder for service %s", r.serviceID)
+
+		// 1. Signal goroutines to exit
+		close(r.closeChan)
+
+		// 2. Stop the consumer; the writer then closes FFmpeg stdin
+		if r.consumer != nil {
+			r.consumer.Stop()
+		}
+
+		// 3. Give FFmpeg time to flush the last segment
+		done := make(chan struct{})
+		go func() {
+			r.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			log.Printf("[VideoRecorder] Goroutines exited cleanly for service %s", r.serviceID)
+		case <-time.After(5 * time.Second):
+			log.Printf("[VideoRecorder] Timeout waiting for goroutines, forcing cleanup for service %s", r.serviceID)
+		}
+
+		// 4. NOW kill FFmpeg (goroutines have stopped)
+		if r.ffmpegCmd != nil && r.ffmpegCmd.Process != nil {
+			r.ffmpegCmd.Process.Kill()
+			r.ffmpegCmd.Wait()
+			log.Printf("[VideoRecorder] FFmpeg process terminated for service %s", r.serviceID)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package webrtc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSegmentLine(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1700000000-00002.mp4"), make([]byte, 1234), 0644))
+
+	r := NewVideoRecorder("cam", filepath.Dir(dir), time.Minute, nil)
+	inputStart := time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)
+	r.inputStart.Store(inputStart.UnixNano())
+
+	// FFmpeg may report the full output path; only the base name is used
+	segment, err := r.parseSegmentLine(dir, "/elsewhere/1700000000-00002.mp4,120.500000,180.750000")
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000-00002", segment.ID)
+	assert.Equal(t, "cam", segment.ServiceID)
+	assert.Equal(t, filepath.Join(dir, "1700000000-00002.mp4"), segment.Path)
+	assert.True(t, segment.StartTime.Equal(inputStart.Add(120500*time.Millisecond)), "start %v", segment.StartTime)
+	assert.Equal(t, 60250*time.Millisecond, segment.Duration)
+	assert.Equal(t, int64(1234), segment.Size)
+}
+
+func TestParseSegmentLineWithoutInputStart(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "seg.mp4"), nil, 0644))
+
+	r := NewVideoRecorder("cam", dir, time.Minute, nil)
+	before := time.Now()
+	segment, err := r.parseSegmentLine(dir, "seg.mp4,0,10")
+	require.NoError(t, err)
+
+	// Falls back to the segment having just finished
+	assert.WithinDuration(t, before.Add(-10*time.Second), segment.StartTime, time.Second)
+}
+
+func TestParseSegmentLineErrors(t *testing.T) {
+	dir := t.TempDir()
+	r := NewVideoRecorder("cam", dir, time.Minute, nil)
+
+	for _, line := range []string{
+		"",
+		"seg.mp4,0",
+		"seg.mp4,x,10",
+		"seg.mp4,0,y",
+		"missing.mp4,0,10", // File does not exist
+	} {
+		_, err := r.parseSegmentLine(dir, line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestInputStartWriter(t *testing.T) {
+	r := NewVideoRecorder("cam", t.TempDir(), time.Minute, nil)
+	var buf bytes.Buffer
+	w := &inputStartWriter{w: &buf, start: &r.inputStart}
+
+	before := time.Now()
+	_, err := w.Write([]byte("ab"))
+	require.NoError(t, err)
+	first := r.inputStart.Load()
+	require.NotZero(t, first)
+	assert.False(t, time.Unix(0, first).Before(before))
+
+	// Later writes keep the first time
+	_, err = w.Write([]byte("cd"))
+	require.NoError(t, err)
+	assert.Equal(t, first, r.inputStart.Load())
+	assert.Equal(t, "abcd", buf.String())
+}
@@ -0,0 +1,58 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	braveDefaultBaseURL = "https://api.search.brave.com"
+	braveMaxResults     = 20
+)
+
+// BraveProvider searches with the Brave Search API
+type BraveProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// Name returns the provider name
+func (p *BraveProvider) Name() string {
+	return "brave"
+}
+
+// Search returns up to limit results for a query
+func (p *BraveProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", strconv.Itoa(min(limit, braveMaxResults)))
+
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/res/v1/web/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Subscription-Token", p.apiKey)
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := getJSON(ctx, p.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Web.Results))
+	for _, r := range resp.Web.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: stripTags(r.Description)})
+	}
+	return truncateResults(results, limit), nil
+}
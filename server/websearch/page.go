@@ -0,0 +1,148 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"unblink/server/internal/netguard"
+)
+
+// maxPageBytes limits how much of a page is read
+const maxPageBytes = 2 << 20
+
+// skippedElements hold no readable page content
+var skippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Svg:      true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Template: true,
+}
+
+// blockElements start a new line in the extracted text
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Article: true, atom.Section: true, atom.Main: true, atom.Blockquote: true, atom.Pre: true,
+}
+
+// NewPageClient returns the client for reading result pages. Result URLs come
+// from the web, so it only connects to public addresses; the stub server's
+// pages are the one exception, as they are served from its configured base URL.
+func NewPageClient(provider Provider, timeout time.Duration) *http.Client {
+	var allowed []string
+	if stub, ok := provider.(*StubProvider); ok {
+		allowed = append(allowed, stub.baseURL)
+	}
+	return &http.Client{Timeout: timeout, Transport: netguard.NewTransport(allowed...)}
+}
+
+// FetchPage downloads a page and returns its readable text: one line per
+// paragraph, without scripts, styles and navigation. Plain text pages are
+// returned as they are. Use a client from NewPageClient.
+func FetchPage(ctx context.Context, client *http.Client, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,text/plain;q=0.9")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("page returned %s", resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	body := io.LimitReader(resp.Body, maxPageBytes)
+
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		doc, err := html.Parse(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse page: %w", err)
+		}
+		return extractText(doc), nil
+	case "text/plain":
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return "", fmt.Errorf("unsupported content type %q", mediaType)
+	}
+}
+
+// extractText returns the readable text of an HTML document
+func extractText(doc *html.Node) string {
+	var lines []string
+	var line strings.Builder
+
+	flush := func() {
+		if text := strings.Join(strings.Fields(line.String()), " "); text != "" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.ElementNode:
+			if skippedElements[n.DataAtom] {
+				return
+			}
+			if blockElements[n.DataAtom] {
+				flush()
+				defer flush()
+			}
+		case html.TextNode:
+			line.WriteString(n.Data)
+			line.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	flush()
+
+	return strings.Join(lines, "\n")
+}
+
+// stripTags removes HTML markup (like the <strong> highlights of snippets)
+func stripTags(s string) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	return strings.Join(strings.Fields(extractText(doc)), " ")
+}
+
+// truncateResults returns at most limit results
+func truncateResults(results []Result, limit int) []Result {
+	if limit > 0 && len(results) > limit {
+		return results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,81 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// searchTimeout bounds one search request
+const searchTimeout = 15 * time.Second
+
+// Result is one hit of a web search
+type Result struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// Provider searches the web
+type Provider interface {
+	// Name returns the provider name as configured
+	Name() string
+	// Search returns up to limit results for a query, best first
+	Search(ctx context.Context, query string, limit int) ([]Result, error)
+}
+
+// NewProvider creates the provider configured by name: "brave", "searxng" or
+// "stub". The base URL overrides the API endpoint of brave.
+func NewProvider(name, baseURL, apiKey string) (Provider, error) {
+	client := &http.Client{Timeout: searchTimeout}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	switch name {
+	case "brave":
+		if apiKey == "" {
+			return nil, fmt.Errorf("brave requires an API key")
+		}
+		if baseURL == "" {
+			baseURL = braveDefaultBaseURL
+		}
+		return &BraveProvider{client: client, baseURL: baseURL, apiKey: apiKey}, nil
+	case "searxng":
+		if baseURL == "" {
+			return nil, fmt.Errorf("searxng requires a base URL")
+		}
+		return &SearXNGProvider{client: client, baseURL: baseURL, apiKey: apiKey}, nil
+	case "stub":
+		if baseURL == "" {
+			return nil, fmt.Errorf("stub requires a base URL")
+		}
+		return &StubProvider{client: client, baseURL: baseURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown web search provider %q", name)
+	}
+}
+
+// getJSON sends a GET request and decodes the JSON response into out
+func getJSON(ctx context.Context, client *http.Client, req *http.Request, out any) error {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("search returned %s: %s", resp.Status, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return nil
+}
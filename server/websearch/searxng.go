@@ -0,0 +1,53 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SearXNGProvider searches through a SearXNG instance. The instance must
+// allow the json format (search.formats in its settings.yml).
+type SearXNGProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string // Optional, sent as a bearer token for instances behind a proxy
+}
+
+// Name returns the provider name
+func (p *SearXNGProvider) Name() string {
+	return "searxng"
+}
+
+// Search returns up to limit results for a query
+func (p *SearXNGProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, p.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, Result{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return truncateResults(results, limit), nil
+}
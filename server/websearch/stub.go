@@ -0,0 +1,116 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// stubPages is the number of results the stub server returns for any query
+const stubPages = 3
+
+// StubProvider searches a local stub server (see NewStubHandler), so web
+// search can be tried and tested without an external provider
+type StubProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// Name returns the provider name
+func (p *StubProvider) Name() string {
+	return "stub"
+}
+
+// Search returns up to limit results for a query
+func (p *StubProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp stubSearchResponse
+	if err := getJSON(ctx, p.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, Result(r))
+	}
+	return truncateResults(results, limit), nil
+}
+
+type stubSearchResponse struct {
+	Results []stubResult `json:"results"`
+}
+
+type stubResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet"`
+}
+
+// NewStubHandler returns the handler of a local stub search server:
+//
+//	GET /search?q=...&limit=N  JSON results linking to pages of the same server
+//	GET /pages/{n}?q=...       an HTML page about the query
+//
+// Results and pages are generated from the query, so answers are predictable.
+func NewStubHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 || limit > stubPages {
+			limit = stubPages
+		}
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+
+		var resp stubSearchResponse
+		for n := 1; n <= limit; n++ {
+			resp.Results = append(resp.Results, stubResult{
+				Title:   fmt.Sprintf("Stub result %d for %s", n, query),
+				URL:     fmt.Sprintf("%s://%s/pages/%d?q=%s", scheme, r.Host, n, url.QueryEscape(query)),
+				Snippet: fmt.Sprintf("Snippet %d about %s.", n, query),
+			})
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("GET /pages/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n := r.PathValue("n")
+		query := html.EscapeString(r.URL.Query().Get("q"))
+
+		var body strings.Builder
+		fmt.Fprintf(&body, "<!DOCTYPE html><html><head><title>Stub page %s</title><style>p{margin:0}</style></head><body>", n)
+		fmt.Fprintf(&body, "<nav><a href=\"/\">Home</a></nav><h1>Stub page %s about %s</h1>", n, query)
+		for i := 1; i <= 20; i++ {
+			fmt.Fprintf(&body, "<p>Paragraph %d of page %s: this stub page is about %s.</p>", i, n, query)
+		}
+		body.WriteString("<script>console.log('not content')</script><footer>Stub footer</footer></body></html>")
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body.String()))
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,173 @@
+package shared
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultBridgeWindow is how many bytes a sender may have in flight on one bridge
+// direction before it has to wait for a window update from the receiver.
+// Both sides start with this much credit when a bridge opens.
+const DefaultBridgeWindow = 1024 * 1024 // 1 MB
+
+// ErrWindowClosed is returned by FlowWindow.Acquire after the bridge is closed
+var ErrWindowClosed = errors.New("flow window closed")
+
+// ErrWindowTimeout is returned by FlowWindow.Acquire when the deadline passes without credit
+var ErrWindowTimeout = errors.New("timed out waiting for flow window")
+
+// FlowWindow tracks the send credit for one direction of a bridge
+type FlowWindow struct {
+	mu     sync.Mutex
+	credit int64
+	size   int64         // Credit never exceeds the window size
+	wake   chan struct{} // closed and replaced whenever credit is added
+	closed bool
+}
+
+// NewFlowWindow creates a send window that starts with its full size as credit
+func NewFlowWindow(size int64) *FlowWindow {
+	return &FlowWindow{
+		credit: size,
+		size:   size,
+		wake:   make(chan struct{}),
+	}
+}
+
+// Acquire blocks until some credit is available and takes up to max bytes of it.
+// It returns early if done is closed or the deadline (when non-zero) passes.
+func (w *FlowWindow) Acquire(max int, done <-chan struct{}, deadline time.Time) (int, error) {
+	var timerCh <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+
+	for {
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			return 0, ErrWindowClosed
+		}
+		if w.credit > 0 {
+			n := int64(max)
+			if n > w.credit {
+				n = w.credit
+			}
+			w.credit -= n
+			w.mu.Unlock()
+			return int(n), nil
+		}
+		wake := w.wake
+		w.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-done:
+			return 0, ErrWindowClosed
+		case <-timerCh:
+			return 0, ErrWindowTimeout
+		}
+	}
+}
+
+// Release returns credit to the window (from a window update, or unused Acquire credit)
+func (w *FlowWindow) Release(n int64) {
+	if n <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.credit = min(w.credit+n, w.size)
+	close(w.wake)
+	w.wake = make(chan struct{})
+}
+
+// Reset sets the credit to an absolute value, used when a resumed bridge
+// re-synchronizes with the receiver and window updates may have been lost
+func (w *FlowWindow) Reset(credit int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.credit = max(min(credit, w.size), 0)
+	close(w.wake)
+	w.wake = make(chan struct{})
+}
+
+// Credit returns the bytes that may currently be sent without waiting
+func (w *FlowWindow) Credit() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.credit
+}
+
+// Close wakes all waiters; later Acquire calls fail with ErrWindowClosed
+func (w *FlowWindow) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.wake)
+}
+
+// CreditTracker batches consumed bytes on the receiving side of a bridge so
+// window updates are sent in chunks rather than once per message.
+type CreditTracker struct {
+	mu          sync.Mutex
+	pending     int64 // Consumed but not yet granted back
+	outstanding int64 // Received but not yet granted back
+	threshold   int64
+}
+
+// NewCreditTracker creates a tracker that grants credit back once a quarter of the window is consumed
+func NewCreditTracker(window int64) *CreditTracker {
+	return &CreditTracker{
+		threshold: window / 4,
+	}
+}
+
+// Received records n bytes accepted from the sender
+func (t *CreditTracker) Received(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outstanding += int64(n)
+}
+
+// Consume records n drained bytes and returns the credit to grant back (0 = not yet)
+func (t *CreditTracker) Consume(n int) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending += int64(n)
+	if t.pending < t.threshold {
+		return 0
+	}
+
+	credit := t.pending
+	t.pending = 0
+	t.outstanding -= credit
+	return credit
+}
+
+// Outstanding returns the bytes received that the sender has not been credited for
+func (t *CreditTracker) Outstanding() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.outstanding
+}
+
+// BridgeStats is a diagnostics snapshot of one bridge's flow control state
+type BridgeStats struct {
+	BridgeID      string `json:"bridge_id"`
+	ServiceID     string `json:"service_id"`
+	BufferedBytes int64  `json:"buffered_bytes"`  // Received but not yet drained by the local consumer
+	InFlightBytes int64  `json:"in_flight_bytes"` // Sent but not yet acknowledged by a window update
+	SendCredit    int64  `json:"send_credit"`     // Bytes that may be sent without waiting
+}
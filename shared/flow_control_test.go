@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowWindowAcquire(t *testing.T) {
+	w := NewFlowWindow(100)
+
+	n, err := w.Acquire(60, nil, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 60, n)
+
+	// Only the remaining credit is handed out
+	n, err = w.Acquire(60, nil, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 40, n)
+	assert.Zero(t, w.Credit())
+
+	_, err = w.Acquire(10, nil, time.Now().Add(10*time.Millisecond))
+	assert.ErrorIs(t, err, ErrWindowTimeout)
+}
+
+func TestFlowWindowReleaseWakesWaiter(t *testing.T) {
+	w := NewFlowWindow(10)
+	_, err := w.Acquire(10, nil, time.Time{})
+	require.NoError(t, err)
+
+	got := make(chan int, 1)
+	go func() {
+		n, err := w.Acquire(10, nil, time.Now().Add(5*time.Second))
+		require.NoError(t, err)
+		got <- n
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	w.Release(4)
+
+	select {
+	case n := <-got:
+		assert.Equal(t, 4, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter not woken by Release")
+	}
+}
+
+func TestFlowWindowCreditBounds(t *testing.T) {
+	w := NewFlowWindow(100)
+
+	// Credit never exceeds the window size
+	w.Release(50)
+	assert.Equal(t, int64(100), w.Credit())
+	w.Release(-5)
+	assert.Equal(t, int64(100), w.Credit())
+
+	w.Reset(30)
+	assert.Equal(t, int64(30), w.Credit())
+	w.Reset(500)
+	assert.Equal(t, int64(100), w.Credit())
+	w.Reset(-20)
+	assert.Zero(t, w.Credit())
+}
+
+func TestFlowWindowClose(t *testing.T) {
+	w := NewFlowWindow(0)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.Acquire(10, nil, time.Time{})
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	w.Close()
+	w.Close() // Closing twice is allowed
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrWindowClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter not woken by Close")
+	}
+
+	_, err := w.Acquire(10, nil, time.Time{})
+	assert.ErrorIs(t, err, ErrWindowClosed)
+}
+
+func TestFlowWindowDone(t *testing.T) {
+	w := NewFlowWindow(0)
+	done := make(chan struct{})
+	close(done)
+
+	_, err := w.Acquire(10, done, time.Time{})
+	assert.ErrorIs(t, err, ErrWindowClosed)
+}
+
+func TestCreditTracker(t *testing.T) {
+	tracker := NewCreditTracker(100) // Grants credit back every 25 bytes
+
+	tracker.Received(30)
+	assert.Equal(t, int64(30), tracker.Outstanding())
+
+	assert.Zero(t, tracker.Consume(10))
+	assert.Equal(t, int64(30), tracker.Outstanding())
+
+	assert.Equal(t, int64(25), tracker.Consume(15))
+	assert.Equal(t, int64(5), tracker.Outstanding())
+
+	tracker.Received(40)
+	assert.Equal(t, int64(45), tracker.Outstanding())
+	assert.Equal(t, int64(45), tracker.Consume(45))
+	assert.Zero(t, tracker.Outstanding())
+}
@@ -3,6 +3,7 @@ package shared
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 )
@@ -17,10 +18,12 @@ type Message struct {
 // Message type constants
 const (
 	// Token messages
-	MessageTypeTokenCheckRequest  = "token_check_request"
-	MessageTypeTokenCheckResponse = "token_check_response"
-	MessageTypeNewTokenRequest    = "new_token_request"
-	MessageTypeNewTokenResponse   = "new_token_response"
+	MessageTypeTokenCheckRequest   = "token_check_request"
+	MessageTypeTokenCheckResponse  = "token_check_response"
+	MessageTypeNewTokenRequest     = "new_token_request"
+	MessageTypeNewTokenResponse    = "new_token_response"
+	MessageTypeRotateTokenRequest  = "rotate_token_request"
+	MessageTypeRotateTokenResponse = "rotate_token_response"
 
 	// Registration messages
 	MessageTypeRegisterRequest  = "register_request"
@@ -34,7 +37,12 @@ const (
 	MessageTypeCloseBridgeResponse = "close_bridge_response"
 
 	// Data messages
-	MessageTypeBridgeData = "bridge_data"
+	MessageTypeBridgeData         = "bridge_data"
+	MessageTypeBridgeWindowUpdate = "bridge_window_update"
+
+	// Discovery messages
+	MessageTypeDiscoverRequest  = "discover_request"
+	MessageTypeDiscoverResponse = "discover_response"
 )
 
 // Messager interface for all message types
@@ -85,6 +93,18 @@ type NewTokenResponse struct {
 	Token string `json:"token"`
 }
 
+// RotateTokenRequest - registered node asks to replace its token
+type RotateTokenRequest struct {
+	Message
+}
+
+// RotateTokenResponse - server provides the replacement; the old token is revoked
+type RotateTokenResponse struct {
+	Message
+	Token    string `json:"token"`
+	RotateAt int64  `json:"rotate_at,omitempty"` // Unix seconds when to rotate the new token (0 = never)
+}
+
 // ============================================================
 // Registration Messages
 // ============================================================
@@ -96,6 +116,16 @@ type RegisterRequest struct {
 	Token        string   `json:"token"`
 	Hostname     string   `json:"hostname,omitempty"`
 	MACAddresses []string `json:"mac_addresses,omitempty"`
+	NodeVersion  string   `json:"node_version,omitempty"` // Node software version, for display only
+
+	// Versioning (see version.go) - zero means a v1 node
+	ProtocolVersion    int      `json:"protocol_version,omitempty"`
+	MinProtocolVersion int      `json:"min_protocol_version,omitempty"`
+	Capabilities       []string `json:"capabilities,omitempty"`
+
+	// Resume - set when reconnecting with bridges still open
+	SessionID string              `json:"session_id,omitempty"`
+	Bridges   []BridgeResumeState `json:"bridges,omitempty"`
 }
 
 // RegisterResponse - server confirms registration
@@ -104,6 +134,26 @@ type RegisterResponse struct {
 	Success      bool   `json:"success"`
 	Error        string `json:"error,omitempty"`
 	DashboardURL string `json:"dashboard_url,omitempty"`
+
+	// Versioning - Capabilities are the ones negotiated for this connection
+	ProtocolVersion int      `json:"protocol_version,omitempty"`
+	Capabilities    []string `json:"capabilities,omitempty"`
+
+	// Resume - SessionID identifies this session for a later resume.
+	// When Resumed is set, Bridges lists the bridges the server kept; the rest are gone.
+	SessionID string              `json:"session_id,omitempty"`
+	Resumed   bool                `json:"resumed,omitempty"`
+	Bridges   []BridgeResumeState `json:"bridges,omitempty"`
+
+	// Token rotation - unix seconds when the node should rotate its token (0 = never)
+	TokenRotateAt int64 `json:"token_rotate_at,omitempty"`
+}
+
+// BridgeResumeState - receive position of one bridge, exchanged on resume
+type BridgeResumeState struct {
+	BridgeID     string `json:"bridge_id"`
+	LastReceived uint64 `json:"last_received"` // Highest data sequence received from the peer
+	Outstanding  int64  `json:"outstanding"`   // Bytes received but not yet granted back as credit
 }
 
 // NodeReadyMessage - node signals ready for bridges
@@ -120,18 +170,45 @@ type Service struct {
 	ServiceURL string `json:"service_url"`
 }
 
+// Bridge transports
+const (
+	BridgeTransportTCP = "tcp" // Stream bridge (default)
+	BridgeTransportUDP = "udp" // Datagram bridge, one BridgeData per packet (requires udp_bridges)
+)
+
 // OpenBridgeRequest - server requests node to open a bridge
 type OpenBridgeRequest struct {
 	Message
 	BridgeID  string  `json:"bridge_id"`
 	ServiceID string  `json:"service_id"`
 	Service   Service `json:"service"`
+	Transport string  `json:"transport,omitempty"`  // BridgeTransportTCP when empty
+	LocalPort int     `json:"local_port,omitempty"` // UDP only: port to bind on the node (0 = any)
 }
 
 // OpenBridgeResponse - node confirms bridge opened
 type OpenBridgeResponse struct {
 	Message
-	Success bool `json:"success"`
+	Success   bool   `json:"success"`
+	Code      string `json:"code,omitempty"`       // BridgeError* code when Success is false
+	LocalPort int    `json:"local_port,omitempty"` // UDP only: port the node bound
+}
+
+// Reasons a node refuses to open a bridge (OpenBridgeResponse.Code)
+const (
+	BridgeErrorInvalidURL   = "invalid_url"   // Service URL does not parse
+	BridgeErrorTargetDenied = "target_denied" // Node's bridge policy does not allow the target
+	BridgeErrorUnreachable  = "unreachable"   // Target could not be resolved or connected to
+)
+
+// BridgeError is a refused OpenBridgeRequest, carrying the node's error code
+type BridgeError struct {
+	Code    string
+	Message string
+}
+
+func (e *BridgeError) Error() string {
+	return e.Message
 }
 
 // CloseBridgeRequest - server requests node to close a bridge
@@ -152,6 +229,71 @@ type BridgeDataMessage struct {
 	BridgeID string `json:"bridge_id"`
 	Sequence uint64 `json:"sequence"`
 	Data     []byte `json:"data"`
+	Port     int    `json:"port,omitempty"` // UDP only: service port the datagram is for (server -> node) or from (node -> server)
+}
+
+// BridgeWindowUpdateMessage - receiver grants the sender more credit on a bridge.
+// Sent in either direction once data has been drained (see DefaultBridgeWindow).
+type BridgeWindowUpdateMessage struct {
+	Message
+	BridgeID string `json:"bridge_id"`
+	Credit   int64  `json:"credit"`        // Additional bytes the sender may send
+	Ack      uint64 `json:"ack,omitempty"` // Highest data sequence received; lets the sender trim its retransmit buffer
+}
+
+// ============================================================
+// Discovery Messages
+// ============================================================
+
+// Where a discovered device was found (DiscoveredDevice.Source)
+const (
+	DiscoverySourceWSDiscovery = "ws-discovery" // ONVIF WS-Discovery probe
+	DiscoverySourceMDNS        = "mdns"         // _rtsp._tcp mDNS service
+)
+
+// DefaultDiscoveryTimeout is how long the node listens for replies when the request does not say
+const DefaultDiscoveryTimeout = 3 * time.Second
+
+// DiscoverRequest - server asks the node to look for cameras on its LAN
+type DiscoverRequest struct {
+	Message
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // How long to listen for replies (0 = DefaultDiscoveryTimeout)
+	Username  string `json:"username,omitempty"`   // ONVIF credentials, needed for stream URLs
+	Password  string `json:"password,omitempty"`
+}
+
+// DiscoverResponse - cameras the node found
+type DiscoverResponse struct {
+	Message
+	Success bool               `json:"success"`
+	Code    string             `json:"code,omitempty"` // BridgeErrorTargetDenied when the bridge policy disables discovery
+	Devices []DiscoveredDevice `json:"devices"`
+}
+
+// DiscoveredDevice - one camera found on the node's LAN
+type DiscoveredDevice struct {
+	Address      string             `json:"address"` // IP address
+	Source       string             `json:"source"`  // DiscoverySource*
+	Name         string             `json:"name,omitempty"`
+	Manufacturer string             `json:"manufacturer,omitempty"`
+	Model        string             `json:"model,omitempty"`
+	ONVIFURL     string             `json:"onvif_url,omitempty"` // ONVIF device service
+	Streams      []DiscoveredStream `json:"streams,omitempty"`
+	Error        string             `json:"error,omitempty"` // Why the streams could not be listed
+}
+
+// DiscoveredStream - a stream URL offered by a discovered device
+type DiscoveredStream struct {
+	Profile  string `json:"profile,omitempty"` // ONVIF profile name
+	URL      string `json:"url"`               // Includes the credentials from the request
+	Encoding string `json:"encoding,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// UnknownMessage - a message of a type this build does not implement
+type UnknownMessage struct {
+	Message
 }
 
 // ============================================================
@@ -173,6 +315,8 @@ func DecodeMessage(data []byte) (any, error) {
 		MessageTypeTokenCheckResponse:  &TokenCheckResponse{},
 		MessageTypeNewTokenRequest:     &NewTokenRequest{},
 		MessageTypeNewTokenResponse:    &NewTokenResponse{},
+		MessageTypeRotateTokenRequest:  &RotateTokenRequest{},
+		MessageTypeRotateTokenResponse: &RotateTokenResponse{},
 		MessageTypeRegisterRequest:     &RegisterRequest{},
 		MessageTypeRegisterResponse:    &RegisterResponse{},
 		MessageTypeNodeReady:           &NodeReadyMessage{},
@@ -181,11 +325,16 @@ func DecodeMessage(data []byte) (any, error) {
 		MessageTypeCloseBridgeRequest:  &CloseBridgeRequest{},
 		MessageTypeCloseBridgeResponse: &CloseBridgeResponse{},
 		MessageTypeBridgeData:          &BridgeDataMessage{},
+		MessageTypeBridgeWindowUpdate:  &BridgeWindowUpdateMessage{},
+		MessageTypeDiscoverRequest:     &DiscoverRequest{},
+		MessageTypeDiscoverResponse:    &DiscoverResponse{},
 	}
 
 	prototype, ok := typeMap[header.Type]
 	if !ok {
-		return nil, fmt.Errorf("unknown message type: %s", header.Type)
+		// Newer peers may send types we don't know; let the handler skip them
+		// instead of failing the whole connection
+		return &UnknownMessage{Message: header}, nil
 	}
 
 	msgValue := reflect.New(reflect.TypeOf(prototype).Elem()).Interface()
@@ -0,0 +1,53 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	tests := []any{
+		&BridgeDataMessage{
+			Message:  Message{Type: MessageTypeBridgeData, ID: 7},
+			BridgeID: "bridge-1",
+			Sequence: 42,
+			Data:     []byte{0, 1, 2, 0xff},
+			Port:     554,
+		},
+		&BridgeWindowUpdateMessage{
+			Message:  Message{Type: MessageTypeBridgeWindowUpdate, ID: 8},
+			BridgeID: "bridge-1",
+			Credit:   DefaultBridgeWindow / 4,
+			Ack:      42,
+		},
+		&CloseBridgeRequest{
+			Message:  Message{Type: MessageTypeCloseBridgeRequest, ID: 9},
+			BridgeID: "bridge-1",
+		},
+	}
+
+	for _, msg := range tests {
+		data, err := EncodeMessage(msg)
+		require.NoError(t, err)
+
+		decoded, err := DecodeMessage(data)
+		require.NoError(t, err)
+		assert.Equal(t, msg, decoded)
+	}
+}
+
+func TestDecodeUnknownMessage(t *testing.T) {
+	data, err := EncodeMessage(&Message{Type: "from_the_future", ID: 3})
+	require.NoError(t, err)
+
+	decoded, err := DecodeMessage(data)
+	require.NoError(t, err)
+	assert.Equal(t, &UnknownMessage{Message: Message{Type: "from_the_future", ID: 3}}, decoded)
+}
+
+func TestDecodeInvalidMessage(t *testing.T) {
+	_, err := DecodeMessage([]byte{0xff, 0x00})
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package shared
+
+import "time"
+
+// ResumeGracePeriod is how long both sides keep bridges alive after the
+// WebSocket drops, waiting for the node to reconnect and resume its session
+const ResumeGracePeriod = 30 * time.Second
+
+// RetransmitEntry is one data message kept for replay
+type RetransmitEntry struct {
+	Sequence uint64
+	Data     []byte
+}
+
+// RetransmitBuffer keeps sent bridge data until the peer acknowledges it, so it
+// can be replayed after a reconnect. Its size is bounded by the flow window.
+// It is not safe for concurrent use; callers hold the bridge lock.
+type RetransmitBuffer struct {
+	entries []RetransmitEntry
+	bytes   int64
+}
+
+// Add stores a copy of data under seq
+func (b *RetransmitBuffer) Add(seq uint64, data []byte) {
+	b.entries = append(b.entries, RetransmitEntry{
+		Sequence: seq,
+		Data:     append([]byte(nil), data...),
+	})
+	b.bytes += int64(len(data))
+}
+
+// Ack drops every entry up to and including seq
+func (b *RetransmitBuffer) Ack(seq uint64) {
+	i := 0
+	for i < len(b.entries) && b.entries[i].Sequence <= seq {
+		b.bytes -= int64(len(b.entries[i].Data))
+		i++
+	}
+	b.entries = b.entries[i:]
+}
+
+// Entries returns the unacknowledged entries in sequence order
+func (b *RetransmitBuffer) Entries() []RetransmitEntry {
+	return b.entries
+}
+
+// Bytes returns the total unacknowledged payload size
+func (b *RetransmitBuffer) Bytes() int64 {
+	return b.bytes
+}
@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetransmitBuffer(t *testing.T) {
+	var b RetransmitBuffer
+
+	data := []byte("abc")
+	b.Add(1, data)
+	b.Add(2, []byte("defg"))
+	b.Add(3, []byte("h"))
+	assert.Equal(t, int64(8), b.Bytes())
+
+	// Entries are copies, so the caller may reuse its read buffer
+	data[0] = 'x'
+	assert.Equal(t, "abc", string(b.Entries()[0].Data))
+
+	b.Ack(2)
+	assert.Equal(t, []RetransmitEntry{{Sequence: 3, Data: []byte("h")}}, b.Entries())
+	assert.Equal(t, int64(1), b.Bytes())
+
+	// Acks for old sequences change nothing
+	b.Ack(1)
+	assert.Len(t, b.Entries(), 1)
+
+	b.Ack(10)
+	assert.Empty(t, b.Entries())
+	assert.Zero(t, b.Bytes())
+}
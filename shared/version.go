@@ -0,0 +1,73 @@
+package shared
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Protocol versions. Version 1 is the original protocol, which had no version
+// field; a missing version is read as 1.
+const (
+	ProtocolVersion    = 2 // Version spoken by this build
+	MinProtocolVersion = 1 // Oldest peer version this build still talks to
+)
+
+// Capabilities are optional protocol features. A feature is only used on a
+// connection when both sides list it in the registration handshake.
+const (
+	CapabilityFlowControl   = "flow_control"   // Credit-based BridgeWindowUpdate messages
+	CapabilityResume        = "resume"         // Session resume with retransmission (requires flow_control)
+	CapabilityUDPBridges    = "udp_bridges"    // Datagram bridges (OpenBridgeRequest.Transport = "udp")
+	CapabilityTokenRotation = "token_rotation" // RotateTokenRequest/Response on registered connections
+	CapabilityDiscovery     = "discovery"      // DiscoverRequest/Response (ONVIF and mDNS camera discovery)
+)
+
+// SupportedCapabilities lists the capabilities implemented by this build
+var SupportedCapabilities = []string{
+	CapabilityFlowControl,
+	CapabilityResume,
+	CapabilityUDPBridges,
+	CapabilityTokenRotation,
+	CapabilityDiscovery,
+}
+
+// EffectiveVersion maps a missing (zero) version to version 1
+func EffectiveVersion(version int) int {
+	if version == 0 {
+		return 1
+	}
+	return version
+}
+
+// CheckVersion reports whether a peer speaking peerVersion, and requiring at
+// least peerMin, can talk to this build. The error says which side to upgrade.
+func CheckVersion(peerVersion, peerMin int, peerName string) error {
+	peerVersion = EffectiveVersion(peerVersion)
+	peerMin = EffectiveVersion(peerMin)
+
+	if peerVersion < MinProtocolVersion {
+		return fmt.Errorf("incompatible protocol: %s speaks v%d but v%d or newer is required; upgrade the %s",
+			peerName, peerVersion, MinProtocolVersion, peerName)
+	}
+	if peerMin > ProtocolVersion {
+		return fmt.Errorf("incompatible protocol: %s requires v%d or newer but this build speaks v%d; upgrade this side",
+			peerName, peerMin, ProtocolVersion)
+	}
+	return nil
+}
+
+// NegotiateCapabilities returns the capabilities both sides support
+func NegotiateCapabilities(local, remote []string) []string {
+	var common []string
+	for _, c := range local {
+		if slices.Contains(remote, c) {
+			common = append(common, c)
+		}
+	}
+
+	// Resume relies on window update acks to trim its retransmit buffers
+	if !slices.Contains(common, CapabilityFlowControl) {
+		common = slices.DeleteFunc(common, func(c string) bool { return c == CapabilityResume })
+	}
+	return common
+}
@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		peerVersion int
+		peerMin     int
+		wantErr     string
+	}{
+		{"legacy peer without version", 0, 0, ""},
+		{"same version", ProtocolVersion, MinProtocolVersion, ""},
+		{"newer peer that still talks to us", ProtocolVersion + 1, ProtocolVersion, ""},
+		{"newer peer that requires a newer version", ProtocolVersion + 2, ProtocolVersion + 1, "upgrade this side"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckVersion(tt.peerVersion, tt.peerMin, "relay")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEffectiveVersion(t *testing.T) {
+	assert.Equal(t, 1, EffectiveVersion(0))
+	assert.Equal(t, 2, EffectiveVersion(2))
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		local  []string
+		remote []string
+		want   []string
+	}{
+		{"legacy peer", SupportedCapabilities, nil, nil},
+		{"same build", SupportedCapabilities, SupportedCapabilities, SupportedCapabilities},
+		{
+			"unknown remote capabilities are ignored",
+			[]string{CapabilityFlowControl, CapabilityDiscovery},
+			[]string{CapabilityDiscovery, "future_feature"},
+			[]string{CapabilityDiscovery},
+		},
+		{
+			"resume needs flow control",
+			[]string{CapabilityFlowControl, CapabilityResume, CapabilityUDPBridges},
+			[]string{CapabilityResume, CapabilityUDPBridges},
+			[]string{CapabilityUDPBridges},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateCapabilities(tt.local, tt.remote))
+		})
+	}
+}